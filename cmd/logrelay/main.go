@@ -0,0 +1,142 @@
+// Command logrelay is a node-local log shipper: it follows log files written
+// by go_logger (classic text or JSON lines) and listens on a unix socket for
+// SocketSink clients, forwarding every entry to one network sink with its
+// original time, level, caller, and fields. Positions in followed files are
+// kept in a state file, so a restart neither loses nor repeats entries.
+//
+// Usage:
+//
+//	logrelay [flags]
+//
+// Examples:
+//
+//	logrelay -file /var/log/app/app.log -sink syslog -network tls -addr logs.example.com:6514
+//	logrelay -socket /run/logrelay.sock -sink mqtt -addr broker:1883 -topic node7/logs
+//	logrelay -file /var/log/a.log -file /var/log/b.log -socket /run/logrelay.sock -socket-network unix -sink json
+//
+// It runs until SIGINT or SIGTERM.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/mordilloSan/go_logger/logger"
+)
+
+func main() {
+	var files []string
+	flag.Func("file", "log file to follow (repeatable)", func(s string) error {
+		files = append(files, s)
+		return nil
+	})
+	socket := flag.String("socket", "", "unix socket path to receive SocketSink entries on")
+	socketNetwork := flag.String("socket-network", "unixgram", "socket type: unixgram or unix")
+	position := flag.String("position", "/var/lib/logrelay/positions.json", `file recording followed file positions ("" to start over each run)`)
+	sinkName := flag.String("sink", "syslog", "destination: syslog, socket, mqtt, or text/json (stdout)")
+	network := flag.String("network", "", `syslog: "", udp, tcp, tls, relp; socket: unixgram or unix`)
+	addr := flag.String("addr", "", "syslog/mqtt host:port, or socket path")
+	topic := flag.String("topic", "", "mqtt topic")
+	flag.Parse()
+
+	if len(files) == 0 && *socket == "" {
+		fmt.Fprintln(os.Stderr, "logrelay: nothing to relay, use -file or -socket")
+		os.Exit(2)
+	}
+	sink, err := newSink(*sinkName, *network, *addr, *topic)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "logrelay:", err)
+		os.Exit(1)
+	}
+	relay, err := logger.NewRelay(logger.RelayConfig{Sink: sink, PositionFile: *position})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "logrelay:", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+	run := func(name string, fn func(context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "logrelay: %s: %v\n", name, err)
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+				cancel()
+			}
+		}()
+	}
+	for _, name := range files {
+		run(name, func(ctx context.Context) error { return relay.FollowFile(ctx, name) })
+	}
+	if *socket != "" {
+		run(*socket, func(ctx context.Context) error { return relay.ServeSocket(ctx, *socketNetwork, *socket) })
+	}
+	wg.Wait()
+
+	if err := relay.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, "logrelay:", err)
+		failed = true
+	}
+	fmt.Fprintf(os.Stderr, "logrelay: %d entries forwarded, %d unparseable records skipped\n", relay.Forwarded(), relay.Skipped())
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func newSink(name, network, addr, topic string) (logger.Sink, error) {
+	switch name {
+	case "text":
+		return &writerSink{w: os.Stdout, enc: logger.TextEncoder{TimeFormat: logger.DefaultTimeFormat}}, nil
+	case "json":
+		return &writerSink{w: os.Stdout, enc: logger.JSONEncoder{}}, nil
+	case "syslog":
+		cfg := logger.SyslogConfig{Network: network, Address: addr}
+		if network == "tls" {
+			cfg.TLSConfig = &tls.Config{}
+		}
+		return logger.NewSyslogSink(cfg)
+	case "socket":
+		if network == "" {
+			network = "unixgram"
+		}
+		return logger.NewSocketSink(network, addr, nil)
+	case "mqtt":
+		return logger.NewMQTTSink(logger.MQTTConfig{Address: addr, Topic: topic, QoS: 1})
+	}
+	return nil, fmt.Errorf("unknown sink %q", name)
+}
+
+// writerSink writes encoded entries, one per line, to w.
+type writerSink struct {
+	w   io.Writer
+	enc logger.Encoder
+}
+
+func (s *writerSink) WriteEntry(e *logger.Entry) error {
+	data, err := s.enc.Encode(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.w, "%s\n", data)
+	return err
+}
+
+func (s *writerSink) Close() error {
+	return nil
+}
@@ -0,0 +1,146 @@
+// Command logreplay reads log files written by go_logger (classic text,
+// JSON lines, or binary files from FileSink) and re-emits each entry to a
+// sink, keeping the original time, level, caller, and fields. It is meant
+// for backfilling logs collected offline, e.g. from air-gapped devices, and
+// for inspecting binary logs ("logreplay -sink text entries.msgpack").
+//
+// Usage:
+//
+//	logreplay [flags] file...   (use "-" or no files for stdin)
+//
+// Examples:
+//
+//	logreplay -sink json app.log > app.jsonl
+//	logreplay -sink syslog -network tls -addr logs.example.com:6514 device-*.log
+//	logreplay -sink mqtt -addr broker:1883 -topic fleet/backfill app.log
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mordilloSan/go_logger/logger"
+)
+
+func main() {
+	sinkName := flag.String("sink", "text", "destination: text, json (stdout), syslog, socket, or mqtt")
+	network := flag.String("network", "", `syslog: "", udp, tcp, tls, relp; socket: unixgram or unix`)
+	addr := flag.String("addr", "", "syslog/mqtt host:port, or socket path")
+	topic := flag.String("topic", "", "mqtt topic")
+	min := logger.DebugLevel
+	flag.Var(&min, "min-level", "skip entries below this level (DEBUG, INFO, WARN, ERROR, FATAL)")
+	flag.Parse()
+
+	sink, err := newSink(*sinkName, *network, *addr, *topic)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "logreplay:", err)
+		os.Exit(1)
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	var replayed, sinkErrs, skipped int
+	failed := false
+	for _, name := range files {
+		n, e, s, err := replay(name, sink, min)
+		replayed += n
+		sinkErrs += e
+		skipped += s
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logreplay: %s: %v\n", name, err)
+			failed = true
+		}
+	}
+	if err := sink.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, "logreplay:", err)
+		failed = true
+	}
+	fmt.Fprintf(os.Stderr, "logreplay: %d entries replayed, %d sink errors, %d unparseable lines skipped\n", replayed, sinkErrs, skipped)
+	if failed || sinkErrs > 0 {
+		os.Exit(1)
+	}
+}
+
+// replay sends every parseable entry of the named file to sink. Sink errors
+// are counted and the first is reported, but replaying continues: sinks such
+// as MQTTSink hold entries while offline and send them once they reconnect.
+// Only errors reading the file stop the replay.
+func replay(name string, sink logger.Sink, min logger.Level) (replayed, sinkErrs, skipped int, err error) {
+	var lr *logger.LogReader
+	if name == "-" {
+		lr, err = logger.NewLogReader(os.Stdin)
+	} else {
+		lr, err = logger.OpenLogFile(name)
+	}
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer lr.Close()
+	for {
+		e, err := lr.Next()
+		if err == io.EOF {
+			return replayed, sinkErrs, lr.Skipped(), nil
+		}
+		if err != nil {
+			return replayed, sinkErrs, lr.Skipped(), err
+		}
+		if e.Level < min {
+			continue
+		}
+		if err := sink.WriteEntry(e); err != nil {
+			if sinkErrs == 0 {
+				fmt.Fprintf(os.Stderr, "logreplay: %s: %v\n", name, err)
+			}
+			sinkErrs++
+			continue
+		}
+		replayed++
+	}
+}
+
+func newSink(name, network, addr, topic string) (logger.Sink, error) {
+	switch name {
+	case "text":
+		return &writerSink{w: os.Stdout, enc: logger.TextEncoder{TimeFormat: logger.DefaultTimeFormat}}, nil
+	case "json":
+		return &writerSink{w: os.Stdout, enc: logger.JSONEncoder{}}, nil
+	case "syslog":
+		cfg := logger.SyslogConfig{Network: network, Address: addr}
+		if network == "tls" {
+			cfg.TLSConfig = &tls.Config{}
+		}
+		return logger.NewSyslogSink(cfg)
+	case "socket":
+		if network == "" {
+			network = "unixgram"
+		}
+		return logger.NewSocketSink(network, addr, nil)
+	case "mqtt":
+		return logger.NewMQTTSink(logger.MQTTConfig{Address: addr, Topic: topic, QoS: 1})
+	}
+	return nil, fmt.Errorf("unknown sink %q", name)
+}
+
+// writerSink writes encoded entries, one per line, to w.
+type writerSink struct {
+	w   io.Writer
+	enc logger.Encoder
+}
+
+func (s *writerSink) WriteEntry(e *logger.Entry) error {
+	data, err := s.enc.Encode(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.w, "%s\n", data)
+	return err
+}
+
+func (s *writerSink) Close() error {
+	return nil
+}
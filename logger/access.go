@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+// AccessRecord describes a finished request for LogAccess.
+type AccessRecord struct {
+	Method string
+	Path   string
+	// Route is the route pattern the request matched, such as
+	// /users/{id}. When set it is logged as the path, and Path as
+	// raw_path, like MiddlewareConfig.RoutePatterns does.
+	Route  string
+	Status int
+	// Duration is how long the request took; when zero it is measured
+	// from Start.
+	Duration time.Duration
+	Start    time.Time
+	// RequestBytes and ResponseBytes are the body sizes read and written,
+	// logged as request_bytes and response_bytes when not zero.
+	RequestBytes  int64
+	ResponseBytes int64
+	// ClientIP is the address of the client, logged as client_ip when set.
+	ClientIP string
+	// Baggage is the request's W3C baggage header, whose members are
+	// added to the entry as with MiddlewareConfig.Baggage.
+	Baggage string
+	// Hijacked marks a connection the handler took over, such as a
+	// websocket, logged as hijacked=true without response_bytes.
+	Hijacked bool
+	// SlowThreshold, when positive, logs requests taking at least this
+	// long at WARN, or ERROR for 5xx responses, with slow=true, like
+	// MiddlewareConfig.SlowThreshold.
+	SlowThreshold time.Duration
+	// Skip drops the entry unless the request failed with a 5xx status,
+	// as MiddlewareConfig.SkipPaths does for health checks.
+	Skip bool
+}
+
+// LogAccess writes the access log entry Middleware writes for net/http
+// requests, for servers built on other HTTP stacks such as fasthttp, so
+// every service's access logs have the same fields. ctx supplies fields
+// added with ContextWithFields; a *fasthttp.RequestCtx is a context too:
+//
+//	func accessLog(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+//	    return func(ctx *fasthttp.RequestCtx) {
+//	        start := time.Now()
+//	        next(ctx)
+//	        logger.LogAccess(ctx, logger.AccessRecord{
+//	            Method:  string(ctx.Method()),
+//	            Path:    string(ctx.Path()),
+//	            Status:  ctx.Response.StatusCode(),
+//	            Start:   start,
+//	            Baggage: string(ctx.Request.Header.Peek(logger.BaggageHeader)),
+//	        })
+//	    }
+//	}
+//	// [INFO] [http] GET /users/42 method=GET path=/users/42 status=200 duration_ms=3
+func LogAccess(ctx context.Context, r AccessRecord) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if r.Duration == 0 && !r.Start.IsZero() {
+		r.Duration = time.Since(r.Start)
+	}
+	if r.Baggage != "" {
+		if members := parseBaggage(r.Baggage); len(members) > 0 {
+			ctx = context.WithValue(ctx, ctxBaggageKey{}, members)
+		}
+	}
+	if level, msg, keyvals, ok := r.entry(); ok {
+		logAccess(ctx, level, msg, keyvals)
+	}
+}
+
+// entry returns the level, message, and fields of the access log entry for
+// r, shared by LogAccess and Middleware, or false when r.Skip drops it.
+func (r AccessRecord) entry() (level Level, msg string, keyvals []any, ok bool) {
+	if r.Skip && r.Status < 500 {
+		return 0, "", nil, false
+	}
+	path := r.Path
+	if r.Route != "" {
+		path = r.Route
+	}
+	keyvals = []any{
+		"method", r.Method,
+		"path", path,
+		"status", r.Status,
+		"duration_ms", r.Duration.Milliseconds(),
+	}
+	if path != r.Path {
+		keyvals = append(keyvals, "raw_path", r.Path)
+	}
+	if r.RequestBytes != 0 {
+		keyvals = append(keyvals, "request_bytes", r.RequestBytes)
+	}
+	if r.ResponseBytes != 0 && !r.Hijacked {
+		keyvals = append(keyvals, "response_bytes", r.ResponseBytes)
+	}
+	if r.ClientIP != "" {
+		keyvals = append(keyvals, "client_ip", r.ClientIP)
+	}
+	if r.Hijacked {
+		keyvals = append(keyvals, "hijacked", true)
+	}
+	level = statusCodeToLevel(r.Status)
+	if r.SlowThreshold > 0 && r.Duration >= r.SlowThreshold {
+		level = max(level, WarnLevel)
+		keyvals = append(keyvals, "slow", true)
+	}
+	return level, r.Method + " " + path, keyvals, true
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// initialized is set by the first InitWithConfig, explicit or automatic
+	initialized atomic.Bool
+	autoInit    sync.Once
+)
+
+// DefaultConfig returns the configuration the logger initializes itself
+// with when an entry is logged before any Init call, read from the
+// environment:
+//
+//	LOGGER_MODE     "development" (default) or "production"
+//	LOGGER_VERBOSE  enables DEBUG in development, e.g. LOGGER_VERBOSE=1
+//	LOGGER_FILE     also log to this file
+//
+// LOGGER_LEVELS and the other variables read by InitWithConfig apply as
+// usual.
+func DefaultConfig() Config {
+	cfg := Config{Mode: os.Getenv("LOGGER_MODE"), FilePath: os.Getenv("LOGGER_FILE")}
+	if cfg.Mode == "" {
+		cfg.Mode = "development"
+	}
+	cfg.Verbose, _ = strconv.ParseBool(os.Getenv("LOGGER_VERBOSE"))
+	return cfg
+}
+
+// Default initializes the logger with DefaultConfig unless Init already
+// ran, and returns the root logger. Logging functions do the same on their
+// first call, so entries logged from package init functions of libraries,
+// before main calls Init, are written instead of lost; a later Init
+// replaces the automatic configuration.
+func Default() *Logger {
+	ensureInit()
+	return Get("")
+}
+
+// ensureInit runs InitWithConfig(DefaultConfig()) once if no Init call came
+// first. It must not be called with logMutex held.
+func ensureInit() {
+	if initialized.Load() {
+		return
+	}
+	autoInit.Do(func() {
+		if !initialized.Load() {
+			InitWithConfig(DefaultConfig())
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// BaggageHeader is the W3C Baggage header carrying correlation fields
+// between services.
+const BaggageHeader = "baggage"
+
+// Limits applied to incoming baggage headers, from the W3C Baggage
+// specification.
+const (
+	maxBaggageMembers = 180
+	maxBaggageBytes   = 8192
+)
+
+type ctxBaggageKey struct{}
+
+// baggageMember is one key-value pair of the baggage.
+type baggageMember struct {
+	key, value string
+}
+
+// ContextWithBaggage returns a copy of ctx carrying key-value pairs that
+// the *Context logging functions add to every entry, like
+// ContextWithFields, and that BaggageTransport forwards to downstream
+// services in the baggage header. Keep it small: tenant, feature flags, and
+// similar correlation fields. Values are converted to strings and a key set
+// again replaces its earlier value.
+//
+//	ctx = logger.ContextWithBaggage(ctx, "tenant", "acme", "flags", "new-checkout")
+//	logger.InfoContext(ctx, "order placed")
+//	// [INFO] [shop.Checkout:42] order placed tenant=acme flags=new-checkout
+func ContextWithBaggage(ctx context.Context, keyvals ...any) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	members := baggageOf(ctx)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		members = setBaggage(members, fmt.Sprint(keyvals[i]), fmt.Sprint(keyvals[i+1]))
+	}
+	return context.WithValue(ctx, ctxBaggageKey{}, members)
+}
+
+// Baggage returns a copy of the baggage carried by ctx, or nil when it has
+// none.
+func Baggage(ctx context.Context) map[string]string {
+	members := baggageOf(ctx)
+	if len(members) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(members))
+	for _, m := range members {
+		out[m.key] = m.value
+	}
+	return out
+}
+
+// baggageOf returns the baggage carried by ctx, in the order the keys were
+// first set.
+func baggageOf(ctx context.Context) []baggageMember {
+	if ctx == nil {
+		return nil
+	}
+	members, _ := ctx.Value(ctxBaggageKey{}).([]baggageMember)
+	return members
+}
+
+// setBaggage returns members with key set to value, leaving members itself
+// unchanged.
+func setBaggage(members []baggageMember, key, value string) []baggageMember {
+	out := make([]baggageMember, len(members), len(members)+1)
+	copy(out, members)
+	for i := range out {
+		if out[i].key == key {
+			out[i].value = value
+			return out
+		}
+	}
+	return append(out, baggageMember{key, value})
+}
+
+// baggageFields returns the baggage of ctx as key-value pairs.
+func baggageFields(ctx context.Context) []any {
+	members := baggageOf(ctx)
+	fields := make([]any, 0, 2*len(members))
+	for _, m := range members {
+		fields = append(fields, m.key, m.value)
+	}
+	return fields
+}
+
+// parseBaggage decodes a baggage header value, dropping member properties
+// and malformed members, and stopping at the specification's limits.
+func parseBaggage(header string) []baggageMember {
+	if len(header) > maxBaggageBytes {
+		return nil
+	}
+	var members []baggageMember
+	for _, raw := range strings.Split(header, ",") {
+		if len(members) == maxBaggageMembers {
+			break
+		}
+		member, _, _ := strings.Cut(raw, ";")
+		key, value, ok := strings.Cut(member, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t\"") {
+			continue
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		members = setBaggage(members, key, value)
+	}
+	return members
+}
+
+// formatBaggage encodes members as a baggage header value.
+func formatBaggage(members []baggageMember) string {
+	var b strings.Builder
+	for i, m := range members {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(m.key)
+		b.WriteByte('=')
+		b.WriteString(url.PathEscape(m.value))
+	}
+	return b.String()
+}
+
+// BaggageTransport returns a RoundTripper that adds the baggage of each
+// request's context to its baggage header before passing it to next, so a
+// downstream service using Middleware with Baggage set logs the same
+// fields. Members already in the header are kept unless the context sets
+// the same key. A nil next uses http.DefaultTransport.
+//
+//	client := &http.Client{Transport: logger.BaggageTransport(nil)}
+func BaggageTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return baggageTransport{next}
+}
+
+type baggageTransport struct {
+	next http.RoundTripper
+}
+
+func (t baggageTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	members := baggageOf(r.Context())
+	if len(members) == 0 {
+		return t.next.RoundTrip(r)
+	}
+	merged := parseBaggage(r.Header.Get(BaggageHeader))
+	for _, m := range members {
+		merged = setBaggage(merged, m.key, m.value)
+	}
+	r = r.Clone(r.Context())
+	r.Header.Set(BaggageHeader, formatBaggage(merged))
+	return t.next.RoundTrip(r)
+}
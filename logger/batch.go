@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"io"
+	"sync"
+)
+
+// maxBatchBytes is how much unwritten output a batchWriter holds before
+// Write blocks until the background goroutine catches up. Log file output
+// is never dropped.
+const maxBatchBytes = 1 << 20
+
+// batchWriter collects writes in memory and hands them to w from a
+// background goroutine, so every line queued while the previous write was
+// in progress goes out in a single write syscall.
+type batchWriter struct {
+	w io.Writer
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	buf      []byte // pending output
+	spare    []byte // buffer reused for the next batch
+	flushing bool
+	closed   bool
+	done     chan struct{}
+}
+
+// fileBatch is the batchWriter in front of logFile in FileAsync mode
+var fileBatch *batchWriter
+
+func newBatchWriter(w io.Writer) *batchWriter {
+	b := &batchWriter{w: w, done: make(chan struct{})}
+	b.cond = sync.NewCond(&b.mu)
+	go b.run()
+	return b
+}
+
+// Write queues p for the next batch. It only blocks when maxBatchBytes are
+// already pending.
+func (b *batchWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for len(b.buf) >= maxBatchBytes && !b.closed {
+		b.cond.Wait()
+	}
+	if b.closed {
+		return 0, io.ErrClosedPipe
+	}
+	b.buf = append(b.buf, p...)
+	b.cond.Broadcast()
+	return len(p), nil
+}
+
+func (b *batchWriter) run() {
+	defer close(b.done)
+	b.mu.Lock()
+	for {
+		for len(b.buf) == 0 && !b.closed {
+			b.cond.Wait()
+		}
+		if len(b.buf) == 0 {
+			b.mu.Unlock()
+			return
+		}
+		batch := b.buf
+		b.buf, b.spare = b.spare[:0], nil
+		b.flushing = true
+		b.mu.Unlock()
+
+		b.w.Write(batch)
+
+		b.mu.Lock()
+		b.spare = batch[:0]
+		b.flushing = false
+		b.cond.Broadcast()
+	}
+}
+
+// Flush blocks until everything written so far has reached w.
+func (b *batchWriter) Flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for len(b.buf) > 0 || b.flushing {
+		b.cond.Wait()
+	}
+}
+
+// Close writes any pending output and stops the background goroutine.
+func (b *batchWriter) Close() {
+	b.mu.Lock()
+	b.closed = true
+	b.cond.Broadcast()
+	b.mu.Unlock()
+	<-b.done
+}
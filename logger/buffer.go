@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"context"
+	"log"
+)
+
+// DefaultRequestBufferSize is the number of entries a RequestBuffer keeps
+// when NewRequestBuffer is called with a non-positive limit.
+const DefaultRequestBufferSize = 1000
+
+// RequestBuffer holds DEBUG and INFO entries for a single request so they can
+// be discarded when the request succeeds, or written in full when it fails.
+//
+// Attach a buffer with ContextWithBuffer and log through the *Context
+// functions. DEBUG/INFO entries are held back (even if their level is
+// filtered by LOGGER_LEVELS); WARN passes straight through; ERROR and FATAL
+// flush the buffer before being logged. After a flush the buffer is tripped
+// and later entries for the request are written immediately.
+//
+// Buffered entries are written through the normal output of their level, so
+// DEBUG entries only appear where DEBUG output is configured.
+type RequestBuffer struct {
+	entries []*Entry
+	limit   int
+	dropped int
+	tripped bool
+	done    bool
+}
+
+// NewRequestBuffer returns a buffer holding up to limit entries. When full,
+// the oldest entries are dropped. A non-positive limit uses DefaultRequestBufferSize.
+func NewRequestBuffer(limit int) *RequestBuffer {
+	if limit <= 0 {
+		limit = DefaultRequestBufferSize
+	}
+	return &RequestBuffer{limit: limit}
+}
+
+type ctxBufferKey struct{}
+
+// ContextWithBuffer returns a copy of ctx that routes *Context logging through b.
+func ContextWithBuffer(ctx context.Context, b *RequestBuffer) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, ctxBufferKey{}, b)
+}
+
+// BufferFromContext returns the RequestBuffer attached to ctx, or nil.
+func BufferFromContext(ctx context.Context) *RequestBuffer {
+	if ctx == nil {
+		return nil
+	}
+	b, _ := ctx.Value(ctxBufferKey{}).(*RequestBuffer)
+	return b
+}
+
+// Flush writes all buffered entries and trips the buffer so later entries
+// are written immediately. Thread-safe for concurrent use.
+func (b *RequestBuffer) Flush() {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	b.flushLocked()
+}
+
+// Discard drops all buffered entries. Later entries for the request are
+// dropped too unless the buffer was already flushed.
+// Thread-safe for concurrent use.
+func (b *RequestBuffer) Discard() {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	b.entries = nil
+	b.dropped = 0
+	b.done = true
+}
+
+// Len returns the number of entries currently held.
+func (b *RequestBuffer) Len() int {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	return len(b.entries)
+}
+
+// hold buffers e and reports true, or reports false if e should be written
+// immediately because the buffer has been flushed. Callers must hold logMutex.
+func (b *RequestBuffer) hold(e *Entry) bool {
+	if b.tripped {
+		return false
+	}
+	if b.done {
+		return true
+	}
+	if len(b.entries) >= b.limit {
+		b.entries = b.entries[1:]
+		b.dropped++
+	}
+	b.entries = append(b.entries, e)
+	return true
+}
+
+// flushLocked writes buffered entries. Callers must hold logMutex.
+func (b *RequestBuffer) flushLocked() {
+	if b.tripped {
+		return
+	}
+	b.tripped = true
+	if b.dropped > 0 {
+		writeEntry(Info, &Entry{ctx: context.Background(), Time: b.entries[0].Time, Level: InfoLevel,
+			Caller: b.entries[0].Caller, Message: "request buffer overflow", Fields: []any{"dropped", b.dropped}})
+	}
+	for _, e := range b.entries {
+		writeEntry(loggerFor(e.Level), e)
+	}
+	b.entries = nil
+	b.dropped = 0
+}
+
+// loggerFor returns the current output logger for level.
+func loggerFor(level Level) *log.Logger {
+	switch level {
+	case DebugLevel:
+		return Debug
+	case InfoLevel:
+		return Info
+	case WarnLevel:
+		return Warning
+	case ErrorLevel:
+		return Error
+	}
+	return Fatal
+}
@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Limits on the log files ExportBundle includes.
+const (
+	// BundleFileAge is how far back LevelFileSink files and hourly
+	// partitions are included.
+	BundleFileAge = 48 * time.Hour
+	// BundleFileBytes is how much of the end of each file is included.
+	BundleFileBytes = 8 << 20
+)
+
+// ExportBundle writes a zip archive to path that a user can attach to a bug
+// report. It holds:
+//
+//	entries.log  the entries of every RingSink added with AddSink, a route,
+//	             or Logger.AddSink, oldest first
+//	config.json  EffectiveConfig
+//	build.txt    the Go version, platform, and build info of the binary
+//	logs/        the end of the Config.FilePath file, and the LevelFileSink
+//	             files and NewHourlyFileSink partitions written within
+//	             BundleFileAge
+//
+// Entries logged before the call are delivered to the sinks first. A
+// partially written archive is removed when ExportBundle fails.
+//
+//	ring := logger.NewRingSink(0)
+//	logger.AddSink(ring)
+//	...
+//	if err := logger.ExportBundle("bug-report.zip"); err != nil {
+//	    logger.Errorf("bug report: %v", err)
+//	}
+func ExportBundle(path string) (err error) {
+	SyncSinks()
+	logMutex.Lock()
+	rc := resolveConfig(appliedConfig, appliedFileErr)
+	var entries []*Entry
+	var files []string
+	if rc.File != "" {
+		files = append(files, rc.File)
+	}
+	since := time.Now().Add(-BundleFileAge)
+	for sink := range workers {
+		switch s := sink.(type) {
+		case *RingSink:
+			entries = append(entries, s.Entries()...)
+		case *LevelFileSink:
+			files = append(files, s.recentFiles(since)...)
+		case *FileSink:
+			files = append(files, s.recentFiles(since)...)
+		}
+	}
+	logMutex.Unlock()
+	// several rings come in no particular order
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	sort.Strings(files)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+	zw := zip.NewWriter(f)
+
+	var text strings.Builder
+	enc := TextEncoder{TimeFormat: DefaultTimeFormat}
+	for _, e := range entries {
+		b, _ := enc.Encode(e)
+		text.Write(b)
+		text.WriteByte('\n')
+	}
+	config, err := json.MarshalIndent(rc, "", "  ")
+	if err != nil {
+		return err
+	}
+	for _, part := range []struct{ name, data string }{
+		{"entries.log", text.String()},
+		{"config.json", string(config) + "\n"},
+		{"build.txt", buildReport()},
+	} {
+		w, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, part.data); err != nil {
+			return err
+		}
+	}
+	seen := map[string]bool{}
+	for _, file := range files {
+		name := "logs/" + filepath.Base(file)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if err := addFileTail(zw, name, file); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// buildReport describes the running binary for ExportBundle.
+func buildReport() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "go: %s\nplatform: %s/%s\npid: %d\n", runtime.Version(), runtime.GOOS, runtime.GOARCH, os.Getpid())
+	if version := buildVersion(); version != "" {
+		fmt.Fprintf(&b, "version: %s\n", version)
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		b.WriteString("\n")
+		b.WriteString(info.String())
+	}
+	return b.String()
+}
+
+// addFileTail adds the last BundleFileBytes of the file at path to zw as
+// name. Files that no longer exist are skipped.
+func addFileTail(zw *zip.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if size := info.Size(); size > BundleFileBytes {
+		if _, err := f.Seek(size-BundleFileBytes, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: info.ModTime()})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// recentFiles returns the files of s for days ending after since.
+func (s *LevelFileSink) recentFiles(since time.Time) []string {
+	var out []string
+	for _, name := range levelNames {
+		prefix := s.cfg.Name + "-" + strings.ToLower(name) + "-"
+		paths, _ := filepath.Glob(filepath.Join(s.cfg.Dir, prefix+"*.log"))
+		for _, path := range paths {
+			day, err := time.ParseInLocation(levelFileDay, strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix), ".log"), time.Local)
+			if err == nil && day.AddDate(0, 0, 1).After(since) {
+				out = append(out, path)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// recentFiles returns the hourly partitions of s for hours ending after
+// since. A FileSink writing a single file has none.
+func (s *FileSink) recentFiles(since time.Time) []string {
+	if s.path == "" {
+		return nil
+	}
+	ext := filepath.Ext(s.path)
+	prefix := strings.TrimSuffix(filepath.Base(s.path), ext) + "-"
+	paths, _ := filepath.Glob(strings.TrimSuffix(s.path, ext) + "-*" + ext)
+	var out []string
+	for _, path := range paths {
+		hour, err := time.Parse("2006010215", strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix), ext))
+		if err == nil && hour.Add(time.Hour).After(since) {
+			out = append(out, path)
+		}
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// DocumentEvent sets the explanation WriteJournalCatalog writes for event
+// id, e.g. what it means and what to do about it. An unregistered id is
+// registered at INFO with the id as its message, as Event logs it.
+func DocumentEvent(id, doc string) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	t, ok := events[id]
+	if !ok {
+		t = EventTemplate{Level: InfoLevel, Message: id}
+	}
+	t.Doc = doc
+	events[id] = t
+}
+
+// EventMessageID returns the journald MESSAGE_ID of event id: 32 hex digits
+// derived from the id, so it stays the same across releases and hosts as
+// long as the id does.
+func EventMessageID(id string) string {
+	sum := sha256.Sum256([]byte("go_logger event " + id))
+	return hex.EncodeToString(sum[:16])
+}
+
+// WriteJournalCatalog writes a journald message catalog for the registered
+// events to w: one entry per event, keyed by EventMessageID, with the
+// template as Subject and the DocumentEvent text as body, plus a
+// translated entry for each RegisterCatalog locale that has the event.
+// {key} placeholders become @KEY@, which journalctl fills from the fields
+// JournalEncoder sends. Install the output and rebuild the catalog
+// database:
+//
+//	f, _ := os.Create("/usr/lib/systemd/catalog/myapp.catalog")
+//	logger.WriteJournalCatalog(f)
+//	f.Close()
+//	// then run: journalctl --update-catalog
+//
+// `journalctl -x` then shows the explanation below matching entries.
+func WriteJournalCatalog(w io.Writer) error {
+	eventsMu.RLock()
+	templates := maps.Clone(events)
+	eventsMu.RUnlock()
+	catalogsMu.RLock()
+	locales := slices.Sorted(maps.Keys(catalogs))
+	catalogsMu.RUnlock()
+
+	var b strings.Builder
+	for _, id := range slices.Sorted(maps.Keys(templates)) {
+		t := templates[id]
+		writeCatalogEntry(&b, id, "", t.Message, t.Doc)
+		for _, locale := range locales {
+			catalogsMu.RLock()
+			msg, ok := catalogs[locale][id]
+			catalogsMu.RUnlock()
+			if ok {
+				writeCatalogEntry(&b, id, locale, msg, t.Doc)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCatalogEntry appends one catalog entry for event id.
+func writeCatalogEntry(b *strings.Builder, id, locale, subject, doc string) {
+	b.WriteString("-- " + EventMessageID(id))
+	if locale != "" {
+		b.WriteString(" " + locale)
+	}
+	fmt.Fprintf(b, "\nSubject: %s\nDefined-By: %s\n\n", catalogPlaceholders(subject), Identifier())
+	if doc = strings.TrimSpace(doc); doc != "" {
+		b.WriteString(catalogPlaceholders(doc) + "\n\n")
+	}
+}
+
+// catalogPlaceholders turns {key} placeholders into journald's @KEY@.
+func catalogPlaceholders(s string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(s, '{')
+		end := strings.IndexByte(s[max(start, 0):], '}') + max(start, 0)
+		if start < 0 || end < start {
+			break
+		}
+		b.WriteString(s[:start] + "@" + journalFieldName(s[start+1:end]) + "@")
+		s = s[end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
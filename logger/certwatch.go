@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertExpiryCritical is how close to expiry a certificate must be for
+// WatchCertExpiry to log it at ERROR rather than WARN.
+const CertExpiryCritical = 7 * 24 * time.Hour
+
+// certWatchCaller is shown on entries from WatchCertExpiry.
+const certWatchCaller = "logger.WatchCertExpiry"
+
+// WatchCertExpiry checks the PEM certificate files at paths now and every
+// interval, and returns a function that stops the checks and waits for one
+// in progress. Certificates expiring within CertExpiryWarning are logged at
+// WARN, those within CertExpiryCritical or already expired at ERROR, as are
+// files that cannot be read or hold no certificate. The files are re-read on
+// each check, so renewals are picked up:
+//
+//	stop := logger.WatchCertExpiry([]string{"/etc/app/tls.crt", "/etc/app/ca.pem"}, 12*time.Hour)
+//	defer stop()
+//	// [WARN] [logger.WatchCertExpiry] certificate expires soon path=/etc/app/tls.crt subject=CN=app.example.com expires=2026-05-01T00:00:00Z days_left=21
+func WatchCertExpiry(paths []string, interval time.Duration) (stop func()) {
+	return runWatchdog(interval, func() {
+		for _, path := range paths {
+			checkCertFile(path, time.Now())
+		}
+	})
+}
+
+// runWatchdog calls check now and every interval from its own goroutine. The
+// returned function stops it, waiting for a check in progress.
+func runWatchdog(interval time.Duration, check func()) (stop func()) {
+	done, exited := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			check()
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// checkCertFile logs the certificates in path that are close to expiry.
+func checkCertFile(path string, now time.Time) {
+	certs, err := readCertFile(path)
+	if err != nil {
+		watchdogLog(ErrorLevel, certWatchCaller, "certificate check failed", "path", path, "error", err)
+		return
+	}
+	for _, cert := range certs {
+		left := cert.NotAfter.Sub(now)
+		level, msg := WarnLevel, "certificate expires soon"
+		switch {
+		case left > CertExpiryWarning:
+			continue
+		case left <= 0:
+			level, msg = ErrorLevel, "certificate expired"
+		case left <= CertExpiryCritical:
+			level = ErrorLevel
+		}
+		watchdogLog(level, certWatchCaller, msg, "path", path,
+			"subject", cert.Subject.String(),
+			"expires", cert.NotAfter.UTC().Format(time.RFC3339),
+			"days_left", int(left.Hours()/24))
+	}
+}
+
+// readCertFile parses every CERTIFICATE block of a PEM file.
+func readCertFile(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+	return certs, nil
+}
+
+// watchdogLog writes an entry from a watchdog goroutine, attributed to
+// caller.
+func watchdogLog(level Level, caller, msg string, keyvals ...any) {
+	if !isLevelEnabled(level) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(level), level, caller, msg, keyvals)
+}
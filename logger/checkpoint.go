@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultCheckpointInterval is the minimum time between two progress entries
+// of one checkpoint.
+const DefaultCheckpointInterval = 5 * time.Second
+
+var (
+	checkpointMu       sync.Mutex
+	checkpoints        = map[string]*checkpointState{}
+	checkpointInterval = DefaultCheckpointInterval
+)
+
+// checkpointState tracks one named job between Checkpoint calls.
+type checkpointState struct {
+	start     time.Time
+	startDone int
+	logged    time.Time
+}
+
+// Checkpoint reports progress of the batch job name, logging at INFO at most
+// once per DefaultCheckpointInterval and always when done reaches total:
+//
+//	for i, row := range rows {
+//	    load(row)
+//	    logger.Checkpoint("import-users", i+1, len(rows), "file", path)
+//	}
+//	// [INFO] ... import-users progress checkpoint=import-users done=1200 total=5000 percent=24.0% rate=240.0/s eta=16s file=users.csv
+//	// [INFO] ... import-users complete checkpoint=import-users done=5000 total=5000 elapsed=20.8s rate=240.4/s file=users.csv
+//
+// A total of 0 or less means it is unknown; entries then omit percent and
+// eta and no completion entry is written. Checkpoint is safe for concurrent
+// use, so workers can report a shared counter directly.
+func Checkpoint(name string, done, total int, keyvals ...any) {
+	now := time.Now()
+	checkpointMu.Lock()
+	st, ok := checkpoints[name]
+	if !ok {
+		st = &checkpointState{start: now, startDone: done}
+		checkpoints[name] = st
+	}
+	final := total > 0 && done >= total
+	if final {
+		delete(checkpoints, name)
+	} else if !st.logged.IsZero() && now.Sub(st.logged) < checkpointInterval {
+		checkpointMu.Unlock()
+		return
+	}
+	st.logged = now
+	checkpointMu.Unlock()
+
+	if !isLevelEnabled(InfoLevel) {
+		return
+	}
+	elapsed := now.Sub(st.start)
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(done-st.startDone) / elapsed.Seconds()
+	}
+	fields := []any{"checkpoint", name, "done", done}
+	msg := name + " progress"
+	switch {
+	case final:
+		msg = name + " complete"
+		fields = append(fields, "total", total, "elapsed", elapsed.Round(time.Millisecond))
+	case total > 0:
+		fields = append(fields, "total", total, "percent", fmt.Sprintf("%.1f%%", float64(done)/float64(total)*100))
+	}
+	fields = append(fields, "rate", fmt.Sprintf("%.1f/s", rate))
+	if !final && total > 0 && rate > 0 {
+		fields = append(fields, "eta", time.Duration(float64(total-done)/rate*float64(time.Second)).Round(time.Second))
+	}
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(InfoLevel), InfoLevel, getCallerInfo(2), msg, append(fields, keyvals...))
+}
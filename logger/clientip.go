@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+// parseTrustedProxies parses CIDRs and single addresses, reporting invalid
+// ones on stderr.
+func parseTrustedProxies(proxies []string) []netip.Prefix {
+	var out []netip.Prefix
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if prefix, err := netip.ParsePrefix(p); err == nil {
+			out = append(out, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(p); err == nil {
+			out = append(out, netip.PrefixFrom(addr, addr.BitLen()))
+		} else {
+			fmt.Fprintf(os.Stderr, "logger: ignoring invalid trusted proxy %q\n", p)
+		}
+	}
+	return out
+}
+
+// clientIP returns the address of the client that sent r, believing the
+// forwarding headers only when they were set by trusted proxies.
+func clientIP(r *http.Request, trusted []netip.Prefix) string {
+	remote := parseIP(r.RemoteAddr)
+	if !remote.IsValid() {
+		return r.RemoteAddr
+	}
+	if !isTrusted(remote, trusted) {
+		return remote.String()
+	}
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := parseIP(strings.TrimSpace(hops[i]))
+			if !hop.IsValid() {
+				break
+			}
+			remote = hop
+			if !isTrusted(hop, trusted) {
+				break
+			}
+		}
+		return remote.String()
+	}
+	if real := parseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); real.IsValid() {
+		return real.String()
+	}
+	return remote.String()
+}
+
+// parseIP parses an address with or without a port.
+func parseIP(s string) netip.Addr {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
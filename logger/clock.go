@@ -0,0 +1,43 @@
+package logger
+
+import "time"
+
+// MonotonicKey is the field name used by Config.MonotonicField.
+const MonotonicKey = "mono"
+
+var (
+	// clockJumpWarning and monotonicField are Config.ClockJumpWarning and
+	// Config.MonotonicField
+	clockJumpWarning time.Duration
+	monotonicField   bool
+
+	// lastWall and lastMono are the wall clock (without monotonic reading)
+	// and the monotonic time since processStart of the previous entry
+	lastWall time.Time
+	lastMono time.Duration
+)
+
+// checkClockJump warns when the wall clock moved by more than
+// clockJumpWarning relative to the monotonic clock since the previous
+// entry, as it does after a suspend/resume or an NTP step. The WARN entry
+// is written before the entry logged at now. Callers must hold logMutex.
+func checkClockJump(now time.Time) {
+	if clockJumpWarning <= 0 {
+		return
+	}
+	wall, mono := now.Round(0), now.Sub(processStart)
+	prevWall, prevMono := lastWall, lastMono
+	lastWall, lastMono = wall, mono
+	if prevWall.IsZero() || !enabledLevels()[WarnLevel] {
+		return
+	}
+	jump := wall.Sub(prevWall) - (mono - prevMono)
+	if jump.Abs() < clockJumpWarning {
+		return
+	}
+	fields := []any{"delta", jump.Round(time.Millisecond), "previous", prevWall.Format(time.RFC3339Nano)}
+	if monotonicField {
+		fields = append(fields, MonotonicKey, mono)
+	}
+	writeEntry(loggerFor(WarnLevel), &Entry{Time: now, Level: WarnLevel, Caller: "logger.clock", Message: "clock jump detected", Fields: fields})
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fields added by cloud metadata enrichment.
+const (
+	CloudProviderKey     = "cloud_provider"
+	CloudInstanceIDKey   = "cloud_instance_id"
+	CloudZoneKey         = "cloud_zone"
+	CloudInstanceTypeKey = "cloud_instance_type"
+)
+
+// CloudMetadataTimeout bounds the metadata queries made by CloudFields.
+const CloudMetadataTimeout = 500 * time.Millisecond
+
+var (
+	// metadata service base URLs, replaced in tests
+	ec2MetadataURL = "http://169.254.169.254/latest"
+	gceMetadataURL = "http://metadata.google.internal/computeMetadata/v1"
+
+	cloudOnce   sync.Once
+	cloudFields []any
+)
+
+// CloudFields returns the provider, instance ID, zone, and instance type of
+// the EC2 or GCE instance the process runs on, or nil elsewhere. Both
+// metadata services are queried concurrently on the first call, within
+// CloudMetadataTimeout; the result is cached for the life of the process.
+// InitWithConfig adds these fields to every entry when Config.CloudMetadata
+// is set.
+func CloudFields() []any {
+	cloudOnce.Do(func() {
+		cloudFields = fetchCloudFields(CloudMetadataTimeout)
+	})
+	return cloudFields
+}
+
+// fetchCloudFields queries the EC2 and GCE metadata services and returns
+// the fields of the first that answers.
+func fetchCloudFields(timeout time.Duration) []any {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	client := &http.Client{Transport: &http.Transport{Proxy: nil}}
+
+	results := make(chan []any, 2)
+	go func() { results <- ec2Fields(ctx, client) }()
+	go func() { results <- gceFields(ctx, client) }()
+	for range 2 {
+		if fields := <-results; fields != nil {
+			return fields
+		}
+	}
+	return nil
+}
+
+// ec2Fields queries the EC2 instance metadata service with an IMDSv2 token.
+func ec2Fields(ctx context.Context, client *http.Client) []any {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPut, ec2MetadataURL+"/api/token", nil)
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	token, ok := metadataGet(client, req)
+	if !ok {
+		return nil
+	}
+	get := func(p string) string {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ec2MetadataURL+"/meta-data/"+p, nil)
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+		v, _ := metadataGet(client, req)
+		return v
+	}
+	id := get("instance-id")
+	if id == "" {
+		return nil
+	}
+	return cloudFieldList("aws", id, get("placement/availability-zone"), get("instance-type"))
+}
+
+// gceFields queries the GCE metadata server. Zone and machine type are
+// returned as resource paths, of which the last element is kept.
+func gceFields(ctx context.Context, client *http.Client) []any {
+	get := func(p string) string {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, gceMetadataURL+"/instance/"+p, nil)
+		req.Header.Set("Metadata-Flavor", "Google")
+		v, _ := metadataGet(client, req)
+		return v
+	}
+	id := get("id")
+	if id == "" {
+		return nil
+	}
+	return cloudFieldList("gcp", id, path.Base(get("zone")), path.Base(get("machine-type")))
+}
+
+func cloudFieldList(provider, id, zone, instanceType string) []any {
+	fields := []any{CloudProviderKey, provider, CloudInstanceIDKey, id}
+	if zone != "" && zone != "." {
+		fields = append(fields, CloudZoneKey, zone)
+	}
+	if instanceType != "" && instanceType != "." {
+		fields = append(fields, CloudInstanceTypeKey, instanceType)
+	}
+	return fields
+}
+
+// metadataGet performs req and returns the trimmed body of a 200 response.
+func metadataGet(client *http.Client, req *http.Request) (string, bool) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", false
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return "", false
+	}
+	return strings.TrimSpace(string(body)), true
+}
@@ -0,0 +1,94 @@
+package logger
+
+import "sync"
+
+// Field names written for error codes.
+const (
+	ErrorCodeKey = "error_code"
+	CategoryKey  = "category"
+)
+
+// Placeholder values used in strict mode.
+const (
+	uncodedErrorCode      = "UNCODED"
+	uncategorizedCategory = "uncategorized"
+	unregisteredCategory  = "unregistered"
+)
+
+// ErrorCode is a standardized error code with its triage category.
+// Used as a field value it expands to error_code and category fields:
+//
+//	logger.RegisterCode("DB_TIMEOUT", "database")
+//	logger.ErrorKV("query timed out", "code", logger.Code("DB_TIMEOUT"))
+//	// ... query timed out error_code=DB_TIMEOUT category=database
+//
+// The key paired with an ErrorCode is ignored. Codes can also be attached
+// to errors with WithFieldsErr(err, "code", logger.Code("DB_TIMEOUT")).
+type ErrorCode struct {
+	Name     string
+	Category string
+}
+
+func (c ErrorCode) String() string {
+	return c.Name
+}
+
+var (
+	codesMu sync.RWMutex
+	codes   = map[string]string{}
+
+	// strictCodes requires an error code on ERROR and FATAL entries
+	strictCodes bool
+)
+
+// RegisterCode adds name to the error code registry under category.
+// Registering an existing name replaces its category.
+func RegisterCode(name, category string) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	codes[name] = category
+}
+
+// Code returns the registered ErrorCode for name. Unregistered codes have an
+// empty category, reported as "unregistered" in strict mode.
+func Code(name string) ErrorCode {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+	return ErrorCode{Name: name, Category: codes[name]}
+}
+
+// expandCodes replaces ErrorCode values with error_code/category pairs and,
+// in strict mode, marks ERROR and FATAL entries that carry no code.
+func expandCodes(level Level, keyvals []any) []any {
+	found := false
+	for i := 1; i < len(keyvals); i += 2 {
+		if _, ok := keyvals[i].(ErrorCode); ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		if strictCodes && level >= ErrorLevel && !hasKey(keyvals, ErrorCodeKey) {
+			return appendMissing(keyvals, []any{ErrorCodeKey, uncodedErrorCode, CategoryKey, uncategorizedCategory})
+		}
+		return keyvals
+	}
+
+	expanded := make([]any, 0, len(keyvals)+2)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		code, ok := keyvals[i+1].(ErrorCode)
+		if !ok {
+			expanded = append(expanded, keyvals[i], keyvals[i+1])
+			continue
+		}
+		category := code.Category
+		if category == "" && strictCodes {
+			category = unregisteredCategory
+		}
+		expanded = append(expanded, ErrorCodeKey, code.Name)
+		if category != "" {
+			expanded = append(expanded, CategoryKey, category)
+		}
+	}
+	return expanded
+}
@@ -0,0 +1,196 @@
+package logger
+
+import (
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ColorsEnv names the environment variable selecting the color profile when
+// Config.Colors is empty, e.g. LOGGER_COLORS=light.
+const ColorsEnv = "LOGGER_COLORS"
+
+// ColorProfile selects the level colors of the development console.
+type ColorProfile string
+
+const (
+	// ColorsDark is the default palette for dark terminal backgrounds.
+	ColorsDark ColorProfile = "dark"
+	// ColorsLight uses darker shades readable on light backgrounds.
+	ColorsLight ColorProfile = "light"
+	// ColorsHighContrast uses bold text and shows WARN and above in
+	// reverse video.
+	ColorsHighContrast ColorProfile = "high-contrast"
+	// ColorsColorblind uses the Okabe-Ito palette, which stays
+	// distinguishable with red-green color blindness.
+	ColorsColorblind ColorProfile = "colorblind"
+)
+
+// colorProfiles holds the ANSI sequences of each profile.
+var colorProfiles = map[ColorProfile]map[Level]string{
+	ColorsDark: {
+		DebugLevel: "\033[36m",
+		InfoLevel:  "\033[32m",
+		WarnLevel:  "\033[33m",
+		ErrorLevel: "\033[31m",
+		FatalLevel: "\033[35m",
+	},
+	ColorsLight: {
+		DebugLevel: "\033[34m",
+		InfoLevel:  "\033[38;5;28m",
+		WarnLevel:  "\033[38;5;130m",
+		ErrorLevel: "\033[38;5;160m",
+		FatalLevel: "\033[38;5;90m",
+	},
+	ColorsHighContrast: {
+		DebugLevel: "\033[1;36m",
+		InfoLevel:  "\033[1;32m",
+		WarnLevel:  "\033[1;7;33m",
+		ErrorLevel: "\033[1;7;31m",
+		FatalLevel: "\033[1;7;35m",
+	},
+	ColorsColorblind: {
+		DebugLevel: "\033[38;5;74m",
+		InfoLevel:  "\033[38;5;32m",
+		WarnLevel:  "\033[38;5;214m",
+		ErrorLevel: "\033[38;5;166m",
+		FatalLevel: "\033[38;5;175m",
+	},
+}
+
+// ColorDepth is the number of colors the console supports.
+type ColorDepth int
+
+const (
+	// ColorDepthAuto detects the depth from NO_COLOR, COLORTERM, and TERM.
+	ColorDepthAuto ColorDepth = iota
+	// ColorDepthNone disables colors.
+	ColorDepthNone
+	// ColorDepth8 limits colors to the basic SGR 30-37 set.
+	ColorDepth8
+	// ColorDepth256 allows the xterm 256-color palette.
+	ColorDepth256
+	// ColorDepthTrue allows 24-bit colors.
+	ColorDepthTrue
+)
+
+// detectColorDepth guesses the console's color support from the
+// environment: NO_COLOR or TERM=dumb disable colors, COLORTERM=truecolor
+// or 24bit and Windows Terminal allow 24-bit colors, and a TERM ending in
+// 256color allows 256.
+func detectColorDepth() ColorDepth {
+	term := os.Getenv("TERM")
+	switch colorterm := os.Getenv("COLORTERM"); {
+	case os.Getenv("NO_COLOR") != "" || term == "dumb":
+		return ColorDepthNone
+	case colorterm == "truecolor" || colorterm == "24bit" || os.Getenv("WT_SESSION") != "":
+		return ColorDepthTrue
+	case strings.Contains(term, "256color"):
+		return ColorDepth256
+	}
+	return ColorDepth8
+}
+
+// resolvePalette returns the level colors for cfg: its profile, or
+// ColorsEnv, or ColorsDark, with Palette entries replacing single levels,
+// reduced to what depth supports.
+func resolvePalette(cfg Config, depth ColorDepth) map[Level]string {
+	profile := cfg.Colors
+	if profile == "" {
+		profile = ColorProfile(os.Getenv(ColorsEnv))
+	}
+	base, ok := colorProfiles[profile]
+	if !ok {
+		base = colorProfiles[ColorsDark]
+	}
+	palette := maps.Clone(base)
+	maps.Copy(palette, cfg.Palette)
+	for level, seq := range palette {
+		palette[level] = downgradeSGR(seq, depth)
+	}
+	return palette
+}
+
+// downgradeSGR rewrites the 256-color (38;5;n) and 24-bit (38;2;r;g;b)
+// foreground and background colors of an SGR sequence such as
+// "\033[1;38;5;130m" to the nearest colors depth supports. Other sequences
+// are returned unchanged.
+func downgradeSGR(seq string, depth ColorDepth) string {
+	params, ok := strings.CutPrefix(seq, "\033[")
+	if params, ok = strings.CutSuffix(params, "m"); !ok || depth == ColorDepthTrue {
+		return seq
+	}
+	in := strings.Split(params, ";")
+	out := make([]string, 0, len(in))
+	num := func(i int) int {
+		if i >= len(in) {
+			return 0
+		}
+		n, _ := strconv.Atoi(in[i])
+		return n
+	}
+	for i := 0; i < len(in); i++ {
+		if (in[i] != "38" && in[i] != "48") || i+1 >= len(in) {
+			out = append(out, in[i])
+			continue
+		}
+		base := num(i) - 8 // 30 or 40
+		var r, g, b, index int
+		switch in[i+1] {
+		case "5":
+			index = num(i + 2)
+			i += 2
+		case "2":
+			r, g, b = num(i+2), num(i+3), num(i+4)
+			index = 16 + 36*cubeLevel(r) + 6*cubeLevel(g) + cubeLevel(b)
+			i += 4
+		default:
+			out = append(out, in[i])
+			continue
+		}
+		if depth >= ColorDepth256 {
+			out = append(out, strconv.Itoa(base+8), "5", strconv.Itoa(index))
+			continue
+		}
+		out = append(out, strconv.Itoa(base+basicColor(index)))
+	}
+	return "\033[" + strings.Join(out, ";") + "m"
+}
+
+// cubeLevel returns the nearest of the six levels (0, 95, 135, 175, 215,
+// 255) of the xterm color cube for an 8-bit channel value.
+func cubeLevel(v int) int {
+	switch {
+	case v < 48:
+		return 0
+	case v < 115:
+		return 1
+	}
+	return min((v-35)/40, 5)
+}
+
+// basicColor maps an xterm 256-color index to the nearest of the eight
+// basic colors (0 black ... 7 white).
+func basicColor(index int) int {
+	switch {
+	case index < 8:
+		return index
+	case index < 16:
+		return index - 8
+	case index >= 232:
+		if index >= 244 {
+			return 7
+		}
+		return 0
+	}
+	c := index - 16
+	r, g, b := c/36, c/6%6, c%6
+	bit := func(v, mask int) int {
+		if v >= 2 {
+			return mask
+		}
+		return 0
+	}
+	return bit(r, 1) | bit(g, 2) | bit(b, 4)
+}
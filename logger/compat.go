@@ -0,0 +1,227 @@
+package logger
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Fields is a set of fields for FieldLogger.WithFields, as in logrus.
+type Fields map[string]any
+
+// FieldLogger offers the logrus method set on top of this logger, for
+// codebases migrating from logrus one package at a time. A FieldLogger is
+// immutable: WithField and friends return a new one.
+//
+//	log := logger.WithFields(logger.Fields{"user": id, "order": orderID})
+//	log.Info("order placed")
+//	log.WithError(err).Warnf("retrying in %s", delay)
+//	// [WARN] [shop.Checkout:57] retrying in 2s order=1042 user=7 error=timeout
+type FieldLogger struct {
+	fields []any
+}
+
+// WithField returns a FieldLogger adding key to every entry.
+func WithField(key string, value any) *FieldLogger {
+	return (&FieldLogger{}).WithField(key, value)
+}
+
+// WithFields returns a FieldLogger adding fields to every entry, in key
+// order.
+func WithFields(fields Fields) *FieldLogger {
+	return (&FieldLogger{}).WithFields(fields)
+}
+
+// WithError returns a FieldLogger adding err as the error field.
+func WithError(err error) *FieldLogger {
+	return (&FieldLogger{}).WithError(err)
+}
+
+// WithField returns a copy of l that also adds key to every entry.
+func (l *FieldLogger) WithField(key string, value any) *FieldLogger {
+	return &FieldLogger{fields: append(slices.Clip(l.fields), key, value)}
+}
+
+// WithFields returns a copy of l that also adds fields, in key order.
+func (l *FieldLogger) WithFields(fields Fields) *FieldLogger {
+	out := &FieldLogger{fields: slices.Clip(l.fields)}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		out.fields = append(out.fields, k, fields[k])
+	}
+	return out
+}
+
+// WithError returns a copy of l that also adds err as the error field.
+func (l *FieldLogger) WithError(err error) *FieldLogger {
+	return l.WithField("error", err)
+}
+
+// Debug logs its arguments joined with fmt.Sprint at DEBUG.
+func (l *FieldLogger) Debug(args ...any) { shimLog(DebugLevel, fmt.Sprint(args...), l.fields) }
+
+// Info logs its arguments joined with fmt.Sprint at INFO.
+func (l *FieldLogger) Info(args ...any) { shimLog(InfoLevel, fmt.Sprint(args...), l.fields) }
+
+// Warn logs its arguments joined with fmt.Sprint at WARN.
+func (l *FieldLogger) Warn(args ...any) { shimLog(WarnLevel, fmt.Sprint(args...), l.fields) }
+
+// Warning is Warn.
+func (l *FieldLogger) Warning(args ...any) { shimLog(WarnLevel, fmt.Sprint(args...), l.fields) }
+
+// Error logs its arguments joined with fmt.Sprint at ERROR.
+func (l *FieldLogger) Error(args ...any) { shimLog(ErrorLevel, fmt.Sprint(args...), l.fields) }
+
+// Fatal logs its arguments joined with fmt.Sprint at FATAL and then calls
+// os.Exit(1).
+func (l *FieldLogger) Fatal(args ...any) {
+	shimLog(FatalLevel, fmt.Sprint(args...), l.fields)
+	exitFatal()
+}
+
+// Debugf logs a message formatted with fmt.Sprintf at DEBUG.
+func (l *FieldLogger) Debugf(format string, args ...any) {
+	shimLog(DebugLevel, fmt.Sprintf(format, args...), l.fields)
+}
+
+// Infof logs a message formatted with fmt.Sprintf at INFO.
+func (l *FieldLogger) Infof(format string, args ...any) {
+	shimLog(InfoLevel, fmt.Sprintf(format, args...), l.fields)
+}
+
+// Warnf logs a message formatted with fmt.Sprintf at WARN.
+func (l *FieldLogger) Warnf(format string, args ...any) {
+	shimLog(WarnLevel, fmt.Sprintf(format, args...), l.fields)
+}
+
+// Warningf is Warnf.
+func (l *FieldLogger) Warningf(format string, args ...any) {
+	shimLog(WarnLevel, fmt.Sprintf(format, args...), l.fields)
+}
+
+// Errorf logs a message formatted with fmt.Sprintf at ERROR.
+func (l *FieldLogger) Errorf(format string, args ...any) {
+	shimLog(ErrorLevel, fmt.Sprintf(format, args...), l.fields)
+}
+
+// Fatalf logs a message formatted with fmt.Sprintf at FATAL and then calls
+// os.Exit(1).
+func (l *FieldLogger) Fatalf(format string, args ...any) {
+	shimLog(FatalLevel, fmt.Sprintf(format, args...), l.fields)
+	exitFatal()
+}
+
+// SugaredLogger offers the method set of zap's SugaredLogger on top of this
+// logger, for codebases migrating from zap. A SugaredLogger is immutable:
+// With returns a new one.
+//
+//	sugar := logger.Sugar().With("component", "billing")
+//	sugar.Infow("charge created", "amount", 1299, "currency", "EUR")
+//	sugar.Errorf("charge %s failed: %v", id, err)
+type SugaredLogger struct {
+	fields []any
+}
+
+// Sugar returns a SugaredLogger without fields.
+func Sugar() *SugaredLogger {
+	return &SugaredLogger{}
+}
+
+// With returns a copy of s that also adds the key-value pairs to every
+// entry.
+func (s *SugaredLogger) With(keyvals ...any) *SugaredLogger {
+	return &SugaredLogger{fields: append(slices.Clip(s.fields), keyvals...)}
+}
+
+// Sync delivers queued entries to the sinks and the log file, like
+// SyncSinks. It always returns nil.
+func (s *SugaredLogger) Sync() error {
+	SyncSinks()
+	return nil
+}
+
+// Debugw logs msg with key-value pairs at DEBUG.
+func (s *SugaredLogger) Debugw(msg string, keyvals ...any) {
+	shimLog(DebugLevel, msg, append(slices.Clip(s.fields), keyvals...))
+}
+
+// Infow logs msg with key-value pairs at INFO.
+func (s *SugaredLogger) Infow(msg string, keyvals ...any) {
+	shimLog(InfoLevel, msg, append(slices.Clip(s.fields), keyvals...))
+}
+
+// Warnw logs msg with key-value pairs at WARN.
+func (s *SugaredLogger) Warnw(msg string, keyvals ...any) {
+	shimLog(WarnLevel, msg, append(slices.Clip(s.fields), keyvals...))
+}
+
+// Errorw logs msg with key-value pairs at ERROR.
+func (s *SugaredLogger) Errorw(msg string, keyvals ...any) {
+	shimLog(ErrorLevel, msg, append(slices.Clip(s.fields), keyvals...))
+}
+
+// Fatalw logs msg with key-value pairs at FATAL and then calls os.Exit(1).
+func (s *SugaredLogger) Fatalw(msg string, keyvals ...any) {
+	shimLog(FatalLevel, msg, append(slices.Clip(s.fields), keyvals...))
+	exitFatal()
+}
+
+// Debugf logs a message formatted with fmt.Sprintf at DEBUG.
+func (s *SugaredLogger) Debugf(format string, args ...any) {
+	shimLog(DebugLevel, fmt.Sprintf(format, args...), s.fields)
+}
+
+// Infof logs a message formatted with fmt.Sprintf at INFO.
+func (s *SugaredLogger) Infof(format string, args ...any) {
+	shimLog(InfoLevel, fmt.Sprintf(format, args...), s.fields)
+}
+
+// Warnf logs a message formatted with fmt.Sprintf at WARN.
+func (s *SugaredLogger) Warnf(format string, args ...any) {
+	shimLog(WarnLevel, fmt.Sprintf(format, args...), s.fields)
+}
+
+// Errorf logs a message formatted with fmt.Sprintf at ERROR.
+func (s *SugaredLogger) Errorf(format string, args ...any) {
+	shimLog(ErrorLevel, fmt.Sprintf(format, args...), s.fields)
+}
+
+// Fatalf logs a message formatted with fmt.Sprintf at FATAL and then calls
+// os.Exit(1).
+func (s *SugaredLogger) Fatalf(format string, args ...any) {
+	shimLog(FatalLevel, fmt.Sprintf(format, args...), s.fields)
+	exitFatal()
+}
+
+// Debug logs its arguments joined with fmt.Sprint at DEBUG.
+func (s *SugaredLogger) Debug(args ...any) { shimLog(DebugLevel, fmt.Sprint(args...), s.fields) }
+
+// Info logs its arguments joined with fmt.Sprint at INFO.
+func (s *SugaredLogger) Info(args ...any) { shimLog(InfoLevel, fmt.Sprint(args...), s.fields) }
+
+// Warn logs its arguments joined with fmt.Sprint at WARN.
+func (s *SugaredLogger) Warn(args ...any) { shimLog(WarnLevel, fmt.Sprint(args...), s.fields) }
+
+// Error logs its arguments joined with fmt.Sprint at ERROR.
+func (s *SugaredLogger) Error(args ...any) { shimLog(ErrorLevel, fmt.Sprint(args...), s.fields) }
+
+// Fatal logs its arguments joined with fmt.Sprint at FATAL and then calls
+// os.Exit(1).
+func (s *SugaredLogger) Fatal(args ...any) {
+	shimLog(FatalLevel, fmt.Sprint(args...), s.fields)
+	exitFatal()
+}
+
+// shimLog writes one entry for a FieldLogger or SugaredLogger method. The
+// caller depth skips shimLog and the method that called it.
+func shimLog(level Level, msg string, keyvals []any) {
+	if !isLevelEnabled(level) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(level), level, getCallerInfo(3), msg, keyvals)
+}
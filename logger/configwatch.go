@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+)
+
+// configWatchCaller is shown on entries from WatchConfig.
+const configWatchCaller = "logger.WatchConfig"
+
+// configPollInterval is how often WatchConfig checks the file for changes.
+var configPollInterval = 2 * time.Second
+
+// WatchConfig applies the JSON config file at path and re-applies it
+// whenever it changes, for appliances administered by editing files. The
+// file holds Config fields by name; fields it leaves out keep the values of
+// the configuration in effect when WatchConfig was called:
+//
+//	{"Mode": "production", "Verbose": true, "LoggerLevels": {"http.client": "DEBUG"}}
+//
+// A file that cannot be parsed, has unknown fields, or fails Validate is
+// rejected: WatchConfig returns the error, and later edits are logged at
+// ERROR and leave the running configuration alone. If a new FilePath
+// cannot be opened, the previous configuration stays in effect. Each applied
+// reload is logged at INFO with the changed fields. Sinks added with
+// AddSink are kept across reloads.
+//
+// The file is polled every two seconds; the returned function stops
+// watching and waits for a check in progress.
+func WatchConfig(path string) (stop func(), err error) {
+	if !globalDisabled.Load() {
+		ensureInit()
+	}
+	base := currentConfig()
+	data, mod, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := decodeConfig(base, data)
+	if err != nil {
+		return nil, err
+	}
+	InitWithConfig(cfg)
+
+	last, lastMod := data, mod
+	return runWatchdog(configPollInterval, func() {
+		info, err := os.Stat(path)
+		if err != nil {
+			if !os.IsNotExist(err) || lastMod != (configStamp{}) {
+				watchdogLog(ErrorLevel, configWatchCaller, "config reload failed", "path", path, "error", err)
+			}
+			lastMod = configStamp{}
+			return
+		}
+		if stampOf(info) == lastMod {
+			return
+		}
+		data, mod, err := readConfigFile(path)
+		if err != nil {
+			watchdogLog(ErrorLevel, configWatchCaller, "config reload failed", "path", path, "error", err)
+			return
+		}
+		lastMod = mod
+		if bytes.Equal(data, last) {
+			return
+		}
+		last = data
+		reloadConfig(path, base, data)
+	}), nil
+}
+
+// reloadConfig applies data over base, keeping the previous configuration
+// if the new log file cannot be opened.
+func reloadConfig(path string, base Config, data []byte) {
+	cfg, err := decodeConfig(base, data)
+	if err != nil {
+		watchdogLog(ErrorLevel, configWatchCaller, "config reload rejected", "path", path, "error", err)
+		return
+	}
+	prev := currentConfig()
+	if err := initWithConfig(cfg, cfg.FilePath != prev.FilePath); err != nil {
+		watchdogLog(ErrorLevel, configWatchCaller, "config reload rolled back", "path", path, "error", err)
+		return
+	}
+	changes := diffFields(prev, cfg)
+	fields := append([]any{"path", path, "changed", len(changes) / 2}, changes...)
+	watchdogLog(InfoLevel, configWatchCaller, "config reloaded", fields...)
+}
+
+// currentConfig returns the Config of the last InitWithConfig call.
+func currentConfig() Config {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	return appliedConfig
+}
+
+// decodeConfig decodes a config file over base and validates the result.
+func decodeConfig(base Config, data []byte) (Config, error) {
+	cfg := cloneConfig(base)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return base, fmt.Errorf("logger: parsing config: %w", err)
+	}
+	if err := Validate(cfg); err != nil {
+		return base, err
+	}
+	return cfg, nil
+}
+
+// cloneConfig copies the maps, slices, and mappings of cfg, which decoding
+// a file over it would otherwise modify in place.
+func cloneConfig(cfg Config) Config {
+	cfg.Palette = maps.Clone(cfg.Palette)
+	cfg.LoggerLevels = maps.Clone(cfg.LoggerLevels)
+	cfg.Fields = slices.Clone(cfg.Fields)
+	for _, m := range []**LevelMapping{&cfg.ConsoleLevels, &cfg.FileLevels} {
+		if *m != nil {
+			c := **m
+			c.Remap = maps.Clone(c.Remap)
+			*m = &c
+		}
+	}
+	return cfg
+}
+
+// configStamp identifies a version of the config file without reading it.
+type configStamp struct {
+	mod  time.Time
+	size int64
+}
+
+func stampOf(info os.FileInfo) configStamp {
+	return configStamp{info.ModTime(), info.Size()}
+}
+
+// readConfigFile reads path along with its stamp.
+func readConfigFile(path string) ([]byte, configStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, configStamp{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, configStamp{}, err
+	}
+	return data, stampOf(info), nil
+}
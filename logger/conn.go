@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// Field names written for long-lived connections.
+const (
+	ConnIDKey      = "conn_id"
+	ProtocolKey    = "protocol"
+	RemoteKey      = "remote"
+	CloseCodeKey   = "close_code"
+	BytesInKey     = "bytes_in"
+	BytesOutKey    = "bytes_out"
+	PingRTTKey     = "ping_rtt"
+	CloseReasonKey = "reason"
+)
+
+// ConnHandle follows a long-lived connection such as a WebSocket, SSE
+// stream, or gRPC stream, whose single request/response line from Api or
+// Middleware says little. Its entries share a conn_id:
+//
+//	c := logger.Conn("websocket", r.RemoteAddr, "user", user)
+//	defer c.Close(1000, "bye")
+//	...
+//	c.AddIn(len(msg))
+//	c.Ping(rtt)
+//	// [INFO] ... websocket connection closed conn_id=8c1e... protocol=websocket remote=10.0.0.7:51234
+//	//   close_code=1000 reason=bye duration=12m3s bytes_in=5120 bytes_out=88231 ping_rtt=23ms user=alice
+//
+// The counters are safe to update from the connection's reader and writer
+// goroutines.
+type ConnHandle struct {
+	protocol string
+	fields   []any
+	start    time.Time
+	in, out  atomic.Int64
+	rtt      atomic.Int64
+	closed   atomic.Bool
+}
+
+// Conn logs an INFO "<protocol> connection opened" entry and returns the
+// handle for the connection. keyvals are repeated on every entry.
+func Conn(protocol, remote string, keyvals ...any) *ConnHandle {
+	c := &ConnHandle{protocol: protocol, start: time.Now()}
+	c.fields = append([]any{ConnIDKey, newSpanID(), ProtocolKey, protocol, RemoteKey, remote}, keyvals...)
+	c.log(InfoLevel, getCallerInfo(2), protocol+" connection opened", nil)
+	return c
+}
+
+// ID returns the conn_id shared by the connection's entries.
+func (c *ConnHandle) ID() string {
+	return c.fields[1].(string)
+}
+
+// AddIn counts n bytes received.
+func (c *ConnHandle) AddIn(n int) {
+	c.in.Add(int64(n))
+}
+
+// AddOut counts n bytes sent.
+func (c *ConnHandle) AddOut(n int) {
+	c.out.Add(int64(n))
+}
+
+// Ping records a ping round trip and logs it at DEBUG. The last one is
+// reported when the connection closes.
+func (c *ConnHandle) Ping(rtt time.Duration) {
+	c.rtt.Store(int64(rtt))
+	c.log(DebugLevel, getCallerInfo(2), c.protocol+" ping", []any{PingRTTKey, rtt})
+}
+
+// Wrap returns conn with its reads and writes counted by c.
+func (c *ConnHandle) Wrap(conn net.Conn) net.Conn {
+	return &countingConn{Conn: conn, c: c}
+}
+
+// Close logs the end of the connection with its close code, reason,
+// duration, byte counts, and last ping RTT. The level follows the
+// WebSocket close code, as Api does for HTTP statuses: normal closures
+// (1000, 1001, 1005) are INFO, abnormal closures and server errors (1006,
+// 1011 and above) are ERROR, and other codes are WARN. Only the first
+// Close or CloseErr is logged.
+func (c *ConnHandle) Close(code int, reason string) {
+	if c.closed.Swap(true) {
+		return
+	}
+	c.log(closeCodeToLevel(code), getCallerInfo(2), c.protocol+" connection closed", c.summary(CloseCodeKey, code, CloseReasonKey, reason))
+}
+
+// CloseErr is Close for protocols without close codes: a nil err or io.EOF
+// is a normal closure at INFO, other errors are logged at ERROR.
+func (c *ConnHandle) CloseErr(err error) {
+	if c.closed.Swap(true) {
+		return
+	}
+	if err == nil || errors.Is(err, io.EOF) {
+		c.log(InfoLevel, getCallerInfo(2), c.protocol+" connection closed", c.summary())
+		return
+	}
+	c.log(ErrorLevel, getCallerInfo(2), c.protocol+" connection failed", c.summary("error", err))
+}
+
+// summary returns keyvals followed by the duration and counters.
+func (c *ConnHandle) summary(keyvals ...any) []any {
+	fields := append(keyvals, DurationKey, time.Since(c.start), BytesInKey, c.in.Load(), BytesOutKey, c.out.Load())
+	if rtt := c.rtt.Load(); rtt > 0 {
+		fields = append(fields, PingRTTKey, time.Duration(rtt))
+	}
+	return fields
+}
+
+func (c *ConnHandle) log(level Level, caller, msg string, keyvals []any) {
+	if !isLevelEnabled(level) {
+		return
+	}
+	fields := append(append([]any{}, c.fields[:6]...), keyvals...)
+	fields = append(fields, c.fields[6:]...)
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(level), level, caller, msg, fields)
+}
+
+// closeCodeToLevel maps WebSocket close codes (RFC 6455) to log levels.
+func closeCodeToLevel(code int) Level {
+	switch {
+	case code == 1000 || code == 1001 || code == 1005:
+		return InfoLevel
+	case code == 1006 || (code >= 1011 && code < 3000):
+		return ErrorLevel
+	}
+	return WarnLevel
+}
+
+// countingConn counts the bytes read and written on a connection.
+type countingConn struct {
+	net.Conn
+	c *ConnHandle
+}
+
+func (cc *countingConn) Read(p []byte) (int, error) {
+	n, err := cc.Conn.Read(p)
+	cc.c.AddIn(n)
+	return n, err
+}
+
+func (cc *countingConn) Write(p []byte) (int, error) {
+	n, err := cc.Conn.Write(p)
+	cc.c.AddOut(n)
+	return n, err
+}
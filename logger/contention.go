@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ContentionStats reports the logger's overhead as measured by the
+// loggercontention build tag. Durations are totals; divide by the matching
+// count for the average per entry.
+type ContentionStats struct {
+	// Enabled is false in builds without the loggercontention tag, where
+	// every other field is zero.
+	Enabled bool
+	// Locks is the number of times the logger lock was taken, and LockWait
+	// the time spent waiting for it while another goroutine held it.
+	Locks       int64
+	LockWait    time.Duration
+	MaxLockWait time.Duration
+	// Entries is the number of entries written, and WriteTime the time spent
+	// writing them to the console, the file, and sink queues under the lock.
+	Entries      int64
+	WriteTime    time.Duration
+	MaxWriteTime time.Duration
+	// SinkEntries is the number of entries delivered to sinks, and
+	// QueueLatency the time from logging each to its sink's WriteEntry call.
+	SinkEntries     int64
+	QueueLatency    time.Duration
+	MaxQueueLatency time.Duration
+}
+
+// contentionCounter accumulates one ContentionStats measurement.
+type contentionCounter struct {
+	count, total, max atomic.Int64
+}
+
+var lockWaits, entryWrites, queueLatencies contentionCounter
+
+func (c *contentionCounter) add(d time.Duration) {
+	c.count.Add(1)
+	c.total.Add(int64(d))
+	for {
+		old := c.max.Load()
+		if int64(d) <= old || c.max.CompareAndSwap(old, int64(d)) {
+			return
+		}
+	}
+}
+
+func (c *contentionCounter) read() (int64, time.Duration, time.Duration) {
+	return c.count.Load(), time.Duration(c.total.Load()), time.Duration(c.max.Load())
+}
+
+func (c *contentionCounter) reset() {
+	c.count.Store(0)
+	c.total.Store(0)
+	c.max.Store(0)
+}
+
+// ReadContentionStats returns the measurements since the program started or
+// the last ResetContentionStats. Build with the loggercontention tag to
+// enable them, and compare runs of the same workload before and after a
+// change such as Config.FileAsync:
+//
+//	go test -tags loggercontention -bench . ./...
+//
+//	s := logger.ReadContentionStats()
+//	fmt.Printf("lock wait %v/entry, write %v/entry\n",
+//	    s.LockWait/time.Duration(max(s.Locks, 1)), s.WriteTime/time.Duration(max(s.Entries, 1)))
+//
+// The measurements cost two clock reads per entry, so leave the tag off in
+// production builds.
+func ReadContentionStats() ContentionStats {
+	s := ContentionStats{Enabled: contentionEnabled}
+	s.Locks, s.LockWait, s.MaxLockWait = lockWaits.read()
+	s.Entries, s.WriteTime, s.MaxWriteTime = entryWrites.read()
+	s.SinkEntries, s.QueueLatency, s.MaxQueueLatency = queueLatencies.read()
+	return s
+}
+
+// ResetContentionStats zeroes the measurements.
+func ResetContentionStats() {
+	lockWaits.reset()
+	entryWrites.reset()
+	queueLatencies.reset()
+}
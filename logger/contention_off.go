@@ -0,0 +1,21 @@
+//go:build !loggercontention
+
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+const contentionEnabled = false
+
+// probedMutex is a plain mutex without the loggercontention tag.
+type probedMutex struct {
+	sync.Mutex
+}
+
+func probeWriteStart() time.Time { return time.Time{} }
+
+func probeWriteDone(time.Time) {}
+
+func probeQueue(*Entry) {}
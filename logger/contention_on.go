@@ -0,0 +1,35 @@
+//go:build loggercontention
+
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+const contentionEnabled = true
+
+// probedMutex measures how long Lock waits for another holder.
+type probedMutex struct {
+	sync.Mutex
+}
+
+func (m *probedMutex) Lock() {
+	if m.TryLock() {
+		lockWaits.add(0)
+		return
+	}
+	start := time.Now()
+	m.Mutex.Lock()
+	lockWaits.add(time.Since(start))
+}
+
+// probeWriteStart and probeWriteDone record the time writeEntry took.
+func probeWriteStart() time.Time { return time.Now() }
+
+func probeWriteDone(start time.Time) { entryWrites.add(time.Since(start)) }
+
+// probeQueue records how long e waited before reaching its sink.
+func probeQueue(e *Entry) {
+	queueLatencies.add(time.Since(e.Time))
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"context"
+)
+
+// ContextExtractor returns key-value pairs derived from a context, such as
+// trace or request IDs. Extractors run while the logger holds its mutex and
+// must not log themselves.
+type ContextExtractor func(ctx context.Context) []any
+
+// contextExtractors are consulted by the *Context logging functions.
+var contextExtractors []ContextExtractor
+
+type ctxFieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying key-value pairs that the
+// *Context logging functions add to every entry. Fields accumulate across calls.
+func ContextWithFields(ctx context.Context, keyvals ...any) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	existing, _ := ctx.Value(ctxFieldsKey{}).([]any)
+	merged := make([]any, 0, len(existing)+len(keyvals))
+	merged = append(merged, existing...)
+	merged = append(merged, keyvals...)
+	return context.WithValue(ctx, ctxFieldsKey{}, merged)
+}
+
+// AddContextExtractor registers fn to enrich entries logged with a context.
+//
+// Example:
+//
+//	logger.AddContextExtractor(func(ctx context.Context) []any {
+//	    if id, ok := ctx.Value(traceKey{}).(string); ok {
+//	        return []any{"trace_id", id}
+//	    }
+//	    return nil
+//	})
+func AddContextExtractor(fn ContextExtractor) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	contextExtractors = append(contextExtractors, fn)
+}
+
+// contextFields collects fields attached with ContextWithFields and
+// ContextWithBaggage, the span_id of the innermost span started with
+// SpanContext, and those returned by registered extractors. Callers must hold logMutex.
+func contextFields(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(ctxFieldsKey{}).([]any)
+	if bf := baggageFields(ctx); len(bf) > 0 {
+		fields = append(fields[:len(fields):len(fields)], bf...)
+	}
+	if sp, ok := ctx.Value(ctxSpanKey{}).(*SpanHandle); ok {
+		fields = append(fields[:len(fields):len(fields)], SpanIDKey, sp.id)
+	}
+	for _, fn := range contextExtractors {
+		fields = append(fields, fn(ctx)...)
+	}
+	return fields
+}
+
+// withContextFields appends context fields after the explicit key-value pairs.
+func withContextFields(ctx context.Context, keyvals []any) []any {
+	extra := contextFields(ctx)
+	if len(extra) == 0 {
+		return keyvals
+	}
+	merged := make([]any, 0, len(keyvals)+len(extra))
+	merged = append(merged, keyvals...)
+	return append(merged, extra...)
+}
+
+// --- Context logging methods (slog naming) ---
+
+// DebugContext logs a debug message with key-value pairs and fields from ctx.
+// If ctx carries a RequestBuffer, the entry is buffered instead.
+// The caller function name and line number are automatically included.
+// Thread-safe for concurrent use.
+func DebugContext(ctx context.Context, msg string, keyvals ...any) {
+	if !debugEnabled() && BufferFromContext(ctx) == nil {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(2)
+	outputContext(ctx, Debug, DebugLevel, caller, msg, withContextFields(ctx, keyvals))
+}
+
+// InfoContext logs an info message with key-value pairs and fields from ctx.
+// If ctx carries a RequestBuffer, the entry is buffered instead.
+// The caller function name and line number are automatically included.
+// Thread-safe for concurrent use.
+func InfoContext(ctx context.Context, msg string, keyvals ...any) {
+	if !isLevelEnabled(InfoLevel) && BufferFromContext(ctx) == nil {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(2)
+	outputContext(ctx, Info, InfoLevel, caller, msg, withContextFields(ctx, keyvals))
+}
+
+// WarnContext logs a warning message with key-value pairs and fields from ctx.
+// The caller function name and line number are automatically included.
+// Thread-safe for concurrent use.
+func WarnContext(ctx context.Context, msg string, keyvals ...any) {
+	if !isLevelEnabled(WarnLevel) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(2)
+	outputContext(ctx, Warning, WarnLevel, caller, msg, withContextFields(ctx, keyvals))
+}
+
+// ErrorContext logs an error message with key-value pairs and fields from ctx.
+// If ctx carries a RequestBuffer, its entries are flushed first.
+// The caller function name and line number are automatically included.
+// Thread-safe for concurrent use.
+func ErrorContext(ctx context.Context, msg string, keyvals ...any) {
+	if !isLevelEnabled(ErrorLevel) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(2)
+	outputContext(ctx, Error, ErrorLevel, caller, msg, withContextFields(ctx, keyvals))
+}
+
+// FatalContext logs a fatal message with key-value pairs and fields from ctx,
+// then calls os.Exit(1).
+// The caller function name and line number are automatically included.
+// Thread-safe for concurrent use.
+func FatalContext(ctx context.Context, msg string, keyvals ...any) {
+	if !isLevelEnabled(FatalLevel) {
+		exitFatal()
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(2)
+	outputContext(ctx, Fatal, FatalLevel, caller, msg, withContextFields(ctx, keyvals))
+	exitFatal()
+}
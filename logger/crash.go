@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Environment variables passed to the re-executed crash monitor process.
+const (
+	crashMonitorEnv = "GO_LOGGER_CRASH_MONITOR"
+	crashFileEnv    = "GO_LOGGER_CRASH_FILE"
+)
+
+// EnableCrashMonitor makes unrecovered panics and fatal runtime errors reach
+// the log file and sinks, not just stderr. It starts a copy of the program
+// as a monitor process and points debug.SetCrashOutput at it; if the
+// program crashes, the monitor logs the crash report as a FATAL entry
+// ("panic: ..." with a stack field) through the same outputs.
+//
+// The monitor runs main again up to this call, so call it right after Init
+// and AddSink, before logging anything or starting other work:
+//
+//	func main() {
+//	    logger.InitWithFile("production", false, "/var/log/app.log")
+//	    if err := logger.EnableCrashMonitor(); err != nil {
+//	        logger.Warnf("crash monitor disabled: %v", err)
+//	    }
+//	    ...
+//	}
+//
+// In the monitor process this call does not return.
+func EnableCrashMonitor() error {
+	return startCrashMonitor("")
+}
+
+// EnableCrashFile is like EnableCrashMonitor, but the full crash report
+// (every goroutine's stack for deadlocks and nil dereferences) is appended
+// to a dedicated file at path, and the monitor logs a final ERROR entry
+// referencing it instead of the FATAL entry with the stack:
+//
+//	[ERROR] [runtime] process crashed crash_file=/var/log/app.crash panic=panic: boom
+//
+// If the monitor cannot be started, crash reports are still written to path
+// and the error is returned.
+func EnableCrashFile(path string) error {
+	if os.Getenv(crashMonitorEnv) == "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := startCrashMonitor(path); err != nil {
+			if serr := debug.SetCrashOutput(f, debug.CrashOptions{}); serr != nil {
+				return serr
+			}
+			return fmt.Errorf("logger: crash monitor unavailable, writing crashes to %s only: %w", path, err)
+		}
+		return nil
+	}
+	return startCrashMonitor(path)
+}
+
+// startCrashMonitor runs the monitor loop when called in the monitor process,
+// or starts the monitor and redirects crash output to it.
+func startCrashMonitor(crashFile string) error {
+	if os.Getenv(crashMonitorEnv) != "" {
+		runCrashMonitor(os.Stdin, os.Getenv(crashFileEnv))
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), crashMonitorEnv+"=1", crashFileEnv+"="+crashFile)
+	cmd.Stdin = r
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	err = cmd.Start()
+	r.Close()
+	if err != nil {
+		return err
+	}
+	// SetCrashOutput keeps its own duplicate of w, so the monitor sees EOF
+	// only when this process exits.
+	return debug.SetCrashOutput(w, debug.CrashOptions{})
+}
+
+// runCrashMonitor waits for the parent to exit and logs its crash report,
+// if any, then exits.
+func runCrashMonitor(r io.Reader, crashFile string) {
+	report, _ := io.ReadAll(r)
+	if len(strings.TrimSpace(string(report))) > 0 {
+		if crashFile != "" {
+			logCrashFile(string(report), crashFile)
+		} else {
+			logCrash(string(report))
+		}
+	}
+	Close()
+	os.Exit(0)
+}
+
+// logCrash writes a runtime crash report as a FATAL entry whose message is
+// the first line of the report ("panic: ..." or "fatal error: ...").
+func logCrash(report string) {
+	if !isLevelEnabled(FatalLevel) {
+		return
+	}
+	msg, stack, _ := strings.Cut(strings.TrimSpace(report), "\n")
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(Fatal, FatalLevel, "runtime", msg, []any{"source", "crash", "stack", strings.TrimSpace(stack)})
+}
+
+// logCrashFile appends a crash report to path and logs an ERROR entry
+// pointing at it. If the file cannot be written the report is logged in full.
+func logCrashFile(report, path string) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err == nil {
+		_, err = fmt.Fprintf(f, "=== crash at %s ===\n%s\n", time.Now().Format(time.RFC3339), strings.TrimSpace(report))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		logCrash(report)
+		return
+	}
+	if !isLevelEnabled(ErrorLevel) {
+		return
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(report), "\n")
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(Error, ErrorLevel, "runtime", "process crashed", []any{"crash_file", path, "panic", first})
+}
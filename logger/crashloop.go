@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CrashLoopEvent is the event ID of the entry written when
+// Config.RestartStateFile shows rapid restarts.
+const CrashLoopEvent = "logger.crash_loop"
+
+const (
+	// DefaultCrashLoopRestarts is the Config.CrashLoopRestarts default.
+	DefaultCrashLoopRestarts = 3
+	// DefaultCrashLoopWindow is the Config.CrashLoopWindow default.
+	DefaultCrashLoopWindow = 5 * time.Minute
+	// maxRecordedStarts bounds the start times kept in the state file.
+	maxRecordedStarts = 100
+)
+
+// restartRecorded is set once this process's start has been recorded, so
+// reconfiguring the logger does not count as a restart.
+var restartRecorded atomic.Bool
+
+// checkCrashLoop records the process start in cfg.RestartStateFile, keeping
+// the starts within cfg.CrashLoopWindow, and writes a WARN when at least
+// cfg.CrashLoopRestarts earlier starts fall in the window:
+//
+//	[WARN] [logger.InitWithConfig] crash loop detected event=logger.crash_loop restarts=4 window=5m0s interval=38s last_start=2026-03-02T10:14:07Z
+//
+// interval is the average time between the starts in the window. The crash
+// monitor started by EnableCrashMonitor and EnableCrashFile runs the
+// program again and is not a start of its own. Callers must be
+// InitWithConfig.
+func checkCrashLoop(cfg Config) {
+	if os.Getenv(crashMonitorEnv) != "" || !restartRecorded.CompareAndSwap(false, true) {
+		return
+	}
+	restarts, window := cfg.CrashLoopRestarts, cfg.CrashLoopWindow
+	if restarts <= 0 {
+		restarts = DefaultCrashLoopRestarts
+	}
+	if window <= 0 {
+		window = DefaultCrashLoopWindow
+	}
+	now := processStart.Round(0)
+	starts, err := recordStart(cfg.RestartStateFile, now, window)
+	if err != nil {
+		initEntry(WarnLevel, "cannot record restart", []any{EventKey, CrashLoopEvent, "path", cfg.RestartStateFile, "error", err})
+		return
+	}
+	if len(starts) < restarts {
+		return
+	}
+	initEntry(WarnLevel, "crash loop detected", []any{
+		EventKey, CrashLoopEvent,
+		"restarts", len(starts),
+		"window", window,
+		"interval", (now.Sub(starts[0]) / time.Duration(len(starts))).Round(time.Second),
+		"last_start", starts[len(starts)-1].UTC().Format(time.RFC3339),
+	})
+}
+
+// recordStart adds now to the start times in path and returns the earlier
+// starts within window, oldest first. The file holds one RFC 3339 time per
+// line and is replaced atomically.
+func recordStart(path string, now time.Time, window time.Duration) ([]time.Time, error) {
+	var starts []time.Time
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for line := range strings.Lines(string(data)) {
+		t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(line))
+		if err == nil && now.Sub(t) < window && !t.After(now) {
+			starts = append(starts, t)
+		}
+	}
+	slices.SortFunc(starts, time.Time.Compare)
+	if len(starts) >= maxRecordedStarts {
+		starts = starts[len(starts)-maxRecordedStarts+1:]
+	}
+
+	var b strings.Builder
+	for _, t := range append(starts, now) {
+		b.WriteString(t.UTC().Format(time.RFC3339Nano))
+		b.WriteByte('\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil {
+		return nil, err
+	}
+	return starts, os.Rename(tmp, path)
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDeadlineMargin is the Config.DeadlineMargin used when it is zero.
+const DefaultDeadlineMargin = 50 * time.Millisecond
+
+// RemoteSink is implemented by sinks that deliver over the network, such as
+// a TCP syslog or MQTTSink, and that the *Deadline logging functions skip
+// when a request is about to run out of time. Custom sinks posting to HTTP
+// collectors should implement it too.
+type RemoteSink interface {
+	Sink
+	Remote() bool
+}
+
+// deadlineMargin is Config.DeadlineMargin in nanoseconds
+var deadlineMargin atomic.Int64
+
+func init() {
+	deadlineMargin.Store(int64(DefaultDeadlineMargin))
+}
+
+type nearDeadlineKey struct{}
+
+// skipNearDeadline reports whether the *Deadline functions skip s: remote sinks,
+// and lossless sinks such as ReliableSink, whose full queue blocks the
+// logging goroutine.
+func skipNearDeadline(s Sink) bool {
+	if _, ok := s.(losslessSink); ok {
+		return true
+	}
+	r, ok := s.(RemoteSink)
+	return ok && r.Remote()
+}
+
+// deadlineContext marks ctx when its deadline is less than
+// Config.DeadlineMargin away, so enqueue skips slow sinks for the entry.
+func deadlineContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return ctx
+	}
+	if d, ok := ctx.Deadline(); ok && time.Until(d) < time.Duration(deadlineMargin.Load()) {
+		return context.WithValue(ctx, nearDeadlineKey{}, true)
+	}
+	return ctx
+}
+
+// nearDeadline reports whether e was logged by a *Deadline function close
+// to its context's deadline.
+func nearDeadline(e *Entry) bool {
+	return e.ctx != nil && e.ctx.Value(nearDeadlineKey{}) != nil
+}
+
+// --- Deadline-aware context logging methods ---
+
+// DebugDeadline logs like DebugContext, except that when ctx's deadline is
+// less than Config.DeadlineMargin away, the entry skips remote and lossless
+// sinks (see RemoteSink) and only reaches the console, the log file, and
+// local sinks, so logging never eats into a handler's remaining budget.
+func DebugDeadline(ctx context.Context, msg string, keyvals ...any) {
+	if !debugEnabled() && BufferFromContext(ctx) == nil {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(2)
+	outputContext(deadlineContext(ctx), Debug, DebugLevel, caller, msg, withContextFields(ctx, keyvals))
+}
+
+// InfoDeadline logs like InfoContext, skipping slow sinks near ctx's
+// deadline as DebugDeadline does.
+func InfoDeadline(ctx context.Context, msg string, keyvals ...any) {
+	if !isLevelEnabled(InfoLevel) && BufferFromContext(ctx) == nil {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(2)
+	outputContext(deadlineContext(ctx), Info, InfoLevel, caller, msg, withContextFields(ctx, keyvals))
+}
+
+// WarnDeadline logs like WarnContext, skipping slow sinks near ctx's
+// deadline as DebugDeadline does.
+func WarnDeadline(ctx context.Context, msg string, keyvals ...any) {
+	if !isLevelEnabled(WarnLevel) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(2)
+	outputContext(deadlineContext(ctx), Warning, WarnLevel, caller, msg, withContextFields(ctx, keyvals))
+}
+
+// ErrorDeadline logs like ErrorContext, skipping slow sinks near ctx's
+// deadline as DebugDeadline does. There is no FatalDeadline: fatal entries
+// always reach every sink.
+func ErrorDeadline(ctx context.Context, msg string, keyvals ...any) {
+	if !isLevelEnabled(ErrorLevel) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(2)
+	outputContext(deadlineContext(ctx), Error, ErrorLevel, caller, msg, withContextFields(ctx, keyvals))
+}
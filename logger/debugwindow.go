@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"io"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// DebugWindowEvent is the event ID of the entry written before the DEBUG
+// entries flushed by FlushDebugWindow.
+const DebugWindowEvent = "logger.debug_window"
+
+var (
+	// debugWindow keeps the DEBUG entries dropped by the level filter when
+	// Config.DebugWindow is set. Guarded by logMutex.
+	debugWindow *RingSink
+	// debugWindowOn reports whether debugWindow is set, for the checks made
+	// before taking logMutex
+	debugWindowOn atomic.Bool
+)
+
+// FlushDebugWindow writes the DEBUG entries kept by Config.DebugWindow,
+// oldest first and with their original times, after an INFO entry giving
+// reason, then empties the window. Calling it when a failure is detected
+// records the lead-up to the failure without running with DEBUG enabled:
+//
+//	logger.InitWithConfig(logger.Config{Mode: "production", DebugWindow: 500})
+//	...
+//	if err := sync(ctx); err != nil {
+//	    logger.FlushDebugWindow("sync failed")
+//	    logger.ErrorKV("sync failed", "error", err)
+//	}
+//	// [INFO] [logger.FlushDebugWindow] flushing debug window event=logger.debug_window reason=sync failed entries=38
+//	// [DEBUG] [store.fetchPage:112] page fetched cursor=c9f2 rows=500
+//	// ...
+//
+// The entries are written to the console even when DEBUG output is off, and
+// to the log file and sinks. It does nothing without Config.DebugWindow.
+func FlushDebugWindow(reason string) {
+	if !globalDisabled.Load() {
+		ensureInit()
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	if debugWindow == nil {
+		return
+	}
+	entries := debugWindow.Entries()
+	if len(entries) == 0 {
+		return
+	}
+	debugWindow = NewRingSink(cap(debugWindow.entries))
+	writeEntry(Info, &Entry{Time: time.Now(), Level: InfoLevel, Caller: "logger.FlushDebugWindow",
+		Message: "flushing debug window", Fields: []any{EventKey, DebugWindowEvent, "reason", reason, "entries", len(entries)}})
+	l := debugWindowLogger()
+	for _, e := range entries {
+		writeEntry(l, e)
+	}
+}
+
+// debugEnabled reports whether DEBUG entries are logged or kept for
+// FlushDebugWindow.
+func debugEnabled() bool {
+	return isLevelEnabled(DebugLevel) || (debugWindowOn.Load() && !enabledLevels()[DebugLevel])
+}
+
+// holdDebug keeps e for FlushDebugWindow and reports true if it is a DEBUG
+// entry dropped by the level filter. Callers must hold logMutex.
+func holdDebug(e *Entry) bool {
+	if e.Level != DebugLevel || debugWindow == nil || enabledLevels()[DebugLevel] {
+		return false
+	}
+	debugWindow.WriteEntry(e)
+	return true
+}
+
+// debugWindowLogger returns the DEBUG console logger, or one writing to the
+// INFO console writer when DEBUG output is off. Callers must hold logMutex.
+func debugWindowLogger() *log.Logger {
+	if Debug.Writer() != io.Discard {
+		return Debug
+	}
+	if jsonOutput || layout != nil || journalStyle != JournalDefault {
+		return log.New(Info.Writer(), "", 0)
+	}
+	return log.New(Info.Writer(), levelPrefix(DebugLevel, textFormat.color), textFormat.consoleFlags)
+}
@@ -0,0 +1,36 @@
+package logger
+
+import "sync"
+
+// DeprecationKey is the field set to true on entries written by Deprecated,
+// so deprecations can be found with a single filter.
+const DeprecationKey = "deprecation"
+
+// deprecations holds the subjects already reported by Deprecated
+var deprecations sync.Map
+
+// Deprecated logs a WARN entry the first time subject is reported in the
+// process, giving products a consistent deprecation surface:
+//
+//	logger.Deprecated("flag --old", "use --new", "since", "v2.3")
+//	// [WARN] [main.parseFlags:88] flag --old is deprecated deprecation=true subject=flag --old replacement=use --new since=v2.3
+//
+// An empty replacement is left out. Later calls with the same subject do
+// nothing, so Deprecated can sit on a hot path.
+func Deprecated(subject, replacement string, keyvals ...any) {
+	if _, seen := deprecations.LoadOrStore(subject, struct{}{}); seen {
+		return
+	}
+	if !isLevelEnabled(WarnLevel) {
+		return
+	}
+	fields := []any{DeprecationKey, true, "subject", subject}
+	if replacement != "" {
+		fields = append(fields, "replacement", replacement)
+	}
+	fields = append(fields, keyvals...)
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(Warning, WarnLevel, getCallerInfo(2), subject+" is deprecated", fields)
+}
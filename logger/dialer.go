@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+)
+
+// lookupHost resolves collector host names for rotatingDialer, returning
+// the record TTL or zero when it is unknown.
+var lookupHost = lookupHostTTL
+
+// minDNSCache is the shortest time rotatingDialer keeps an answer, so a
+// zero TTL does not cause a lookup per connection.
+const minDNSCache = time.Second
+
+// rotatingDialer spreads the connections of a network sink across the
+// addresses a collector's host name resolves to. The answer is cached for
+// its record TTL, capped at refresh and at least minDNSCache or refresh if
+// shorter; answers without a known TTL are cached for refresh. Connections
+// older than refresh are replaced, so a sink behind a DNS-balanced
+// collector does not stay pinned to one backend.
+type rotatingDialer struct {
+	host, port string
+	refresh    time.Duration
+	lookup     func(ctx context.Context, host string) ([]string, time.Duration, error)
+
+	addrs   []string
+	next    int
+	expires time.Time
+	dialed  time.Time
+}
+
+// newRotatingDialer returns a dialer for address, or nil when refresh is
+// zero and the sink should dial address as given.
+func newRotatingDialer(address string, refresh time.Duration) (*rotatingDialer, error) {
+	if refresh <= 0 {
+		return nil, nil
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("logger: %w", err)
+	}
+	return &rotatingDialer{host: host, port: port, refresh: refresh, lookup: lookupHost}, nil
+}
+
+// target returns the address to dial next, re-resolving the host when the
+// cached answer has expired. A failed lookup keeps using the previous
+// addresses.
+func (d *rotatingDialer) target(timeout time.Duration) (string, error) {
+	if len(d.addrs) == 0 || !time.Now().Before(d.expires) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		addrs, ttl, err := d.lookup(ctx, d.host)
+		cancel()
+		switch {
+		case err == nil && len(addrs) > 0:
+			if ttl <= 0 || ttl > d.refresh {
+				ttl = d.refresh
+			}
+			d.addrs, d.expires = addrs, time.Now().Add(max(ttl, min(minDNSCache, d.refresh)))
+		case len(d.addrs) == 0:
+			if err == nil {
+				err = fmt.Errorf("logger: no addresses for %s", d.host)
+			}
+			return "", err
+		}
+	}
+	addr := d.addrs[d.next%len(d.addrs)]
+	d.next++
+	return net.JoinHostPort(addr, d.port), nil
+}
+
+// dial connects to the next address over network ("tcp", "udp", or "tls").
+// TLS connections verify the certificate against the host name rather than
+// the resolved address.
+func (d *rotatingDialer) dial(network string, tlsConfig *tls.Config, timeout time.Duration) (net.Conn, error) {
+	addr, err := d.target(timeout)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	if network == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, d.tlsConfig(tlsConfig))
+	} else {
+		conn, err = dialer.Dial(network, addr)
+	}
+	if err == nil {
+		d.dialed = time.Now()
+	}
+	return conn, err
+}
+
+// tlsConfig returns cfg with ServerName set to the host.
+func (d *rotatingDialer) tlsConfig(cfg *tls.Config) *tls.Config {
+	if cfg.ServerName != "" {
+		return cfg
+	}
+	cfg = cfg.Clone()
+	cfg.ServerName = d.host
+	return cfg
+}
+
+// expired reports whether the current connection should be replaced to pick
+// up a new address.
+func (d *rotatingDialer) expired() bool {
+	return d != nil && !d.dialed.IsZero() && time.Since(d.dialed) >= d.refresh
+}
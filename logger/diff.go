@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Diff logs msg at INFO with the fields that differ between old and new,
+// for configuration reloads and state-transition audits:
+//
+//	logger.Diff("config reloaded", oldCfg, newCfg)
+//	// [INFO] [main.reload:40] config reloaded changed=2 db.pool_size=10->20 log.level=info->debug
+//
+// Structs and maps are compared field by field, recursing into nested
+// structs and maps and naming fields by their dotted path; struct fields
+// use their json tag name when they have one and are skipped when it is
+// "-". Other values, including slices, are compared whole. Each differing
+// path is logged as "old->new", with <unset> for a side that lacks it.
+// keyvals are added after the changes.
+func Diff(msg string, old, new any, keyvals ...any) {
+	if !isLevelEnabled(InfoLevel) {
+		return
+	}
+	changes := diffFields(old, new)
+	fields := append([]any{"changed", len(changes) / 2}, changes...)
+	fields = append(fields, keyvals...)
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(InfoLevel), InfoLevel, getCallerInfo(2), msg, fields)
+}
+
+// diffUnset stands for a path present on only one side.
+const diffUnset = "<unset>"
+
+// diffFields returns "path", "old->new" pairs for each differing path,
+// sorted by path.
+func diffFields(old, new any) []any {
+	before, after := map[string]any{}, map[string]any{}
+	flattenDiff("", reflect.ValueOf(old), before)
+	flattenDiff("", reflect.ValueOf(new), after)
+
+	paths := make([]string, 0, len(before)+len(after))
+	for path := range before {
+		paths = append(paths, path)
+	}
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	var changes []any
+	for _, path := range paths {
+		a, inBefore := before[path]
+		b, inAfter := after[path]
+		if inBefore && inAfter && reflect.DeepEqual(a, b) {
+			continue
+		}
+		from, to := diffUnset, diffUnset
+		if inBefore {
+			from = fmt.Sprint(a)
+		}
+		if inAfter {
+			to = fmt.Sprint(b)
+		}
+		key := path
+		if key == "" {
+			key = "value"
+		}
+		changes = append(changes, key, from+"->"+to)
+	}
+	return changes
+}
+
+// flattenDiff adds the leaves of v to out under dotted paths below prefix.
+// A nil root has no leaves, so every path of the other side is added.
+func flattenDiff(prefix string, v reflect.Value, out map[string]any) {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch {
+	case !v.IsValid() || v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface:
+		if prefix != "" {
+			out[prefix] = nil
+		}
+	case v.Kind() == reflect.Struct && !isDiffLeaf(v):
+		t := v.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name := f.Name
+			if tag, _, _ := strings.Cut(f.Tag.Get("json"), ","); tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+			flattenDiff(joinDiffPath(prefix, name), v.Field(i), out)
+		}
+	case v.Kind() == reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			flattenDiff(joinDiffPath(prefix, fmt.Sprint(iter.Key().Interface())), iter.Value(), out)
+		}
+	default:
+		out[prefix] = v.Interface()
+	}
+}
+
+// isDiffLeaf reports whether a struct is compared whole, as for time.Time
+// and other types with their own String method.
+func isDiffLeaf(v reflect.Value) bool {
+	_, ok := v.Interface().(fmt.Stringer)
+	if !ok && v.CanAddr() {
+		_, ok = v.Addr().Interface().(fmt.Stringer)
+	}
+	return ok
+}
+
+func joinDiffPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
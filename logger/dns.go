@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// DNS record types queried by lookupHostTTL.
+const (
+	dnsTypeA    = 1
+	dnsTypeAAAA = 28
+)
+
+// dnsServers returns the name servers queried for record TTLs, from
+// /etc/resolv.conf.
+var dnsServers = func() []string {
+	f, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return []string{"127.0.0.1:53"}
+	}
+	defer f.Close()
+	var servers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" && net.ParseIP(fields[1]) != nil {
+			servers = append(servers, net.JoinHostPort(fields[1], "53"))
+		}
+	}
+	if len(servers) == 0 {
+		return []string{"127.0.0.1:53"}
+	}
+	return servers
+}
+
+// lookupHostTTL resolves host and returns how long the answer may be
+// cached: the smallest TTL of the A and AAAA records and CNAMEs leading to
+// them. Names the name servers cannot answer directly, such as those in
+// /etc/hosts or needing a search domain, are resolved by the system
+// resolver with an unknown (zero) TTL, as are IP literals.
+func lookupHostTTL(ctx context.Context, host string) ([]string, time.Duration, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, 0, nil
+	}
+	for _, server := range dnsServers() {
+		addrs, ttl, err := dnsQuery(ctx, server, host, dnsTypeA)
+		if err != nil {
+			continue
+		}
+		if addrs6, ttl6, err := dnsQuery(ctx, server, host, dnsTypeAAAA); err == nil && len(addrs6) > 0 {
+			if len(addrs) == 0 || ttl6 < ttl {
+				ttl = ttl6
+			}
+			addrs = append(addrs, addrs6...)
+		}
+		if len(addrs) > 0 {
+			return addrs, ttl, nil
+		}
+		break
+	}
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	return addrs, 0, err
+}
+
+// dnsQuery sends one recursive query for host's qtype records to server
+// over UDP and returns the addresses and the smallest TTL in the answer.
+func dnsQuery(ctx context.Context, server, host string, qtype uint16) ([]string, time.Duration, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", server)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	id := uint16(rand.Uint32())
+	// header: ID, flags with recursion desired, one question
+	q := binary.BigEndian.AppendUint16(nil, id)
+	q = append(q, 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0)
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, 0, errors.New("logger: invalid host name")
+		}
+		q = append(q, byte(len(label)))
+		q = append(q, label...)
+	}
+	q = append(q, 0)
+	q = binary.BigEndian.AppendUint16(q, qtype)
+	q = append(q, 0, 1) // class IN
+	if _, err := conn.Write(q); err != nil {
+		return nil, 0, err
+	}
+
+	buf := make([]byte, 1232)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, 0, err
+		}
+		if n >= 12 && binary.BigEndian.Uint16(buf) == id {
+			return parseDNSAnswer(buf[:n], qtype)
+		}
+	}
+}
+
+// parseDNSAnswer returns the qtype addresses in a DNS response and the
+// smallest TTL among the answer records.
+func parseDNSAnswer(msg []byte, qtype uint16) ([]string, time.Duration, error) {
+	errMalformed := errors.New("logger: malformed DNS response")
+	if len(msg) < 12 || msg[2]&0x80 == 0 {
+		return nil, 0, errMalformed
+	}
+	if rcode := msg[3] & 0x0f; rcode != 0 {
+		return nil, 0, errors.New("logger: DNS lookup failed")
+	}
+	qdcount, ancount := binary.BigEndian.Uint16(msg[4:]), binary.BigEndian.Uint16(msg[6:])
+	off := 12
+	for range qdcount {
+		if off = skipDNSName(msg, off); off < 0 || off+4 > len(msg) {
+			return nil, 0, errMalformed
+		}
+		off += 4
+	}
+	var addrs []string
+	ttl := time.Duration(-1)
+	for range ancount {
+		if off = skipDNSName(msg, off); off < 0 || off+10 > len(msg) {
+			return nil, 0, errMalformed
+		}
+		typ := binary.BigEndian.Uint16(msg[off:])
+		rttl := time.Duration(binary.BigEndian.Uint32(msg[off+4:])) * time.Second
+		size := int(binary.BigEndian.Uint16(msg[off+8:]))
+		off += 10
+		if off+size > len(msg) {
+			return nil, 0, errMalformed
+		}
+		if typ == qtype && (size == net.IPv4len || size == net.IPv6len) {
+			addrs = append(addrs, net.IP(msg[off:off+size]).String())
+		}
+		if ttl < 0 || rttl < ttl {
+			ttl = rttl
+		}
+		off += size
+	}
+	return addrs, max(ttl, 0), nil
+}
+
+// skipDNSName returns the offset after the possibly compressed name at off,
+// or -1 if it runs past msg.
+func skipDNSName(msg []byte, off int) int {
+	for off < len(msg) {
+		switch n := int(msg[off]); {
+		case n == 0:
+			return off + 1
+		case n&0xc0 == 0xc0:
+			return off + 2
+		default:
+			off += 1 + n
+		}
+	}
+	return -1
+}
@@ -42,6 +42,7 @@
 // Control which levels are logged via environment variable:
 //
 //	LOGGER_LEVELS="INFO,ERROR" ./myapp
+//	LOGGER_LEVELS=">=WARN,http.client=DEBUG,pkg:store=ERROR" ./myapp
 //
 // This package is lightweight and has no external dependencies.
 package logger
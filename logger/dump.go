@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// dumpRedactedHeaders are logged as [REDACTED] by DumpRequest and
+// DumpResponse.
+var dumpRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// DumpRequest logs r at DEBUG with its method, URL, headers, and up to
+// maxBody bytes of body, for debugging client and server exchanges:
+//
+//	logger.DumpRequest(req, 2048)
+//	// [DEBUG] [api.call:31] http request method=POST url=https://api.example.com/v1/items headers=Authorization: [REDACTED]; Content-Type: application/json body={"name":"a"}
+//
+// Authorization, Proxy-Authorization, and Cookie headers are redacted. The
+// body is read only as far as maxBody and put back, so the request can
+// still be sent or handled in full; body_truncated=true marks a longer
+// body. Nothing is read when DEBUG is disabled.
+func DumpRequest(r *http.Request, maxBody int) {
+	if !isLevelEnabled(DebugLevel) || r == nil {
+		return
+	}
+	fields := []any{"method", r.Method, "url", r.URL.String(), "headers", dumpHeaders(r.Header)}
+	r.Body, fields = dumpBody(r.Body, maxBody, fields)
+	logDump(getCallerInfo(2), "http request", fields)
+}
+
+// DumpResponse logs resp at DEBUG with its status, the request URL,
+// headers, and up to maxBody bytes of body, redacting Set-Cookie and
+// restoring the body like DumpRequest.
+func DumpResponse(resp *http.Response, maxBody int) {
+	if !isLevelEnabled(DebugLevel) || resp == nil {
+		return
+	}
+	fields := []any{"status", resp.StatusCode}
+	if resp.Request != nil && resp.Request.URL != nil {
+		fields = append(fields, "url", resp.Request.URL.String())
+	}
+	fields = append(fields, "headers", dumpHeaders(resp.Header))
+	resp.Body, fields = dumpBody(resp.Body, maxBody, fields)
+	logDump(getCallerInfo(2), "http response", fields)
+}
+
+func logDump(caller, msg string, fields []any) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(DebugLevel), DebugLevel, caller, msg, fields)
+}
+
+// dumpHeaders renders h as "Name: value; ..." sorted by name, with
+// sensitive values redacted.
+func dumpHeaders(h http.Header) string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		if slices.Contains(dumpRedactedHeaders, http.CanonicalHeaderKey(name)) {
+			value = redacted
+		}
+		parts = append(parts, name+": "+value)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// dumpBody reads up to maxBody bytes of body, adds them to fields, and
+// returns a body that yields the full original content.
+func dumpBody(body io.ReadCloser, maxBody int, fields []any) (io.ReadCloser, []any) {
+	if body == nil || body == http.NoBody || maxBody <= 0 {
+		return body, fields
+	}
+	head, err := io.ReadAll(io.LimitReader(body, int64(maxBody)+1))
+	truncated := len(head) > maxBody
+	shown := head[:min(len(head), maxBody)]
+	// do not cut a character in two
+	for i := 0; truncated && i < utf8.UTFMax-1 && !utf8.Valid(shown); i++ {
+		shown = shown[:len(shown)-1]
+	}
+	text := string(shown)
+	if !utf8.Valid(shown) {
+		text = strconv.Quote(text)
+	}
+	fields = append(fields, "body", text)
+	if truncated {
+		fields = append(fields, "body_truncated", true)
+	}
+	if err != nil {
+		fields = append(fields, "body_error", err)
+	}
+	return &restoredBody{Reader: io.MultiReader(bytes.NewReader(head), body), Closer: body}, fields
+}
+
+// restoredBody replays the bytes read by dumpBody ahead of the rest of
+// the original body.
+type restoredBody struct {
+	io.Reader
+	io.Closer
+}
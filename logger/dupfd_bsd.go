@@ -0,0 +1,10 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import "syscall"
+
+// dupFd makes newfd refer to the same open file as oldfd.
+func dupFd(oldfd, newfd int) error {
+	return syscall.Dup2(oldfd, newfd)
+}
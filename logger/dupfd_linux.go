@@ -0,0 +1,8 @@
+package logger
+
+import "syscall"
+
+// dupFd makes newfd refer to the same open file as oldfd.
+func dupFd(oldfd, newfd int) error {
+	return syscall.Dup3(oldfd, newfd, 0)
+}
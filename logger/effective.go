@@ -0,0 +1,185 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ResolvedConfig describes the configuration in effect after the last Init
+// call, with environment variables and auto-detection applied. It marshals
+// to JSON for admin endpoints:
+//
+//	http.HandleFunc("/debug/logger", func(w http.ResponseWriter, r *http.Request) {
+//	    json.NewEncoder(w).Encode(logger.EffectiveConfig())
+//	})
+type ResolvedConfig struct {
+	Mode    string `json:"mode"`
+	Verbose bool   `json:"verbose"`
+	// Identifier is the SYSLOG_IDENTIFIER and default syslog Tag.
+	Identifier string `json:"identifier"`
+	// Levels lists the levels written to the console.
+	Levels []string `json:"levels"`
+	// Console is where console output goes: "color" (development stdout),
+	// "plain" (stdout/stderr, or development without color support),
+	// "json", "syslog", or "discard".
+	Console    string `json:"console"`
+	Layout     string `json:"layout,omitempty"`
+	TimeFormat string `json:"time_format,omitempty"`
+	// Journald reports whether stdout is connected to the systemd journal,
+	// and JournaldReason why it was or was not used.
+	Journald       bool   `json:"journald"`
+	JournaldReason string `json:"journald_reason"`
+	// JournalStyle is "default", "compact", or "bare".
+	JournalStyle string `json:"journal_style"`
+	// File is the log file path, empty when file logging is off or the file
+	// could not be opened (see FileError).
+	File         string            `json:"file,omitempty"`
+	FileAsync    bool              `json:"file_async,omitempty"`
+	FileError    string            `json:"file_error,omitempty"`
+	Severity     string            `json:"severity"`
+	StrictCodes  bool              `json:"strict_codes,omitempty"`
+	Locale       string            `json:"locale,omitempty"`
+	LoggerLevels map[string]string `json:"logger_levels,omitempty"`
+	// PackageLevels holds the "pkg:" rules of LOGGER_LEVELS.
+	PackageLevels map[string]string `json:"package_levels,omitempty"`
+	Sinks         int               `json:"sinks"`
+	Routes        int               `json:"routes"`
+	Metrics       int               `json:"metrics"`
+	// Problems lists the findings of Validate for the configuration.
+	Problems []string `json:"problems,omitempty"`
+}
+
+var (
+	// appliedConfig and appliedFileErr record the last InitWithConfig call
+	appliedConfig  Config
+	appliedFileErr error
+)
+
+// EffectiveConfig returns the resolved configuration of the last Init call.
+// It does not change any logger state.
+func EffectiveConfig() ResolvedConfig {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	return resolveConfig(appliedConfig, appliedFileErr)
+}
+
+// resolveConfig describes the state InitWithConfig derived from cfg.
+// Callers must hold logMutex or be InitWithConfig.
+func resolveConfig(cfg Config, fileErr error) ResolvedConfig {
+	production := cfg.Mode == "production"
+	rc := ResolvedConfig{
+		Mode:         cfg.Mode,
+		Verbose:      cfg.Verbose,
+		Identifier:   Identifier(),
+		JournalStyle: []string{"default", "compact", "bare"}[journalStyle],
+		Severity:     []string{"none", "field", "prefix"}[severityMode],
+		StrictCodes:  strictCodes,
+		Locale:       consoleLocale,
+		Sinks:        len(sinks),
+		Routes:       len(routes),
+		Metrics:      len(metrics),
+	}
+	for level := DebugLevel; level <= FatalLevel; level++ {
+		if enabledLevels()[level] && (level != DebugLevel || debugOutput || cfg.ConsoleLevels != nil) {
+			rc.Levels = append(rc.Levels, levelNames[level])
+		}
+	}
+
+	switch {
+	case jsonOutput:
+		rc.Console = "json"
+	case production && cfg.Fallback == FallbackDiscard:
+		rc.Console = "discard"
+	case production && cfg.Fallback == FallbackFileOnly && logFile != nil:
+		rc.Console = "discard"
+	case autoSyslog != nil:
+		rc.Console = "syslog"
+	case production || !textFormat.color:
+		rc.Console = "plain"
+	default:
+		rc.Console = "color"
+	}
+	if layout != nil {
+		rc.Layout, rc.TimeFormat = cfg.Layout, layout.timeFormat
+	}
+
+	rc.Journald = underJournald()
+	switch {
+	case os.Getenv("JOURNAL_STREAM") == "":
+		rc.JournaldReason = "JOURNAL_STREAM unset"
+	case !rc.Journald:
+		rc.JournaldReason = "stdout redirected"
+	case journalStream != nil:
+		rc.JournaldReason = "journal detected, own stream for identifier " + Identifier()
+	case journalStyle != JournalDefault:
+		rc.JournaldReason = "journal detected, <N> priority prefixes"
+	case cfg.Journal != JournalDefault:
+		rc.JournaldReason = "journal detected, Journal style ignored with FallbackJSON"
+	default:
+		rc.JournaldReason = "journal detected, timestamps omitted"
+	}
+
+	if fileErr != nil {
+		rc.FileError = fileErr.Error()
+	} else if logFile != nil {
+		rc.File, rc.FileAsync = cfg.FilePath, fileBatch != nil
+	}
+
+	namedMu.RLock()
+	if len(namedLevels) > 0 {
+		rc.LoggerLevels = make(map[string]string, len(namedLevels))
+		for name, level := range namedLevels {
+			rc.LoggerLevels[name] = levelNames[level]
+		}
+	}
+	namedMu.RUnlock()
+	if packages := levelFilter.Load().packages; len(packages) > 0 {
+		rc.PackageLevels = make(map[string]string, len(packages))
+		for pkg, level := range packages {
+			rc.PackageLevels[pkg] = levelNames[level]
+		}
+	}
+
+	if err := Validate(cfg); err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			rc.Problems = append(rc.Problems, strings.TrimPrefix(line, "logger: "))
+		}
+	}
+	return rc
+}
+
+// diagnosticsEnabled reports whether LOGGER_DEBUG asks for self-diagnostics.
+func diagnosticsEnabled() bool {
+	v := os.Getenv("LOGGER_DEBUG")
+	return v != "" && v != "0"
+}
+
+// writeDiagnostics prints rc as "logger: ..." lines for LOGGER_DEBUG.
+func writeDiagnostics(w io.Writer, rc ResolvedConfig) {
+	if w == nil {
+		return
+	}
+	fmt.Fprintf(w, "logger: mode=%s verbose=%t levels=%s identifier=%s\n", rc.Mode, rc.Verbose, strings.Join(rc.Levels, ","), rc.Identifier)
+	console := rc.Console
+	if rc.Layout != "" {
+		console += ", layout " + rc.Layout
+	}
+	fmt.Fprintf(w, "logger: console: %s\n", console)
+	fmt.Fprintf(w, "logger: journald: %t (%s), style %s\n", rc.Journald, rc.JournaldReason, rc.JournalStyle)
+	switch {
+	case rc.FileError != "":
+		fmt.Fprintf(w, "logger: file: could not be opened: %s\n", rc.FileError)
+	case rc.File == "":
+		fmt.Fprintln(w, "logger: file: disabled")
+	case rc.FileAsync:
+		fmt.Fprintf(w, "logger: file: %s (async)\n", rc.File)
+	default:
+		fmt.Fprintf(w, "logger: file: %s (sync)\n", rc.File)
+	}
+	fmt.Fprintf(w, "logger: sinks=%d routes=%d metrics=%d\n", rc.Sinks, rc.Routes, rc.Metrics)
+	for _, p := range rc.Problems {
+		fmt.Fprintf(w, "logger: problem: %s\n", p)
+	}
+}
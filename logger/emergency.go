@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"math"
+	rtmetrics "runtime/metrics"
+	"strconv"
+	"time"
+)
+
+// EmergencyBufferSize is the size of the buffer FATAL entries are formatted
+// into when the heap is exhausted. Longer entries are truncated.
+const EmergencyBufferSize = 4096
+
+// EmergencyMemoryRatio is the fraction of the Go memory limit (GOMEMLIMIT)
+// in use by heap objects at which FATAL entries take the emergency path.
+const EmergencyMemoryRatio = 0.95
+
+var (
+	// emergencyBuf and emergencySamples are allocated with the program, so
+	// the emergency path needs no heap memory of its own
+	emergencyBuf     [EmergencyBufferSize]byte
+	emergencySamples = []rtmetrics.Sample{
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/gc/gomemlimit:bytes"},
+	}
+)
+
+// heapExhausted reports whether heap objects use at least
+// EmergencyMemoryRatio of the Go memory limit. Without a limit it is false.
+// Callers must hold logMutex.
+func heapExhausted() bool {
+	rtmetrics.Read(emergencySamples)
+	heap, limit := emergencySamples[0].Value, emergencySamples[1].Value
+	if heap.Kind() != rtmetrics.KindUint64 || limit.Kind() != rtmetrics.KindUint64 {
+		return false
+	}
+	if limit.Uint64() >= math.MaxInt64 {
+		return false
+	}
+	return float64(heap.Uint64()) >= EmergencyMemoryRatio*float64(limit.Uint64())
+}
+
+// recoverFatal writes a FATAL entry whose regular formatting panicked, e.g.
+// in a field's String method, through the emergency path. It must be
+// deferred directly.
+func recoverFatal(caller, msg string, keyvals []any) {
+	if recover() != nil {
+		writeEmergency(caller, msg, keyvals)
+	}
+}
+
+// writeEmergency formats a FATAL entry as "date time [FATAL] [caller] msg
+// key=value" into emergencyBuf without allocating, and writes it to stderr
+// and the log file. Only string, integer, boolean, and error field values
+// are kept; sinks, layouts, and JSON output are bypassed. Callers must hold
+// logMutex.
+func writeEmergency(caller, msg string, keyvals []any) {
+	b := emergencyBuf[:0]
+	b = time.Now().AppendFormat(b, DefaultTimeFormat)
+	b = appendTruncated(b, " [FATAL] [")
+	b = appendTruncated(b, caller)
+	b = appendTruncated(b, "] ")
+	b = appendTruncated(b, msg)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			continue
+		}
+		start := len(b)
+		b = appendTruncated(b, " ")
+		b = appendTruncated(b, key)
+		b = appendTruncated(b, "=")
+		switch v := keyvals[i+1].(type) {
+		case string:
+			b = appendTruncated(b, v)
+		case error:
+			b = appendTruncated(b, v.Error())
+		case int:
+			b = appendInt(b, int64(v))
+		case int64:
+			b = appendInt(b, v)
+		case bool:
+			b = appendTruncated(b, strconv.FormatBool(v))
+		default:
+			b = b[:start]
+		}
+	}
+	if len(b) == cap(b) {
+		b = b[:len(b)-1]
+	}
+	b = append(b, '\n')
+
+	outStderr.Write(b)
+	if logFile != nil {
+		if fileBatch != nil {
+			fileBatch.Flush()
+		}
+		logFile.Write(b)
+	}
+}
+
+// appendTruncated appends s to b up to the capacity of b.
+func appendTruncated(b []byte, s string) []byte {
+	return append(b, s[:min(len(s), cap(b)-len(b))]...)
+}
+
+// appendInt appends n to b if any value fits in the capacity of b.
+func appendInt(b []byte, n int64) []byte {
+	if cap(b)-len(b) < len("-9223372036854775808") {
+		return b
+	}
+	return strconv.AppendInt(b, n, 10)
+}
@@ -0,0 +1,183 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Fields added by Kubernetes enrichment.
+const (
+	PodKey         = "k8s_pod"
+	NamespaceKey   = "k8s_namespace"
+	NodeKey        = "k8s_node"
+	ContainerIDKey = "container_id"
+)
+
+var (
+	// globalFields are added to every entry, resolved at Init
+	globalFields []any
+
+	// files read by KubernetesFields
+	cgroupPath           = "/proc/self/cgroup"
+	serviceAccountNSPath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+	containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+)
+
+// resolveGlobalFields returns Config.Fields followed by the fields of
+// LOGGER_FIELDS, the Kubernetes metadata when running in a pod and, with
+// Config.CloudMetadata, the cloud instance metadata. A malformed
+// LOGGER_FIELDS is skipped and its error returned.
+func resolveGlobalFields(cfg Config) ([]any, error) {
+	fields := append([]any(nil), cfg.Fields...)
+	env, err := parseEnvFields(os.Getenv("LOGGER_FIELDS"))
+	fields = appendMissing(fields, env)
+	if !cfg.DisableKubernetesFields {
+		fields = appendMissing(fields, KubernetesFields())
+	}
+	if cfg.CloudMetadata {
+		fields = appendMissing(fields, CloudFields())
+	}
+	return fields, err
+}
+
+// parseEnvFields parses the LOGGER_FIELDS variable, which orchestration
+// layers set to add deployment metadata to every entry. It holds either a
+// JSON object or logfmt pairs, with double-quoted values for spaces:
+//
+//	LOGGER_FIELDS='region=eu-west-1 cluster=prod-3 team="core infra"'
+//	LOGGER_FIELDS='{"region": "eu-west-1", "replicas": 3}'
+//
+// Fields keep their order. Config.Fields take precedence over fields with
+// the same key.
+func parseEnvFields(s string) ([]any, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(s, "{") {
+		return parseJSONFields(s)
+	}
+	var fields []any
+	for rest := s; rest != ""; rest = strings.TrimLeft(rest, " \t") {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t\"") {
+			return nil, fmt.Errorf("logger: LOGGER_FIELDS: expected key=value at %q", rest)
+		}
+		rest = value
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, fmt.Errorf("logger: LOGGER_FIELDS: bad quoted value for %s", key)
+			}
+			rest = rest[len(quoted):]
+			value, _ = strconv.Unquote(quoted)
+		} else {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				end = len(rest)
+			}
+			value, rest = rest[:end], rest[end:]
+		}
+		if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			return nil, fmt.Errorf("logger: LOGGER_FIELDS: expected space after %s", key)
+		}
+		fields = append(fields, key, value)
+	}
+	return fields, nil
+}
+
+// parseJSONFields decodes a JSON object into fields, keeping key order.
+func parseJSONFields(s string) ([]any, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("logger: LOGGER_FIELDS: expected a JSON object")
+	}
+	var fields []any
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("logger: LOGGER_FIELDS: %w", err)
+		}
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("logger: LOGGER_FIELDS: %w", err)
+		}
+		fields = append(fields, tok.(string), value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("logger: LOGGER_FIELDS: %w", err)
+	}
+	if dec.More() {
+		return nil, errors.New("logger: LOGGER_FIELDS: trailing data after object")
+	}
+	return fields, nil
+}
+
+// KubernetesFields returns the pod, namespace, node, and container ID of the
+// current process when it runs in Kubernetes, or nil otherwise. The pod,
+// namespace, and node come from the POD_NAME, POD_NAMESPACE, and NODE_NAME
+// variables set through the Downward API:
+//
+//	env:
+//	- name: POD_NAME
+//	  valueFrom: {fieldRef: {fieldPath: metadata.name}}
+//	- name: POD_NAMESPACE
+//	  valueFrom: {fieldRef: {fieldPath: metadata.namespace}}
+//	- name: NODE_NAME
+//	  valueFrom: {fieldRef: {fieldPath: spec.nodeName}}
+//
+// Without them the pod name falls back to HOSTNAME and the namespace to the
+// service account's namespace file. The container ID is read from the
+// process's cgroup. InitWithConfig adds these fields to every entry unless
+// Config.DisableKubernetesFields is set.
+func KubernetesFields() []any {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return nil
+	}
+	var fields []any
+	add := func(key, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			fields = append(fields, key, value)
+		}
+	}
+	pod := os.Getenv("POD_NAME")
+	if pod == "" {
+		pod = os.Getenv("HOSTNAME")
+	}
+	add(PodKey, pod)
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		if b, err := os.ReadFile(serviceAccountNSPath); err == nil {
+			namespace = string(b)
+		}
+	}
+	add(NamespaceKey, namespace)
+	add(NodeKey, os.Getenv("NODE_NAME"))
+	add(ContainerIDKey, containerID())
+	return fields
+}
+
+// containerID returns the 64-hex-digit container ID found in the process's
+// cgroup paths, e.g. ".../cri-containerd-<id>.scope" or ".../docker/<id>".
+func containerID() string {
+	f, err := os.Open(cgroupPath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := containerIDPattern.FindString(scanner.Text()); id != "" {
+			return id
+		}
+	}
+	return ""
+}
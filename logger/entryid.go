@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"encoding/hex"
+	"time"
+)
+
+// EntryIDKey is the field name used by Config.EntryIDs.
+const EntryIDKey = "entry_id"
+
+// entryIDs is Config.EntryIDs
+var entryIDs bool
+
+// ID returns the entry's EntryIDKey field, set when Config.EntryIDs is on,
+// or "" when it has none.
+func (e *Entry) ID() string {
+	for i := 0; i+1 < len(e.Fields); i += 2 {
+		if e.Fields[i] == EntryIDKey {
+			id, _ := e.Fields[i+1].(string)
+			return id
+		}
+	}
+	return ""
+}
+
+// newEntryID returns the ID of an entry logged at t from the
+// SetIDGenerator generator, or a UUIDv7.
+func newEntryID(t time.Time) string {
+	if g := idGenerator.Load(); g != nil {
+		return (*g).EntryID(t)
+	}
+	return uuidV7(t)
+}
+
+// uuidV7 returns a UUIDv7 (RFC 9562) for t. The 12 bits after the
+// millisecond timestamp hold the sub-millisecond fraction, so IDs sort by
+// time to about 250ns.
+func uuidV7(t time.Time) string {
+	var b [16]byte
+	readRandom(b[8:])
+	ms := uint64(t.UnixMilli())
+	frac := uint64(t.Nanosecond()%1e6) * 4096 / 1e6
+	for i := range 6 {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+	b[6] = 0x70 | byte(frac>>8)
+	b[7] = byte(frac)
+	b[8] = 0x80 | b[8]&0x3f
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
@@ -0,0 +1,119 @@
+package logger
+
+import "errors"
+
+// fieldsError is an error carrying key-value pairs that are merged into any
+// entry the error is logged with.
+type fieldsError struct {
+	msg     string
+	err     error
+	keyvals []any
+}
+
+func (e *fieldsError) Error() string {
+	switch {
+	case e.err == nil:
+		return e.msg
+	case e.msg == "":
+		return e.err.Error()
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e *fieldsError) Unwrap() error {
+	return e.err
+}
+
+// NewError returns an error with message msg that carries key-value pairs.
+// When the error is logged as a field value (e.g. ErrorKV("...", "error", err))
+// or with Err, its pairs are added to the entry.
+func NewError(msg string, keyvals ...any) error {
+	return &fieldsError{msg: msg, keyvals: keyvals}
+}
+
+// WithFieldsErr wraps err with key-value pairs. The message is unchanged and
+// errors.Is/As still see err. Returns nil if err is nil.
+func WithFieldsErr(err error, keyvals ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &fieldsError{err: err, keyvals: keyvals}
+}
+
+// ErrorFields returns the key-value pairs attached anywhere in err's tree,
+// outermost first. Like errors.As, it follows both Unwrap() error and
+// Unwrap() []error, so fields inside errors.Join are found too.
+func ErrorFields(err error) []any {
+	return appendErrorFields(nil, err)
+}
+
+// appendErrorFields walks err's tree depth-first in the order errors.As
+// does, appending the fields of each fieldsError.
+func appendErrorFields(fields []any, err error) []any {
+	for err != nil {
+		if fe, ok := err.(*fieldsError); ok {
+			fields = append(fields, fe.keyvals...)
+		}
+		if multi, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range multi.Unwrap() {
+				fields = appendErrorFields(fields, e)
+			}
+			return fields
+		}
+		err = errors.Unwrap(err)
+	}
+	return fields
+}
+
+// withErrorFields appends fields carried by error values in keyvals, skipping
+// keys that are already present.
+func withErrorFields(keyvals []any) []any {
+	var extra []any
+	for i := 1; i < len(keyvals); i += 2 {
+		if err, ok := keyvals[i].(error); ok {
+			extra = append(extra, ErrorFields(err)...)
+		}
+	}
+	return appendMissing(keyvals, extra)
+}
+
+// appendMissing returns keyvals followed by the pairs of extra whose keys are
+// not already present. keyvals is not modified.
+func appendMissing(keyvals, extra []any) []any {
+	if len(extra) == 0 {
+		return keyvals
+	}
+	merged := make([]any, 0, len(keyvals)+len(extra))
+	merged = append(merged, keyvals...)
+	for i := 0; i+1 < len(extra); i += 2 {
+		if !hasKey(merged, extra[i]) {
+			merged = append(merged, extra[i], extra[i+1])
+		}
+	}
+	return merged
+}
+
+// hasKey reports whether key appears as a key in keyvals.
+func hasKey(keyvals []any, key any) bool {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Err logs err at ERROR level with its attached fields and any extra key-value
+// pairs. Nil errors are ignored.
+// The caller function name and line number are automatically included.
+// Thread-safe for concurrent use.
+func Err(err error, keyvals ...any) {
+	if err == nil || !isLevelEnabled(ErrorLevel) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(2)
+	output(Error, ErrorLevel, caller, err.Error(), appendMissing(keyvals, ErrorFields(err)))
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package logger
+
+import "errors"
+
+// EventLogSink writes entries to the Windows Event Log. It is only
+// available on Windows.
+type EventLogSink struct{}
+
+// NewEventLogSink returns errors.ErrUnsupported on this platform.
+func NewEventLogSink(source string) (*EventLogSink, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (*EventLogSink) WriteEntry(e *Entry) error { return errors.ErrUnsupported }
+
+func (*EventLogSink) Close() error { return nil }
+
+// InstallEventSource returns errors.ErrUnsupported on this platform.
+func InstallEventSource(source string) error {
+	return errors.ErrUnsupported
+}
+
+// RemoveEventSource returns errors.ErrUnsupported on this platform.
+func RemoveEventSource(source string) error {
+	return errors.ErrUnsupported
+}
+
+// InitService initializes the logger for a service. On Windows production
+// mode logs to the Event Log; elsewhere it is InitWithConfig, since systemd
+// and other supervisors collect stdout/stderr themselves.
+func InitService(cfg Config, source string) error {
+	InitWithConfig(cfg)
+	return nil
+}
@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32                  = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSourceW  = advapi32.NewProc("RegisterEventSourceW")
+	procDeregisterEventSource = advapi32.NewProc("DeregisterEventSource")
+	procReportEventW          = advapi32.NewProc("ReportEventW")
+	procRegCreateKeyExW       = advapi32.NewProc("RegCreateKeyExW")
+	procRegSetValueExW        = advapi32.NewProc("RegSetValueExW")
+	procRegDeleteKeyW         = advapi32.NewProc("RegDeleteKeyW")
+)
+
+// Event Log entry types.
+const (
+	eventlogErrorType       = 0x0001
+	eventlogWarningType     = 0x0002
+	eventlogInformationType = 0x0004
+)
+
+// eventLogKey is the registry key holding Application event sources.
+const eventLogKey = `SYSTEM\CurrentControlSet\Services\EventLog\Application\`
+
+// EventLogSink writes entries to the Windows Event Log. DEBUG entries are
+// skipped; INFO maps to Information, WARN to Warning, and ERROR/FATAL to
+// Error events.
+type EventLogSink struct {
+	handle uintptr
+}
+
+// NewEventLogSink opens the Application event log for source. Register the
+// source once with InstallEventSource (which needs administrator rights);
+// unregistered sources still log, but Event Viewer shows a "description
+// cannot be found" note with each event.
+func NewEventLogSink(source string) (*EventLogSink, error) {
+	name, err := syscall.UTF16PtrFromString(source)
+	if err != nil {
+		return nil, err
+	}
+	h, _, err := procRegisterEventSourceW.Call(0, uintptr(unsafe.Pointer(name)))
+	if h == 0 {
+		return nil, fmt.Errorf("logger: RegisterEventSource %s: %w", source, err)
+	}
+	return &EventLogSink{handle: h}, nil
+}
+
+// WriteEntry reports e as an event with ID 1.
+func (s *EventLogSink) WriteEntry(e *Entry) error {
+	var typ uintptr
+	switch {
+	case e.Level == DebugLevel:
+		return nil
+	case e.Level == InfoLevel:
+		typ = eventlogInformationType
+	case e.Level == WarnLevel:
+		typ = eventlogWarningType
+	default:
+		typ = eventlogErrorType
+	}
+	msg, err := syscall.UTF16PtrFromString(fmt.Sprintf("[%s] %s%s", e.Caller, e.Message, encodeFields(e.Fields...)))
+	if err != nil {
+		return err
+	}
+	strs := []*uint16{msg}
+	r, _, err := procReportEventW.Call(s.handle, typ, 0, 1, 0, 1, 0, uintptr(unsafe.Pointer(&strs[0])), 0)
+	if r == 0 {
+		return fmt.Errorf("logger: ReportEvent: %w", err)
+	}
+	return nil
+}
+
+// Close releases the event source handle.
+func (s *EventLogSink) Close() error {
+	if s.handle == 0 {
+		return nil
+	}
+	procDeregisterEventSource.Call(s.handle)
+	s.handle = 0
+	return nil
+}
+
+// InstallEventSource registers source under the Application event log using
+// EventCreate.exe as its message file, so Event Viewer displays messages
+// verbatim. Run it from the service installer; it needs administrator rights.
+func InstallEventSource(source string) error {
+	keyName, err := syscall.UTF16PtrFromString(eventLogKey + source)
+	if err != nil {
+		return err
+	}
+	var key syscall.Handle
+	r, _, _ := procRegCreateKeyExW.Call(uintptr(syscall.HKEY_LOCAL_MACHINE), uintptr(unsafe.Pointer(keyName)),
+		0, 0, 0, syscall.KEY_WRITE, 0, uintptr(unsafe.Pointer(&key)), 0)
+	if r != 0 {
+		return fmt.Errorf("logger: creating event source key: %w", syscall.Errno(r))
+	}
+	defer syscall.RegCloseKey(key)
+
+	if err := setRegString(key, "EventMessageFile", `%SystemRoot%\System32\EventCreate.exe`, syscall.REG_EXPAND_SZ); err != nil {
+		return err
+	}
+	types := uint32(eventlogErrorType | eventlogWarningType | eventlogInformationType)
+	name, _ := syscall.UTF16PtrFromString("TypesSupported")
+	r, _, _ = procRegSetValueExW.Call(uintptr(key), uintptr(unsafe.Pointer(name)), 0, syscall.REG_DWORD,
+		uintptr(unsafe.Pointer(&types)), 4)
+	if r != 0 {
+		return fmt.Errorf("logger: setting TypesSupported: %w", syscall.Errno(r))
+	}
+	return nil
+}
+
+// RemoveEventSource deletes the registration made by InstallEventSource.
+func RemoveEventSource(source string) error {
+	keyName, err := syscall.UTF16PtrFromString(eventLogKey + source)
+	if err != nil {
+		return err
+	}
+	if r, _, _ := procRegDeleteKeyW.Call(uintptr(syscall.HKEY_LOCAL_MACHINE), uintptr(unsafe.Pointer(keyName))); r != 0 {
+		return fmt.Errorf("logger: removing event source: %w", syscall.Errno(r))
+	}
+	return nil
+}
+
+func setRegString(key syscall.Handle, name, value string, typ uintptr) error {
+	n, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+	v, err := syscall.UTF16FromString(value)
+	if err != nil {
+		return err
+	}
+	r, _, _ := procRegSetValueExW.Call(uintptr(key), uintptr(unsafe.Pointer(n)), 0, typ,
+		uintptr(unsafe.Pointer(&v[0])), uintptr(len(v)*2))
+	if r != 0 {
+		return fmt.Errorf("logger: setting %s: %w", name, syscall.Errno(r))
+	}
+	return nil
+}
+
+// InitService initializes the logger for a Windows service. In production
+// mode entries go to the Application event log under source. Services run
+// without a console, so console output is discarded when the process has
+// no usable stdout (the log file, if any, is still written).
+//
+// On other platforms InitService is InitWithConfig; systemd and other
+// supervisors collect stdout/stderr themselves.
+func InitService(cfg Config, source string) error {
+	if !hasConsole() && cfg.Fallback != FallbackFileOnly {
+		cfg.Fallback = FallbackDiscard
+	}
+	InitWithConfig(cfg)
+	if cfg.Mode != "production" {
+		return nil
+	}
+	sink, err := NewEventLogSink(source)
+	if err != nil {
+		return err
+	}
+	AddSink(sink)
+	return nil
+}
+
+// hasConsole reports whether stdout is a usable handle. Processes started
+// by the service control manager have none.
+func hasConsole() bool {
+	h, err := syscall.GetStdHandle(syscall.STD_OUTPUT_HANDLE)
+	return err == nil && h != 0 && h != syscall.InvalidHandle
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+)
+
+// EventKey is the field carrying the ID of entries logged with Event.
+const EventKey = "event"
+
+// EventTemplate is a registered event: the level it is logged at and its
+// message. Message may reference fields as {key}, filled from the values
+// passed to Event.
+type EventTemplate struct {
+	Level   Level
+	Message string
+	// Doc is the explanation shown by `journalctl -x`, set with
+	// DocumentEvent.
+	Doc string
+}
+
+var (
+	eventsMu sync.RWMutex
+	events   = map[string]EventTemplate{}
+)
+
+// RegisterEvent adds an event template under id. Registering an existing id
+// replaces its level and message:
+//
+//	logger.RegisterEvent("user_login", logger.InfoLevel, "user {user} logged in")
+//	logger.Event("user_login", "user", "alice", "method", "sso")
+//	// ... user alice logged in event=user_login user=alice method=sso
+func RegisterEvent(id string, level Level, message string) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	t := events[id]
+	t.Level, t.Message = level, message
+	events[id] = t
+}
+
+// LookupEvent returns the template registered under id.
+func LookupEvent(id string) (EventTemplate, bool) {
+	eventsMu.RLock()
+	defer eventsMu.RUnlock()
+	t, ok := events[id]
+	return t, ok
+}
+
+// Event logs the registered event id with structured key-value pairs. Every
+// entry carries event=id, so occurrences can be counted and searched by a
+// stable name even when the message wording changes. Unregistered IDs are
+// logged at INFO with the ID as the message. FATAL events exit like FatalKV.
+func Event(id string, keyvals ...any) {
+	t, ok := LookupEvent(id)
+	if !ok {
+		t = EventTemplate{Level: InfoLevel, Message: id}
+	}
+	if !isLevelEnabled(t.Level) {
+		if t.Level == FatalLevel {
+			exitFatal()
+		}
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(2)
+	fields := append([]any{EventKey, id}, keyvals...)
+	output(loggerFor(t.Level), t.Level, caller, expandTemplate(t.Message, keyvals), fields)
+	if t.Level == FatalLevel {
+		exitFatal()
+	}
+}
+
+// expandTemplate replaces {key} placeholders in msg with field values.
+// Placeholders without a matching field are left as they are.
+func expandTemplate(msg string, keyvals []any) string {
+	if !strings.Contains(msg, "{") {
+		return msg
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(msg, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(msg[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(msg[:start])
+		if v, ok := fieldValue(keyvals, msg[start+1:end]); ok {
+			b.WriteString(v)
+		} else {
+			b.WriteString(msg[start : end+1])
+		}
+		msg = msg[end+1:]
+	}
+	b.WriteString(msg)
+	return b.String()
+}
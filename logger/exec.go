@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// redacted replaces argument values listed in CommandConfig.Redact.
+const redacted = "[REDACTED]"
+
+// CommandConfig configures StartCommand and RunCommand.
+type CommandConfig struct {
+	// Name identifies the process in the cmd field. Defaults to the base
+	// name of the executable.
+	Name string
+	// Redact lists flags whose values are hidden in the logged arguments,
+	// e.g. "--password" covers both "--password x" and "--password=x".
+	Redact []string
+	// ParentSocket, when set, is passed to the process as LOGGER_PARENT so
+	// a child using ConnectParent sends its entries to ServeChildren there.
+	ParentSocket string
+}
+
+// Command is a subprocess started with StartCommand.
+type Command struct {
+	Cmd   *exec.Cmd
+	name  string
+	start time.Time
+}
+
+// StartCommand starts cmd and logs its start at INFO with cmd, pid, and the
+// redacted args, or its failure to start at ERROR. Call Wait to log its exit.
+func StartCommand(cmd *exec.Cmd, cfg CommandConfig) (*Command, error) {
+	return startCommand(cmd, cfg, getCallerInfo(2))
+}
+
+func startCommand(cmd *exec.Cmd, cfg CommandConfig, caller string) (*Command, error) {
+	c := &Command{Cmd: cmd, name: cfg.Name}
+	if c.name == "" {
+		c.name = filepath.Base(cmd.Path)
+	}
+	if cfg.ParentSocket != "" {
+		if cmd.Env == nil {
+			cmd.Env = cmd.Environ()
+		}
+		cmd.Env = append(cmd.Env, ParentEnv+"="+cfg.ParentSocket)
+	}
+	args := strings.Join(redactArgs(cmd.Args[min(1, len(cmd.Args)):], cfg.Redact), " ")
+
+	if err := cmd.Start(); err != nil {
+		logProcess(ErrorLevel, caller, "process failed to start", "cmd", c.name, "args", args, "error", err)
+		return nil, err
+	}
+	c.start = time.Now()
+	logProcess(InfoLevel, caller, "process started", "cmd", c.name, "pid", cmd.Process.Pid, "args", args)
+	return c, nil
+}
+
+// Wait waits for the process to exit and logs its exit code and duration:
+// at INFO for a zero exit code, WARN for a non-zero one, and ERROR with the
+// signal name when the process was killed by a signal.
+func (c *Command) Wait() error {
+	return c.wait(getCallerInfo(2))
+}
+
+func (c *Command) wait(caller string) error {
+	err := c.Cmd.Wait()
+	duration := time.Since(c.start).Round(time.Millisecond)
+	keyvals := []any{"cmd", c.name, "pid", c.Cmd.Process.Pid}
+
+	state := c.Cmd.ProcessState
+	var exitErr *exec.ExitError
+	sig, killed := signaled(state)
+	switch {
+	case state == nil:
+		logProcess(ErrorLevel, caller, "process wait failed", append(keyvals, "error", err)...)
+	case killed:
+		keyvals = append(keyvals, "exit_code", state.ExitCode(), "signal", sig, "duration", duration)
+		logProcess(ErrorLevel, caller, "process killed", keyvals...)
+	case state.ExitCode() != 0:
+		logProcess(WarnLevel, caller, "process exited", append(keyvals, "exit_code", state.ExitCode(), "duration", duration)...)
+	case err != nil && !errors.As(err, &exitErr):
+		// the process exited cleanly but copying its output failed
+		logProcess(WarnLevel, caller, "process exited", append(keyvals, "exit_code", 0, "duration", duration, "error", err)...)
+	default:
+		logProcess(InfoLevel, caller, "process exited", append(keyvals, "exit_code", 0, "duration", duration)...)
+	}
+	return err
+}
+
+// RunCommand starts cmd and waits for it, logging both like StartCommand
+// and Wait.
+func RunCommand(cmd *exec.Cmd, cfg CommandConfig) error {
+	caller := getCallerInfo(2)
+	c, err := startCommand(cmd, cfg, caller)
+	if err != nil {
+		return err
+	}
+	return c.wait(caller)
+}
+
+// redactArgs returns a copy of args with the values of the given flags
+// replaced by "[REDACTED]".
+func redactArgs(args, flags []string) []string {
+	out := make([]string, len(args))
+	copy(out, args)
+	for i := 0; i < len(out); i++ {
+		for _, flag := range flags {
+			switch {
+			case out[i] == flag && i+1 < len(out):
+				i++
+				out[i] = redacted
+			case strings.HasPrefix(out[i], flag+"="):
+				out[i] = flag + "=" + redacted
+			default:
+				continue
+			}
+			break
+		}
+	}
+	return out
+}
+
+// logProcess writes a process lifecycle entry.
+func logProcess(level Level, caller, msg string, keyvals ...any) {
+	if !isLevelEnabled(level) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(level), level, caller, msg, keyvals)
+}
@@ -0,0 +1,11 @@
+//go:build plan9
+
+package logger
+
+import "os"
+
+// signaled is not supported on this platform; processes ended by a note
+// are reported by their exit code.
+func signaled(state *os.ProcessState) (string, bool) {
+	return "", false
+}
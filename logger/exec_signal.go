@@ -0,0 +1,24 @@
+//go:build !plan9
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// signaled returns the name of the signal that terminated the process, if
+// any.
+func signaled(state *os.ProcessState) (string, bool) {
+	if state == nil {
+		return "", false
+	}
+	ws, ok := state.Sys().(interface {
+		Signaled() bool
+		Signal() syscall.Signal
+	})
+	if !ok || !ws.Signaled() {
+		return "", false
+	}
+	return ws.Signal().String(), true
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	// exemptions is Config.Exemptions
+	exemptions []Exemption
+	// exemptionsOn is set when exemptions is not empty, for checks made
+	// without logMutex
+	exemptionsOn atomic.Bool
+)
+
+// Exemption matches entries that volume controls must never drop, such as
+// audit records or payment events:
+//
+//	logger.InitWithConfig(logger.Config{
+//	    Mode:                "production",
+//	    MaxEntriesPerSecond: 500,
+//	    Exemptions: []logger.Exemption{
+//	        {Field: "audit", Value: "true"},
+//	        {Logger: "payments"},
+//	    },
+//	})
+//
+// Matching entries bypass MaxEntriesPerSecond, the sampling and drop
+// filters of WatchRemotePolicy, and StartGovernor, and wait for room in a
+// full sink queue instead of being dropped. Every set condition must match;
+// an Exemption with none matches nothing.
+type Exemption struct {
+	// Logger matches entries of the named logger and its children.
+	Logger string
+	// Message matches entries with exactly this message.
+	Message string
+	// Field matches entries with this field and, when Value is set, with
+	// that value as printed.
+	Field string
+	Value string
+}
+
+// exempt reports whether e matches one of Config.Exemptions.
+func exempt(e *Entry) bool {
+	for _, x := range exemptions {
+		if x.matches(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (x Exemption) matches(e *Entry) bool {
+	if x == (Exemption{}) {
+		return false
+	}
+	if x.Logger != "" {
+		name, _ := fieldValue(e.Fields, LoggerKey)
+		if name != x.Logger && !strings.HasPrefix(name, x.Logger+".") {
+			return false
+		}
+	}
+	if x.Message != "" && e.Message != x.Message {
+		return false
+	}
+	if x.Field != "" {
+		value, ok := fieldValue(e.Fields, x.Field)
+		if !ok || (x.Value != "" && value != x.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// volumeDropped reports whether StartGovernor, the remote policy, or
+// MaxEntriesPerSecond drops e. Callers must hold logMutex.
+func volumeDropped(e *Entry) bool {
+	if len(exemptions) > 0 && exempt(e) {
+		e.trace.step("filter", "", "exempt from volume controls")
+		return false
+	}
+	switch {
+	case governorDropped(e.Level):
+		e.trace.step("governor", "", "dropped")
+	case policyDropped(e):
+		e.trace.step("remote policy", "", "dropped")
+	case rateLimited(e):
+		e.trace.step("rate limit", "", "dropped")
+	default:
+		return false
+	}
+	return true
+}
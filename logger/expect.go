@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// ExpectHandle is a liveness check started with Expect.
+type ExpectHandle struct {
+	name     string
+	caller   string
+	interval time.Duration
+
+	mu       sync.Mutex
+	timer    *time.Timer
+	lastSeen time.Time
+	silent   bool
+	stopped  bool
+}
+
+// Expect starts a liveness check for a background loop: unless Kick is
+// called at least once per interval, an ERROR "goroutine silent" entry is
+// logged with the time the loop was last seen. The next Kick logs a WARN
+// "goroutine resumed" with how long it was silent:
+//
+//	alive := logger.Expect("poller", time.Minute)
+//	defer alive.Stop()
+//	for {
+//	    poll()
+//	    alive.Kick()
+//	    time.Sleep(10 * time.Second)
+//	}
+//	// [ERROR] [main.runPoller:40] goroutine silent name=poller last_seen=2026-03-02T09:14:03Z interval=1m0s
+//
+// Entries are attributed to where Expect was called.
+func Expect(name string, interval time.Duration) *ExpectHandle {
+	h := &ExpectHandle{name: name, caller: getCallerInfo(2), interval: interval, lastSeen: time.Now()}
+	h.timer = time.AfterFunc(interval, h.expire)
+	return h
+}
+
+// Kick records that the loop is alive and restarts the interval.
+func (h *ExpectHandle) Kick() {
+	h.mu.Lock()
+	if h.stopped {
+		h.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	silentFor, wasSilent := now.Sub(h.lastSeen), h.silent
+	h.lastSeen, h.silent = now, false
+	h.timer.Reset(h.interval)
+	h.mu.Unlock()
+
+	if wasSilent {
+		h.log(WarnLevel, "goroutine resumed", "name", h.name, "silent_for", silentFor.Round(time.Millisecond))
+	}
+}
+
+// Stop ends the check, e.g. when the loop exits normally.
+func (h *ExpectHandle) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopped = true
+	h.timer.Stop()
+}
+
+// expire runs when an interval passes without a Kick.
+func (h *ExpectHandle) expire() {
+	h.mu.Lock()
+	if h.stopped || h.silent || time.Since(h.lastSeen) < h.interval {
+		h.mu.Unlock()
+		return
+	}
+	h.silent = true
+	lastSeen := h.lastSeen
+	h.mu.Unlock()
+
+	h.log(ErrorLevel, "goroutine silent", "name", h.name, "last_seen", lastSeen.Format(time.RFC3339), "interval", h.interval)
+}
+
+func (h *ExpectHandle) log(level Level, msg string, keyvals ...any) {
+	if !isLevelEnabled(level) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(level), level, h.caller, msg, keyvals)
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+)
+
+// ParentEnv names the environment variable holding the unix socket path a
+// child process connects to with ConnectParent.
+const ParentEnv = "LOGGER_PARENT"
+
+// Fields added by child processes to every forwarded entry.
+const (
+	ChildKey    = "child"
+	ChildPIDKey = "child_pid"
+)
+
+// ErrNoParent is returned by ConnectParent when ParentEnv is not set.
+var ErrNoParent = errors.New("logger: " + ParentEnv + " is not set")
+
+// ServeChildren accepts connections from child processes on ln and writes
+// their entries through this process's outputs, keeping each entry's time,
+// level, and caller and tagging it with the child's identifier and PID:
+//
+//	ln, _ := net.Listen("unix", "/run/supervisor/logs.sock")
+//	go logger.ServeChildren(ln)
+//	cmd := exec.Command("worker")
+//	cmd.Env = append(os.Environ(), logger.ParentEnv+"=/run/supervisor/logs.sock")
+//
+// Child entries pass this process's LOGGER_LEVELS, routes, and sinks, but a
+// FATAL entry from a child does not exit the parent. ServeChildren blocks
+// until ln is closed, then disconnects the children and returns the Accept
+// error once every entry already received has been written.
+func ServeChildren(ln net.Listener) error {
+	var (
+		mu    sync.Mutex
+		conns = map[net.Conn]struct{}{}
+		wg    sync.WaitGroup
+	)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			mu.Lock()
+			for c := range conns {
+				c.Close()
+			}
+			mu.Unlock()
+			wg.Wait()
+			return err
+		}
+		mu.Lock()
+		conns[conn] = struct{}{}
+		mu.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serveChild(conn)
+			mu.Lock()
+			delete(conns, conn)
+			mu.Unlock()
+		}()
+	}
+}
+
+// serveChild logs every JSON line read from conn until it closes, through
+// the same sanitizing, global fields, and hooks as local entries.
+func serveChild(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		e, err := ParseLine(scanner.Text())
+		if err != nil || !isLevelEnabled(e.Level) {
+			continue
+		}
+		logChildEntry(e)
+	}
+}
+
+// logChildEntry writes an entry read from a child process, keeping its time.
+func logChildEntry(e *Entry) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	outputAt(context.Background(), loggerFor(e.Level), e.Level, e.Time, e.Caller, e.Message, e.Fields)
+}
+
+// ConnectParent forwards this process's entries to the parent process
+// serving ParentEnv with ServeChildren. Entries carry child=Identifier()
+// and child_pid fields, and are still written to the local outputs. The
+// connection is closed by Close.
+func ConnectParent() error {
+	path := os.Getenv(ParentEnv)
+	if path == "" {
+		return ErrNoParent
+	}
+	sink, err := NewSocketSink("unix", path, childEncoder{name: Identifier(), pid: os.Getpid()})
+	if err != nil {
+		return err
+	}
+	AddSink(sink)
+	return nil
+}
+
+// childEncoder JSON-encodes entries with the child's attribution fields.
+type childEncoder struct {
+	name string
+	pid  int
+}
+
+func (c childEncoder) Encode(e *Entry) ([]byte, error) {
+	tagged := *e
+	tagged.Fields = append(append([]any{}, e.Fields...), ChildKey, c.name, ChildPIDKey, c.pid)
+	return JSONEncoder{}.Encode(&tagged)
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// FDWarnRatio is the fraction of the open file limit (RLIMIT_NOFILE) at
+// which WatchFDs warns.
+const FDWarnRatio = 0.8
+
+// fdWatchCaller is shown on entries from WatchFDs.
+const fdWatchCaller = "logger.WatchFDs"
+
+var (
+	// fileFailures counts failed log file writes since the last WatchFDs
+	// check, and lastFileError is the most recent of them
+	fileFailMu    sync.Mutex
+	fileFailures  int
+	lastFileError error
+)
+
+// fileErrorRecorder records failed writes to the log file for WatchFDs.
+type fileErrorRecorder struct {
+	w io.Writer
+}
+
+func (r fileErrorRecorder) Write(p []byte) (int, error) {
+	n, err := r.w.Write(p)
+	if err != nil {
+		fileFailMu.Lock()
+		fileFailures++
+		lastFileError = err
+		fileFailMu.Unlock()
+	}
+	return n, err
+}
+
+// WatchFDs checks now and every interval how many file descriptors the
+// process has open, and whether writes to the Config.FilePath log file are
+// failing, e.g. with EMFILE or ENOSPC. It returns a function that stops the
+// checks. A WARN is logged when the open count reaches FDWarnRatio of the
+// soft limit, and again only after it has dropped below; failed file writes
+// are reported at WARN on every check that saw new failures:
+//
+//	stop := logger.WatchFDs(time.Minute)
+//	defer stop()
+//	// [WARN] [logger.WatchFDs] open file descriptors near limit open=830 limit=1024
+//	// [WARN] [logger.WatchFDs] log file writes failing failures=212 error=write /var/log/app.log: no space left on device
+//
+// Counting descriptors is only supported on Unix systems; elsewhere only
+// file write failures are reported.
+func WatchFDs(interval time.Duration) (stop func()) {
+	var w fdWatch
+	return runWatchdog(interval, w.check)
+}
+
+// fdWatch is the state of one WatchFDs goroutine.
+type fdWatch struct {
+	// near is set while the open count is at or above the warning ratio
+	near bool
+}
+
+func (w *fdWatch) check() {
+	if open, limit, err := openFDs(); err == nil {
+		w.report(open, limit)
+	}
+	fileFailMu.Lock()
+	n, err := fileFailures, lastFileError
+	fileFailures = 0
+	fileFailMu.Unlock()
+	if n > 0 {
+		watchdogLog(WarnLevel, fdWatchCaller, "log file writes failing", "failures", n, "error", err)
+	}
+}
+
+// report warns when open first reaches FDWarnRatio of limit. A limit of 0
+// means there is none.
+func (w *fdWatch) report(open, limit int) {
+	near := limit > 0 && float64(open) >= FDWarnRatio*float64(limit)
+	if near && !w.near {
+		watchdogLog(WarnLevel, fdWatchCaller, "open file descriptors near limit", "open", open, "limit", limit)
+	}
+	w.near = near
+}
@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logger
+
+import "errors"
+
+// openFDs is not supported on this platform.
+func openFDs() (open, limit int, err error) {
+	return 0, 0, errors.ErrUnsupported
+}
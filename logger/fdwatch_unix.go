@@ -0,0 +1,31 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// openFDs returns the number of open file descriptors and the soft
+// RLIMIT_NOFILE, or a limit of 0 when it is unlimited.
+func openFDs() (open, limit int, err error) {
+	var rl syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rl); err != nil {
+		return 0, 0, err
+	}
+	if rl.Cur <= 1<<31 {
+		limit = int(rl.Cur)
+	}
+	entries, err := os.ReadDir("/dev/fd")
+	if errors.Is(err, syscall.EMFILE) {
+		// no descriptor left to list them with
+		return limit, limit, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	// one of the entries is the descriptor reading the directory
+	return len(entries) - 1, limit, nil
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// FileSink appends encoded entries to a file, one per line for text
+// encoders. With a binary encoder such as MsgpackEncoder it writes a
+// binary log file: a header naming the encoding followed by length-prefixed
+// records, which OpenLogFile reads back. It complements the main log file
+// when a second copy in another format is wanted, e.g. JSON for a shipper
+// alongside the human-readable file.
+type FileSink struct {
+	f       *os.File
+	enc     Encoder
+	buf     []byte
+	cleanup runtime.Cleanup
+
+	// path is the path partitioned by NewHourlyFileSink, and hour the
+	// partition f belongs to
+	path string
+	hour time.Time
+}
+
+// NewFileSink opens path for appending, creating it if needed. A nil enc
+// uses JSONEncoder. Appending binary records to a file that was not
+// written with the same encoding is an error.
+func NewFileSink(path string, enc Encoder) (*FileSink, error) {
+	if enc == nil {
+		enc = JSONEncoder{}
+	}
+	f, err := openSinkFile(path, enc)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileSink{f: f, enc: enc}
+	s.cleanup = runtime.AddCleanup(s, warnUnclosedFileSink, path)
+	return s, nil
+}
+
+// NewHourlyFileSink is like NewFileSink but writes each entry to the file
+// for the UTC hour of its time, named by inserting the hour before the
+// extension of path:
+//
+//	sink, err := logger.NewHourlyFileSink("/var/log/app/app.log", nil)
+//	// /var/log/app/app-2024050113.log, /var/log/app/app-2024050114.log, ...
+//
+// Files are never renamed, so shippers that tail a file by name read each
+// partition to its end, and append-only analytics can load finished hours
+// as they are. A file is opened when the first entry of its hour arrives
+// and the previous one is closed; removing old partitions is left to the
+// application.
+func NewHourlyFileSink(path string, enc Encoder) (*FileSink, error) {
+	if enc == nil {
+		enc = JSONEncoder{}
+	}
+	hour := time.Now().UTC().Truncate(time.Hour)
+	f, err := openSinkFile(hourlyPath(path, hour), enc)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileSink{f: f, enc: enc, path: path, hour: hour}
+	s.cleanup = runtime.AddCleanup(s, warnUnclosedFileSink, path)
+	return s, nil
+}
+
+// openSinkFile opens path for appending, writing or checking the header of
+// binary encodings.
+func openSinkFile(path string, enc Encoder) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if b, ok := enc.(binaryEncoder); ok {
+		if err := prepareBinaryLogFile(f, b.binaryFormat()); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// hourlyPath returns the partition of path for hour, e.g.
+// app-2024050113.log for app.log.
+func hourlyPath(path string, hour time.Time) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + hour.Format("2006010215") + ext
+}
+
+// WriteEntry encodes e and appends it to the file.
+func (s *FileSink) WriteEntry(e *Entry) error {
+	if s.path != "" {
+		if err := s.partition(e.Time.UTC().Truncate(time.Hour)); err != nil {
+			return err
+		}
+	}
+	data, err := s.enc.Encode(e)
+	if err != nil {
+		return err
+	}
+	s.buf = appendFrame(s.buf[:0], s.enc, data)
+	_, err = s.f.Write(s.buf)
+	return err
+}
+
+// partition switches to the file for hour. If it cannot be opened, the
+// current file is kept for later entries.
+func (s *FileSink) partition(hour time.Time) error {
+	if hour.Equal(s.hour) {
+		return nil
+	}
+	f, err := openSinkFile(hourlyPath(s.path, hour), s.enc)
+	if err != nil {
+		return err
+	}
+	old := s.f
+	s.f, s.hour = f, hour
+	return old.Close()
+}
+
+// Close closes the file. A FileSink garbage collected without Close logs
+// a WARN entry with event UnclosedEvent.
+func (s *FileSink) Close() error {
+	s.cleanup.Stop()
+	return s.f.Close()
+}
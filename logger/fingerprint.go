@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// FingerprintKey is the field name used by Config.ErrorFingerprints.
+const FingerprintKey = "fingerprint"
+
+// errorFingerprints is Config.ErrorFingerprints
+var errorFingerprints bool
+
+// messageVariables matches the parts of a formatted message that vary
+// between occurrences of the same error: quoted strings, hex IDs and UUIDs,
+// and numbers.
+var messageVariables = regexp.MustCompile(`"[^"]*"|'[^']*'|\b[0-9a-fA-F]{8,}(-[0-9a-fA-F]{4,})*\b|\d+(\.\d+)?`)
+
+// Fingerprint returns the entry's FingerprintKey field, set on ERROR and
+// FATAL entries when Config.ErrorFingerprints is on, or "" when it has none.
+func (e *Entry) Fingerprint() string {
+	for i := 0; i+1 < len(e.Fields); i += 2 {
+		if e.Fields[i] == FingerprintKey {
+			fp, _ := e.Fields[i+1].(string)
+			return fp
+		}
+	}
+	return ""
+}
+
+// errorFingerprint returns a stable 16 hex digit fingerprint for an error
+// logged by caller with msg. The caller's line number is left out, so the
+// fingerprint survives edits elsewhere in the file, and the message is
+// reduced to its template: the event ID of an entry logged with one, or
+// the message with numbers, IDs, and quoted strings replaced.
+func errorFingerprint(caller, msg string, keyvals []any) string {
+	if i := strings.LastIndexByte(caller, ':'); i >= 0 {
+		caller = caller[:i]
+	}
+	template := messageVariables.ReplaceAllString(msg, "?")
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == EventKey {
+			if id, ok := keyvals[i+1].(string); ok {
+				template = "event " + id
+			}
+			break
+		}
+	}
+	sum := sha256.Sum256([]byte(caller + "\x00" + template))
+	return hex.EncodeToString(sum[:8])
+}
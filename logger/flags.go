@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// FlagsEnv names the environment variable listing flags enabled at startup,
+// e.g. LOGGER_FLAGS=wire-dump,sql-trace.
+const FlagsEnv = "LOGGER_FLAGS"
+
+var (
+	flagsMu sync.Mutex
+	flags   = map[string]*FlagHandle{}
+)
+
+// FlagHandle is a named boolean switch for a verbose subsystem, toggled at
+// runtime independently of LOGGER_LEVELS.
+type FlagHandle struct {
+	name string
+	on   atomic.Bool
+}
+
+// Flag returns the handle for name, creating it on first use. A new flag is
+// on when FlagsEnv lists it. Guard high-volume debug blocks with On, which
+// is a single atomic load:
+//
+//	var wireDump = logger.Flag("wire-dump")
+//
+//	if wireDump.On() {
+//	    logger.DebugKV("frame", "bytes", hex.EncodeToString(frame))
+//	}
+//
+// Flags can be flipped with Set, FlagsHandler, or ToggleFlagsOnSignal.
+func Flag(name string) *FlagHandle {
+	flagsMu.Lock()
+	defer flagsMu.Unlock()
+	f, ok := flags[name]
+	if !ok {
+		f = &FlagHandle{name: name}
+		f.on.Store(slices.Contains(envFlags(), name))
+		flags[name] = f
+	}
+	return f
+}
+
+// Name returns the flag's name.
+func (f *FlagHandle) Name() string {
+	return f.name
+}
+
+// On reports whether the flag is set.
+func (f *FlagHandle) On() bool {
+	return f.on.Load()
+}
+
+// Set turns the flag on or off, logging the change at INFO.
+func (f *FlagHandle) Set(on bool) {
+	f.set(on, getCallerInfo(2))
+}
+
+func (f *FlagHandle) set(on bool, caller string) {
+	if f.on.Swap(on) == on || !isLevelEnabled(InfoLevel) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(InfoLevel), InfoLevel, caller, "flag changed", []any{"flag", f.name, "on", on})
+}
+
+// Flags returns the state of every flag created so far.
+func Flags() map[string]bool {
+	flagsMu.Lock()
+	defer flagsMu.Unlock()
+	out := make(map[string]bool, len(flags))
+	for name, f := range flags {
+		out[name] = f.On()
+	}
+	return out
+}
+
+// FlagsHandler serves the flags as "name=true|false" lines on GET and sets
+// them on POST from form values, e.g.
+//
+//	curl -d wire-dump=true localhost:6060/debug/flags
+//
+// Unknown names are created, so a flag can be set before the code reading it
+// first runs. Mount it on an admin-only listener.
+func FlagsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPost, http.MethodPut:
+			if err := r.ParseForm(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			caller := getCallerInfo(1)
+			for name, values := range r.PostForm {
+				on, err := strconv.ParseBool(values[len(values)-1])
+				if err != nil {
+					http.Error(w, fmt.Sprintf("flag %s: invalid value %q", name, values[len(values)-1]), http.StatusBadRequest)
+					return
+				}
+				Flag(name).set(on, caller)
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		state := Flags()
+		names := make([]string, 0, len(state))
+		for name := range state {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, name := range names {
+			fmt.Fprintf(w, "%s=%t\n", name, state[name])
+		}
+	})
+}
+
+// ToggleFlagsOnSignal flips the named flags each time sig is received, e.g.
+// SIGUSR2, and returns a function that stops listening.
+func ToggleFlagsOnSignal(sig os.Signal, names ...string) (stop func()) {
+	handles := make([]*FlagHandle, len(names))
+	for i, name := range names {
+		handles[i] = Flag(name)
+	}
+	caller := getCallerInfo(2)
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sig)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				for _, f := range handles {
+					f.set(!f.On(), caller)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// envFlags returns the names listed in FlagsEnv.
+func envFlags() []string {
+	var names []string
+	for _, name := range strings.Split(os.Getenv(FlagsEnv), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
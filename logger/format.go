@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+)
+
+// Format selects how console and file lines are encoded.
+type Format int
+
+const (
+	// FormatText writes the classic "[LEVEL] time [caller] msg" lines, or
+	// the configured Layout.
+	FormatText Format = iota
+	// FormatJSON writes one JSON object per line, as FallbackJSON does.
+	FormatJSON
+)
+
+func (f Format) String() string {
+	if f == FormatJSON {
+		return "json"
+	}
+	return "text"
+}
+
+// textFormat records the prefixes InitWithConfig chose for text output, so
+// SetFormat can switch back to them.
+var textFormat struct {
+	consoleFlags, fileFlags int
+	color                   bool
+}
+
+// consoleRouting records the console loggers InitWithConfig created and the
+// writer of each level in either format, so SetFormat can move WARN and
+// above between stderr and stdout as InitWithConfig would have.
+var consoleRouting struct {
+	loggers map[Level]*log.Logger
+	outputs [2]map[Level]io.Writer // indexed by Format
+}
+
+// CurrentFormat returns the format of console and file output.
+func CurrentFormat() Format {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	if jsonOutput {
+		return FormatJSON
+	}
+	return FormatText
+}
+
+// SetFormat switches console and file output between text and JSON,
+// effective from the next entry, e.g. to attach a parser to a live
+// process. A change is logged at INFO in the new format. The next
+// InitWithConfig restores the configured format.
+func SetFormat(f Format) {
+	setFormat(f, getCallerInfo(2))
+}
+
+func setFormat(f Format, caller string) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	if jsonOutput == (f == FormatJSON) {
+		return
+	}
+	jsonOutput = f == FormatJSON
+	for level := DebugLevel; level <= FatalLevel; level++ {
+		if l := loggerFor(level); l.Writer() != io.Discard {
+			// loggers replaced since InitWithConfig, e.g. by tests, keep their writer
+			if out := consoleRouting.outputs[f][level]; out != nil && l == consoleRouting.loggers[level] {
+				l.SetOutput(out)
+			}
+			prefix, flags := "", 0
+			if !jsonOutput && layout == nil && journalStyle == JournalDefault {
+				prefix, flags = levelPrefix(level, textFormat.color), textFormat.consoleFlags
+			}
+			l.SetPrefix(prefix)
+			l.SetFlags(flags)
+		}
+		if fl := fileLoggers[level]; fl != nil {
+			prefix, flags := "", 0
+			if !jsonOutput && layout == nil {
+				prefix, flags = levelPrefix(level, false), textFormat.fileFlags
+			}
+			fl.SetPrefix(prefix)
+			fl.SetFlags(flags)
+		}
+	}
+	if enabledLevels()[InfoLevel] {
+		output(loggerFor(InfoLevel), InfoLevel, caller, "log format changed", []any{"format", f.String()})
+	}
+}
+
+// FormatHandler serves the current format ("text" or "json") on GET and
+// sets it on POST from the format form value, e.g.
+//
+//	curl -d format=json localhost:6060/debug/log-format
+//
+// Mount it on an admin-only listener.
+func FormatHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPost, http.MethodPut:
+			if err := r.ParseForm(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			switch value := strings.ToLower(r.PostForm.Get("format")); value {
+			case "json":
+				setFormat(FormatJSON, getCallerInfo(1))
+			case "text":
+				setFormat(FormatText, getCallerInfo(1))
+			default:
+				http.Error(w, fmt.Sprintf("invalid format %q (want text or json)", value), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, CurrentFormat())
+	})
+}
+
+// ToggleFormatOnSignal switches between text and JSON each time sig is
+// received, e.g. SIGUSR2, and returns a function that stops listening.
+func ToggleFormatOnSignal(sig os.Signal) (stop func()) {
+	caller := getCallerInfo(2)
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sig)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				next := FormatJSON
+				if CurrentFormat() == FormatJSON {
+					next = FormatText
+				}
+				setFormat(next, caller)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
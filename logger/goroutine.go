@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// RestartPolicy controls whether SafeGo runs a function again after it
+// panicked.
+type RestartPolicy struct {
+	// MaxRestarts is how many times the function is restarted; negative
+	// restarts it without limit and zero never.
+	MaxRestarts int
+	// Backoff is the delay before the first restart, doubling for each
+	// further one up to MaxBackoff. They default to one second and one
+	// minute.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// Go runs fn in a new goroutine, recovering a panic and logging it at ERROR
+// with the panic value and stack instead of crashing the process. The
+// entry's caller is where Go was called:
+//
+//	logger.Go(func() { consume(queue) })
+//	// [ERROR] [main.startWorkers:31] goroutine panicked panic=runtime error: index out of range [3] with length 3 stack=goroutine 7 [running]:...
+func Go(fn func()) {
+	go runRecovered("", getCallerInfo(2), RestartPolicy{}, fn)
+}
+
+// SafeGo is Go for long-running background loops: entries carry the
+// goroutine name, and fn is restarted after a panic as policy allows, with
+// restarts counting the restarts so far and restart_in the delay before
+// the next one:
+//
+//	logger.SafeGo("poller", logger.RestartPolicy{MaxRestarts: -1}, poll)
+//	// [ERROR] [main.main:57] goroutine panicked goroutine=poller panic=connection reset restarts=0 restart_in=1s stack=...
+func SafeGo(name string, policy RestartPolicy, fn func()) {
+	if policy.Backoff <= 0 {
+		policy.Backoff = time.Second
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = time.Minute
+	}
+	go runRecovered(name, getCallerInfo(2), policy, fn)
+}
+
+// runRecovered calls fn until it returns without panicking or policy allows
+// no further restarts.
+func runRecovered(name, caller string, policy RestartPolicy, fn func()) {
+	backoff := policy.Backoff
+	for restarts := 0; ; restarts++ {
+		value, stack, panicked := callRecovered(fn)
+		if !panicked {
+			return
+		}
+		restart := policy.MaxRestarts < 0 || restarts < policy.MaxRestarts
+		var keyvals []any
+		if name != "" {
+			keyvals = append(keyvals, "goroutine", name)
+		}
+		keyvals = append(keyvals, "panic", fmt.Sprint(value))
+		if policy.MaxRestarts != 0 {
+			keyvals = append(keyvals, "restarts", restarts)
+		}
+		if restart {
+			keyvals = append(keyvals, "restart_in", backoff)
+		}
+		logPanic(caller, append(keyvals, "stack", stack))
+		if !restart {
+			return
+		}
+		time.Sleep(backoff)
+		backoff = min(2*backoff, policy.MaxBackoff)
+	}
+}
+
+// callRecovered calls fn and returns the value and stack of a panic.
+func callRecovered(fn func()) (value any, stack string, panicked bool) {
+	defer func() {
+		if value = recover(); value != nil {
+			stack, panicked = strings.TrimSpace(string(debug.Stack())), true
+		}
+	}()
+	fn()
+	return nil, "", false
+}
+
+func logPanic(caller string, keyvals []any) {
+	if !isLevelEnabled(ErrorLevel) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(Error, ErrorLevel, caller, "goroutine panicked", keyvals)
+}
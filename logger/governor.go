@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// GovernorEvent is the event ID of the entries written when the governor
+// engages and disengages.
+const GovernorEvent = "logger.governor"
+
+// governorCaller is shown on entries from StartGovernor.
+const governorCaller = "logger.StartGovernor"
+
+var (
+	// governorMin is the lowest level written while the governor is
+	// engaged, and DebugLevel otherwise
+	governorMin atomic.Int32
+	// governed counts the entries suppressed while it is engaged
+	governed atomic.Int64
+
+	// sinkWriteNanos and sinkWrites add up the time spent in sink writes
+	sinkWriteNanos atomic.Int64
+	sinkWrites     atomic.Int64
+)
+
+// GovernorConfig configures StartGovernor. At least one of QueueDepth and
+// WriteLatency must be set for the governor to engage.
+type GovernorConfig struct {
+	// QueueDepth is the number of entries waiting in sink queues at which
+	// the governor engages.
+	QueueDepth int
+	// WriteLatency is the average sink write time over an interval at which
+	// the governor engages.
+	WriteLatency time.Duration
+	// MinLevel is the lowest level written while engaged. Defaults to
+	// InfoLevel.
+	MinLevel Level
+	// Interval is the time between checks. Defaults to one second.
+	Interval time.Duration
+}
+
+// StartGovernor checks sink queue depth and write latency every
+// cfg.Interval and, when either crosses its threshold, raises the lowest
+// level written to cfg.MinLevel until both have fallen below half of their
+// thresholds, degrading gracefully instead of dropping entries from full
+// queues. Engaging is logged at WARN and disengaging at INFO:
+//
+//	stop := logger.StartGovernor(logger.GovernorConfig{QueueDepth: 512, WriteLatency: 50 * time.Millisecond})
+//	defer stop()
+//	// [WARN] [logger.StartGovernor] log pressure, raising minimum level event=logger.governor min_level=INFO queued=530 write_latency=12ms
+//	// [INFO] [logger.StartGovernor] log pressure relieved event=logger.governor suppressed=18230
+//
+// The returned function stops the checks and disengages the governor.
+func StartGovernor(cfg GovernorConfig) (stop func()) {
+	if cfg.MinLevel == DebugLevel {
+		cfg.MinLevel = InfoLevel
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	g := &governor{cfg: cfg, nanos: sinkWriteNanos.Load(), writes: sinkWrites.Load()}
+	stopChecks := runWatchdog(cfg.Interval, g.check)
+	return func() {
+		stopChecks()
+		if g.engaged {
+			g.disengage()
+		}
+	}
+}
+
+// governor holds the state of one StartGovernor loop.
+type governor struct {
+	cfg           GovernorConfig
+	engaged       bool
+	nanos, writes int64 // sink write totals at the previous check
+}
+
+// check engages or disengages the governor from the queue depth and the
+// average sink write time since the previous check.
+func (g *governor) check() {
+	logMutex.Lock()
+	queued := 0
+	for _, w := range workers {
+		queued += w.queued()
+	}
+	logMutex.Unlock()
+	nanos, writes := sinkWriteNanos.Load(), sinkWrites.Load()
+	var latency time.Duration
+	if writes > g.writes {
+		latency = time.Duration((nanos - g.nanos) / (writes - g.writes))
+	}
+	g.nanos, g.writes = nanos, writes
+
+	over := func(ratio float64) bool {
+		return (g.cfg.QueueDepth > 0 && float64(queued) >= float64(g.cfg.QueueDepth)*ratio) ||
+			(g.cfg.WriteLatency > 0 && float64(latency) >= float64(g.cfg.WriteLatency)*ratio)
+	}
+	switch {
+	case !g.engaged && over(1):
+		g.engaged = true
+		governed.Store(0)
+		governorMin.Store(int32(g.cfg.MinLevel))
+		watchdogLog(WarnLevel, governorCaller, "log pressure, raising minimum level", EventKey, GovernorEvent,
+			"min_level", g.cfg.MinLevel, "queued", queued, "write_latency", latency)
+	case g.engaged && !over(0.5):
+		g.disengage()
+	}
+}
+
+// disengage restores the levels and logs how many entries were suppressed.
+func (g *governor) disengage() {
+	g.engaged = false
+	governorMin.Store(int32(DebugLevel))
+	watchdogLog(InfoLevel, governorCaller, "log pressure relieved", EventKey, GovernorEvent,
+		"suppressed", governed.Swap(0))
+}
+
+// governorDropped reports whether the governor suppresses entries at level.
+func governorDropped(level Level) bool {
+	if level >= Level(governorMin.Load()) {
+		return false
+	}
+	governed.Add(1)
+	return true
+}
+
+// timeSinkWrite adds the duration of one sink write since start to the
+// totals the governor reads.
+func timeSinkWrite(start time.Time) {
+	sinkWriteNanos.Add(int64(time.Since(start)))
+	sinkWrites.Add(1)
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// HTTPClientConfig holds the transport settings of clients built by
+// NewHTTPClient. WatchRemotePolicy takes one; the package has no HTTP sinks
+// of its own, but a custom Sink shipping to Loki, Splunk HEC, an OTLP/HTTP
+// collector, or a webhook behind a corporate proxy can build its client
+// with it:
+//
+//	client, err := logger.NewHTTPClient(logger.HTTPClientConfig{
+//	    Proxy:    "http://proxy.corp:3128",
+//	    CAFile:   "/etc/pki/corp-root.pem",
+//	    CertFile: "/etc/app/client.pem",
+//	    KeyFile:  "/etc/app/client-key.pem",
+//	})
+type HTTPClientConfig struct {
+	// Client, when set, is returned as is and the other settings are
+	// ignored.
+	Client *http.Client
+	// Transport replaces the default transport. Proxy and TLS settings are
+	// not applied to it.
+	Transport http.RoundTripper
+	// Proxy is the proxy URL. Empty uses HTTP_PROXY, HTTPS_PROXY, and
+	// NO_PROXY from the environment; "direct" disables proxying.
+	Proxy string
+	// CAFile is a PEM bundle of certificate authorities trusted in addition
+	// to the system roots.
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and key for mutual
+	// TLS.
+	CertFile string
+	KeyFile  string
+	// TLSConfig is the base TLS configuration; CAFile and the client
+	// certificate are added to a copy of it.
+	TLSConfig *tls.Config
+	// Timeout bounds each request. Defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// NewHTTPClient builds the HTTP client described by cfg.
+func NewHTTPClient(cfg HTTPClientConfig) (*http.Client, error) {
+	if cfg.Client != nil {
+		return cfg.Client, nil
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Transport != nil {
+		return &http.Client{Transport: cfg.Transport, Timeout: cfg.Timeout}, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	switch cfg.Proxy {
+	case "":
+		transport.Proxy = http.ProxyFromEnvironment
+	case "direct":
+		transport.Proxy = nil
+	default:
+		proxy, err := url.Parse(cfg.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("logger: invalid proxy URL %q", cfg.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig := &tls.Config{}
+	if cfg.TLSConfig != nil {
+		tlsConfig = cfg.TLSConfig.Clone()
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("logger: CA bundle: %w", err)
+		}
+		var pool *x509.CertPool
+		if tlsConfig.RootCAs != nil {
+			// Clone shares RootCAs with the caller's config
+			pool = tlsConfig.RootCAs.Clone()
+		} else if pool, err = x509.SystemCertPool(); err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("logger: CA bundle %s contains no certificates", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, errors.New("logger: client certificate requires both CertFile and KeyFile")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("logger: client certificate: %w", err)
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	}
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport, Timeout: cfg.Timeout}, nil
+}
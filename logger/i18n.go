@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	catalogsMu sync.RWMutex
+	catalogs   = map[string]map[string]string{}
+
+	// consoleLocale is set from Config.Locale; empty disables translation
+	consoleLocale string
+)
+
+// RegisterCatalog adds translations for locale ("de", "pt_BR", ...). Keys are
+// event IDs (see Event) or canonical English messages; values may use
+// {key} placeholders filled from the entry fields:
+//
+//	logger.RegisterCatalog("de", map[string]string{
+//	    "download_done": "{file} heruntergeladen",
+//	    "Checking for updates": "Suche nach Updates",
+//	})
+//
+// Registering the same locale again adds to its catalog.
+func RegisterCatalog(locale string, messages map[string]string) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+	c := catalogs[locale]
+	if c == nil {
+		c = make(map[string]string, len(messages))
+		catalogs[locale] = c
+	}
+	for k, v := range messages {
+		c[k] = v
+	}
+}
+
+// LocaleFromEnv returns the message locale from LC_ALL, LC_MESSAGES, or
+// LANG, e.g. "de_DE" for LANG=de_DE.UTF-8. It returns "" for the C and POSIX
+// locales.
+func LocaleFromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		v, _, _ = strings.Cut(v, ".")
+		v, _, _ = strings.Cut(v, "@")
+		if v == "C" || v == "POSIX" {
+			return ""
+		}
+		return v
+	}
+	return ""
+}
+
+// translate returns e with its message translated for the console, or e
+// itself when no translation applies. Only INFO and WARN entries, which CLI
+// users read, are translated. Callers must hold logMutex.
+func translate(e *Entry) *Entry {
+	if consoleLocale == "" || jsonOutput || (e.Level != InfoLevel && e.Level != WarnLevel) {
+		return e
+	}
+	key := e.Message
+	if id, ok := fieldValue(e.Fields, EventKey); ok {
+		key = id
+	}
+	msg, ok := lookupTranslation(consoleLocale, key)
+	if !ok && key != e.Message {
+		msg, ok = lookupTranslation(consoleLocale, e.Message)
+	}
+	if !ok {
+		return e
+	}
+	t := *e
+	t.Message = expandTemplate(msg, e.Fields)
+	return &t
+}
+
+// lookupTranslation finds key in the catalog for locale, falling back from
+// "pt_BR" to "pt".
+func lookupTranslation(locale, key string) (string, bool) {
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+	if msg, ok := catalogs[locale][key]; ok {
+		return msg, true
+	}
+	if lang, _, found := strings.Cut(locale, "_"); found {
+		msg, ok := catalogs[lang][key]
+		return msg, ok
+	}
+	return "", false
+}
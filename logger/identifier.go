@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// identifier is the SYSLOG_IDENTIFIER/APP-NAME resolved at Init, read
+	// by Identifier without logMutex
+	identifier atomic.Pointer[string]
+
+	// journalStream is our own connection to journald's stdout stream
+	// socket, opened when the identifier differs from the program name
+	journalStream     net.Conn
+	journalStreamPath = "/run/systemd/journal/stdout"
+)
+
+// Identifier returns the name the process logs under: the SYSLOG_IDENTIFIER
+// in the journal and the APP-NAME of syslog sinks. It is Config.Identifier
+// (or LOGGER_IDENTIFIER, or the program name) followed by "@" and
+// Config.Instance (or LOGGER_INSTANCE) when an instance is set, e.g.
+// "worker@3".
+func Identifier() string {
+	if id := identifier.Load(); id != nil && *id != "" {
+		return *id
+	}
+	return programName()
+}
+
+func programName() string {
+	return filepath.Base(os.Args[0])
+}
+
+// resolveIdentifier applies the Config and environment overrides.
+func resolveIdentifier(cfg Config) string {
+	name := cfg.Identifier
+	if name == "" {
+		name = os.Getenv("LOGGER_IDENTIFIER")
+	}
+	if name == "" {
+		name = programName()
+	}
+	instance := cfg.Instance
+	if instance == "" {
+		instance = os.Getenv("LOGGER_INSTANCE")
+	}
+	if instance != "" {
+		name += "@" + instance
+	}
+	return name
+}
+
+// openJournalStream connects to journald's stdout stream socket the way
+// systemd-cat does, announcing id as the identifier. Lines keep "<N>"
+// priority prefixes; others are logged at INFO.
+func openJournalStream(id string) (net.Conn, error) {
+	conn, err := net.DialTimeout("unix", journalStreamPath, time.Second)
+	if err != nil {
+		return nil, err
+	}
+	// identifier, unit ID, priority, level prefix, and forwarding to
+	// syslog, kmsg, and console
+	if _, err := fmt.Fprintf(conn, "%s\n\n6\n1\n0\n0\n0\n", id); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// closeJournalStream closes the connection opened by openJournalStream,
+// and the native socket connection used by writeJournalEntry.
+func closeJournalStream() {
+	if journalStream != nil {
+		journalStream.Close()
+		journalStream = nil
+	}
+	if journalNative != nil {
+		journalNative.Close()
+		journalNative = nil
+	}
+}
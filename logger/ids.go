@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// IDGenerator creates the IDs the logger writes. Install one with
+// SetIDGenerator, e.g. to use sequential IDs in tests or IDs issued by
+// another system.
+type IDGenerator interface {
+	// EntryID returns the entry_id of an entry logged at t (see
+	// Config.EntryIDs and Config.LastErrors), also used as the SelfTest
+	// token.
+	EntryID(t time.Time) string
+	// SpanID returns the span_id of a new span, also used as the conn_id
+	// of Conn.
+	SpanID() string
+}
+
+var (
+	// idGenerator is the SetIDGenerator generator, nil for the built-in IDs
+	idGenerator atomic.Pointer[IDGenerator]
+
+	// randSource feeds the built-in IDs, guarded by randMu since readers
+	// such as math/rand/v2's ChaCha8 are not safe for concurrent use
+	randSource io.Reader = rand.Reader
+	randMu     sync.Mutex
+)
+
+// SetIDGenerator makes g create entry and span IDs in place of the built-in
+// UUIDv7 entry IDs and random span IDs; nil restores those. The returned
+// function restores the previous generator:
+//
+//	defer logger.SetIDGenerator(sequentialIDs{})()
+func SetIDGenerator(g IDGenerator) (restore func()) {
+	var next *IDGenerator
+	if g != nil {
+		next = &g
+	}
+	prev := idGenerator.Swap(next)
+	return func() { idGenerator.Store(prev) }
+}
+
+// SetRandomSource makes the built-in IDs read their random bits from r
+// instead of crypto/rand; nil restores crypto/rand. A seeded reader gives
+// reproducible IDs in tests, and environments restricted to a certified
+// module can supply its generator. Reads are serialized, so r need not be
+// safe for concurrent use. The returned function restores the previous
+// source:
+//
+//	defer logger.SetRandomSource(rand.NewChaCha8([32]byte{}))() // math/rand/v2
+func SetRandomSource(r io.Reader) (restore func()) {
+	if r == nil {
+		r = rand.Reader
+	}
+	randMu.Lock()
+	defer randMu.Unlock()
+	prev := randSource
+	randSource = r
+	return func() {
+		randMu.Lock()
+		defer randMu.Unlock()
+		randSource = prev
+	}
+}
+
+// readRandom fills b from the random source. Bytes a failing source does
+// not provide are left zero.
+func readRandom(b []byte) {
+	randMu.Lock()
+	defer randMu.Unlock()
+	io.ReadFull(randSource, b)
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// maxInterned bounds each intern table so key spaces built from input (for
+// example field names in replayed logs) cannot grow it without limit. Once
+// full, new strings are returned as-is and counted as misses.
+const maxInterned = 4096
+
+// InternStats reports how effective string interning is. A high miss count
+// with a full table means the process logs more distinct callers or keys
+// than are cached.
+type InternStats struct {
+	// CallerHits counts call sites resolved from the caller cache, skipping
+	// the runtime lookup and string formatting.
+	CallerHits uint64
+	// CallerMisses counts call sites that had to be resolved and formatted.
+	CallerMisses uint64
+	// Callers is the number of cached call sites.
+	Callers int
+	// KeyHits counts parsed field keys and callers replaced by an existing copy.
+	KeyHits uint64
+	// KeyMisses counts parsed strings that were not in the table yet.
+	KeyMisses uint64
+	// Keys is the number of interned strings.
+	Keys int
+}
+
+// ReadInternStats returns the current interning counters.
+func ReadInternStats() InternStats {
+	callerCache.mu.RLock()
+	callers := len(callerCache.m)
+	callerCache.mu.RUnlock()
+	strTable.mu.RLock()
+	keys := len(strTable.m)
+	strTable.mu.RUnlock()
+	return InternStats{
+		CallerHits:   callerCache.hits.Load(),
+		CallerMisses: callerCache.misses.Load(),
+		Callers:      callers,
+		KeyHits:      strTable.hits.Load(),
+		KeyMisses:    strTable.misses.Load(),
+		Keys:         keys,
+	}
+}
+
+// callerCache maps a call site's program counter to its formatted
+// "package.Function:line" string.
+var callerCache = struct {
+	mu           sync.RWMutex
+	m            map[uintptr]string
+	hits, misses atomic.Uint64
+}{m: make(map[uintptr]string)}
+
+// cachedCaller returns the caller string for pc, formatting it with format
+// on the first lookup.
+func cachedCaller(pc uintptr, format func() string) string {
+	callerCache.mu.RLock()
+	s, ok := callerCache.m[pc]
+	callerCache.mu.RUnlock()
+	if ok {
+		callerCache.hits.Add(1)
+		return s
+	}
+	callerCache.misses.Add(1)
+	s = format()
+	callerCache.mu.Lock()
+	if len(callerCache.m) < maxInterned {
+		callerCache.m[pc] = s
+	}
+	callerCache.mu.Unlock()
+	return s
+}
+
+// strTable holds one canonical copy of strings that repeat across entries.
+var strTable = struct {
+	mu           sync.RWMutex
+	m            map[string]string
+	hits, misses atomic.Uint64
+}{m: make(map[string]string)}
+
+// intern returns the canonical copy of s, so entries built from parsed lines
+// share key and caller strings instead of each pinning its own copy.
+func intern(s string) string {
+	strTable.mu.RLock()
+	c, ok := strTable.m[s]
+	strTable.mu.RUnlock()
+	if ok {
+		strTable.hits.Add(1)
+		return c
+	}
+	strTable.misses.Add(1)
+	strTable.mu.Lock()
+	if len(strTable.m) < maxInterned {
+		// clone so the table never retains the line s was sliced from
+		s = string([]byte(s))
+		strTable.m[s] = s
+	}
+	strTable.mu.Unlock()
+	return s
+}
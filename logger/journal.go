@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JournalStyle controls console lines when stdout is connected to the
+// systemd journal. The journal records the level as PRIORITY from a "<N>"
+// line prefix, so the bracketed level and caller can be made compact or
+// dropped to keep `journalctl -o cat` output clean.
+type JournalStyle int
+
+const (
+	// JournalDefault keeps the normal console layout.
+	JournalDefault JournalStyle = iota
+	// JournalCompact writes "<N>LEVEL caller: msg key=value".
+	JournalCompact
+	// JournalBare writes "<N>msg key=value"; the level is only carried by PRIORITY.
+	JournalBare
+)
+
+// journalStyle is the effective style; it stays JournalDefault unless
+// stdout is connected to the journal.
+var journalStyle JournalStyle
+
+// journalLine renders e for the journal in the current style, without the
+// "<N>" prefix added by the console writer.
+func journalLine(e *Entry) string {
+	if journalStyle == JournalBare {
+		return e.Message + encodeFields(e.Fields...)
+	}
+	return fmt.Sprintf("%s %s: %s%s", levelNames[e.Level], e.Caller, e.Message, encodeFields(e.Fields...))
+}
+
+// JournalSocket is the socket of journald's native protocol.
+const JournalSocket = "/run/systemd/journal/socket"
+
+// JournalEncoder renders entries as journald native protocol datagrams, for
+// a SocketSink on JournalSocket:
+//
+//	sink, err := logger.NewSocketSink("unixgram", logger.JournalSocket, logger.JournalEncoder{})
+//
+// Each entry is sent as MESSAGE, PRIORITY, SYSLOG_IDENTIFIER, and CODE_FUNC,
+// with MESSAGE_ID for entries logged with Event (see EventMessageID), UNIT
+// for loggers with Logger.SetJournalUnit, and its own fields under
+// upper-cased names, e.g. USER for user. The identifier is that of the
+// entry's logger when set with Logger.SetJournalIdentifier.
+type JournalEncoder struct{}
+
+func (JournalEncoder) Encode(e *Entry) ([]byte, error) {
+	var b []byte
+	b = appendJournalField(b, "MESSAGE", e.Message)
+	b = appendJournalField(b, "PRIORITY", strconv.Itoa(syslogSeverities[e.Level]))
+	identifier, unit := journalIdentity(e)
+	if identifier == "" {
+		identifier = Identifier()
+	}
+	b = appendJournalField(b, "SYSLOG_IDENTIFIER", identifier)
+	if unit != "" {
+		b = appendJournalField(b, "UNIT", unit)
+	}
+	b = appendJournalField(b, "CODE_FUNC", e.Caller)
+	if id, ok := fieldValue(e.Fields, EventKey); ok {
+		b = appendJournalField(b, "MESSAGE_ID", EventMessageID(id))
+	}
+	for i := 0; i+1 < len(e.Fields); i += 2 {
+		if key, ok := e.Fields[i].(string); ok {
+			b = appendJournalField(b, journalFieldName(key), fmt.Sprint(e.Fields[i+1]))
+		}
+	}
+	return b, nil
+}
+
+// appendJournalField appends one field, using the length-prefixed form for
+// values containing newlines.
+func appendJournalField(b []byte, name, value string) []byte {
+	if !strings.Contains(value, "\n") {
+		return append(append(append(b, name...), '='), value+"\n"...)
+	}
+	b = append(append(b, name...), '\n')
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(value)))
+	return append(b, value+"\n"...)
+}
+
+// journalFieldName turns a field key into a valid journal field name:
+// upper case letters, digits, and underscores, not starting with an
+// underscore or digit, and at most 64 characters.
+func journalFieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	s := strings.TrimLeft(string(name), "_")
+	if s == "" || s[0] <= '9' {
+		s = "F_" + s
+	}
+	return s[:min(len(s), 64)]
+}
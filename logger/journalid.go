@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"net"
+	"strings"
+	"sync/atomic"
+)
+
+// journalID is the journal identity set on a named logger.
+type journalID struct {
+	identifier, unit string
+}
+
+var (
+	// journalIDs holds identities set with SetJournalIdentifier and
+	// SetJournalUnit, guarded by namedMu
+	journalIDs = map[string]journalID{}
+	// hasJournalIDs is set once any logger has an identity
+	hasJournalIDs atomic.Bool
+
+	// journalNative is the connection to journald's native socket used for
+	// entries of loggers with an identity, guarded by logMutex
+	journalNative     net.Conn
+	journalSocketPath = JournalSocket
+)
+
+// SetJournalIdentifier makes entries from l and its children appear in
+// the journal under identifier instead of the process's Identifier, so
+// the components of one binary can be read separately:
+//
+//	logger.Get("scheduler").SetJournalIdentifier("myapp-scheduler")
+//	// journalctl -t myapp-scheduler
+//
+// When stdout is the journal, their console output is sent over journald's
+// native protocol, as by JournalEncoder, which sets the identifier too.
+// An empty identifier restores the inherited one.
+func (l *Logger) SetJournalIdentifier(identifier string) {
+	l.setJournalID(func(id *journalID) { id.identifier = identifier })
+}
+
+// SetJournalUnit adds a UNIT field to the journal entries of l and its
+// children, like SetJournalIdentifier, for filtering with
+// "journalctl UNIT=name". Journald only matches its own _SYSTEMD_UNIT for
+// "journalctl -u", so use distinct names rather than those of real units.
+// An empty unit restores the inherited one.
+func (l *Logger) SetJournalUnit(unit string) {
+	l.setJournalID(func(id *journalID) { id.unit = unit })
+}
+
+func (l *Logger) setJournalID(set func(*journalID)) {
+	if l.nop {
+		return
+	}
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	id := journalIDs[l.name]
+	set(&id)
+	if id == (journalID{}) {
+		delete(journalIDs, l.name)
+	} else {
+		journalIDs[l.name] = id
+		hasJournalIDs.Store(true)
+	}
+}
+
+// journalIdentity returns the identifier and unit of the logger e came
+// from, each set on it or its nearest ancestor that sets one, and empty
+// when none does. It is safe to call from sink workers.
+func journalIdentity(e *Entry) (identifier, unit string) {
+	if !hasJournalIDs.Load() {
+		return "", ""
+	}
+	name, ok := fieldValue(e.Fields, LoggerKey)
+	if !ok {
+		return "", ""
+	}
+	namedMu.RLock()
+	defer namedMu.RUnlock()
+	for {
+		id := journalIDs[name]
+		if identifier == "" {
+			identifier = id.identifier
+		}
+		if unit == "" {
+			unit = id.unit
+		}
+		if (identifier != "" && unit != "") || name == "" {
+			return identifier, unit
+		}
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			name = name[:i]
+		} else {
+			name = ""
+		}
+	}
+}
+
+// writeJournalEntry sends e to journald's native socket when stdout is the
+// journal and e's logger has an identity, reporting whether it did so the
+// console line is skipped. Callers must hold logMutex.
+func writeJournalEntry(e *Entry) bool {
+	if !hasJournalIDs.Load() || !underJournald() {
+		return false
+	}
+	if identifier, unit := journalIdentity(e); identifier == "" && unit == "" {
+		return false
+	}
+	return sendJournal(e)
+}
+
+// sendJournal writes e as a native protocol datagram, reconnecting once
+// after a failed write. Callers must hold logMutex.
+func sendJournal(e *Entry) bool {
+	data, err := JournalEncoder{}.Encode(e)
+	if err != nil {
+		return false
+	}
+	for range 2 {
+		if journalNative == nil {
+			conn, err := net.Dial("unixgram", journalSocketPath)
+			if err != nil {
+				return false
+			}
+			journalNative = conn
+		}
+		if _, err := journalNative.Write(data); err == nil {
+			return true
+		}
+		journalNative.Close()
+		journalNative = nil
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// levelNames maps levels to the names used in output.
+var levelNames = map[Level]string{
+	DebugLevel: "DEBUG",
+	InfoLevel:  "INFO",
+	WarnLevel:  "WARN",
+	ErrorLevel: "ERROR",
+	FatalLevel: "FATAL",
+}
+
+// encodeJSON renders an entry as a single-line JSON object.
+// Built-in keys come first (time, level, caller, msg), followed by the
+// key-value pairs in the order they were given.
+func encodeJSON(e *Entry) string {
+	var b strings.Builder
+	b.WriteString(`{"time":`)
+	writeJSONValue(&b, e.Time.Format(time.RFC3339Nano))
+	b.WriteString(`,"level":`)
+	writeJSONValue(&b, levelNames[e.Level])
+	b.WriteString(`,"caller":`)
+	writeJSONValue(&b, e.Caller)
+	b.WriteString(`,"msg":`)
+	writeJSONValue(&b, e.Message)
+	for i := 0; i+1 < len(e.Fields); i += 2 {
+		key, ok := e.Fields[i].(string)
+		if !ok {
+			continue
+		}
+		b.WriteByte(',')
+		writeJSONValue(&b, key)
+		b.WriteByte(':')
+		writeJSONValue(&b, e.Fields[i+1])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// writeJSONValue appends v as JSON. Errors are written as their message and
+// values that cannot be marshaled fall back to their fmt.Sprint form.
+func writeJSONValue(b *strings.Builder, v any) {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.Write(data)
+}
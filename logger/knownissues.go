@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// KnownIssueKey is the field naming the known issue of an entry that
+// matched RegisterKnownIssue.
+const KnownIssueKey = "known_issue"
+
+// knownIssueSaveInterval is how often counts of suppressed occurrences are
+// saved to Config.KnownIssuesFile between the entries that are written.
+const knownIssueSaveInterval = time.Minute
+
+var (
+	// knownIssueReasons maps registered fingerprints to their reasons
+	knownIssueReasons = map[string]string{}
+	// knownIssueStates holds the last day each known issue was logged and
+	// the occurrences suppressed since, as saved in knownIssuesFile
+	knownIssueStates = map[string]*knownIssueState{}
+	// knownIssuesFile is Config.KnownIssuesFile
+	knownIssuesFile string
+	// knownIssuesSaved is when knownIssueStates were last saved
+	knownIssuesSaved time.Time
+)
+
+// knownIssueState is the saved state of one known issue.
+type knownIssueState struct {
+	Day        string `json:"day"`
+	Suppressed int64  `json:"suppressed"`
+}
+
+// RegisterKnownIssue marks the ERROR entries with fingerprint, as reported
+// by Config.ErrorFingerprints, as a known, benign issue. The first
+// occurrence of each UTC day is written with a known_issue field giving
+// reason and a suppressed field counting the occurrences dropped since the
+// previous one; the rest of the day's occurrences are dropped:
+//
+//	logger.InitWithConfig(logger.Config{Mode: "production", KnownIssuesFile: "/var/lib/myapp/known-issues.json"})
+//	logger.RegisterKnownIssue("9f1c2ab04e7d3310", "upstream CDN resets idle connections, JIRA-4412")
+//	// [ERROR] [cdn.fetch:88] connection reset fingerprint=9f1c2ab04e7d3310 known_issue=upstream CDN resets idle connections, JIRA-4412 suppressed=5214
+//
+// With Config.KnownIssuesFile, the days and counts survive restarts, so a
+// process that keeps restarting does not log the issue again each time.
+// Counts are saved when an entry is written, at most every minute while
+// occurrences are dropped, and by Close.
+func RegisterKnownIssue(fingerprint, reason string) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	knownIssueReasons[fingerprint] = reason
+}
+
+// setKnownIssuesFile loads the known issue state saved in path when it
+// differs from the current file. Callers must be InitWithConfig.
+func setKnownIssuesFile(path string) {
+	if path == knownIssuesFile {
+		return
+	}
+	knownIssuesFile, knownIssueStates = path, map[string]*knownIssueState{}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &knownIssueStates)
+	}
+}
+
+// knownIssue returns keyvals with the known issue fields for the first
+// occurrence of the day of a registered issue, and false for the later
+// ones, which are dropped. Callers must hold logMutex.
+func knownIssue(caller, msg string, keyvals []any, now time.Time) ([]any, bool) {
+	fp := errorFingerprint(caller, msg, keyvals)
+	reason, ok := knownIssueReasons[fp]
+	if !ok {
+		return keyvals, true
+	}
+	st := knownIssueStates[fp]
+	if st == nil {
+		st = &knownIssueState{}
+		knownIssueStates[fp] = st
+	}
+	day := now.UTC().Format(time.DateOnly)
+	if st.Day == day {
+		st.Suppressed++
+		if now.Sub(knownIssuesSaved) >= knownIssueSaveInterval {
+			saveKnownIssues(now)
+		}
+		return keyvals, false
+	}
+	keyvals = appendMissing(keyvals, []any{FingerprintKey, fp, KnownIssueKey, reason, "suppressed", st.Suppressed})
+	st.Day, st.Suppressed = day, 0
+	saveKnownIssues(now)
+	return keyvals, true
+}
+
+// saveKnownIssues writes knownIssueStates to Config.KnownIssuesFile,
+// replacing it atomically. Callers must hold logMutex.
+func saveKnownIssues(now time.Time) {
+	if knownIssuesFile == "" || len(knownIssueStates) == 0 {
+		return
+	}
+	knownIssuesSaved = now
+	data, err := json.Marshal(knownIssueStates)
+	if err == nil {
+		tmp := knownIssuesFile + ".tmp"
+		if err = os.WriteFile(tmp, data, 0644); err == nil {
+			err = os.Rename(tmp, knownIssuesFile)
+		}
+	}
+	if err != nil {
+		writeErrors.Add(1)
+	}
+}
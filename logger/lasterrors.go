@@ -0,0 +1,41 @@
+package logger
+
+// lastErrors keeps the recent ERROR and FATAL entries when
+// Config.LastErrors is set. Guarded by logMutex.
+var lastErrors *RingSink
+
+// LastErrors returns up to n of the most recent ERROR and FATAL entries,
+// newest first, or all of those kept when n is not positive. It returns nil
+// unless Config.LastErrors is set. Each entry carries an entry_id (see
+// Entry.ID), so a health endpoint can report recent errors that are then
+// looked up in the logs:
+//
+//	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+//	    for _, e := range logger.LastErrors(5) {
+//	        fmt.Fprintf(w, "%s %s %s\n", e.Time.Format(time.RFC3339), e.ID(), e.Message)
+//	    }
+//	})
+func LastErrors(n int) []*Entry {
+	logMutex.Lock()
+	ring := lastErrors
+	logMutex.Unlock()
+	if ring == nil {
+		return nil
+	}
+	entries := ring.Entries()
+	if n <= 0 || n > len(entries) {
+		n = len(entries)
+	}
+	out := make([]*Entry, n)
+	for i := range out {
+		out[i] = entries[len(entries)-1-i]
+	}
+	return out
+}
+
+// recordLastError keeps e for LastErrors. Callers must hold logMutex.
+func recordLastError(e *Entry) {
+	if lastErrors != nil && e.Level >= ErrorLevel {
+		lastErrors.WriteEntry(e)
+	}
+}
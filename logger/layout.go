@@ -0,0 +1,111 @@
+package logger
+
+import "strings"
+
+// DefaultTimeFormat is the timestamp layout used by the {time} token when
+// Config.TimeFormat is empty. It matches the standard log package output.
+const DefaultTimeFormat = "2006/01/02 15:04:05"
+
+// levelColors holds the ANSI color used for each level in development mode,
+// from Config.Colors and Config.Palette.
+var levelColors = colorProfiles[ColorsDark]
+
+const colorReset = "\033[0m"
+
+// layoutToken identifies a placeholder in a layout template.
+type layoutToken int
+
+const (
+	tokenLiteral layoutToken = iota
+	tokenTime
+	tokenLevel
+	tokenCaller
+	tokenMsg
+	tokenFields
+	tokenColor
+	tokenReset
+)
+
+var layoutTokens = map[string]layoutToken{
+	"{time}":   tokenTime,
+	"{level}":  tokenLevel,
+	"{caller}": tokenCaller,
+	"{msg}":    tokenMsg,
+	"{fields}": tokenFields,
+	"{color}":  tokenColor,
+	"{reset}":  tokenReset,
+}
+
+type layoutPart struct {
+	token   layoutToken
+	literal string
+}
+
+// lineLayout is a parsed layout template.
+type lineLayout struct {
+	parts      []layoutPart
+	timeFormat string
+	color      bool
+}
+
+// parseLayout splits a template such as "{time} {level} {caller} {msg} {fields}"
+// into literal text and tokens. Unknown placeholders are kept as literal text.
+func parseLayout(tmpl, timeFormat string, color bool) *lineLayout {
+	if timeFormat == "" {
+		timeFormat = DefaultTimeFormat
+	}
+	l := &lineLayout{timeFormat: timeFormat, color: color}
+	var lit strings.Builder
+	for i := 0; i < len(tmpl); {
+		if tmpl[i] == '{' {
+			if end := strings.IndexByte(tmpl[i:], '}'); end > 0 {
+				if tok, ok := layoutTokens[tmpl[i:i+end+1]]; ok {
+					if lit.Len() > 0 {
+						l.parts = append(l.parts, layoutPart{literal: lit.String()})
+						lit.Reset()
+					}
+					l.parts = append(l.parts, layoutPart{token: tok})
+					i += end + 1
+					continue
+				}
+			}
+		}
+		lit.WriteByte(tmpl[i])
+		i++
+	}
+	if lit.Len() > 0 {
+		l.parts = append(l.parts, layoutPart{literal: lit.String()})
+	}
+	return l
+}
+
+// render formats an entry according to the layout. Trailing whitespace is
+// trimmed so an empty {fields} at the end does not leave a dangling space.
+func (l *lineLayout) render(e *Entry) string {
+	var b strings.Builder
+	for _, p := range l.parts {
+		switch p.token {
+		case tokenLiteral:
+			b.WriteString(p.literal)
+		case tokenTime:
+			b.WriteString(formatTime(e.Time, l.timeFormat))
+		case tokenLevel:
+			b.WriteString(levelNames[e.Level])
+		case tokenCaller:
+			b.WriteString(e.Caller)
+		case tokenMsg:
+			b.WriteString(e.Message)
+		case tokenFields:
+			b.WriteString(strings.TrimPrefix(encodeFields(e.Fields...), " "))
+		case tokenColor:
+			if l.color {
+				b.WriteString(levelColors[e.Level])
+			}
+		case tokenReset:
+			if l.color {
+				b.WriteString(colorReset)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), " \t")
+}
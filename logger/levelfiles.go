@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LevelFilesConfig configures NewLevelFileSink.
+type LevelFilesConfig struct {
+	// Dir holds the log files. Required.
+	Dir string
+	// Name prefixes the file names. Defaults to the program name.
+	Name string
+	// Encoder defaults to TextEncoder with DefaultTimeFormat.
+	Encoder Encoder
+	// Retention is how long the files of each level are kept, e.g.
+	// {ErrorLevel: 90 * 24 * time.Hour, DebugLevel: 2 * 24 * time.Hour}.
+	// Levels without an entry use DefaultRetention; zero keeps files
+	// forever.
+	Retention        map[Level]time.Duration
+	DefaultRetention time.Duration
+}
+
+// LevelFileSink writes each level to its own file, rotated daily and named
+// <Name>-<level>-<YYYY-MM-DD>.log after the local date of the entries, and
+// deletes each level's files once they are older than its retention. Old
+// files are removed when the sink opens and at each rotation.
+//
+//	sink, err := logger.NewLevelFileSink(logger.LevelFilesConfig{
+//	    Dir:       "/var/log/app",
+//	    Retention: map[logger.Level]time.Duration{logger.ErrorLevel: 90 * 24 * time.Hour, logger.DebugLevel: 48 * time.Hour},
+//	    DefaultRetention: 14 * 24 * time.Hour,
+//	})
+type LevelFileSink struct {
+	cfg   LevelFilesConfig
+	files map[Level]*levelFile
+}
+
+// levelFile is the open file of one level.
+type levelFile struct {
+	day  string
+	sink *FileSink
+}
+
+// levelFileDay is the date layout in file names.
+const levelFileDay = time.DateOnly
+
+// NewLevelFileSink creates cfg.Dir if needed and removes expired files.
+func NewLevelFileSink(cfg LevelFilesConfig) (*LevelFileSink, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("logger: level file sink requires a directory")
+	}
+	if cfg.Name == "" {
+		cfg.Name = programName()
+	}
+	if cfg.Encoder == nil {
+		cfg.Encoder = TextEncoder{TimeFormat: DefaultTimeFormat}
+	}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return nil, err
+	}
+	s := &LevelFileSink{cfg: cfg, files: map[Level]*levelFile{}}
+	s.prune(time.Now())
+	return s, nil
+}
+
+// WriteEntry appends e to the file of its level and day, rotating and
+// pruning when the day changes.
+func (s *LevelFileSink) WriteEntry(e *Entry) error {
+	day := e.Time.Local().Format(levelFileDay)
+	lf := s.files[e.Level]
+	if lf == nil || lf.day != day {
+		if lf != nil {
+			lf.sink.Close()
+			s.prune(e.Time)
+		}
+		sink, err := NewFileSink(s.path(e.Level, day), s.cfg.Encoder)
+		if err != nil {
+			delete(s.files, e.Level)
+			return err
+		}
+		lf = &levelFile{day: day, sink: sink}
+		s.files[e.Level] = lf
+	}
+	return lf.sink.WriteEntry(e)
+}
+
+func (s *LevelFileSink) path(level Level, day string) string {
+	return filepath.Join(s.cfg.Dir, s.cfg.Name+"-"+strings.ToLower(levelNames[level])+"-"+day+".log")
+}
+
+// retention returns how long files of level are kept.
+func (s *LevelFileSink) retention(level Level) time.Duration {
+	if d, ok := s.cfg.Retention[level]; ok {
+		return d
+	}
+	return s.cfg.DefaultRetention
+}
+
+// prune deletes the files of each level whose day ended more than its
+// retention before now.
+func (s *LevelFileSink) prune(now time.Time) {
+	for level, name := range levelNames {
+		keep := s.retention(level)
+		if keep <= 0 {
+			continue
+		}
+		prefix := s.cfg.Name + "-" + strings.ToLower(name) + "-"
+		paths, _ := filepath.Glob(filepath.Join(s.cfg.Dir, prefix+"*.log"))
+		for _, path := range paths {
+			day, err := time.ParseInLocation(levelFileDay, strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix), ".log"), time.Local)
+			if err != nil {
+				continue
+			}
+			if now.Sub(day.AddDate(0, 0, 1)) > keep {
+				os.Remove(path)
+			}
+		}
+	}
+}
+
+// Close closes the open files.
+func (s *LevelFileSink) Close() error {
+	var first error
+	for level, lf := range s.files {
+		if err := lf.sink.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(s.files, level)
+	}
+	return first
+}
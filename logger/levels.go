@@ -0,0 +1,56 @@
+package logger
+
+// LevelMapping filters and remaps entry levels for one output. Remapping is
+// applied first, then entries whose resulting level is below Min are
+// dropped:
+//
+//	// console shows WARN and above, the file keeps everything
+//	logger.InitWithConfig(logger.Config{
+//	    Mode:          "development",
+//	    FilePath:      "app.log",
+//	    ConsoleLevels: &logger.LevelMapping{Min: logger.WarnLevel},
+//	    FileLevels:    &logger.LevelMapping{Min: logger.DebugLevel},
+//	})
+//
+//	// a network sink that only receives errors, with FATAL reported as ERROR
+//	logger.AddSinkWithLevels(sink, logger.LevelMapping{
+//	    Min:   logger.ErrorLevel,
+//	    Remap: map[logger.Level]logger.Level{logger.FatalLevel: logger.ErrorLevel},
+//	})
+//
+// An explicit mapping replaces the mode default for that output, so DEBUG
+// entries reach it without Verbose when Min allows them.
+type LevelMapping struct {
+	// Min is the lowest level written to the output.
+	Min Level
+	// Remap changes entry levels before filtering, e.g. to upgrade WARN to
+	// ERROR or clamp FATAL to ERROR.
+	Remap map[Level]Level
+}
+
+var (
+	// consoleLevels and fileLevels are set from Config; nil keeps the mode default
+	consoleLevels *LevelMapping
+	fileLevels    *LevelMapping
+)
+
+// apply returns e as the output should see it, or false if the output drops
+// it. A nil mapping passes every entry through unchanged.
+func (m *LevelMapping) apply(e *Entry) (*Entry, bool) {
+	if m == nil {
+		return e, true
+	}
+	level := e.Level
+	if to, ok := m.Remap[level]; ok {
+		level = to
+	}
+	if level < m.Min {
+		return nil, false
+	}
+	if level == e.Level {
+		return e, true
+	}
+	remapped := *e
+	remapped.Level = level
+	return &remapped, true
+}
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// levelState is the level filter in effect. It is never modified once
+// stored in levelFilter; changes store a new one.
+type levelState struct {
+	// enabled holds the levels set by LOGGER_LEVELS, SetMinLevel, or a
+	// remote policy
+	enabled map[Level]bool
+	// packages holds the minimum level for callers in a package, set from
+	// "pkg:" terms of LOGGER_LEVELS or a remote policy
+	packages map[string]Level
+}
+
+// levelFilter holds the current levelState, so the logging functions can
+// check levels without logMutex.
+var levelFilter atomic.Pointer[levelState]
+
+func init() {
+	levelFilter.Store(&levelState{enabled: allLevels()})
+}
+
+// enabledLevels returns the enabled levels. The map must not be modified.
+func enabledLevels() map[Level]bool {
+	return levelFilter.Load().enabled
+}
+
+// setEnabledLevels replaces the enabled levels with levels, which must not
+// be modified afterwards, keeping the package levels.
+func setEnabledLevels(levels map[Level]bool) {
+	setLevels(levels, levelFilter.Load().packages)
+}
+
+// setLevels replaces the enabled levels and the package levels together.
+// Neither map may be modified afterwards.
+func setLevels(levels map[Level]bool, packages map[string]Level) {
+	levelFilter.Store(&levelState{enabled: levels, packages: packages})
+}
+
+// levelSpec is a parsed LOGGER_LEVELS value:
+//
+//	spec   = term { "," term }
+//	term   = level              enable one level, e.g. "INFO"
+//	       | ">=" level         enable a level and those above, e.g. ">=WARN"
+//	       | name "=" level     minimum level of a named logger and its
+//	                            children, e.g. "http.client=DEBUG"
+//	       | "pkg:" name "=" level
+//	                            minimum level for callers in a package,
+//	                            e.g. "pkg:store=ERROR"
+//
+// Level names are case-insensitive and spaces around terms are ignored. A
+// spec with no level or ">=" terms enables every level, so
+// "LOGGER_LEVELS=pkg:store=WARN" only quiets one package.
+type levelSpec struct {
+	levels   map[Level]bool
+	loggers  map[string]Level
+	packages map[string]Level
+}
+
+// parseLevelSpec parses s, skipping invalid terms and returning one error
+// for each of them.
+func parseLevelSpec(s string) (levelSpec, []error) {
+	spec := levelSpec{levels: map[Level]bool{}}
+	var errs []error
+	if strings.TrimSpace(s) == "" {
+		spec.levels = allLevels()
+		return spec, nil
+	}
+	selected := false
+	for _, raw := range strings.Split(s, ",") {
+		term := strings.TrimSpace(raw)
+		bad := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("LOGGER_LEVELS term %q: "+format, append([]any{term}, args...)...))
+		}
+		level := func(name string) (Level, bool) {
+			l, err := ParseLevel(name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("LOGGER_LEVELS has unknown level %q", strings.TrimSpace(name)))
+			}
+			return l, err == nil
+		}
+
+		switch {
+		case term == "":
+			bad("empty term")
+		case strings.HasPrefix(term, ">="):
+			if from, ok := level(term[2:]); ok {
+				selected = true
+				for l := from; l <= FatalLevel; l++ {
+					spec.levels[l] = true
+				}
+			}
+		case strings.Contains(term, "="):
+			name, value, _ := strings.Cut(term, "=")
+			name = strings.TrimSpace(name)
+			target := &spec.loggers
+			if pkg, ok := strings.CutPrefix(name, "pkg:"); ok {
+				name, target = strings.TrimSpace(pkg), &spec.packages
+				if name == "" || strings.ContainsAny(name, ".:/ ") {
+					bad("malformed package name %q", name)
+					continue
+				}
+			} else if name == "" || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") ||
+				strings.Contains(name, "..") || strings.ContainsAny(name, ":> ") {
+				bad("malformed logger name %q", name)
+				continue
+			}
+			if lowest, ok := level(value); ok {
+				if *target == nil {
+					*target = map[string]Level{}
+				}
+				(*target)[name] = lowest
+			}
+		default:
+			if l, ok := level(term); ok {
+				selected = true
+				spec.levels[l] = true
+			}
+		}
+	}
+	if !selected {
+		spec.levels = allLevels()
+	}
+	return spec, errs
+}
+
+// String renders the spec in canonical form, which parses back to an equal
+// spec.
+func (s levelSpec) String() string {
+	var terms []string
+	if len(s.levels) < int(FatalLevel)+1 {
+		for l := DebugLevel; l <= FatalLevel; l++ {
+			if s.levels[l] {
+				terms = append(terms, levelNames[l])
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.loggers)) {
+		terms = append(terms, name+"="+levelNames[s.loggers[name]])
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.packages)) {
+		terms = append(terms, "pkg:"+name+"="+levelNames[s.packages[name]])
+	}
+	return strings.Join(terms, ",")
+}
+
+func allLevels() map[Level]bool {
+	return map[Level]bool{
+		DebugLevel: true,
+		InfoLevel:  true,
+		WarnLevel:  true,
+		ErrorLevel: true,
+		FatalLevel: true,
+	}
+}
+
+// packageEnabled reports whether e passes the "pkg:" rules of LOGGER_LEVELS.
+func packageEnabled(e *Entry) bool {
+	packages := levelFilter.Load().packages
+	if len(packages) == 0 {
+		return true
+	}
+	pkg, _, _ := strings.Cut(e.Caller, ".")
+	lowest, ok := packages[pkg]
+	return !ok || e.Level >= lowest
+}
+
+// unrecognizedTerms returns the non-empty terms of s that parseLevelSpec
+// rejects.
+func unrecognizedTerms(s string) []string {
+	var terms []string
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if _, errs := parseLevelSpec(term); term != "" && len(errs) > 0 {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// enabledLevelNames lists the enabled levels, e.g. "INFO,ERROR".
+func enabledLevelNames(levels map[Level]bool) string {
+	var names []string
+	for l := DebugLevel; l <= FatalLevel; l++ {
+		if levels[l] {
+			names = append(names, levelNames[l])
+		}
+	}
+	return strings.Join(names, ",")
+}
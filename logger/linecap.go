@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"io"
+	"log"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// LinePartKey is the field marking the lines an entry longer than
+// Config.MaxLineBytes is split into, as "part/parts".
+const LinePartKey = "line_part"
+
+// MinLineBytes is the smallest Config.MaxLineBytes; lower values are
+// raised to it, leaving room for the caller and continuation marker.
+const MinLineBytes = 256
+
+// maxLineBytes is Config.MaxLineBytes, 0 when lines are not capped
+var maxLineBytes int
+
+// printCapped writes line, rendered from e, through l. A line that would
+// exceed maxLineBytes with the logger's prefix and timestamp is split into
+// several lines, each an entry with e's time, level, and caller whose
+// message is a piece of line and whose LinePartKey field numbers it.
+// Joining the messages of the parts gives back the original line, so an
+// oversized JSON entry becomes several valid JSON entries instead of one
+// that a shipper truncates. Callers must hold logMutex.
+func printCapped(l *log.Logger, e *Entry, line string) {
+	if maxLineBytes <= 0 || l.Writer() == io.Discard {
+		printLine(l, line)
+		return
+	}
+	budget := maxLineBytes - headerLen(l)
+	if len(line) <= budget {
+		printLine(l, line)
+		return
+	}
+	for _, part := range splitLine(e, line, budget) {
+		printLine(l, part)
+	}
+}
+
+// headerLen returns an upper bound on the bytes l adds before a line: its
+// prefix, timestamp, and file name.
+func headerLen(l *log.Logger) int {
+	n := len(l.Prefix())
+	flags := l.Flags()
+	if flags&log.Ldate != 0 {
+		n += len("2006/01/02 ")
+	}
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		n += len("15:04:05 ")
+		if flags&log.Lmicroseconds != 0 {
+			n += len(".000000")
+		}
+	}
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		_, file, _, _ := runtime.Caller(0)
+		if flags&log.Lshortfile != 0 {
+			file = filepath.Base(file)
+		}
+		n += len(file) + len(":99999: ")
+	}
+	return n
+}
+
+// splitLine splits line into rendered parts of at most budget bytes, on
+// rune boundaries. Each part holds at least one rune, so a budget too small
+// for the marker still makes progress.
+func splitLine(e *Entry, line string, budget int) []string {
+	// size the parts with the widest marker they can get
+	width := len(strconv.Itoa(len(line)))
+	widest := strings.Repeat("9", width) + "/" + strings.Repeat("9", width)
+
+	var pieces []string
+	for rest := line; rest != ""; {
+		n := min(len(rest), budget)
+		for {
+			for n > 0 && n < len(rest) && !utf8.RuneStart(rest[n]) {
+				n--
+			}
+			if n == 0 {
+				_, n = utf8.DecodeRuneInString(rest)
+				break
+			}
+			over := len(renderLine(linePart(e, rest[:n], widest))) - budget
+			if over <= 0 {
+				break
+			}
+			n = max(n-over, 0)
+		}
+		pieces = append(pieces, rest[:n])
+		rest = rest[n:]
+	}
+
+	parts := make([]string, len(pieces))
+	for i, piece := range pieces {
+		marker := strconv.Itoa(i+1) + "/" + strconv.Itoa(len(pieces))
+		parts[i] = renderLine(linePart(e, piece, marker))
+	}
+	return parts
+}
+
+// linePart returns the entry rendering one piece of a split line.
+func linePart(e *Entry, piece, marker string) *Entry {
+	return &Entry{ctx: e.ctx, Time: e.Time, Level: e.Level, Caller: e.Caller, Message: piece,
+		Fields: []any{LinePartKey, marker}}
+}
@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// binaryLogMagic starts binary log files written by FileSink. It is
+// followed by a length byte and the name of the record encoding, then by
+// records with a 4-byte big-endian length prefix.
+const binaryLogMagic = "GOLOGBIN1\n"
+
+// maxLogRecord bounds the record size accepted by LogReader, so a corrupt
+// length cannot exhaust memory.
+const maxLogRecord = 16 << 20
+
+// prepareBinaryLogFile writes the header to an empty file, or checks the
+// header of an existing one against format.
+func prepareBinaryLogFile(f *os.File, format string) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		_, err := f.Write(appendBinaryLogHeader(nil, format))
+		return err
+	}
+	got, err := readBinaryLogHeader(bufio.NewReader(io.NewSectionReader(f, 0, info.Size())))
+	if err != nil {
+		return fmt.Errorf("logger: %s: %w", f.Name(), err)
+	}
+	if got != format {
+		return fmt.Errorf("logger: %s holds %s records, not %s", f.Name(), got, format)
+	}
+	return nil
+}
+
+func appendBinaryLogHeader(b []byte, format string) []byte {
+	b = append(b, binaryLogMagic...)
+	b = append(b, byte(len(format)))
+	return append(b, format...)
+}
+
+// readBinaryLogHeader consumes the header and returns the record encoding.
+func readBinaryLogHeader(r *bufio.Reader) (string, error) {
+	head := make([]byte, len(binaryLogMagic)+1)
+	if _, err := io.ReadFull(r, head); err != nil || string(head[:len(binaryLogMagic)]) != binaryLogMagic {
+		return "", errors.New("not a binary log file")
+	}
+	format := make([]byte, head[len(binaryLogMagic)])
+	if _, err := io.ReadFull(r, format); err != nil {
+		return "", errors.New("truncated binary log header")
+	}
+	return string(format), nil
+}
+
+// LogReader reads entries back from a log file written by this logger:
+// classic text lines, JSON lines, or a binary log file written by FileSink.
+// The format is detected from the content.
+type LogReader struct {
+	r       *bufio.Reader
+	closer  io.Closer
+	format  string // binary record encoding, "" for line formats
+	skipped int
+}
+
+// OpenLogFile opens the log file at path for reading with Next:
+//
+//	lf, err := logger.OpenLogFile("/var/log/app/entries.msgpack")
+//	if err != nil { ... }
+//	defer lf.Close()
+//	for {
+//	    e, err := lf.Next()
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    if err != nil { ... }
+//	    fmt.Println(e.Time, e.Message)
+//	}
+func OpenLogFile(path string) (*LogReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	lr, err := NewLogReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("logger: %s: %w", path, err)
+	}
+	lr.closer = f
+	return lr, nil
+}
+
+// NewLogReader reads entries from r like OpenLogFile.
+func NewLogReader(r io.Reader) (*LogReader, error) {
+	lr := &LogReader{r: bufio.NewReaderSize(r, 64*1024)}
+	head, err := lr.r.Peek(len(binaryLogMagic))
+	if err == nil && string(head) == binaryLogMagic {
+		if lr.format, err = readBinaryLogHeader(lr.r); err != nil {
+			return nil, err
+		}
+		if lr.format != "msgpack" {
+			return nil, fmt.Errorf("unsupported record encoding %q", lr.format)
+		}
+	}
+	return lr, nil
+}
+
+// Next returns the next entry, or io.EOF after the last one. Unparseable
+// lines of text files are skipped and counted by Skipped; a corrupt binary
+// record is an error.
+func (lr *LogReader) Next() (*Entry, error) {
+	if lr.format != "" {
+		return lr.nextRecord()
+	}
+	for {
+		line, err := lr.r.ReadString('\n')
+		if len(line) == 0 && err != nil {
+			return nil, err
+		}
+		if line = strings.TrimRight(line, "\r\n"); line == "" {
+			continue
+		}
+		e, perr := ParseLine(line)
+		if perr == nil {
+			return e, nil
+		}
+		lr.skipped++
+	}
+}
+
+func (lr *LogReader) nextRecord() (*Entry, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(lr.r, size[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("logger: truncated record length")
+		}
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxLogRecord {
+		return nil, fmt.Errorf("logger: record of %d bytes exceeds limit", n)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(lr.r, data); err != nil {
+		return nil, errors.New("logger: truncated record")
+	}
+	return decodeMsgpackEntry(data)
+}
+
+// Skipped returns the number of unparseable lines skipped so far.
+func (lr *LogReader) Skipped() int {
+	return lr.skipped
+}
+
+// Close closes the file opened by OpenLogFile. It does nothing for
+// readers from NewLogReader.
+func (lr *LogReader) Close() error {
+	if lr.closer == nil {
+		return nil
+	}
+	return lr.closer.Close()
+}
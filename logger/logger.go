@@ -1,13 +1,15 @@
 package logger
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -32,33 +34,262 @@ var (
 	Fatal   = log.New(io.Discard, "", 0)
 
 	// Mutex for thread-safe logging across concurrent goroutines
-	logMutex sync.Mutex
-
-	// enabled levels (for filtering)
-	enabledLevels = map[Level]bool{
-		DebugLevel: true,
-		InfoLevel:  true,
-		WarnLevel:  true,
-		ErrorLevel: true,
-		FatalLevel: true,
-	}
+	logMutex probedMutex
 
 	// logFile holds the file handle for file logging (if enabled)
 	logFile *os.File
+
+	// jsonOutput switches entries to JSON encoding (production FallbackJSON)
+	jsonOutput bool
+
+	// layout renders whole lines from Config.Layout (nil keeps the classic prefix layout)
+	layout *lineLayout
+
+	// fileLoggers write plain copies of each level to the log file (if enabled)
+	fileLoggers map[Level]*log.Logger
 )
 
-// Dependency injection points for testing outputs.
+// Dependency injection points for testing outputs, set by SetOutputs.
 var (
 	outStdout io.Writer = os.Stdout
 	outStderr io.Writer = os.Stderr
 )
 
+// SetOutputs replaces the writers used in place of os.Stdout and os.Stderr,
+// so tests can check what each mode writes to which stream. A nil writer
+// leaves that stream unchanged. The console loggers pick up the writers on
+// the next Init; the returned function restores the previous ones:
+//
+//	var stdout, stderr bytes.Buffer
+//	defer logger.SetOutputs(&stdout, &stderr)()
+//	logger.Init("production", false)
+//	logger.Warnf("disk at 91%%") // written to stderr
+func SetOutputs(stdout, stderr io.Writer) (restore func()) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	prevOut, prevErr := outStdout, outStderr
+	if stdout != nil {
+		outStdout = stdout
+	}
+	if stderr != nil {
+		outStderr = stderr
+	}
+	return func() {
+		logMutex.Lock()
+		outStdout, outStderr = prevOut, prevErr
+		logMutex.Unlock()
+	}
+}
+
+// FallbackPolicy selects what production mode writes to the console.
+// Production mode has no native journal backend, so the policy always applies.
+type FallbackPolicy int
+
+const (
+	// FallbackPlain writes plain text to stdout/stderr (the default).
+	FallbackPlain FallbackPolicy = iota
+	// FallbackDiscard drops console output; the log file, if any, is still written.
+	FallbackDiscard
+	// FallbackFileOnly writes only to the log file. If no file is configured or it
+	// cannot be opened, output falls back to plain stdout/stderr so logs are not lost.
+	FallbackFileOnly
+	// FallbackJSON writes one JSON object per line to stdout (and to the log file).
+	FallbackJSON
+	// FallbackAuto writes to the local syslog daemon on hosts without systemd
+	// (Alpine/OpenRC, BSDs) when one is running, and behaves like FallbackPlain
+	// otherwise.
+	FallbackAuto
+)
+
+// Config holds the settings used by InitWithConfig.
+type Config struct {
+	// Mode is "development" or "production".
+	Mode string
+	// Verbose enables DEBUG logs in development mode.
+	Verbose bool
+	// FilePath enables file logging when non-empty.
+	FilePath string
+	// Fallback controls production console output. Ignored in development mode.
+	Fallback FallbackPolicy
+	// Layout is an optional line template, e.g. "{time} {level} {caller} {msg} {fields}".
+	// Supported tokens are {time}, {level}, {caller}, {msg}, {fields}, and
+	// {color}/{reset} which wrap text in the level color on the development
+	// console. When empty, the classic "[LEVEL] time [caller] msg" layout is used.
+	Layout string
+	// TimeFormat is the time.Format layout for the {time} token.
+	// Defaults to DefaultTimeFormat.
+	TimeFormat string
+	// Colors selects the development console palette: ColorsDark (the
+	// default), ColorsLight, ColorsHighContrast, or ColorsColorblind. It
+	// defaults to the LOGGER_COLORS environment variable. Palette replaces
+	// the ANSI sequence of single levels, e.g. {WarnLevel: "\033[38;5;130m"}.
+	Colors  ColorProfile
+	Palette map[Level]string
+	// ColorDepth limits console colors to what the terminal supports,
+	// converting 256-color and 24-bit palette entries to the nearest
+	// available color. ColorDepthAuto detects it from NO_COLOR, COLORTERM,
+	// and TERM; ColorDepthNone turns colors off.
+	ColorDepth ColorDepth
+	// ConsoleFlags and FileFlags set standard log package flags (log.Ldate,
+	// log.Lmicroseconds, log.LUTC, log.Lmsgprefix, ...) for each output.
+	// Zero keeps the mode default and FlagsNone drops timestamps entirely.
+	// The development console omits timestamps by default when stdout is
+	// connected to the systemd journal, which adds its own.
+	ConsoleFlags int
+	FileFlags    int
+	// StrictCodes requires an error code (see Code) on ERROR and FATAL entries.
+	// Entries without one are tagged error_code=UNCODED so they stand out in
+	// triage, and unregistered codes get category=unregistered.
+	StrictCodes bool
+	// Severity adds numeric syslog severities (0-7) as a field or as a "<N>"
+	// line prefix, for relays that route on priority numbers.
+	Severity SeverityMode
+	// Sanitize cleans messages and field values from untrusted input of
+	// invalid UTF-8, control characters, and ANSI escape sequences, against
+	// forged lines and terminal escapes. SanitizeStrict also marks entries
+	// that held such sequences.
+	Sanitize SanitizeMode
+	// Journal selects a compact or bare console layout when stdout is
+	// connected to the systemd journal; the level then travels as a "<N>"
+	// priority prefix. Ignored elsewhere and with FallbackJSON.
+	Journal JournalStyle
+	// ConsoleLevels and FileLevels filter and remap levels per output, e.g.
+	// WARN and above on the console while the file records DEBUG and above.
+	// nil keeps the mode default.
+	ConsoleLevels *LevelMapping
+	FileLevels    *LevelMapping
+	// FileAsync writes the log file from a background goroutine, coalescing
+	// lines queued during the previous write into a single write syscall.
+	// FATAL entries, SyncSinks, and Close wait for pending lines to be written.
+	FileAsync bool
+	// Locale translates INFO and WARN console messages using catalogs added
+	// with RegisterCatalog, e.g. "de" or LocaleFromEnv(). The log file and
+	// JSON output keep the canonical messages. Empty disables translation.
+	Locale string
+	// LoggerLevels sets the levels of named loggers (see Get), replacing any
+	// set before; e.g. {"": WarnLevel, "http.client": DebugLevel}. Loggers
+	// without an entry inherit the level of their nearest ancestor.
+	LoggerLevels map[string]Level
+	// Identifier replaces the program name as SYSLOG_IDENTIFIER in the
+	// journal and as the default syslog Tag; Instance is appended as
+	// "name@instance" to tell apart several copies of one program. They
+	// default to LOGGER_IDENTIFIER and LOGGER_INSTANCE. Under journald a
+	// changed identifier is announced over a new journal stream, since the
+	// one inherited on stdout is fixed by the unit.
+	Identifier string
+	Instance   string
+	// Fields are key-value pairs added to every entry, after its own fields
+	// and without replacing keys it already has.
+	Fields []any
+	// DisableKubernetesFields turns off the k8s_pod, k8s_namespace,
+	// k8s_node, and container_id fields otherwise added to every entry when
+	// running in Kubernetes (see KubernetesFields).
+	DisableKubernetesFields bool
+	// CloudMetadata adds the cloud_provider, cloud_instance_id, cloud_zone,
+	// and cloud_instance_type of an EC2 or GCE instance to every entry.
+	// The metadata services are queried once per process, delaying the
+	// first Init by up to CloudMetadataTimeout off the cloud.
+	CloudMetadata bool
+	// StartupEntry logs an INFO "logger initialized" entry at the end of
+	// InitWithConfig, with event=logger.initialized and the mode, levels,
+	// console output, sink count, pid, identifier, and version, so log
+	// pipelines can detect restarts and configuration changes. It is written
+	// even when LOGGER_LEVELS disables INFO.
+	StartupEntry bool
+	// ShutdownSummary logs an INFO "logger shutdown" entry from Close, with
+	// event=logger.shutdown, the uptime, the number of entries written per
+	// level, entries dropped by full sink queues, and failed writes, as an
+	// end-of-run report for batch jobs and CLIs. It is written even when
+	// LOGGER_LEVELS disables INFO.
+	ShutdownSummary bool
+	// ClockJumpWarning, when positive, logs a WARN "clock jump detected"
+	// with the delta whenever the wall clock moved by at least this much
+	// relative to the monotonic clock between two consecutive entries, as
+	// after a suspend/resume or an NTP step.
+	ClockJumpWarning time.Duration
+	// MonotonicField adds the monotonic time since the process started to
+	// every entry as mono=1h2m3.456789012s, so post-processing can order
+	// entries correctly even across wall clock steps.
+	MonotonicField bool
+	// EntryIDs gives every entry a UUIDv7 as its entry_id field, so single
+	// lines can be referenced in tickets and looked up in aggregators. Sinks
+	// read it with Entry.ID.
+	EntryIDs bool
+	// ErrorFingerprints adds a fingerprint field to ERROR and FATAL
+	// entries: a hash of the calling function and the message template
+	// that stays the same for every occurrence of an error, so files and
+	// journald can be grouped by it like an error tracker would. Sinks read
+	// it with Entry.Fingerprint.
+	ErrorFingerprints bool
+	// SourceContext adds the source of the call site, with one line on
+	// each side, to ERROR and FATAL entries as a source_context field,
+	// read from the source files at runtime. Development mode only; entries
+	// of binaries whose source files are not on disk are left as they are.
+	SourceContext bool
+	// CallerModule adds module and module_version fields to ERROR and
+	// FATAL entries logged from a dependency rather than the main module,
+	// resolved from the build info, so third-party errors stand out.
+	CallerModule bool
+	// LastErrors, when positive, keeps that many of the most recent ERROR
+	// and FATAL entries in memory for LastErrors, and gives those entries
+	// an entry_id even without EntryIDs.
+	LastErrors int
+	// DebugWindow, when positive, keeps that many of the most recent DEBUG
+	// entries dropped by the level filter in memory, for FlushDebugWindow.
+	DebugWindow int
+	// ResourceAdvisory logs a WARN at the end of InitWithConfig when
+	// GOMAXPROCS exceeds the cgroup CPU quota or memory usage is near the
+	// cgroup memory limit, with event=logger.resources. Like the startup
+	// entry it is written even when LOGGER_LEVELS disables WARN.
+	ResourceAdvisory bool
+	// RestartStateFile, when set, records the start time of each run in
+	// that file and logs a WARN at the first InitWithConfig with
+	// event=logger.crash_loop, the restart count, and the average interval
+	// when CrashLoopRestarts earlier starts fall within CrashLoopWindow,
+	// signaling a crash loop where no supervisor reports one.
+	RestartStateFile string
+	// CrashLoopRestarts defaults to DefaultCrashLoopRestarts (3).
+	CrashLoopRestarts int
+	// CrashLoopWindow defaults to DefaultCrashLoopWindow (5 minutes).
+	CrashLoopWindow time.Duration
+	// KnownIssuesFile keeps the state of RegisterKnownIssue across
+	// restarts, so each known issue is logged once per day rather than
+	// once per run.
+	KnownIssuesFile string
+	// MaxEntriesPerSecond, when positive, caps the entries below ERROR
+	// written per second, protecting the disk from accidental log loops.
+	// Entries over the cap are dropped and summarized: a single WARN with
+	// event=logger.rate_limited starts each episode, and an INFO with
+	// event=logger.rate_summary reports the count and top caller of each
+	// second with dropped entries. ERROR and FATAL are never dropped.
+	MaxEntriesPerSecond int
+	// Exemptions match entries, such as audit records, that
+	// MaxEntriesPerSecond, WatchRemotePolicy sampling and filters,
+	// StartGovernor, and full sink queues never drop.
+	Exemptions []Exemption
+	// MaxLineBytes, when positive, caps the length of console and log
+	// file lines, newline excluded, for shippers that truncate long lines.
+	// Longer entries are split into several lines marked with a line_part
+	// field. Values below MinLineBytes are raised to it.
+	MaxLineBytes int
+	// DeadlineMargin is how close to its context's deadline an entry logged
+	// with InfoDeadline and the other *Deadline functions skips remote and
+	// lossless sinks. Zero uses DefaultDeadlineMargin.
+	DeadlineMargin time.Duration
+	// Version is reported by StartupEntry. It defaults to the main module
+	// version, or the VCS revision of development builds.
+	Version string
+}
+
+// FlagsNone disables log package prefixes such as timestamps for an output.
+const FlagsNone = -1
+
 // Init initializes the logger for development or production mode.
 // Development uses colored stdout; production uses plain stdout/stderr.
 // Set verbose=true to enable DEBUG logs in development mode.
 // Respects LOGGER_LEVELS environment variable for filtering (e.g., "INFO,ERROR").
 func Init(logMode string, verboseMode bool) {
-	InitWithFile(logMode, verboseMode, "")
+	InitWithConfig(Config{Mode: logMode, Verbose: verboseMode})
 }
 
 // InitWithFile initializes the logger with optional file logging.
@@ -66,124 +297,406 @@ func Init(logMode string, verboseMode bool) {
 // The file is created with append mode and 0644 permissions.
 // Call Close() to properly close the log file when shutting down.
 func InitWithFile(logMode string, verboseMode bool, filePath string) {
+	InitWithConfig(Config{Mode: logMode, Verbose: verboseMode, FilePath: filePath})
+}
+
+// InitWithConfig initializes the logger from a Config. It does nothing after
+// DisableGlobal. It may be called again to reconfigure the logger while
+// other goroutines log; the log file of the previous configuration is
+// closed.
+// Call Close() to properly close the log file when shutting down.
+// Set LOGGER_DEBUG=1 to print the resulting setup and any problems found by
+// Validate to stderr.
+func InitWithConfig(cfg Config) {
+	initWithConfig(cfg, false)
+}
+
+// initWithConfig is InitWithConfig. With requireFile, a Config.FilePath
+// that cannot be opened is returned and leaves the configuration in effect
+// alone. The log file is opened and the global fields resolved first; the
+// rest is applied under logMutex, so concurrent entries see either the old
+// or the new configuration.
+func initWithConfig(cfg Config, requireFile bool) error {
+	if globalDisabled.Load() {
+		return nil
+	}
+	// Open log file if specified
+	var f *os.File
+	var fileErr error
+	if cfg.FilePath != "" {
+		f, fileErr = os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if fileErr != nil {
+			if requireFile {
+				return fileErr
+			}
+			fmt.Fprintf(os.Stderr, "failed to open log file %s: %v\n", cfg.FilePath, fileErr)
+		}
+	}
+	initialized.Store(true)
 	// Parse level filtering from environment
-	if levels := os.Getenv("LOGGER_LEVELS"); levels != "" {
-		enabledLevels = parseLevels(levels)
+	var envLevels levelSpec
+	var badLevelTerms []string
+	levels := os.Getenv("LOGGER_LEVELS")
+	if levels != "" {
+		envLevels, _ = parseLevelSpec(levels)
+		badLevelTerms = unrecognizedTerms(levels)
 	}
+	fields, fieldsErr := resolveGlobalFields(cfg)
 
-	// Open log file if specified
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	enabled := enabledLevels()
+	if levels != "" {
+		enabled = envLevels.levels
+	}
+	setLevels(enabled, envLevels.packages)
+
+	if fileBatch != nil {
+		fileBatch.Close()
+		fileBatch = nil
+	}
+	if logFile != nil {
+		logFile.Close()
+	}
+	logFile = f
 	var fileWriter io.Writer
-	if filePath != "" {
-		f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to open log file %s: %v\n", filePath, err)
-		} else {
-			logFile = f
-			fileWriter = f
+	if f != nil {
+		fileWriter = &plainFileWriter{w: fileErrorRecorder{f}}
+		if cfg.FileAsync {
+			fileBatch = newBatchWriter(fileErrorRecorder{f})
+			fileWriter = &plainFileWriter{w: fileBatch}
 		}
 	}
 
-	if logMode == "production" {
-		Debug = newPlainLogger(outStdout, "DEBUG", fileWriter)
-		Info = newPlainLogger(outStdout, "INFO", fileWriter)
-		Warning = newPlainLogger(outStderr, "WARN", fileWriter)
-		Error = newPlainLogger(outStderr, "ERROR", fileWriter)
-		Fatal = newPlainLogger(outStderr, "FATAL", fileWriter)
-		return
+	stopAutoSyslog()
+	closeJournalStream()
+	id := resolveIdentifier(cfg)
+	identifier.Store(&id)
+	strictCodes = cfg.StrictCodes
+	globalFields = fields
+	severityMode = cfg.Severity
+	sanitizeMode = cfg.Sanitize
+	clockJumpWarning, lastWall = cfg.ClockJumpWarning, time.Time{}
+	monotonicField, entryIDs = cfg.MonotonicField, cfg.EntryIDs
+	errorFingerprints = cfg.ErrorFingerprints
+	sourceContext = cfg.SourceContext && cfg.Mode != "production"
+	callerModule = cfg.CallerModule
+	maxEntriesPerSecond, rate = cfg.MaxEntriesPerSecond, rateWindow{}
+	exemptions = cfg.Exemptions
+	exemptionsOn.Store(len(exemptions) > 0)
+	setKnownIssuesFile(cfg.KnownIssuesFile)
+	maxLineBytes = cfg.MaxLineBytes
+	if cfg.DeadlineMargin <= 0 {
+		cfg.DeadlineMargin = DefaultDeadlineMargin
+	}
+	deadlineMargin.Store(int64(cfg.DeadlineMargin))
+	if maxLineBytes > 0 {
+		maxLineBytes = max(maxLineBytes, MinLineBytes)
+	}
+	lastErrors = nil
+	if cfg.LastErrors > 0 {
+		lastErrors = NewRingSink(cfg.LastErrors)
+	}
+	debugWindow = nil
+	if cfg.DebugWindow > 0 {
+		debugWindow = NewRingSink(cfg.DebugWindow)
+	}
+	debugWindowOn.Store(debugWindow != nil)
+
+	production := cfg.Mode == "production"
+	debugOutput = production || cfg.Verbose
+	jsonOutput = production && cfg.Fallback == FallbackJSON
+	depth := cfg.ColorDepth
+	if depth == ColorDepthAuto {
+		depth = detectColorDepth()
+	}
+	color := !production && depth != ColorDepthNone
+	levelColors = resolvePalette(cfg, depth)
+	layout = nil
+	if cfg.Layout != "" && !jsonOutput {
+		layout = parseLayout(cfg.Layout, cfg.TimeFormat, color)
+	}
+
+	consoleLevels, fileLevels = cfg.ConsoleLevels, cfg.FileLevels
+	consoleLocale = cfg.Locale
+	setLoggerLevels(cfg.LoggerLevels)
+	for name, level := range envLevels.loggers {
+		Get(name).SetLevel(level)
+	}
+	journalStyle = JournalDefault
+	if cfg.Journal != JournalDefault && !jsonOutput && underJournald() {
+		journalStyle = cfg.Journal
 	}
 
-	// Development mode
-	Debug = newDevLogger(outStdout, "DEBUG", verboseMode, fileWriter)
-	Info = newDevLogger(outStdout, "INFO", true, fileWriter)
-	Warning = newDevLogger(outStdout, "WARN", true, fileWriter)
-	Error = newDevLogger(outStdout, "ERROR", true, fileWriter)
-	Fatal = newDevLogger(outStderr, "FATAL", true, fileWriter)
+	// Development sends everything but FATAL to stdout; production splits
+	// INFO/DEBUG to stdout and WARN and above to stderr.
+	stdout, stderr := outStdout, outStderr
+	if id := Identifier(); id != programName() && underJournald() {
+		if conn, err := openJournalStream(id); err == nil {
+			journalStream = conn
+			stdout, stderr = conn, conn
+		}
+	}
+	consoleFlags, fileFlags := log.LstdFlags, log.LstdFlags
+	if underJournald() {
+		consoleFlags = 0
+	}
+	if production {
+		consoleFlags, fileFlags = 0, log.LstdFlags|log.Lmsgprefix
+		switch cfg.Fallback {
+		case FallbackDiscard:
+			stdout, stderr = nil, nil
+		case FallbackFileOnly:
+			if fileWriter != nil {
+				stdout, stderr = nil, nil
+			}
+		case FallbackAuto:
+			if startAutoSyslog() {
+				stdout, stderr = nil, nil
+			}
+		}
+	}
+	consoleFlags = resolveFlags(cfg.ConsoleFlags, consoleFlags)
+	fileFlags = resolveFlags(cfg.FileFlags, fileFlags)
+	textFormat.consoleFlags, textFormat.fileFlags, textFormat.color = consoleFlags, fileFlags, color
+
+	// JSON goes entirely to stdout, so the routing of both formats is kept
+	// for SetFormat.
+	for _, f := range []Format{FormatText, FormatJSON} {
+		outputs := map[Level]io.Writer{
+			DebugLevel: stdout,
+			InfoLevel:  stdout,
+			WarnLevel:  stdout,
+			ErrorLevel: stdout,
+			FatalLevel: stderr,
+		}
+		if production && f == FormatJSON {
+			outputs[FatalLevel] = stdout
+		} else if production {
+			outputs[WarnLevel] = stderr
+			outputs[ErrorLevel] = stderr
+		}
+		for level, out := range outputs {
+			consoleOut := withSeverityPrefix(out, level)
+			if journalStyle != JournalDefault && out != nil {
+				consoleOut = &prefixWriter{prefix: severityPrefix(level), w: out}
+			}
+			outputs[level] = consoleOut
+		}
+		consoleRouting.outputs[f] = outputs
+	}
+	format := FormatText
+	if jsonOutput {
+		format = FormatJSON
+	}
+
+	fileLoggers = map[Level]*log.Logger{}
+	consoleRouting.loggers = map[Level]*log.Logger{}
+	for level, consoleOut := range consoleRouting.outputs[format] {
+		enabled := level != DebugLevel || cfg.Verbose || production
+		fileEnabled := enabled || cfg.FileLevels != nil
+		if cfg.ConsoleLevels != nil {
+			enabled = true
+		}
+		console := newConsoleLogger(consoleOut, level, enabled, color, consoleFlags)
+		consoleRouting.loggers[level] = console
+		switch level {
+		case DebugLevel:
+			Debug = console
+		case InfoLevel:
+			Info = console
+		case WarnLevel:
+			Warning = console
+		case ErrorLevel:
+			Error = console
+		case FatalLevel:
+			Fatal = console
+		}
+		if fileEnabled && fileWriter != nil {
+			fileLoggers[level] = newFileLogger(withSeverityPrefix(fileWriter, level), level, fileFlags)
+		}
+	}
+
+	appliedConfig, appliedFileErr = cfg, fileErr
+	if len(badLevelTerms) > 0 {
+		initEntry(WarnLevel, "ignoring unrecognized LOGGER_LEVELS terms",
+			[]any{"terms", strings.Join(badLevelTerms, ","), "levels", enabledLevelNames(enabledLevels())})
+	}
+	if fieldsErr != nil {
+		initEntry(WarnLevel, "ignoring LOGGER_FIELDS", []any{"error", fieldsErr})
+	}
+	if cfg.StartupEntry {
+		logStartup(cfg, fileErr)
+	}
+	if cfg.ResourceAdvisory {
+		logResourceAdvisory()
+	}
+	if cfg.RestartStateFile != "" {
+		checkCrashLoop(cfg)
+	}
+	if diagnosticsEnabled() {
+		writeDiagnostics(outStderr, resolveConfig(cfg, fileErr))
+	}
+	return nil
+}
+
+// resolveFlags returns the configured log flags, the mode default when unset,
+// or no flags at all for FlagsNone.
+func resolveFlags(configured, def int) int {
+	switch configured {
+	case 0:
+		return def
+	case FlagsNone:
+		return 0
+	}
+	return configured
 }
 
-// Close closes the log file if it was opened.
-// Call this function when your application shuts down to ensure logs are flushed.
+// underJournald reports whether stdout is connected to the systemd journal,
+// which timestamps every line itself.
+func underJournald() bool {
+	return outStdout == os.Stdout && os.Getenv("JOURNAL_STREAM") != ""
+}
+
+// Close closes the log file if it was opened, along with every sink added
+// with AddSink, AddRoute, or Logger.AddSink, and returns all the errors
+// encountered, joined with errors.Join; one failing sink does not keep the
+// others open. Call this function when your application shuts down to ensure
+// logs are flushed. It is safe to call from several goroutines and more than
+// once; later calls return nil. With Config.ShutdownSummary it first logs
+// the summary entry.
 func Close() error {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	flushRateSummary(time.Now())
+	saveKnownIssues(time.Now())
+	if appliedConfig.ShutdownSummary {
+		logShutdown()
+	}
+	fileLoggers = nil
+	err := closeSinks()
+	closeJournalStream()
+	if fileBatch != nil {
+		fileBatch.Close()
+		fileBatch = nil
+	}
 	if logFile != nil {
-		err := logFile.Close()
+		if ferr := logFile.Close(); ferr != nil {
+			err = errors.Join(err, fmt.Errorf("logger: closing log file: %w", ferr))
+		}
 		logFile = nil
-		return err
 	}
-	return nil
+	return err
 }
 
-// parseLevels parses a comma-separated list of level names.
+// parseLevels returns the levels enabled by a LOGGER_LEVELS value.
 // Empty string enables all levels.
 func parseLevels(s string) map[Level]bool {
-	m := map[Level]bool{}
-	s = strings.TrimSpace(s)
-	if s == "" {
-		m[DebugLevel] = true
-		m[InfoLevel] = true
-		m[WarnLevel] = true
-		m[ErrorLevel] = true
-		m[FatalLevel] = true
-		return m
-	}
-	for _, p := range strings.Split(s, ",") {
-		switch strings.ToUpper(strings.TrimSpace(p)) {
-		case "DEBUG":
-			m[DebugLevel] = true
-		case "INFO":
-			m[InfoLevel] = true
-		case "WARN", "WARNING":
-			m[WarnLevel] = true
-		case "ERROR":
-			m[ErrorLevel] = true
-		case "FATAL":
-			m[FatalLevel] = true
-		}
+	spec, _ := parseLevelSpec(s)
+	return spec.levels
+}
+
+// ParseLevel parses a level name such as "info" or "WARNING", case-insensitively.
+// It is the parser behind LOGGER_LEVELS, ParseLine, and the log stream's
+// minimum level, so integrations accept the same names.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "DEBUG":
+		return DebugLevel, nil
+	case "INFO":
+		return InfoLevel, nil
+	case "WARN", "WARNING":
+		return WarnLevel, nil
+	case "ERROR":
+		return ErrorLevel, nil
+	case "FATAL":
+		return FatalLevel, nil
 	}
-	return m
+	return 0, fmt.Errorf("logger: unknown level %q", strings.TrimSpace(s))
 }
 
-// isLevelEnabled checks if a level is enabled for logging.
-func isLevelEnabled(level Level) bool {
-	return enabledLevels[level]
+// String returns the name used in output, e.g. "WARN", or "Level(7)" for a
+// value outside DebugLevel to FatalLevel.
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return "Level(" + strconv.Itoa(int(l)) + ")"
 }
 
-// newDevLogger returns a colored logger for the level, or discards if disabled.
-// If fileWriter is provided, logs are written to both console and file.
-func newDevLogger(out io.Writer, level string, enabled bool, fileWriter io.Writer) *log.Logger {
-	if !enabled {
-		return log.New(io.Discard, "", 0)
+// MarshalText returns the level name, so levels encode as "WARN" in JSON and
+// other text formats, including as map keys.
+func (l Level) MarshalText() ([]byte, error) {
+	name, ok := levelNames[l]
+	if !ok {
+		return nil, fmt.Errorf("logger: invalid level %d", int(l))
 	}
-	colors := map[string]string{
-		"DEBUG": "\033[36m",
-		"INFO":  "\033[32m",
-		"WARN":  "\033[33m",
-		"ERROR": "\033[31m",
-		"FATAL": "\033[35m",
+	return []byte(name), nil
+}
+
+// UnmarshalText parses a level name with ParseLevel.
+func (l *Level) UnmarshalText(text []byte) error {
+	return l.Set(string(text))
+}
+
+// Set parses s with ParseLevel, so a *Level can be used as a flag.Value:
+//
+//	min := logger.InfoLevel
+//	flag.Var(&min, "min-level", "lowest level to log")
+func (l *Level) Set(s string) error {
+	level, err := ParseLevel(s)
+	if err != nil {
+		return err
 	}
-	reset := "\033[0m"
-	levelLabel := fmt.Sprintf("%s[%s]%s", colors[level], level, reset)
+	*l = level
+	return nil
+}
 
-	// Combine console and file output if file writer is provided
-	if fileWriter != nil {
-		// Write colored output to console, plain output to file
-		return log.New(io.MultiWriter(out, &plainFileWriter{w: fileWriter, level: level}), levelLabel+" ", log.LstdFlags)
+// isLevelEnabled checks if a level is enabled for logging and not
+// suppressed by StartGovernor, initializing the logger with DefaultConfig on
+// first use. Callers holding logMutex read enabledLevels instead.
+func isLevelEnabled(level Level) bool {
+	if globalDisabled.Load() {
+		return false
 	}
-	return log.New(out, levelLabel+" ", log.LstdFlags)
+	ensureInit()
+	// with exemptions the governor can only be applied to the whole entry
+	return enabledLevels()[level] && (exemptionsOn.Load() || !governorDropped(level))
 }
 
-// newPlainLogger returns a non-colored logger for production stdout/stderr fallback.
-// If fileWriter is provided, logs are written to both console and file.
-func newPlainLogger(out io.Writer, level string, fileWriter io.Writer) *log.Logger {
-	prefix := fmt.Sprintf("[%s] ", level)
-	if fileWriter != nil {
-		return log.New(io.MultiWriter(out, &timestampWriter{w: fileWriter}), prefix, 0)
+// levelPrefix returns the "[LEVEL] " prefix, colored for the development console.
+func levelPrefix(level Level, color bool) string {
+	if color {
+		return fmt.Sprintf("%s[%s]%s ", levelColors[level], levelNames[level], colorReset)
 	}
-	return log.New(out, prefix, 0)
+	return fmt.Sprintf("[%s] ", levelNames[level])
+}
+
+// newConsoleLogger returns the console logger for a level, or discards if the
+// level is disabled or out is nil. JSON, layout, and journal output render the
+// whole line themselves, so those loggers carry no prefix or flags.
+func newConsoleLogger(out io.Writer, level Level, enabled, color bool, flags int) *log.Logger {
+	if !enabled || out == nil {
+		return log.New(io.Discard, "", 0)
+	}
+	if jsonOutput || layout != nil || journalStyle != JournalDefault {
+		return log.New(out, "", 0)
+	}
+	return log.New(out, levelPrefix(level, color), flags)
+}
+
+// newFileLogger returns the plain (uncolored) file logger for a level.
+func newFileLogger(w io.Writer, level Level, flags int) *log.Logger {
+	if jsonOutput || layout != nil {
+		return log.New(w, "", 0)
+	}
+	return log.New(w, levelPrefix(level, false), flags)
 }
 
 // plainFileWriter wraps a file writer to strip ANSI color codes before writing.
 type plainFileWriter struct {
-	w     io.Writer
-	level string
+	w io.Writer
 }
 
 func (p *plainFileWriter) Write(data []byte) (int, error) {
@@ -211,38 +724,35 @@ func (p *plainFileWriter) Write(data []byte) (int, error) {
 	return p.w.Write([]byte(result.String()))
 }
 
-// timestampWriter prepends a timestamp to each log line for file outputs.
-// Used to keep timestamps in files while omitting them from stdout/stderr in production.
-type timestampWriter struct {
-	w io.Writer
-}
-
-func (t *timestampWriter) Write(data []byte) (int, error) {
-	ts := time.Now().Format("2006/01/02 15:04:05 ")
-	buf := make([]byte, 0, len(ts)+len(data))
-	buf = append(buf, ts...)
-	buf = append(buf, data...)
-	return t.w.Write(buf)
-}
-
 // getCallerInfo returns formatted caller information at the specified stack depth.
 // Returns "package.Function" format for better log clarity.
+// Results are cached per call site, so repeated calls do not allocate.
 func getCallerInfo(depth int) string {
-	pc, _, line, ok := runtime.Caller(depth)
-	if !ok {
-		return "unknown"
-	}
-	fn := runtime.FuncForPC(pc)
-	if fn == nil {
+	var pcs [1]uintptr
+	if runtime.Callers(depth+1, pcs[:]) == 0 {
 		return "unknown"
 	}
-	full := fn.Name()
-	// Strip package path, keep package.Function
-	lastSlash := strings.LastIndex(full, "/")
-	if lastSlash >= 0 && lastSlash+1 < len(full) {
-		full = full[lastSlash+1:]
-	}
-	return fmt.Sprintf("%s:%d", full, line)
+	return callerForPC(pcs[0])
+}
+
+// callerForPC returns the "package.Function:line" caller string for a
+// program counter.
+func callerForPC(pc uintptr) string {
+	return cachedCaller(pc, func() string {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		if frame.Function == "" {
+			return "unknown"
+		}
+		full := frame.Function
+		// Strip package path, keep package.Function
+		lastSlash := strings.LastIndex(full, "/")
+		if lastSlash >= 0 && lastSlash+1 < len(full) {
+			full = full[lastSlash+1:]
+		}
+		caller := fmt.Sprintf("%s:%d", full, frame.Line)
+		callerSites.Store(caller, callerSite{file: frame.File, function: frame.Function})
+		return caller
+	})
 }
 
 // encodeFields formats key-value pairs as "key=value" strings.
@@ -264,21 +774,200 @@ func encodeFields(keyvals ...any) string {
 	return " " + strings.Join(parts, " ")
 }
 
+// Entry is a single log record as delivered to the outputs and sinks.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Caller  string // "package.Function:line"
+	Message string
+	Fields  []any // alternating key-value pairs
+
+	ctx   context.Context
+	trace *entryTrace // set while TracePipeline runs
+}
+
+// Context returns the context the entry was logged with, or
+// context.Background for entries logged without one.
+func (e *Entry) Context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+// output writes a single entry through l. Callers must hold logMutex.
+func output(l *log.Logger, level Level, caller, msg string, keyvals []any) {
+	outputContext(context.Background(), l, level, caller, msg, keyvals)
+}
+
+// outputContext writes a single entry through l. Without a layout, text output
+// keeps the "[caller] message key=value" body behind the logger's own prefix; a
+// layout or JSON output renders the whole line. Callers must hold logMutex.
+// Entries logged with a RequestBuffer in ctx are held or trigger a flush.
+// DEBUG entries dropped by the level filter are kept for FlushDebugWindow.
+// FATAL entries are written through writeEmergency instead when the heap is
+// nearly exhausted or their formatting panics.
+func outputContext(ctx context.Context, l *log.Logger, level Level, caller, msg string, keyvals []any) {
+	now := time.Now()
+	checkClockJump(now)
+	outputAt(ctx, l, level, now, caller, msg, keyvals)
+}
+
+// outputAt is outputContext for an entry that happened at now, such as one
+// relayed from a child process. Callers must hold logMutex.
+func outputAt(ctx context.Context, l *log.Logger, level Level, now time.Time, caller, msg string, keyvals []any) {
+	if level == FatalLevel && !globalDisabled.Load() {
+		if heapExhausted() {
+			writeEmergency(caller, msg, keyvals)
+			return
+		}
+		defer recoverFatal(caller, msg, keyvals)
+	}
+	keyvals = expandCodes(level, withErrorFields(keyvals))
+	if sanitizeMode != SanitizeNone {
+		msg, keyvals = sanitizeEntry(msg, keyvals)
+	}
+	if len(globalFields) > 0 {
+		keyvals = appendMissing(keyvals, globalFields)
+	}
+	if severityMode == SeverityField {
+		keyvals = appendMissing(keyvals, []any{SeverityKey, syslogSeverities[level]})
+	}
+	if monotonicField {
+		keyvals = appendMissing(keyvals, []any{MonotonicKey, now.Sub(processStart)})
+	}
+	if entryIDs || (lastErrors != nil && level >= ErrorLevel) {
+		keyvals = appendMissing(keyvals, []any{EntryIDKey, newEntryID(now)})
+	}
+	if errorFingerprints && level >= ErrorLevel {
+		keyvals = appendMissing(keyvals, []any{FingerprintKey, errorFingerprint(caller, msg, keyvals)})
+	}
+	if len(knownIssueReasons) > 0 && level == ErrorLevel {
+		var ok bool
+		if keyvals, ok = knownIssue(caller, msg, keyvals, now); !ok {
+			return
+		}
+	}
+	if callerModule && level >= ErrorLevel {
+		if fields := callerModuleFields(caller); fields != nil {
+			keyvals = appendMissing(keyvals, fields)
+		}
+	}
+	if sourceContext && level >= ErrorLevel {
+		if src := sourceLines(caller); src != "" {
+			keyvals = appendMissing(keyvals, []any{SourceContextKey, src})
+		}
+	}
+	e := &Entry{ctx: ctx, Time: now, Level: level, Caller: caller, Message: msg, Fields: keyvals}
+	if rb := BufferFromContext(ctx); rb != nil {
+		switch {
+		case level <= InfoLevel:
+			if rb.hold(e) || !enabledLevels()[level] {
+				return
+			}
+		case level >= ErrorLevel:
+			rb.flushLocked()
+		}
+	}
+	if holdDebug(e) {
+		return
+	}
+	writeEntry(l, e)
+}
+
+// writeEntry counts e for metrics, adds it to running CaptureRegion calls
+// and LastErrors, and passes it to matching routes and to the sinks of its
+// named logger, then renders it and writes it to l, the file, and
+// registered sinks unless an exclusive route or a non-additive logger took
+// it.
+// When ConsoleLevels remaps the entry, the logger for the new level is used
+// instead of l, and console messages are translated when Config.Locale is
+// set. Callers must hold logMutex.
+func writeEntry(l *log.Logger, e *Entry) {
+	defer probeWriteDone(probeWriteStart())
+	if globalDisabled.Load() {
+		return
+	}
+	traceEntry(e)
+	if !packageEnabled(e) {
+		e.trace.step("filter", "", "dropped by package level")
+		return
+	}
+	if volumeDropped(e) {
+		return
+	}
+	countEntry(e)
+	countLevel(e)
+	captureRegions(e)
+	recordLastError(e)
+	if routeEntry(e) || writeLoggerSinks(e) {
+		return
+	}
+	writeSinks(e)
+
+	var line string
+	ce, ok := consoleLevels.apply(e)
+	if !ok {
+		e.trace.step("console", "", "skipped by ConsoleLevels")
+	} else {
+		ce = translate(ce)
+		if ce.Level != e.Level {
+			l = loggerFor(ce.Level)
+		}
+		switch {
+		case writeJournalEntry(ce):
+			// sent under the logger's own journal identity
+		case journalStyle != JournalDefault && !jsonOutput:
+			printLine(l, journalLine(ce))
+		default:
+			line = renderLine(ce)
+			printCapped(l, ce, line)
+		}
+		if l.Writer() != io.Discard {
+			e.trace.step("console", ce.Level.String(), "written")
+		}
+	}
+	if fe, ok := fileLevels.apply(e); ok {
+		if fl := fileLoggers[fe.Level]; fl != nil {
+			if fe != ce || line == "" {
+				line = renderLine(fe)
+			}
+			printCapped(fl, fe, line)
+			e.trace.step("file", fe.Level.String(), "written")
+			if fe.Level == FatalLevel && fileBatch != nil {
+				// the process is about to exit
+				fileBatch.Flush()
+			}
+		}
+	}
+}
+
+// renderLine formats e as JSON, with the configured layout, or as the
+// classic "[caller] message key=value" body behind the logger's own prefix.
+func renderLine(e *Entry) string {
+	switch {
+	case jsonOutput:
+		return encodeJSON(e)
+	case layout != nil:
+		return layout.render(e)
+	}
+	return fmt.Sprintf("[%s] %s%s", e.Caller, e.Message, encodeFields(e.Fields...))
+}
+
 // --- Formatted logging methods (fmt.Sprintf style) ---
 
 // Debugf logs a debug message formatted with fmt.Sprintf.
 // The caller function name and line number are automatically included.
 // Thread-safe for concurrent use.
 func Debugf(format string, v ...any) {
-	if !isLevelEnabled(DebugLevel) {
+	if !debugEnabled() {
 		return
 	}
 	logMutex.Lock()
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	msg := fmt.Sprintf("[%s] %s", caller, fmt.Sprintf(format, v...))
-	Debug.Println(msg)
+	output(Debug, DebugLevel, caller, fmt.Sprintf(format, v...), nil)
 }
 
 // Infof logs an informational message formatted with fmt.Sprintf.
@@ -292,8 +981,7 @@ func Infof(format string, v ...any) {
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	msg := fmt.Sprintf("[%s] %s", caller, fmt.Sprintf(format, v...))
-	Info.Println(msg)
+	output(Info, InfoLevel, caller, fmt.Sprintf(format, v...), nil)
 }
 
 // Warnf logs a warning message formatted with fmt.Sprintf.
@@ -307,8 +995,7 @@ func Warnf(format string, v ...any) {
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	msg := fmt.Sprintf("[%s] %s", caller, fmt.Sprintf(format, v...))
-	Warning.Println(msg)
+	output(Warning, WarnLevel, caller, fmt.Sprintf(format, v...), nil)
 }
 
 // Errorf logs an error message formatted with fmt.Sprintf.
@@ -322,8 +1009,7 @@ func Errorf(format string, v ...any) {
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	msg := fmt.Sprintf("[%s] %s", caller, fmt.Sprintf(format, v...))
-	Error.Println(msg)
+	output(Error, ErrorLevel, caller, fmt.Sprintf(format, v...), nil)
 }
 
 // Fatalf logs a fatal message formatted with fmt.Sprintf and then calls os.Exit(1).
@@ -331,15 +1017,14 @@ func Errorf(format string, v ...any) {
 // Thread-safe for concurrent use.
 func Fatalf(format string, v ...any) {
 	if !isLevelEnabled(FatalLevel) {
-		os.Exit(1)
+		exitFatal()
 	}
 	logMutex.Lock()
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	msg := fmt.Sprintf("[%s] %s", caller, fmt.Sprintf(format, v...))
-	Fatal.Println(msg)
-	os.Exit(1)
+	output(Fatal, FatalLevel, caller, fmt.Sprintf(format, v...), nil)
+	exitFatal()
 }
 
 // --- Plain logging methods (Println style) ---
@@ -348,15 +1033,14 @@ func Fatalf(format string, v ...any) {
 // The caller function name and line number are automatically included.
 // Thread-safe for concurrent use.
 func Debugln(v ...any) {
-	if !isLevelEnabled(DebugLevel) {
+	if !debugEnabled() {
 		return
 	}
 	logMutex.Lock()
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	msg := fmt.Sprintf("[%s] %s", caller, fmt.Sprint(v...))
-	Debug.Println(msg)
+	output(Debug, DebugLevel, caller, fmt.Sprint(v...), nil)
 }
 
 // Infoln logs an informational message by joining arguments with fmt.Sprint.
@@ -370,8 +1054,7 @@ func Infoln(v ...any) {
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	msg := fmt.Sprintf("[%s] %s", caller, fmt.Sprint(v...))
-	Info.Println(msg)
+	output(Info, InfoLevel, caller, fmt.Sprint(v...), nil)
 }
 
 // Warnln logs a warning message by joining arguments with fmt.Sprint.
@@ -385,8 +1068,7 @@ func Warnln(v ...any) {
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	msg := fmt.Sprintf("[%s] %s", caller, fmt.Sprint(v...))
-	Warning.Println(msg)
+	output(Warning, WarnLevel, caller, fmt.Sprint(v...), nil)
 }
 
 // Errorln logs an error message by joining arguments with fmt.Sprint.
@@ -400,8 +1082,7 @@ func Errorln(v ...any) {
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	msg := fmt.Sprintf("[%s] %s", caller, fmt.Sprint(v...))
-	Error.Println(msg)
+	output(Error, ErrorLevel, caller, fmt.Sprint(v...), nil)
 }
 
 // Fatalln logs a fatal message by joining arguments with fmt.Sprint and then calls os.Exit(1).
@@ -409,15 +1090,14 @@ func Errorln(v ...any) {
 // Thread-safe for concurrent use.
 func Fatalln(v ...any) {
 	if !isLevelEnabled(FatalLevel) {
-		os.Exit(1)
+		exitFatal()
 	}
 	logMutex.Lock()
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	msg := fmt.Sprintf("[%s] %s", caller, fmt.Sprint(v...))
-	Fatal.Println(msg)
-	os.Exit(1)
+	output(Fatal, FatalLevel, caller, fmt.Sprint(v...), nil)
+	exitFatal()
 }
 
 // --- Structured logging methods (key-value pairs) ---
@@ -426,15 +1106,14 @@ func Fatalln(v ...any) {
 // The caller function name and line number are automatically included.
 // Thread-safe for concurrent use.
 func DebugKV(msg string, keyvals ...any) {
-	if !isLevelEnabled(DebugLevel) {
+	if !debugEnabled() {
 		return
 	}
 	logMutex.Lock()
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	fields := encodeFields(keyvals...)
-	Debug.Printf("[%s] %s%s", caller, msg, fields)
+	output(Debug, DebugLevel, caller, msg, keyvals)
 }
 
 // InfoKV logs an info message with structured key-value pairs.
@@ -448,8 +1127,7 @@ func InfoKV(msg string, keyvals ...any) {
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	fields := encodeFields(keyvals...)
-	Info.Printf("[%s] %s%s", caller, msg, fields)
+	output(Info, InfoLevel, caller, msg, keyvals)
 }
 
 // WarnKV logs a warning message with structured key-value pairs.
@@ -463,8 +1141,7 @@ func WarnKV(msg string, keyvals ...any) {
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	fields := encodeFields(keyvals...)
-	Warning.Printf("[%s] %s%s", caller, msg, fields)
+	output(Warning, WarnLevel, caller, msg, keyvals)
 }
 
 // ErrorKV logs an error message with structured key-value pairs.
@@ -478,8 +1155,7 @@ func ErrorKV(msg string, keyvals ...any) {
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	fields := encodeFields(keyvals...)
-	Error.Printf("[%s] %s%s", caller, msg, fields)
+	output(Error, ErrorLevel, caller, msg, keyvals)
 }
 
 // FatalKV logs a fatal message with structured key-value pairs and then calls os.Exit(1).
@@ -487,15 +1163,14 @@ func ErrorKV(msg string, keyvals ...any) {
 // Thread-safe for concurrent use.
 func FatalKV(msg string, keyvals ...any) {
 	if !isLevelEnabled(FatalLevel) {
-		os.Exit(1)
+		exitFatal()
 	}
 	logMutex.Lock()
 	defer logMutex.Unlock()
 
 	caller := getCallerInfo(2)
-	fields := encodeFields(keyvals...)
-	Fatal.Printf("[%s] %s%s", caller, msg, fields)
-	os.Exit(1)
+	output(Fatal, FatalLevel, caller, msg, keyvals)
+	exitFatal()
 }
 
 // --- API logging methods (HTTP status code based) ---
@@ -510,6 +1185,24 @@ func FatalKV(msg string, keyvals ...any) {
 //	logger.Api(404, "resource not found")
 //	logger.Api(500, "internal server error")
 func Api(statusCode int, msg string) {
+	apiLog(3, statusCode, msg)
+}
+
+// ApiSkip is Api for helpers that wrap it, such as response writers: skip
+// is the number of stack frames between the helper and the function the
+// entry should be attributed to. ApiSkip(0, ...) behaves like Api, and a
+// helper called directly by the handler passes 1:
+//
+//	func writeError(w http.ResponseWriter, code int, msg string) {
+//	    logger.ApiSkip(1, code, msg) // attributed to the handler calling writeError
+//	    http.Error(w, msg, code)
+//	}
+func ApiSkip(skip int, statusCode int, msg string) {
+	apiLog(3+max(skip, 0), statusCode, msg)
+}
+
+// apiLog writes the Api entry attributed to the caller depth frames up.
+func apiLog(depth int, statusCode int, msg string) {
 	level := statusCodeToLevel(statusCode)
 	if !isLevelEnabled(level) {
 		return
@@ -517,16 +1210,16 @@ func Api(statusCode int, msg string) {
 	logMutex.Lock()
 	defer logMutex.Unlock()
 
-	caller := getCallerInfo(2)
-	logMsg := fmt.Sprintf("[%s] [%d] %s", caller, statusCode, msg)
+	caller := getCallerInfo(depth)
+	logMsg := fmt.Sprintf("[%d] %s", statusCode, msg)
 
 	switch level {
 	case InfoLevel:
-		Info.Println(logMsg)
+		output(Info, level, caller, logMsg, nil)
 	case WarnLevel:
-		Warning.Println(logMsg)
+		output(Warning, level, caller, logMsg, nil)
 	case ErrorLevel:
-		Error.Println(logMsg)
+		output(Error, level, caller, logMsg, nil)
 	}
 }
 
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogAccess_MatchesMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	h := Middleware(MiddlewareConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	serve(h, "/users/42")
+	fromMiddleware := buf.String()
+	buf.Reset()
+
+	LogAccess(context.Background(), AccessRecord{Method: "GET", Path: "/users/42", Status: 503, Duration: 0})
+	if got := buf.String(); got != fromMiddleware {
+		t.Fatalf("LogAccess = %q, Middleware = %q", got, fromMiddleware)
+	}
+}
+
+func TestLogAccess_DurationAndBaggage(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	ctx := ContextWithFields(context.Background(), "gateway", "edge-1")
+	LogAccess(ctx, AccessRecord{Method: "POST", Path: "/orders", Status: 201, Duration: 1500 * time.Millisecond, Baggage: "tenant=acme"})
+	want := "[INFO] [http] POST /orders method=POST path=/orders status=201 duration_ms=1500 gateway=edge-1 tenant=acme\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got  %q\nwant %q", got, want)
+	}
+
+	buf.Reset()
+	LogAccess(nil, AccessRecord{Method: "GET", Path: "/", Status: 200, Start: time.Now().Add(-2 * time.Second)})
+	if got := buf.String(); !strings.Contains(got, " duration_ms=20") {
+		t.Fatalf("duration not measured from Start: %q", got)
+	}
+}
+
+func TestLogAccess_SlowAndSkip(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	LogAccess(context.Background(), AccessRecord{Method: "GET", Path: "/report", Status: 200, Duration: 2 * time.Second, SlowThreshold: time.Second})
+	want := "[WARN] [http] GET /report method=GET path=/report status=200 duration_ms=2000 slow=true\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got  %q\nwant %q", got, want)
+	}
+
+	buf.Reset()
+	LogAccess(context.Background(), AccessRecord{Method: "GET", Path: "/healthz", Status: 200, Skip: true})
+	if buf.Len() != 0 {
+		t.Fatalf("skipped request was logged: %q", buf.String())
+	}
+	LogAccess(context.Background(), AccessRecord{Method: "GET", Path: "/healthz", Status: 503, Skip: true})
+	if !strings.Contains(buf.String(), "status=503") {
+		t.Fatalf("failed skipped request should still be logged, got %q", buf.String())
+	}
+}
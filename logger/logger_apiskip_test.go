@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// writeError stands for a response helper wrapping Api.
+func writeError(code int, msg string) {
+	ApiSkip(1, code, msg)
+}
+
+func TestApiSkip(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	Api(200, "direct")
+	ApiSkip(0, 201, "no skip")
+	writeError(404, "via helper")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got:\n%s", buf.String())
+	}
+	for i, want := range []string{"[INFO] [logger.TestApiSkip:", "[INFO] [logger.TestApiSkip:", "[WARN] [logger.TestApiSkip:"} {
+		if !strings.HasPrefix(lines[i], want) {
+			t.Errorf("line %d = %q, want prefix %q", i, lines[i], want)
+		}
+	}
+	if !strings.HasSuffix(lines[2], "] [404] via helper") {
+		t.Errorf("line 2 = %q", lines[2])
+	}
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Many tests replace the level loggers directly; initialize first so the
+	// first log call of a test run on its own does not replace them with
+	// DefaultConfig.
+	Init("development", true)
+	os.Exit(m.Run())
+}
+
+// forgetInit makes the logger behave as if Init had never been called.
+func forgetInit() {
+	initialized.Store(false)
+	autoInit = sync.Once{}
+	for _, l := range []*log.Logger{Debug, Info, Warning, Error, Fatal} {
+		l.SetOutput(io.Discard)
+	}
+}
+
+func TestAutoInit_FirstLogCall(t *testing.T) {
+	var buf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout, outStderr = &buf, &buf
+	t.Setenv("JOURNAL_STREAM", "")
+	t.Setenv("LOGGER_MODE", "production")
+	t.Setenv("LOGGER_VERBOSE", "")
+	defer Init("development", true)
+
+	forgetInit()
+	Infof("from a library init")
+	if got := buf.String(); !strings.HasPrefix(got, "[INFO] [") || !strings.HasSuffix(got, "from a library init\n") {
+		t.Fatalf("first log call should initialize in production mode, got %q", got)
+	}
+	if EffectiveConfig().Mode != "production" {
+		t.Fatalf("expected the DefaultConfig mode, got %+v", EffectiveConfig())
+	}
+
+	buf.Reset()
+	var console bytes.Buffer
+	Init("development", false)
+	Info.SetOutput(&console)
+	Infof("after main's Init")
+	if buf.Len() != 0 || !strings.Contains(console.String(), "after main's Init") {
+		t.Fatalf("explicit Init should replace the automatic configuration, got %q and %q", buf.String(), console.String())
+	}
+}
+
+func TestAutoInit_ExplicitInitFirst(t *testing.T) {
+	defer Init("development", true)
+	forgetInit()
+	t.Setenv("LOGGER_MODE", "production")
+	Init("development", true)
+	if Default() != Get("") || EffectiveConfig().Mode != "development" {
+		t.Fatalf("Default should keep the explicit configuration, got %+v", EffectiveConfig())
+	}
+}
+
+func TestAutoInit_NoDeadlockUnderLock(t *testing.T) {
+	defer Init("development", true)
+	forgetInit()
+	// EffectiveConfig takes logMutex and must not trigger initialization
+	EffectiveConfig()
+	Default()
+	if !initialized.Load() {
+		t.Fatal("Default should initialize the logger")
+	}
+}
+
+func TestDefaultConfig_FromEnvironment(t *testing.T) {
+	t.Setenv("LOGGER_MODE", "")
+	t.Setenv("LOGGER_VERBOSE", "true")
+	t.Setenv("LOGGER_FILE", "/var/log/app.log")
+	cfg := DefaultConfig()
+	if cfg.Mode != "development" || !cfg.Verbose || cfg.FilePath != "/var/log/app.log" {
+		t.Fatalf("unexpected DefaultConfig %+v", cfg)
+	}
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContextWithBaggage_Fields(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	ctx := ContextWithBaggage(context.Background(), "tenant", "acme", "flags", "beta")
+	ctx = ContextWithBaggage(ctx, "tenant", "globex")
+	InfoContext(ctx, "order placed", "order", 7)
+	if got := buf.String(); !strings.HasSuffix(got, "] order placed order=7 tenant=globex flags=beta\n") {
+		t.Fatalf("got %q", got)
+	}
+	if b := Baggage(ctx); len(b) != 2 || b["tenant"] != "globex" || b["flags"] != "beta" {
+		t.Fatalf("Baggage = %v", b)
+	}
+	if Baggage(context.Background()) != nil {
+		t.Fatal("baggage on empty context")
+	}
+}
+
+func TestBaggage_PropagatesAcrossServices(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	var header string
+	downstream := httptest.NewServer(Middleware(MiddlewareConfig{Baggage: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(BaggageHeader)
+		WarnContext(r.Context(), "quota low")
+	})))
+	defer downstream.Close()
+
+	ctx := ContextWithBaggage(context.Background(), "tenant", "acme corp", "flags", "a,b")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, downstream.URL+"/v1/quota", nil)
+	req.Header.Set(BaggageHeader, "upstream=1;prop=x, tenant=old")
+	client := &http.Client{Transport: BaggageTransport(nil)}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if header != "upstream=1,tenant=acme%20corp,flags=a%2Cb" {
+		t.Fatalf("header = %q", header)
+	}
+	if got := req.Header.Get(BaggageHeader); got != "upstream=1;prop=x, tenant=old" {
+		t.Fatalf("caller's request modified: %q", got)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "] quota low upstream=1 tenant=acme corp flags=a,b") ||
+		!strings.Contains(lines[1], " status=200 ") ||
+		!strings.HasSuffix(lines[1], " upstream=1 tenant=acme corp flags=a,b") {
+		t.Fatalf("got:\n%s", buf.String())
+	}
+}
+
+func TestParseBaggage_Malformed(t *testing.T) {
+	members := parseBaggage("=x, noequals, bad key=1, ok=%zz, good=v;p")
+	if len(members) != 1 || members[0] != (baggageMember{"good", "v"}) {
+		t.Fatalf("got %v", members)
+	}
+	if parseBaggage(strings.Repeat("k=v,", maxBaggageBytes)) != nil {
+		t.Fatal("oversized header accepted")
+	}
+}
@@ -2,6 +2,11 @@ package logger
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -89,3 +94,128 @@ func TestProductionStdout_NoTimestamps(t *testing.T) {
 		t.Fatalf("production stdout should omit date/time when not logging to file, got: %q", line)
 	}
 }
+
+func TestProductionFallback_Discard(t *testing.T) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout = &stdoutBuf
+	outStderr = &stderrBuf
+
+	InitWithConfig(Config{Mode: "production", Fallback: FallbackDiscard})
+	Infof("dropped info")
+	Errorf("dropped error")
+
+	if stdoutBuf.Len() != 0 || stderrBuf.Len() != 0 {
+		t.Fatalf("discard policy should write nothing, got stdout=%q stderr=%q", stdoutBuf.String(), stderrBuf.String())
+	}
+}
+
+func TestProductionFallback_FileOnly(t *testing.T) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout = &stdoutBuf
+	outStderr = &stderrBuf
+
+	logPath := filepath.Join(t.TempDir(), "only.log")
+	InitWithConfig(Config{Mode: "production", FilePath: logPath, Fallback: FallbackFileOnly})
+	defer Close()
+	Infof("file only info")
+	Errorf("file only error")
+
+	if stdoutBuf.Len() != 0 || stderrBuf.Len() != 0 {
+		t.Fatalf("file-only policy should not write to console, got stdout=%q stderr=%q", stdoutBuf.String(), stderrBuf.String())
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(content), "file only info") || !strings.Contains(string(content), "file only error") {
+		t.Fatalf("log file missing expected logs, got: %q", string(content))
+	}
+}
+
+func TestProductionFallback_FileOnlyWithoutFile(t *testing.T) {
+	var stdoutBuf bytes.Buffer
+	oldStdout := outStdout
+	defer func() { outStdout = oldStdout }()
+	outStdout = &stdoutBuf
+
+	InitWithConfig(Config{Mode: "production", Fallback: FallbackFileOnly})
+	Infof("kept on stdout")
+
+	if got := stdoutBuf.String(); !strings.Contains(got, "kept on stdout") {
+		t.Fatalf("file-only policy without a file should fall back to stdout, got: %q", got)
+	}
+}
+
+func TestProductionFallback_JSON(t *testing.T) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout = &stdoutBuf
+	outStderr = &stderrBuf
+	defer Init("development", true)
+
+	InitWithConfig(Config{Mode: "production", Fallback: FallbackJSON})
+	ErrorKV("json error", "code", 42, "err", errors.New("boom"))
+
+	if stderrBuf.Len() != 0 {
+		t.Fatalf("JSON policy should write everything to stdout, got stderr=%q", stderrBuf.String())
+	}
+	var got map[string]any
+	if err := json.Unmarshal(stdoutBuf.Bytes(), &got); err != nil {
+		t.Fatalf("stdout is not valid JSON: %v (%q)", err, stdoutBuf.String())
+	}
+	if got["level"] != "ERROR" || got["msg"] != "json error" || got["code"] != float64(42) || got["err"] != "boom" {
+		t.Fatalf("unexpected JSON entry: %v", got)
+	}
+	if caller, _ := got["caller"].(string); !strings.Contains(caller, "TestProductionFallback_JSON") {
+		t.Fatalf("expected caller in JSON entry, got: %v", got["caller"])
+	}
+}
+
+func TestSeverity_PrefixAndField(t *testing.T) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout = &stdoutBuf
+	outStderr = &stderrBuf
+	defer Init("development", true)
+
+	InitWithConfig(Config{Mode: "production", Severity: SeverityPrefix})
+	Infof("prefixed info")
+	Errorf("prefixed error")
+
+	if got := stdoutBuf.String(); !strings.HasPrefix(got, "<6>[INFO] ") {
+		t.Fatalf("expected <6> prefix on INFO line, got: %q", got)
+	}
+	if got := stderrBuf.String(); !strings.HasPrefix(got, "<3>[ERROR] ") {
+		t.Fatalf("expected <3> prefix on ERROR line, got: %q", got)
+	}
+
+	stdoutBuf.Reset()
+	InitWithConfig(Config{Mode: "production", Severity: SeverityField})
+	Debugf("with field")
+	if got := stdoutBuf.String(); !strings.HasPrefix(got, "[DEBUG] ") || !strings.Contains(got, "with field severity=7") {
+		t.Fatalf("expected severity field, got: %q", got)
+	}
+}
+
+func TestSetOutputs_NilKeepsStreamAndRestore(t *testing.T) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	restore := SetOutputs(&stdoutBuf, &stderrBuf)
+	inner := SetOutputs(nil, io.Discard)
+	if outStdout != &stdoutBuf || outStderr != io.Discard {
+		t.Fatal("expected nil stdout to keep the current writer")
+	}
+	inner()
+	if outStdout != &stdoutBuf || outStderr != &stderrBuf {
+		t.Fatal("expected inner restore to bring back the outer writers")
+	}
+	restore()
+	if outStdout != os.Stdout || outStderr != os.Stderr {
+		t.Fatal("expected os.Stdout and os.Stderr after restore")
+	}
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"maps"
+	"strings"
+	"testing"
+)
+
+// captureLevels points every level logger at buf and enables all levels.
+func captureLevels(buf *bytes.Buffer) {
+	Debug = log.New(buf, "[DEBUG] ", 0)
+	Info = log.New(buf, "[INFO] ", 0)
+	Warning = log.New(buf, "[WARN] ", 0)
+	Error = log.New(buf, "[ERROR] ", 0)
+	setEnabledLevels(allLevels())
+}
+
+// enableLevels turns levels on in addition to those already enabled.
+func enableLevels(levels ...Level) {
+	enabled := maps.Clone(enabledLevels())
+	for _, level := range levels {
+		enabled[level] = true
+	}
+	setEnabledLevels(enabled)
+}
+
+func TestRequestBuffer_DiscardOnSuccess(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+
+	rb := NewRequestBuffer(0)
+	ctx := ContextWithBuffer(context.Background(), rb)
+	DebugContext(ctx, "debug detail")
+	InfoContext(ctx, "info detail")
+	WarnContext(ctx, "warn passes through")
+
+	if got := buf.String(); strings.Contains(got, "detail") || !strings.Contains(got, "warn passes through") {
+		t.Fatalf("DEBUG/INFO should be held and WARN written, got: %q", got)
+	}
+	if rb.Len() != 2 {
+		t.Fatalf("expected 2 buffered entries, got %d", rb.Len())
+	}
+
+	rb.Discard()
+	InfoContext(ctx, "after discard")
+	if got := buf.String(); strings.Contains(got, "detail") || strings.Contains(got, "after discard") {
+		t.Fatalf("discarded buffer should not write entries, got: %q", got)
+	}
+}
+
+func TestRequestBuffer_FlushOnError(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer SetMinLevel(InfoLevel)()
+
+	ctx := ContextWithBuffer(context.Background(), NewRequestBuffer(0))
+	DebugContext(ctx, "filtered debug kept for failures")
+	InfoContext(ctx, "loaded user", "user_id", 7)
+	ErrorContext(ctx, "query failed")
+	InfoContext(ctx, "after error")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{"[DEBUG]", "[INFO]", "[ERROR]", "[INFO]"}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %d: %q", len(want), len(lines), buf.String())
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Fatalf("line %d: expected prefix %s, got %q", i, prefix, lines[i])
+		}
+	}
+	if !strings.Contains(lines[1], "loaded user user_id=7") || !strings.Contains(lines[3], "after error") {
+		t.Fatalf("unexpected flushed output: %q", buf.String())
+	}
+}
+
+func TestRequestBuffer_Overflow(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+
+	rb := NewRequestBuffer(2)
+	ctx := ContextWithBuffer(context.Background(), rb)
+	InfoContext(ctx, "first")
+	InfoContext(ctx, "second")
+	InfoContext(ctx, "third")
+	rb.Flush()
+
+	out := buf.String()
+	if strings.Contains(out, "first") || !strings.Contains(out, "second") || !strings.Contains(out, "third") {
+		t.Fatalf("oldest entry should be dropped, got: %q", out)
+	}
+	if !strings.Contains(out, "request buffer overflow dropped=1") {
+		t.Fatalf("expected overflow note, got: %q", out)
+	}
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportBundle(t *testing.T) {
+	dir := t.TempDir()
+	InitWithConfig(Config{Mode: "production", FilePath: filepath.Join(dir, "app.log")})
+	defer Init("development", true)
+	ring := NewRingSink(0)
+	AddSink(ring)
+	levels, err := NewLevelFileSink(LevelFilesConfig{Dir: dir, Name: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	AddSink(levels)
+	old := filepath.Join(dir, "app-error-"+time.Now().AddDate(0, 0, -10).Format(time.DateOnly)+".log")
+	os.WriteFile(old, []byte("old\n"), 0644)
+
+	Infof("starting")
+	Errorf("disk full")
+
+	path := filepath.Join(dir, "report.zip")
+	if err := ExportBundle(path); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	today := time.Now().Format(time.DateOnly)
+	for _, name := range []string{"entries.log", "config.json", "build.txt", "logs/app.log", "logs/app-info-" + today + ".log", "logs/app-error-" + today + ".log"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("bundle is missing %s", name)
+		}
+	}
+	if _, ok := files["logs/"+filepath.Base(old)]; ok {
+		t.Error("bundle includes a file older than BundleFileAge")
+	}
+	if lines := strings.Split(strings.TrimSpace(files["entries.log"]), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "[ERROR]") || !strings.HasSuffix(lines[1], "disk full") {
+		t.Errorf("entries.log = %q", files["entries.log"])
+	}
+	if !strings.Contains(files["config.json"], `"mode": "production"`) {
+		t.Errorf("config.json = %s", files["config.json"])
+	}
+	if !strings.Contains(files["build.txt"], "go: go1.") {
+		t.Errorf("build.txt = %s", files["build.txt"])
+	}
+	if !strings.Contains(files["logs/app.log"], "disk full") {
+		t.Errorf("logs/app.log = %q", files["logs/app.log"])
+	}
+}
+
+func TestExportBundle_ReportsCreateErrors(t *testing.T) {
+	dir := t.TempDir()
+	InitWithConfig(Config{Mode: "production", FilePath: filepath.Join(dir, "app.log")})
+	defer Init("development", true)
+	if err := ExportBundle(filepath.Join(dir, "missing", "report.zip")); err == nil {
+		t.Fatal("expected an error for a missing directory")
+	}
+	if err := ExportBundle(dir); err == nil {
+		t.Fatal("expected an error for a directory path")
+	}
+}
+
+func TestExportBundle_RoutedAndLoggerSinks(t *testing.T) {
+	dir := t.TempDir()
+	Close()
+	Init("production", false)
+	defer Init("development", true)
+	defer Close()
+	routed, named := NewRingSink(0), NewRingSink(0)
+	AddRoute(Route{MinLevel: ErrorLevel, Sinks: []Sink{routed}, Exclusive: true})
+	Get("billing").AddSink(named)
+	hourly, err := NewHourlyFileSink(filepath.Join(dir, "events.log"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	AddSink(hourly)
+	stale := filepath.Join(dir, "events-"+time.Now().UTC().Add(-72*time.Hour).Format("2006010215")+".log")
+	os.WriteFile(stale, []byte("{}\n"), 0644)
+
+	Get("billing").Info("invoice sent")
+	Errorf("payment failed")
+
+	path := filepath.Join(dir, "report.zip")
+	if err := ExportBundle(path); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	if lines := strings.Split(strings.TrimSpace(files["entries.log"]), "\n"); len(lines) != 2 ||
+		!strings.HasSuffix(lines[0], "invoice sent logger=billing") || !strings.HasSuffix(lines[1], "payment failed") {
+		t.Errorf("entries.log = %q", files["entries.log"])
+	}
+	current := "logs/" + filepath.Base(hourlyPath(filepath.Join(dir, "events.log"), time.Now().UTC().Truncate(time.Hour)))
+	if !strings.Contains(files[current], "invoice sent") {
+		t.Errorf("%s = %q", current, files[current])
+	}
+	if _, ok := files["logs/"+filepath.Base(stale)]; ok {
+		t.Error("bundle includes a partition older than BundleFileAge")
+	}
+}
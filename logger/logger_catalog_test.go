@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestWriteJournalCatalog(t *testing.T) {
+	RegisterEvent("test_disk_full", ErrorLevel, "disk {mount} is full")
+	DocumentEvent("test_disk_full", "The volume mounted at {mount} has no space left.\nFree space or grow the volume.")
+	RegisterEvent("test_disk_full", WarnLevel, "disk {mount} is full")
+	RegisterCatalog("test-de", map[string]string{"test_disk_full": "Datenträger {mount} ist voll"})
+	defer func() {
+		eventsMu.Lock()
+		delete(events, "test_disk_full")
+		eventsMu.Unlock()
+		catalogsMu.Lock()
+		delete(catalogs, "test-de")
+		catalogsMu.Unlock()
+	}()
+
+	if tmpl, _ := LookupEvent("test_disk_full"); tmpl.Level != WarnLevel || tmpl.Doc == "" {
+		t.Fatalf("re-registering lost the doc: %+v", tmpl)
+	}
+	var buf bytes.Buffer
+	if err := WriteJournalCatalog(&buf); err != nil {
+		t.Fatal(err)
+	}
+	id := EventMessageID("test_disk_full")
+	if len(id) != 32 || id != EventMessageID("test_disk_full") || id == EventMessageID("other") {
+		t.Fatalf("message id %q", id)
+	}
+	want := "-- " + id + "\n" +
+		"Subject: disk @MOUNT@ is full\n" +
+		"Defined-By: " + Identifier() + "\n\n" +
+		"The volume mounted at @MOUNT@ has no space left.\nFree space or grow the volume.\n\n" +
+		"-- " + id + " test-de\n" +
+		"Subject: Datenträger @MOUNT@ ist voll\n" +
+		"Defined-By: " + Identifier() + "\n\n" +
+		"The volume mounted at @MOUNT@ has no space left.\nFree space or grow the volume.\n\n"
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Fatalf("catalog:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestJournalEncoder(t *testing.T) {
+	e := &Entry{Level: WarnLevel, Caller: "main.run:12", Message: "disk full",
+		Fields: []any{EventKey, "disk_full", "mount", "/var", "http.status", 507, "9lives", "x", "stack", "a\nb"}}
+	data, err := JournalEncoder{}.Encode(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "MESSAGE=disk full\n" +
+		"PRIORITY=4\n" +
+		"SYSLOG_IDENTIFIER=" + Identifier() + "\n" +
+		"CODE_FUNC=main.run:12\n" +
+		"MESSAGE_ID=" + EventMessageID("disk_full") + "\n" +
+		"EVENT=disk_full\n" +
+		"MOUNT=/var\n" +
+		"HTTP_STATUS=507\n" +
+		"F_9LIVES=x\n" +
+		"STACK\n" + string(binary.LittleEndian.AppendUint64(nil, 3)) + "a\nb\n"
+	if string(data) != want {
+		t.Fatalf("got %q\nwant %q", data, want)
+	}
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeCertExpiring(t *testing.T, dir, name string, notAfter time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestWatchCertExpiry(t *testing.T) {
+	var buf lockedBuffer
+	captureLevels(new(bytes.Buffer))
+	Warning = log.New(&buf, "[WARN] ", 0)
+	Error = log.New(&buf, "[ERROR] ", 0)
+	defer Init("development", true)
+
+	now := time.Now()
+	dir := t.TempDir()
+	fresh := writeCertExpiring(t, dir, "fresh", now.Add(90*24*time.Hour))
+	soon := writeCertExpiring(t, dir, "soon", now.Add(20*24*time.Hour+time.Hour))
+	critical := writeCertExpiring(t, dir, "critical", now.Add(3*24*time.Hour+time.Hour))
+	expired := writeCertExpiring(t, dir, "expired", now.Add(-time.Hour))
+	missing := filepath.Join(dir, "missing.pem")
+
+	stop := WatchCertExpiry([]string{fresh, soon, critical, expired, missing}, time.Hour)
+	deadline := time.Now().Add(5 * time.Second)
+	for strings.Count(buf.String(), "\n") < 4 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+	stop()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	want := []string{
+		"[WARN] [logger.WatchCertExpiry] certificate expires soon path=" + soon + " subject=CN=soon expires=",
+		"[ERROR] [logger.WatchCertExpiry] certificate expires soon path=" + critical + " subject=CN=critical expires=",
+		"[ERROR] [logger.WatchCertExpiry] certificate expired path=" + expired + " subject=CN=expired expires=",
+		"[ERROR] [logger.WatchCertExpiry] certificate check failed path=" + missing + " error=",
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("line %d = %q, want prefix %q", i, lines[i], prefix)
+		}
+	}
+	for i, days := range []string{" days_left=20", " days_left=3", " days_left=0"} {
+		if !strings.HasSuffix(lines[i], days) {
+			t.Errorf("line %d = %q, want suffix %q", i, lines[i], days)
+		}
+	}
+}
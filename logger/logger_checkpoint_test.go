@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckpoint_RateLimitsAndLogsCompletion(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	for i := 1; i <= 1000; i++ {
+		Checkpoint("import", i, 1000, "file", "users.csv")
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected the first and final entries only, got:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "[INFO] [logger.TestCheckpoint_RateLimitsAndLogsCompletion:") ||
+		!strings.Contains(lines[0], "import progress checkpoint=import done=1 total=1000 percent=0.1% rate=") {
+		t.Errorf("unexpected progress entry %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "import complete checkpoint=import done=1000 total=1000 elapsed=") ||
+		!strings.HasSuffix(lines[1], " file=users.csv") {
+		t.Errorf("unexpected completion entry %q", lines[1])
+	}
+	if _, ok := checkpoints["import"]; ok {
+		t.Error("expected completed checkpoint state to be removed")
+	}
+}
+
+func TestCheckpoint_IntervalAndUnknownTotal(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	checkpointInterval = 10 * time.Millisecond
+	defer func() {
+		checkpointInterval = DefaultCheckpointInterval
+		delete(checkpoints, "scan")
+	}()
+
+	Checkpoint("scan", 10, 0)
+	Checkpoint("scan", 20, 0)
+	time.Sleep(20 * time.Millisecond)
+	Checkpoint("scan", 30, 0)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "scan progress checkpoint=scan done=30 rate=") ||
+		strings.Contains(buf.String(), "percent=") || strings.Contains(buf.String(), "eta=") {
+		t.Fatalf("unexpected entries:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := parseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.5", "::1"})
+	for _, tc := range []struct {
+		name, remote, xff, realIP, want string
+	}{
+		{"direct", "203.0.113.7:5000", "", "", "203.0.113.7"},
+		{"untrusted remote ignores headers", "203.0.113.7:5000", "1.2.3.4", "5.6.7.8", "203.0.113.7"},
+		{"one proxy", "10.1.2.3:443", "198.51.100.9", "", "198.51.100.9"},
+		{"proxy chain", "10.1.2.3:443", "198.51.100.9, 192.168.1.5, 10.9.9.9", "", "198.51.100.9"},
+		{"spoofed left entry", "10.1.2.3:443", "6.6.6.6, 198.51.100.9", "", "198.51.100.9"},
+		{"all hops trusted", "10.1.2.3:443", "10.4.4.4", "", "10.4.4.4"},
+		{"real ip", "[::1]:8080", "", "198.51.100.10", "198.51.100.10"},
+		{"mapped ipv4", "[::ffff:10.0.0.1]:80", "2001:db8::1", "", "2001:db8::1"},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tc.remote
+		if tc.xff != "" {
+			r.Header.Set("X-Forwarded-For", tc.xff)
+		}
+		if tc.realIP != "" {
+			r.Header.Set("X-Real-IP", tc.realIP)
+		}
+		if got := clientIP(r, trusted); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMiddleware_ClientIP(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	h := Middleware(MiddlewareConfig{ClientIP: true, TrustedProxies: []string{"192.0.2.0/24"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil) // RemoteAddr 192.0.2.1:1234
+	r.Header.Set("X-Forwarded-For", "198.51.100.23")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if !strings.HasSuffix(buf.String(), " client_ip=198.51.100.23\n") {
+		t.Fatalf("got %q", buf.String())
+	}
+}
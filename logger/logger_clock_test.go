@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClockJumpWarning(t *testing.T) {
+	InitWithConfig(Config{Mode: "development", ClockJumpWarning: time.Minute})
+	defer Init("development", true)
+	var buf bytes.Buffer
+	captureLevels(&buf)
+
+	InfoKV("first")
+	InfoKV("steady")
+	if got := buf.String(); strings.Contains(got, "clock jump") {
+		t.Fatalf("unexpected warning: %q", got)
+	}
+	buf.Reset()
+
+	// the wall clock was stepped forward an hour since the previous entry
+	logMutex.Lock()
+	lastWall = lastWall.Add(-time.Hour)
+	logMutex.Unlock()
+	InfoKV("after step")
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "[WARN] [logger.clock] clock jump detected delta=1h0m0s previous=") ||
+		!strings.HasSuffix(lines[1], "] after step") {
+		t.Fatalf("got %q", buf.String())
+	}
+}
+
+func TestClockJumpWarning_OffByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	InfoKV("first")
+	logMutex.Lock()
+	lastWall = time.Now().Add(-time.Hour)
+	logMutex.Unlock()
+	InfoKV("second")
+	if strings.Contains(buf.String(), "clock jump") {
+		t.Fatalf("got %q", buf.String())
+	}
+}
+
+func TestMonotonicField(t *testing.T) {
+	InitWithConfig(Config{Mode: "development", MonotonicField: true})
+	defer Init("development", true)
+	var buf bytes.Buffer
+	captureLevels(&buf)
+
+	InfoKV("first", "k", "v")
+	InfoKV("second")
+	var monos []time.Duration
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		_, value, ok := strings.Cut(line, " mono=")
+		if !ok {
+			t.Fatalf("no mono field in %q", line)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		monos = append(monos, d)
+	}
+	if len(monos) != 2 || monos[0] <= 0 || monos[1] < monos[0] {
+		t.Fatalf("mono values %v", monos)
+	}
+}
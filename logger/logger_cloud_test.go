@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFetchCloudFields_EC2(t *testing.T) {
+	ec2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/token":
+			w.Write([]byte("tok"))
+		case r.Header.Get("X-aws-ec2-metadata-token") != "tok":
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case r.URL.Path == "/meta-data/instance-id":
+			w.Write([]byte("i-0abc123"))
+		case r.URL.Path == "/meta-data/placement/availability-zone":
+			w.Write([]byte("eu-west-1b"))
+		case r.URL.Path == "/meta-data/instance-type":
+			w.Write([]byte("m7g.large\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ec2.Close()
+	gce := httptest.NewServer(http.NotFoundHandler())
+	defer gce.Close()
+	oldEC2, oldGCE := ec2MetadataURL, gceMetadataURL
+	defer func() { ec2MetadataURL, gceMetadataURL = oldEC2, oldGCE }()
+	ec2MetadataURL, gceMetadataURL = ec2.URL, gce.URL
+
+	want := []any{CloudProviderKey, "aws", CloudInstanceIDKey, "i-0abc123", CloudZoneKey, "eu-west-1b", CloudInstanceTypeKey, "m7g.large"}
+	if got := fetchCloudFields(time.Second); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestFetchCloudFields_GCE(t *testing.T) {
+	gce := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing header", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/instance/id":
+			w.Write([]byte("4520031799277581759"))
+		case "/instance/zone":
+			w.Write([]byte("projects/123/zones/us-central1-a"))
+		case "/instance/machine-type":
+			w.Write([]byte("projects/123/machineTypes/e2-medium"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gce.Close()
+	oldEC2, oldGCE := ec2MetadataURL, gceMetadataURL
+	defer func() { ec2MetadataURL, gceMetadataURL = oldEC2, oldGCE }()
+	ec2MetadataURL, gceMetadataURL = "http://127.0.0.1:1", gce.URL
+
+	want := []any{CloudProviderKey, "gcp", CloudInstanceIDKey, "4520031799277581759", CloudZoneKey, "us-central1-a", CloudInstanceTypeKey, "e2-medium"}
+	if got := fetchCloudFields(time.Second); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	ec2MetadataURL, gceMetadataURL = "http://127.0.0.1:1", "http://127.0.0.1:1"
+	if got := fetchCloudFields(100 * time.Millisecond); got != nil {
+		t.Fatalf("expected no fields off the cloud, got %v", got)
+	}
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestCode_ExpandsToFields(t *testing.T) {
+	var buf bytes.Buffer
+	Error = log.New(&buf, "", 0)
+	enableLevels(ErrorLevel)
+
+	RegisterCode("DB_TIMEOUT", "database")
+	ErrorKV("query timed out", "code", Code("DB_TIMEOUT"), "table", "users")
+
+	if out := buf.String(); !strings.Contains(out, "query timed out error_code=DB_TIMEOUT category=database table=users") {
+		t.Fatalf("expected code and category fields, got: %q", out)
+	}
+}
+
+func TestCode_AttachedToError(t *testing.T) {
+	var buf bytes.Buffer
+	Error = log.New(&buf, "", 0)
+	enableLevels(ErrorLevel)
+
+	RegisterCode("CACHE_MISS", "cache")
+	Err(WithFieldsErr(errors.New("not found"), "code", Code("CACHE_MISS")))
+
+	if out := buf.String(); !strings.Contains(out, "not found error_code=CACHE_MISS category=cache") {
+		t.Fatalf("expected code from the error's fields, got: %q", out)
+	}
+}
+
+func TestCode_StrictMode(t *testing.T) {
+	var buf bytes.Buffer
+	Warning = log.New(&buf, "", 0)
+	Error = log.New(&buf, "", 0)
+	enableLevels(WarnLevel, ErrorLevel)
+	strictCodes = true
+	defer func() { strictCodes = false }()
+
+	Warnf("warnings need no code")
+	Errorf("missing code")
+	ErrorKV("unknown code", "code", Code("NOT_REGISTERED"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got: %q", buf.String())
+	}
+	if strings.Contains(lines[0], "error_code") {
+		t.Fatalf("WARN entries should not be tagged, got: %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "missing code error_code=UNCODED category=uncategorized") {
+		t.Fatalf("uncoded ERROR should be tagged, got: %q", lines[1])
+	}
+	if !strings.HasSuffix(lines[2], "error_code=NOT_REGISTERED category=unregistered") {
+		t.Fatalf("unregistered code should be flagged, got: %q", lines[2])
+	}
+}
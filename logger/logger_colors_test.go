@@ -0,0 +1,86 @@
+package logger
+
+import "testing"
+
+func TestColorProfiles(t *testing.T) {
+	defer Init("development", true)
+
+	InitWithConfig(Config{Mode: "development", Colors: ColorsLight, ColorDepth: ColorDepth256,
+		Palette: map[Level]string{ErrorLevel: "\033[1;31m"}})
+	if got := levelPrefix(WarnLevel, true); got != "\033[38;5;130m[WARN]\033[0m " {
+		t.Fatalf("light WARN prefix = %q", got)
+	}
+	if got := levelPrefix(ErrorLevel, true); got != "\033[1;31m[ERROR]\033[0m " {
+		t.Fatalf("palette ERROR prefix = %q", got)
+	}
+
+	t.Setenv(ColorsEnv, "colorblind")
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm")
+	t.Setenv("COLORTERM", "truecolor")
+	Init("development", true)
+	if got := levelColors[InfoLevel]; got != "\033[38;5;32m" {
+		t.Fatalf("LOGGER_COLORS=colorblind INFO = %q", got)
+	}
+
+	t.Setenv(ColorsEnv, "sepia")
+	Init("development", true)
+	if got := levelColors[InfoLevel]; got != "\033[32m" {
+		t.Fatalf("unknown profile INFO = %q", got)
+	}
+	if colorProfiles[ColorsLight][ErrorLevel] != "\033[38;5;160m" {
+		t.Fatal("Palette modified the built-in profile")
+	}
+}
+
+func TestDetectColorDepth(t *testing.T) {
+	for _, tt := range []struct {
+		noColor, term, colorterm string
+		want                     ColorDepth
+	}{
+		{"1", "xterm-256color", "truecolor", ColorDepthNone},
+		{"", "dumb", "", ColorDepthNone},
+		{"", "xterm", "truecolor", ColorDepthTrue},
+		{"", "xterm-256color", "", ColorDepth256},
+		{"", "xterm", "", ColorDepth8},
+		{"", "", "", ColorDepth8},
+	} {
+		t.Setenv("NO_COLOR", tt.noColor)
+		t.Setenv("TERM", tt.term)
+		t.Setenv("COLORTERM", tt.colorterm)
+		t.Setenv("WT_SESSION", "")
+		if got := detectColorDepth(); got != tt.want {
+			t.Errorf("NO_COLOR=%q TERM=%q COLORTERM=%q: depth %d, want %d", tt.noColor, tt.term, tt.colorterm, got, tt.want)
+		}
+	}
+}
+
+func TestDowngradeSGR(t *testing.T) {
+	for _, tt := range []struct {
+		seq   string
+		depth ColorDepth
+		want  string
+	}{
+		{"\033[38;5;130m", ColorDepthTrue, "\033[38;5;130m"},
+		{"\033[38;5;130m", ColorDepth256, "\033[38;5;130m"},
+		{"\033[38;5;130m", ColorDepth8, "\033[31m"},
+		{"\033[1;7;38;5;214m", ColorDepth8, "\033[1;7;33m"},
+		{"\033[38;2;0;114;178m", ColorDepth256, "\033[38;5;25m"},
+		{"\033[38;2;0;114;178m", ColorDepth8, "\033[34m"},
+		{"\033[48;5;252;38;5;16m", ColorDepth8, "\033[47;30m"},
+		{"\033[32m", ColorDepth8, "\033[32m"},
+	} {
+		if got := downgradeSGR(tt.seq, tt.depth); got != tt.want {
+			t.Errorf("downgradeSGR(%q, %d) = %q, want %q", tt.seq, tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestColorDepthNone(t *testing.T) {
+	defer Init("development", true)
+	t.Setenv("NO_COLOR", "1")
+	Init("development", true)
+	if textFormat.color || Info.Prefix() != "[INFO] " {
+		t.Fatalf("colors with NO_COLOR: prefix %q", Info.Prefix())
+	}
+}
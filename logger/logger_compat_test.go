@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFieldLogger(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	base := WithFields(Fields{"user": 7, "order": 1042})
+	base.WithError(errors.New("timeout")).Warnf("retrying in %s", "2s")
+	base.Info("order ", "placed")
+	WithField("k", "v").Warning("deprecated")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{
+		"] retrying in 2s order=1042 user=7 error=timeout",
+		"] order placed order=1042 user=7",
+		"] deprecated k=v",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	for i, w := range want {
+		if !strings.Contains(lines[i], "[logger.TestFieldLogger:") || !strings.HasSuffix(lines[i], w) {
+			t.Errorf("line %d = %q, want suffix %q", i, lines[i], w)
+		}
+	}
+}
+
+func TestSugaredLogger(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	sugar := Sugar().With("component", "billing")
+	sugar.Infow("charge created", "amount", 1299)
+	sugar.Errorf("charge %s failed", "ch_1")
+	sugar.With("retry", true).Debug("scheduled")
+	if err := sugar.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.String()
+	for _, want := range []string{
+		"[INFO] [logger.TestSugaredLogger:",
+		"] charge created component=billing amount=1299\n",
+		"] charge ch_1 failed component=billing\n",
+		"] scheduled component=billing retry=true\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
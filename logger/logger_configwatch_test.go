@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or a second passes.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatalf("timed out waiting for %s", what)
+
+}
+
+func TestWatchConfig_ReloadsRejectsAndRollsBack(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	t.Setenv("NO_COLOR", "1")
+	oldInterval := configPollInterval
+	defer func() { configPollInterval = oldInterval }()
+	configPollInterval = 5 * time.Millisecond
+	var out lockedBuffer
+	defer SetOutputs(&out, &out)()
+	InitWithConfig(Config{Mode: "development", Verbose: true, Identifier: "appliance"})
+	defer Init("development", true)
+
+	path := filepath.Join(t.TempDir(), "logger.json")
+	write := func(s string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"Verbose": false}`)
+	stop, err := WatchConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	if got := EffectiveConfig(); got.Verbose || got.Identifier != "appliance" {
+		t.Fatalf("expected file applied over the running config, got %+v", got)
+	}
+
+	write(`{"Verbose": true, "LoggerLevels": {"http": "WARN"}}`)
+	waitFor(t, "reload", func() bool { return strings.Contains(out.String(), "config reloaded") })
+	if !EffectiveConfig().Verbose || appliedConfig.LoggerLevels["http"] != WarnLevel {
+		t.Fatalf("reload not applied: %+v", appliedConfig)
+	}
+	if got := out.String(); !strings.Contains(got, "Verbose=false->true") {
+		t.Errorf("expected changed fields in reload entry, got %q", got)
+	}
+
+	write(`{"Verbose": false, "Mode": "prod"}`)
+	waitFor(t, "rejection", func() bool { return strings.Contains(out.String(), "config reload rejected") })
+	if !EffectiveConfig().Verbose {
+		t.Fatal("rejected file changed the configuration")
+	}
+
+	// a directory passes Validate but cannot be opened as the log file
+	write(`{"FilePath": "` + t.TempDir() + `"}`)
+	waitFor(t, "rollback", func() bool { return strings.Contains(out.String(), "config reload rolled back") })
+	if got := EffectiveConfig(); got.File != "" || got.FileError != "" || !got.Verbose {
+		t.Fatalf("expected previous configuration restored, got %+v", got)
+	}
+}
+
+func TestWatchConfig_InvalidFileReturnsError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logger.json")
+	if err := os.WriteFile(path, []byte(`{"Verbos": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WatchConfig(path); err == nil || !strings.Contains(err.Error(), "Verbos") {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+	if _, err := WatchConfig(filepath.Join(t.TempDir(), "none.json")); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
+
+func TestWatchConfig_ReloadWhileLogging(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	var out lockedBuffer
+	defer SetOutputs(&out, &out)()
+	Init("development", true)
+	defer Init("development", true)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 500 {
+			InfoKV("tick", "n", 1)
+		}
+	}()
+	dir := t.TempDir()
+	for i := range 20 {
+		data := fmt.Sprintf(`{"Mode": "production", "FilePath": %q, "Layout": "{level} {msg}", "LoggerLevels": {"http": "WARN"}}`, filepath.Join(dir, fmt.Sprint(i%2, ".log")))
+		reloadConfig("logger.json", Config{Mode: "development"}, []byte(data))
+	}
+	<-done
+	if !strings.Contains(out.String(), "config reloaded") {
+		t.Fatalf("expected reload entries, got %q", out.String())
+	}
+}
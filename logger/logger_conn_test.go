@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConn_Lifecycle(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	c := Conn("websocket", "10.0.0.7:51234", "user", "alice")
+	c.AddIn(10)
+	c.AddOut(20)
+	c.Ping(23 * time.Millisecond)
+	c.Close(1000, "bye")
+	c.Close(1006, "again")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines = %q", lines)
+	}
+	prefix := "conn_id=" + c.ID() + " protocol=websocket remote=10.0.0.7:51234"
+	if !strings.HasPrefix(lines[0], "[INFO] ") || !strings.HasSuffix(lines[0], "websocket connection opened "+prefix+" user=alice") {
+		t.Fatalf("open = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "[DEBUG] ") || !strings.HasSuffix(lines[1], prefix+" ping_rtt=23ms user=alice") {
+		t.Fatalf("ping = %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "[INFO] ") || !strings.Contains(lines[2], "websocket connection closed "+prefix+" close_code=1000 reason=bye duration=") ||
+		!strings.HasSuffix(lines[2], " bytes_in=10 bytes_out=20 ping_rtt=23ms user=alice") {
+		t.Fatalf("close = %q", lines[2])
+	}
+}
+
+func TestConn_CloseLevelsAndWrap(t *testing.T) {
+	for code, want := range map[int]Level{1001: InfoLevel, 1008: WarnLevel, 1006: ErrorLevel, 1011: ErrorLevel, 4000: WarnLevel} {
+		if got := closeCodeToLevel(code); got != want {
+			t.Errorf("closeCodeToLevel(%d) = %v, want %v", code, got, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	client, server := net.Pipe()
+	c := Conn("tcp-stream", "pipe")
+	wrapped := c.Wrap(server)
+	go client.Write([]byte("hello"))
+	p := make([]byte, 5)
+	wrapped.Read(p)
+	go client.Read(make([]byte, 3))
+	wrapped.Write([]byte("abc"))
+	c.CloseErr(errors.New("reset by peer"))
+	client.Close()
+
+	if got := buf.String(); !strings.Contains(got, "[ERROR] ") || !strings.Contains(got, "tcp-stream connection failed") ||
+		!strings.Contains(got, "error=reset by peer duration=") || !strings.Contains(got, "bytes_in=5 bytes_out=3") {
+		t.Fatalf("got %q", got)
+	}
+}
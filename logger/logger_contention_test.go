@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestContentionStats(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	ring := NewRingSink(0)
+	AddSink(ring)
+	defer Close()
+	ResetContentionStats()
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				Infof("worker %d entry %d", g, i)
+			}
+		}()
+	}
+	wg.Wait()
+	SyncSinks()
+
+	s := ReadContentionStats()
+	if s.Enabled != contentionEnabled {
+		t.Fatalf("Enabled = %v, want %v", s.Enabled, contentionEnabled)
+	}
+	if !contentionEnabled {
+		if s != (ContentionStats{}) {
+			t.Fatalf("stats without the loggercontention tag should be zero, got %+v", s)
+		}
+		return
+	}
+	if s.Entries < 400 || s.Locks < s.Entries || s.SinkEntries < 400 {
+		t.Fatalf("expected every entry to be measured, got %+v", s)
+	}
+	if s.WriteTime <= 0 || s.MaxWriteTime > s.WriteTime || s.MaxLockWait > s.LockWait || s.QueueLatency <= 0 {
+		t.Fatalf("inconsistent durations %+v", s)
+	}
+	ResetContentionStats()
+	if s := ReadContentionStats(); s.Locks != 0 || s.WriteTime != 0 || s.MaxQueueLatency != 0 {
+		t.Fatalf("ResetContentionStats left %+v", s)
+	}
+}
+
+// BenchmarkContention logs from parallel goroutines to a log file, with
+// and without Config.FileAsync. With -tags loggercontention it also reports
+// the lock wait and write time per entry.
+func BenchmarkContention(b *testing.B) {
+	for _, async := range []bool{false, true} {
+		b.Run(fmt.Sprintf("async=%t", async), func(b *testing.B) {
+			InitWithConfig(Config{Mode: "production", FilePath: filepath.Join(b.TempDir(), "bench.log"), FileAsync: async})
+			captureLevels(&bytes.Buffer{})
+			defer Init("development", true)
+			defer Close()
+			ResetContentionStats()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					InfoKV("request served", "path", "/api/items", "status", 200)
+				}
+			})
+			SyncSinks()
+			if s := ReadContentionStats(); s.Enabled {
+				b.ReportMetric(float64(s.LockWait)/float64(max(s.Locks, 1)), "lock-wait-ns/op")
+				b.ReportMetric(float64(s.WriteTime)/float64(max(s.Entries, 1)), "write-ns/op")
+				b.ReportMetric(float64(s.MaxLockWait)/float64(time.Microsecond), "max-lock-wait-µs")
+			}
+		})
+	}
+}
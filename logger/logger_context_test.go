@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+type traceKey struct{}
+
+func TestContext_FieldsAndExtractors(t *testing.T) {
+	var buf bytes.Buffer
+	Info = log.New(&buf, "", 0)
+	enableLevels(InfoLevel)
+
+	oldExtractors := contextExtractors
+	defer func() { contextExtractors = oldExtractors }()
+	AddContextExtractor(func(ctx context.Context) []any {
+		if id, ok := ctx.Value(traceKey{}).(string); ok {
+			return []any{"trace_id", id}
+		}
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "abc123")
+	ctx = ContextWithFields(ctx, "tenant", "acme")
+	ctx = ContextWithFields(ctx, "region", "eu")
+
+	InfoContext(ctx, "context message", "status", 200)
+
+	out := buf.String()
+	for _, want := range []string{"TestContext_FieldsAndExtractors", "context message", "status=200 tenant=acme region=eu trace_id=abc123"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got: %q", want, out)
+		}
+	}
+}
+
+func TestContext_NilContext(t *testing.T) {
+	var buf bytes.Buffer
+	Warning = log.New(&buf, "", 0)
+	enableLevels(WarnLevel)
+
+	var ctx context.Context
+	WarnContext(ctx, "nil context", "k", "v")
+
+	if out := buf.String(); !strings.Contains(out, "nil context k=v") {
+		t.Fatalf("expected entry without context fields, got: %q", out)
+	}
+}
+
+func TestContext_LevelFiltering(t *testing.T) {
+	var buf bytes.Buffer
+	Debug = log.New(&buf, "", 0)
+	defer SetMinLevel(InfoLevel)()
+
+	DebugContext(context.Background(), "filtered")
+
+	if buf.Len() != 0 {
+		t.Fatalf("disabled level should not log, got: %q", buf.String())
+	}
+}
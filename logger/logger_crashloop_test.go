@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRestartStateFile_DetectsCrashLoop(t *testing.T) {
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	t.Setenv("JOURNAL_STREAM", "")
+	defer Init("development", true)
+	restartRecorded.Store(false)
+	t.Cleanup(func() { restartRecorded.Store(true) })
+
+	path := filepath.Join(t.TempDir(), "state", "starts")
+	now := processStart
+	var state strings.Builder
+	for _, ago := range []time.Duration{time.Hour, 3 * time.Minute, 2 * time.Minute, time.Minute} {
+		state.WriteString(now.Add(-ago).UTC().Format(time.RFC3339Nano) + "\n")
+	}
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte(state.String()), 0644)
+
+	cfg := Config{Mode: "production", RestartStateFile: path}
+	InitWithConfig(cfg)
+	out := buf.String()
+	if !strings.Contains(out, "[WARN] [logger.InitWithConfig] crash loop detected event=logger.crash_loop restarts=3 window=5m0s interval=1m0s last_start=") {
+		t.Fatalf("missing crash loop warning: %q", out)
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 4 {
+		t.Fatalf("expected the old start pruned and this one added, got %q", data)
+	}
+
+	// reconfiguring is not a restart
+	buf.Reset()
+	InitWithConfig(cfg)
+	if strings.Contains(buf.String(), "crash loop") {
+		t.Fatalf("expected one check per process, got %q", buf.String())
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(after, data) {
+		t.Fatalf("expected the state file unchanged, got %q", after)
+	}
+}
+
+func TestRestartStateFile_BelowThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	defer Init("development", true)
+	restartRecorded.Store(false)
+	t.Cleanup(func() { restartRecorded.Store(true) })
+
+	path := filepath.Join(t.TempDir(), "starts")
+	InitWithConfig(Config{Mode: "production", RestartStateFile: path, CrashLoopRestarts: 2})
+	if strings.Contains(buf.String(), CrashLoopEvent) {
+		t.Fatalf("unexpected warning on a first start: %q", buf.String())
+	}
+	if data, err := os.ReadFile(path); err != nil || strings.Count(string(data), "\n") != 1 {
+		t.Fatalf("expected this start recorded, got %q, %v", data, err)
+	}
+}
+
+func TestRestartStateFile_SkippedInCrashMonitor(t *testing.T) {
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	defer Init("development", true)
+	restartRecorded.Store(false)
+	t.Cleanup(func() { restartRecorded.Store(true) })
+	t.Setenv(crashMonitorEnv, "1")
+
+	path := filepath.Join(t.TempDir(), "starts")
+	InitWithConfig(Config{Mode: "production", RestartStateFile: path})
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the monitor's start not recorded, got %v", err)
+	}
+	if restartRecorded.Load() {
+		t.Fatal("expected the monitor to leave the start of its parent unrecorded")
+	}
+}
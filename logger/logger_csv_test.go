@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSVEncoder_ColumnsAndQuoting(t *testing.T) {
+	enc := CSVEncoder{Columns: []string{"time", "level", "msg", "user", "missing", "fields"}, TimeFormat: time.DateOnly}
+	e := &Entry{
+		Time:    time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		Level:   InfoLevel,
+		Caller:  "api.Login:10",
+		Message: "said \"hi\", left\nearly",
+		Fields:  []any{"user", "ana", "status", 200, "path", "/a,b"},
+	}
+	got, err := enc.Encode(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "2026-03-04,INFO,\"said \"\"hi\"\", left\nearly\",ana,,\"status=200 path=/a,b\""
+	if string(got) != want {
+		t.Fatalf("got  %q\nwant %q", got, want)
+	}
+	if h := string(enc.Header()); h != "time,level,msg,user,missing,fields" {
+		t.Fatalf("header = %q", h)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(string(enc.Header()) + "\n" + string(got) + "\n")).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1][2] != e.Message || records[1][5] != "status=200 path=/a,b" {
+		t.Fatalf("round trip = %q", records)
+	}
+}
+
+func TestCSVEncoder_Defaults(t *testing.T) {
+	got, _ := CSVEncoder{Comma: ';'}.Encode(&Entry{
+		Time:    time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		Level:   ErrorLevel,
+		Caller:  "db.Query:7",
+		Message: "failed",
+		Fields:  []any{"table", "users"},
+	})
+	if want := "2026-03-04T05:06:07Z;ERROR;db.Query:7;failed;table=users"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
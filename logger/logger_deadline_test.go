@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type remoteMemorySink struct{ memorySink }
+
+func (*remoteMemorySink) Remote() bool { return true }
+
+func TestDeadline_SkipsRemoteSinksNearDeadline(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer SetOutputs(&buf, &buf)()
+	logMutex.Lock()
+	closeSinks()
+	logMutex.Unlock()
+	local, remote := &memorySink{}, &remoteMemorySink{}
+	AddSink(local)
+	AddSink(remote)
+	defer func() {
+		logMutex.Lock()
+		closeSinks()
+		logMutex.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	InfoDeadline(ctx, "plenty of time")
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	WarnDeadline(ctx, "almost out of time")
+	InfoContext(ctx, "not deadline aware")
+	SyncSinks()
+
+	if len(local.lines) != 3 {
+		t.Fatalf("expected every entry in the local sink, got %q", local.lines)
+	}
+	if len(remote.lines) != 2 || strings.Contains(strings.Join(remote.lines, "\n"), "almost out of time") {
+		t.Fatalf("expected the remote sink to skip the entry near its deadline, got %q", remote.lines)
+	}
+	if !strings.Contains(buf.String(), "almost out of time") {
+		t.Fatalf("expected the console to get the entry, got %q", buf.String())
+	}
+	for _, s := range SinkStats() {
+		if want := int64(map[Sink]int{remote: 1}[s.Sink]); s.Skipped != want {
+			t.Errorf("%s: expected %d skipped, got %d", s.Name, want, s.Skipped)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFlushDebugWindow(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", ">=INFO")
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	InitWithConfig(Config{Mode: "development", DebugWindow: 3})
+	defer Init("development", true)
+
+	for i := range 5 {
+		DebugKV("step", "n", i)
+	}
+	Get("db").Debug("query planned")
+	Infof("working")
+	if got := buf.String(); strings.Contains(got, "step") || !strings.Contains(got, "working") {
+		t.Fatalf("expected DEBUG entries held back, got %q", got)
+	}
+
+	buf.Reset()
+	FlushDebugWindow("sync failed")
+	got := buf.String()
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 entries, got %q", got)
+	}
+	if !strings.Contains(lines[0], "[INFO]") || !strings.Contains(lines[0], "event="+DebugWindowEvent) ||
+		!strings.Contains(lines[0], "reason=sync failed entries=3") {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	for i, want := range []string{"step n=3", "step n=4", "query planned logger=db"} {
+		if !strings.Contains(lines[i+1], "[DEBUG]") || !strings.Contains(lines[i+1], want) {
+			t.Fatalf("line %d = %q, want %q", i+1, lines[i+1], want)
+		}
+	}
+
+	buf.Reset()
+	FlushDebugWindow("again")
+	if buf.Len() != 0 {
+		t.Fatalf("expected an empty window after a flush, got %q", buf.String())
+	}
+}
+
+func TestFlushDebugWindow_Off(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", ">=INFO")
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	Init("development", true)
+	defer Init("development", true)
+
+	Debugf("step")
+	FlushDebugWindow("failure")
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output without Config.DebugWindow, got %q", buf.String())
+	}
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDeprecated_LogsOncePerSubject(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer deprecations.Clear()
+
+	for range 3 {
+		Deprecated("flag --old", "use --new", "since", "v2.3")
+	}
+	Deprecated("config key cache_mb", "")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one entry per subject, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], "TestDeprecated_LogsOncePerSubject") ||
+		!strings.HasSuffix(lines[0], "flag --old is deprecated deprecation=true subject=flag --old replacement=use --new since=v2.3") {
+		t.Errorf("unexpected entry %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "config key cache_mb is deprecated deprecation=true subject=config key cache_mb") {
+		t.Errorf("unexpected entry %q", lines[1])
+	}
+}
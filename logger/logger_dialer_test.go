@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingDialer_RotatesAndReresolves(t *testing.T) {
+	answers := [][]string{{"10.0.0.1", "10.0.0.2"}, {"10.0.0.3"}}
+	lookups := 0
+	d, err := newRotatingDialer("collector.internal:514", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttl := time.Duration(0)
+	d.lookup = func(ctx context.Context, host string) ([]string, time.Duration, error) {
+		if host != "collector.internal" {
+			t.Fatalf("unexpected host %q", host)
+		}
+		lookups++
+		return answers[min(lookups-1, len(answers)-1)], ttl, nil
+	}
+
+	var got []string
+	for range 3 {
+		addr, err := d.target(time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, addr)
+	}
+	if strings.Join(got, ",") != "10.0.0.1:514,10.0.0.2:514,10.0.0.1:514" || lookups != 1 {
+		t.Fatalf("expected rotation over one lookup, got %v after %d lookups", got, lookups)
+	}
+
+	d.expires = time.Now().Add(-time.Second)
+	ttl = 30 * time.Second
+	if addr, _ := d.target(time.Second); addr != "10.0.0.3:514" || lookups != 2 {
+		t.Fatalf("expected a fresh lookup once stale, got %s after %d lookups", addr, lookups)
+	}
+	if left := time.Until(d.expires); left > ttl || left < ttl-time.Second {
+		t.Fatalf("expected the answer cached for its 30s TTL, expires in %v", left)
+	}
+	ttl = 2 * time.Hour
+	d.expires = time.Now().Add(-time.Second)
+	d.target(time.Second)
+	if left := time.Until(d.expires); left > time.Hour || left < time.Hour-time.Second {
+		t.Fatalf("expected a TTL over the refresh interval capped at it, expires in %v", left)
+	}
+
+	none, _ := newRotatingDialer("collector.internal:514", 0)
+	if none != nil || none.expired() {
+		t.Fatal("expected no dialer, which never expires, without a refresh interval")
+	}
+}
+
+func TestSyslogSink_DNSRefreshReconnects(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	conns := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conns <- readOctetFrame(t, bufio.NewReader(conn))
+			}()
+		}
+	}()
+
+	oldLookup := lookupHost
+	defer func() { lookupHost = oldLookup }()
+	lookupHost = func(ctx context.Context, host string) ([]string, time.Duration, error) {
+		return []string{"127.0.0.1"}, 0, nil
+	}
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	s, err := NewSyslogSink(SyslogConfig{Network: "tcp", Address: "collector.internal:" + port, DNSRefresh: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	s.WriteEntry(testEntry())
+	time.Sleep(30 * time.Millisecond)
+	s.WriteEntry(testEntry())
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-conns:
+			if !strings.HasSuffix(msg, "disk full path=/var") {
+				t.Fatalf("unexpected message %q", msg)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected each entry on its own connection, got %d", i)
+		}
+	}
+}
+
+func TestLookupHostTTL_ReadsRecordTTLs(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp unavailable: %v", err)
+	}
+	defer pc.Close()
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			q := buf[:n]
+			qtype := q[n-3]
+			// response header: same ID, QR and RA set, one question, and
+			// for A queries a CNAME (TTL 300) and an A record (TTL 60)
+			resp := append([]byte{q[0], q[1], 0x81, 0x80, 0, 1, 0, 0, 0, 0, 0, 0}, q[12:]...)
+			if qtype == 1 {
+				resp[7] = 2
+				resp = append(resp, 0xc0, 12, 0, 5, 0, 1, 0, 0, 0x01, 0x2c, 0, 2, 0xc0, 12)
+				resp = append(resp, 0xc0, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 10, 0, 0, 7)
+			}
+			pc.WriteTo(resp, addr)
+		}
+	}()
+
+	oldServers := dnsServers
+	defer func() { dnsServers = oldServers }()
+	dnsServers = func() []string { return []string{pc.LocalAddr().String()} }
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	addrs, ttl, err := lookupHostTTL(ctx, "collector.internal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(addrs, ",") != "10.0.0.7" || ttl != 60*time.Second {
+		t.Fatalf("expected 10.0.0.7 with the smallest TTL 60s, got %v %v", addrs, ttl)
+	}
+}
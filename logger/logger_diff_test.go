@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type diffConfig struct {
+	Addr    string        `json:"addr"`
+	Timeout time.Duration `json:"timeout"`
+	Secret  string        `json:"-"`
+	DB      struct {
+		Pool  int `json:"pool_size"`
+		Hosts []string
+	} `json:"db"`
+	Labels  map[string]string `json:"labels"`
+	Started time.Time         `json:"started"`
+	private int
+}
+
+func TestDiff_StructFields(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	var old, cur diffConfig
+	old.Addr, cur.Addr = ":80", ":80"
+	old.Timeout, cur.Timeout = time.Second, 2*time.Second
+	old.Secret, cur.Secret = "a", "b"
+	old.DB.Pool, cur.DB.Pool = 10, 20
+	old.DB.Hosts, cur.DB.Hosts = []string{"a"}, []string{"a"}
+	old.Labels = map[string]string{"team": "core", "tier": "1"}
+	cur.Labels = map[string]string{"team": "core", "zone": "eu"}
+	old.private, cur.private = 1, 2
+
+	Diff("config reloaded", old, &cur, "source", "sighup")
+	want := "[INFO] [logger.TestDiff_StructFields:"
+	got := buf.String()
+	if !strings.HasPrefix(got, want) {
+		t.Fatalf("got %q", got)
+	}
+	if !strings.HasSuffix(got, "] config reloaded changed=4 db.pool_size=10->20 labels.tier=1-><unset> labels.zone=<unset>->eu timeout=1s->2s source=sighup\n") {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestDiffFields_Values(t *testing.T) {
+	if got := diffFields(1, 2); len(got) != 2 || got[0] != "value" || got[1] != "1->2" {
+		t.Fatalf("scalar diff = %v", got)
+	}
+	if got := diffFields(map[string]any{"a": []int{1}}, map[string]any{"a": []int{1}}); len(got) != 0 {
+		t.Fatalf("equal maps diff = %v", got)
+	}
+	if got := diffFields(nil, map[string]int{"n": 1}); len(got) != 2 || got[1] != "<unset>->1" {
+		t.Fatalf("nil vs map diff = %v", got)
+	}
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDumpRequest_RedactsAndRestoresBody(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	req := httptest.NewRequest("POST", "https://api.example.com/v1/items?id=1", strings.NewReader(`{"name":"a","note":"long text"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Cookie", "session=secret")
+	req.Header.Set("Content-Type", "application/json")
+	DumpRequest(req, 12)
+
+	got := buf.String()
+	want := `] http request method=POST url=https://api.example.com/v1/items?id=1 headers=Authorization: [REDACTED]; Content-Type: application/json; Cookie: [REDACTED] body={"name":"a", body_truncated=true` + "\n"
+	if !strings.HasPrefix(got, "[DEBUG] [logger.TestDumpRequest_RedactsAndRestoresBody:") || !strings.HasSuffix(got, want) {
+		t.Fatalf("got %q", got)
+	}
+	if strings.Contains(got, "secret") {
+		t.Fatal("credentials leaked")
+	}
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != `{"name":"a","note":"long text"}` {
+		t.Fatalf("restored body = %q", body)
+	}
+}
+
+func TestDumpResponse(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	req, _ := http.NewRequest("GET", "http://example.com/x", nil)
+	resp := &http.Response{
+		StatusCode: 404,
+		Header:     http.Header{"Set-Cookie": {"a=b"}},
+		Body:       io.NopCloser(strings.NewReader("héllo")),
+		Request:    req,
+	}
+	DumpResponse(resp, 2)
+	if got := buf.String(); !strings.HasSuffix(got, "] http response status=404 url=http://example.com/x headers=Set-Cookie: [REDACTED] body=h body_truncated=true\n") {
+		t.Fatalf("got %q", got)
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != "héllo" {
+		t.Fatalf("restored body = %q", body)
+	}
+
+	buf.Reset()
+	defer SetMinLevel(InfoLevel)()
+	DumpResponse(resp, 10)
+	if buf.Len() != 0 {
+		t.Fatalf("dumped with DEBUG disabled: %q", buf.String())
+	}
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+type panicMarshaler struct{}
+
+func (panicMarshaler) MarshalJSON() ([]byte, error) { panic("broken MarshalJSON") }
+
+func TestEmergency_HeapExhausted(t *testing.T) {
+	var buf bytes.Buffer
+	oldStderr := outStderr
+	defer func() { outStderr = oldStderr }()
+	outStderr = &buf
+	var console bytes.Buffer
+	captureLevels(&console)
+	defer Init("development", true)
+
+	old := debug.SetMemoryLimit(1)
+	logMutex.Lock()
+	output(Fatal, FatalLevel, "main.main:9", "cannot allocate", []any{"size", 1 << 30, "err", errors.New("no memory"), "skipped", 1.5, "ok", true})
+	logMutex.Unlock()
+	debug.SetMemoryLimit(old)
+
+	line := buf.String()
+	if !strings.HasSuffix(line, " [FATAL] [main.main:9] cannot allocate size=1073741824 err=no memory ok=true\n") {
+		t.Fatalf("unexpected emergency line %q", line)
+	}
+	if e, err := ParseLine(line); err != nil || e.Level != FatalLevel || e.Time.IsZero() {
+		t.Fatalf("emergency line should parse back, got %+v, %v", e, err)
+	}
+	if console.Len() != 0 {
+		t.Fatalf("regular path should be bypassed, got %q", console.String())
+	}
+}
+
+func TestEmergency_FormattingPanic(t *testing.T) {
+	var buf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout, outStderr = &buf, &buf
+	defer Init("development", true)
+	InitWithConfig(Config{Mode: "production", Fallback: FallbackJSON})
+
+	logMutex.Lock()
+	output(Fatal, FatalLevel, "main.main:9", "bad field", []any{"v", panicMarshaler{}})
+	logMutex.Unlock()
+	if !strings.HasSuffix(buf.String(), " [FATAL] [main.main:9] bad field\n") {
+		t.Fatalf("panicking entry should be written through the emergency path, got %q", buf.String())
+	}
+}
+
+func TestEmergency_TruncatesWithoutAllocating(t *testing.T) {
+	var buf bytes.Buffer
+	oldStderr := outStderr
+	defer func() { outStderr = oldStderr }()
+	outStderr = &buf
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	writeEmergency("main.main:9", strings.Repeat("x", 2*EmergencyBufferSize), []any{"n", 1})
+	if buf.Len() != EmergencyBufferSize || !strings.HasSuffix(buf.String(), "x\n") {
+		t.Fatalf("expected a %d-byte line ending in a newline, got %d bytes", EmergencyBufferSize, buf.Len())
+	}
+	outStderr = io.Discard
+	if n := testing.AllocsPerRun(10, func() { writeEmergency("main.main:9", "out of memory", []any{"n", 1, "ok", true}) }); n != 0 {
+		t.Fatalf("emergency path allocated %v times", n)
+	}
+}
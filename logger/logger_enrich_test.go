@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKubernetesFields(t *testing.T) {
+	dir := t.TempDir()
+	id := strings.Repeat("ab12", 16)
+	cgroup := filepath.Join(dir, "cgroup")
+	os.WriteFile(cgroup, []byte("0::/kubepods.slice/kubepods-pod1.slice/cri-containerd-"+id+".scope\n"), 0644)
+	ns := filepath.Join(dir, "namespace")
+	os.WriteFile(ns, []byte("payments\n"), 0644)
+	oldCgroup, oldNS := cgroupPath, serviceAccountNSPath
+	defer func() { cgroupPath, serviceAccountNSPath = oldCgroup, oldNS }()
+	cgroupPath, serviceAccountNSPath = cgroup, ns
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	if fields := KubernetesFields(); fields != nil {
+		t.Fatalf("expected no fields outside Kubernetes, got %v", fields)
+	}
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("POD_NAME", "")
+	t.Setenv("HOSTNAME", "api-7d9f-xk2lp")
+	t.Setenv("POD_NAMESPACE", "")
+	t.Setenv("NODE_NAME", "node-3")
+	got := KubernetesFields()
+	want := []any{PodKey, "api-7d9f-xk2lp", NamespaceKey, "payments", NodeKey, "node-3", ContainerIDKey, id}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestConfigFields_AddedToEveryEntry(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("POD_NAME", "api-0")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("NODE_NAME", "")
+	oldCgroup := cgroupPath
+	defer func() { cgroupPath = oldCgroup }()
+	cgroupPath = filepath.Join(t.TempDir(), "missing")
+
+	InitWithConfig(Config{Mode: "development", Fields: []any{"region", "eu-west-1"}})
+	defer func() {
+		t.Setenv("KUBERNETES_SERVICE_HOST", "")
+		Init("development", true)
+	}()
+	var buf bytes.Buffer
+	captureLevels(&buf)
+
+	InfoKV("ready", "region", "local")
+	if got, want := buf.String(), "ready region=local k8s_pod=api-0 k8s_namespace=default\n"; !strings.HasSuffix(got, want) {
+		t.Fatalf("got %q, want suffix %q", got, want)
+	}
+
+	InitWithConfig(Config{Mode: "development", DisableKubernetesFields: true})
+	captureLevels(&buf)
+	buf.Reset()
+	Infof("plain")
+	if strings.Contains(buf.String(), "k8s_") {
+		t.Fatalf("expected Kubernetes fields to be disabled, got %q", buf.String())
+	}
+}
+
+func TestParseEnvFields(t *testing.T) {
+	for in, want := range map[string]string{
+		``:                                `[]`,
+		`region=eu-west-1 cluster=prod-3`: `[region eu-west-1 cluster prod-3]`,
+		`team="core infra"  empty= note="a \"b\""`: `[team core infra empty  note a "b"]`,
+		`{"region": "eu-west-1", "replicas": 3}`:   `[region eu-west-1 replicas 3]`,
+	} {
+		got, err := parseEnvFields(in)
+		if err != nil {
+			t.Errorf("parseEnvFields(%q): %v", in, err)
+			continue
+		}
+		if s := fmt.Sprint(got); s != want {
+			t.Errorf("parseEnvFields(%q) = %s, want %s", in, s, want)
+		}
+	}
+	for _, in := range []string{`region`, `=x`, `a="open`, `a="b"c`, `{"a": 1`, `{"a": 1} x`, `["a"]`, `[1]`} {
+		if _, err := parseEnvFields(in); err == nil {
+			t.Errorf("parseEnvFields(%q): expected error", in)
+		}
+	}
+}
+
+func TestLoggerFieldsEnv_AddedAtInit(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("LOGGER_FIELDS", "region=eu-west-1 cluster=prod-3")
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	InitWithConfig(Config{Mode: "development", Fields: []any{"region", "us-east-1"}})
+	defer Init("development", true)
+	Infof("ready")
+	if got := buf.String(); !strings.HasSuffix(got, "ready region=us-east-1 cluster=prod-3\n") {
+		t.Fatalf("expected env fields after Config.Fields, got %q", got)
+	}
+
+	buf.Reset()
+	t.Setenv("LOGGER_FIELDS", "region")
+	Init("development", false)
+	if got := buf.String(); !strings.Contains(got, "ignoring LOGGER_FIELDS") {
+		t.Fatalf("expected warning for malformed LOGGER_FIELDS, got %q", got)
+	}
+	if err := Validate(Config{Mode: "development"}); err == nil || !strings.Contains(err.Error(), "LOGGER_FIELDS") {
+		t.Fatalf("expected Validate to report LOGGER_FIELDS, got %v", err)
+	}
+}
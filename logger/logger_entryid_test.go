@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var uuidv7 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// entrySink records the entries it receives.
+type entrySink struct {
+	entries []*Entry
+}
+
+func (s *entrySink) WriteEntry(e *Entry) error {
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+func (s *entrySink) Close() error { return nil }
+
+func TestEntryIDs(t *testing.T) {
+	InitWithConfig(Config{Mode: "development", EntryIDs: true})
+	captureLevels(new(bytes.Buffer))
+	sink := &entrySink{}
+	AddSink(sink)
+	defer Init("development", true)
+
+	InfoKV("first")
+	InfoKV("second")
+	InfoKV("own id", EntryIDKey, "ticket-42")
+	SyncSinks()
+
+	entries := sink.entries
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries", len(entries))
+	}
+	first, second := entries[0].ID(), entries[1].ID()
+	if !uuidv7.MatchString(first) || !uuidv7.MatchString(second) || first >= second {
+		t.Fatalf("ids %q, %q", first, second)
+	}
+	if got := entries[2].ID(); got != "ticket-42" {
+		t.Fatalf("explicit id = %q", got)
+	}
+	if got := testEntry().ID(); got != "" {
+		t.Fatalf("entry without id = %q", got)
+	}
+}
+
+func TestNewEntryID_EncodesTime(t *testing.T) {
+	at := time.Date(2026, 3, 2, 9, 14, 3, 512_250_000, time.UTC)
+	id := newEntryID(at)
+	// 48-bit milliseconds, version 7, then 0.25ms as 1024/4096
+	if want := "019cadd3-7978-7400-"; id[:19] != want {
+		t.Fatalf("id %s, want prefix %s", id, want)
+	}
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestNewError_FieldsMergedIntoErrorKV(t *testing.T) {
+	var buf bytes.Buffer
+	Error = log.New(&buf, "", 0)
+	enableLevels(ErrorLevel)
+
+	err := NewError("query failed", "table", "users", "attempt", 3)
+	ErrorKV("request failed", "error", err, "attempt", 1)
+
+	out := buf.String()
+	if !strings.Contains(out, "error=query failed") || !strings.Contains(out, "table=users") {
+		t.Fatalf("expected error message and attached fields, got: %q", out)
+	}
+	if strings.Contains(out, "attempt=3") || !strings.Contains(out, "attempt=1") {
+		t.Fatalf("explicit fields should win over attached fields, got: %q", out)
+	}
+}
+
+func TestWithFieldsErr_WrappedChain(t *testing.T) {
+	base := WithFieldsErr(io.EOF, "file", "config.yaml")
+	wrapped := fmt.Errorf("load: %w", WithFieldsErr(base, "stage", "parse"))
+
+	if !errors.Is(wrapped, io.EOF) {
+		t.Fatal("wrapped error should still match io.EOF")
+	}
+	if wrapped.Error() != "load: EOF" {
+		t.Fatalf("WithFieldsErr should not change the message, got %q", wrapped.Error())
+	}
+	fields := fmt.Sprint(ErrorFields(wrapped))
+	if fields != "[stage parse file config.yaml]" {
+		t.Fatalf("expected outermost fields first, got %s", fields)
+	}
+	if WithFieldsErr(nil, "k", "v") != nil {
+		t.Fatal("WithFieldsErr(nil) should return nil")
+	}
+}
+
+func TestErrorFields_Join(t *testing.T) {
+	joined := errors.Join(
+		WithFieldsErr(io.EOF, "file", "a.yaml"),
+		fmt.Errorf("b: %w", WithFieldsErr(io.ErrUnexpectedEOF, "file", "b.yaml", "line", 3)),
+	)
+	wrapped := WithFieldsErr(fmt.Errorf("load: %w", joined), "stage", "parse")
+
+	fields := fmt.Sprint(ErrorFields(wrapped))
+	if fields != "[stage parse file a.yaml file b.yaml line 3]" {
+		t.Fatalf("expected fields from every joined error, got %s", fields)
+	}
+}
+
+func TestErr_LogsMessageAndFields(t *testing.T) {
+	var buf bytes.Buffer
+	Error = log.New(&buf, "", 0)
+	enableLevels(ErrorLevel)
+
+	Err(WithFieldsErr(errors.New("disk full"), "path", "/var/log"), "retry", false)
+	Err(nil)
+
+	out := strings.TrimSpace(buf.String())
+	if strings.Count(out, "\n") != 0 {
+		t.Fatalf("Err(nil) should not log, got: %q", out)
+	}
+	if !strings.Contains(out, "TestErr_LogsMessageAndFields") || !strings.HasSuffix(out, "disk full retry=false path=/var/log") {
+		t.Fatalf("unexpected Err output: %q", out)
+	}
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEvent_RegisteredTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	RegisterEvent("user_login", WarnLevel, "user {user} logged in via {method} from {ip}")
+	Event("user_login", "user", "alice", "method", "sso")
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "[WARN] ") {
+		t.Fatalf("expected the registered level, got %q", got)
+	}
+	want := "user alice logged in via sso from {ip} event=user_login user=alice method=sso"
+	if !strings.Contains(got, want) {
+		t.Fatalf("expected %q in %q", want, got)
+	}
+}
+
+func TestEvent_Unregistered(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	Event("cache_warmed", "entries", 42)
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "[INFO] ") || !strings.Contains(got, "cache_warmed event=cache_warmed entries=42") {
+		t.Fatalf("unexpected output for an unregistered event: %q", got)
+	}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRedactArgs(t *testing.T) {
+	got := redactArgs([]string{"--user", "bob", "--password", "hunter2", "--token=abc", "-v"}, []string{"--password", "--token"})
+	want := "--user bob --password [REDACTED] --token=[REDACTED] -v"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("redactArgs = %q, want %q", strings.Join(got, " "), want)
+	}
+}
+
+func TestRunCommand_LogsLifecycle(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	if err := RunCommand(exec.Command(sh, "-c", "exit 0", "--secret=x"), CommandConfig{Name: "ok", Redact: []string{"--secret"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RunCommand(exec.Command(sh, "-c", "exit 3"), CommandConfig{Name: "fails"}); err == nil {
+		t.Fatal("expected an exit error")
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"process started cmd=ok pid=",
+		"args=-c exit 0 --secret=[REDACTED]",
+		"[INFO] [logger.TestRunCommand_LogsLifecycle:",
+		"process exited cmd=ok pid=",
+		"exit_code=0 duration=",
+		"[WARN] [logger.TestRunCommand_LogsLifecycle:",
+		"exit_code=3 duration=",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestCommand_KilledBySignal(t *testing.T) {
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep not available")
+	}
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	c, err := StartCommand(exec.Command(sleep, "10"), CommandConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Cmd.Process.Kill()
+	c.Wait()
+
+	out := buf.String()
+	if !strings.Contains(out, "[ERROR] ") || !strings.Contains(out, "process killed cmd=sleep") || !strings.Contains(out, "signal=killed") {
+		t.Fatalf("expected a killed entry with the signal, got:\n%s", out)
+	}
+}
+
+func TestStartCommand_FailureAndParentSocket(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	cmd := exec.Command("/nonexistent/binary")
+	if _, err := StartCommand(cmd, CommandConfig{ParentSocket: "/run/app/logs.sock"}); err == nil {
+		t.Fatal("expected a start error")
+	}
+	if !strings.Contains(buf.String(), "[ERROR] ") || !strings.Contains(buf.String(), "process failed to start cmd=binary") {
+		t.Fatalf("expected a start failure entry, got:\n%s", buf.String())
+	}
+	if env := cmd.Env[len(cmd.Env)-1]; env != ParentEnv+"=/run/app/logs.sock" {
+		t.Fatalf("expected LOGGER_PARENT in the child environment, got %q", env)
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExemption_Matches(t *testing.T) {
+	e := &Entry{Message: "charge captured", Fields: []any{LoggerKey, "payments.stripe", "audit", true}}
+	for _, tc := range []struct {
+		x    Exemption
+		want bool
+	}{
+		{Exemption{}, false},
+		{Exemption{Logger: "payments"}, true},
+		{Exemption{Logger: "pay"}, false},
+		{Exemption{Message: "charge captured"}, true},
+		{Exemption{Message: "charge"}, false},
+		{Exemption{Field: "audit"}, true},
+		{Exemption{Field: "audit", Value: "true"}, true},
+		{Exemption{Field: "audit", Value: "false"}, false},
+		{Exemption{Logger: "payments", Field: "refund"}, false},
+	} {
+		if got := tc.x.matches(e); got != tc.want {
+			t.Errorf("%+v.matches = %v, want %v", tc.x, got, tc.want)
+		}
+	}
+}
+
+func TestExemptions_BypassVolumeControls(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	InitWithConfig(Config{
+		Mode:                "development",
+		Verbose:             true,
+		MaxEntriesPerSecond: 2,
+		Exemptions:          []Exemption{{Field: "audit", Value: "true"}, {Logger: "payments"}},
+	})
+	defer Init("development", true)
+
+	for i := range 20 {
+		InfoKV("request", "n", i)
+		InfoKV("login", "n", i, "audit", true)
+	}
+	got := buf.String()
+	if n := strings.Count(got, " request n="); n >= 20 {
+		t.Fatalf("expected the rate limit to drop plain entries, got %d", n)
+	}
+	if n := strings.Count(got, " login n="); n != 20 {
+		t.Fatalf("expected every audit entry, got %d", n)
+	}
+
+	buf.Reset()
+	logMutex.Lock()
+	policy = &activePolicy{Policy: Policy{Drop: []string{"charge"}}}
+	logMutex.Unlock()
+	defer func() {
+		logMutex.Lock()
+		policy = nil
+		logMutex.Unlock()
+	}()
+	Get("payments.stripe").Warn("charge captured")
+	Get("http").Warn("charge page viewed")
+	if got := buf.String(); !strings.Contains(got, "charge captured") || strings.Contains(got, "charge page") {
+		t.Fatalf("expected the payments entry kept and the other dropped, got %q", got)
+	}
+
+	governorMin.Store(int32(ErrorLevel))
+	defer governorMin.Store(int32(DebugLevel))
+	buf.Reset()
+	Get("payments").Info("refund issued")
+	Infof("suppressed")
+	if got := buf.String(); !strings.Contains(got, "refund issued") || strings.Contains(got, "suppressed") {
+		t.Fatalf("expected the governor to skip only the exempt entry, got %q", got)
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpect_SilentAndResumed(t *testing.T) {
+	var buf lockedBuffer
+	captureLevels(new(bytes.Buffer))
+	Warning = log.New(&buf, "[WARN] ", 0)
+	Error = log.New(&buf, "[ERROR] ", 0)
+	defer Init("development", true)
+
+	h := Expect("poller", 20*time.Millisecond)
+	defer h.Stop()
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "goroutine silent") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	logMutex.Lock()
+	logMutex.Unlock()
+	h.Kick()
+	h.Stop()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 ||
+		!strings.HasPrefix(lines[0], "[ERROR] [logger.TestExpect_SilentAndResumed:") ||
+		!strings.Contains(lines[0], "] goroutine silent name=poller last_seen=") ||
+		!strings.HasSuffix(lines[0], " interval=20ms") ||
+		!strings.Contains(lines[1], "] goroutine resumed name=poller silent_for=") {
+		t.Fatalf("got:\n%s", buf.String())
+	}
+}
+
+func TestExpect_KickedInTime(t *testing.T) {
+	var buf lockedBuffer
+	captureLevels(new(bytes.Buffer))
+	Error = log.New(&buf, "[ERROR] ", 0)
+	defer Init("development", true)
+
+	h := Expect("worker", 50*time.Millisecond)
+	for range 5 {
+		time.Sleep(10 * time.Millisecond)
+		h.Kick()
+	}
+	h.Stop()
+	time.Sleep(60 * time.Millisecond)
+	if got := buf.String(); got != "" {
+		t.Fatalf("got %q", got)
+	}
+}
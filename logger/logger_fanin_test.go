@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer is a bytes.Buffer safe for concurrent writes and reads.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestServeChildren_ReceivesChildEntries(t *testing.T) {
+	if os.Getenv("TEST_FANIN_CHILD") == "1" {
+		InitWithConfig(Config{Mode: "production", Identifier: "worker"})
+		if err := ConnectParent(); err != nil {
+			t.Fatal(err)
+		}
+		WarnKV("disk low", "free", "5%")
+		Close()
+		return
+	}
+
+	var buf lockedBuffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout, outStderr = &buf, &buf
+	Init("production", false)
+	defer Init("development", true)
+
+	path := filepath.Join(t.TempDir(), "children.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- ServeChildren(ln) }()
+	var once sync.Once
+	var serveErr error
+	stop := func() error {
+		once.Do(func() {
+			ln.Close()
+			serveErr = <-served
+		})
+		return serveErr
+	}
+	defer stop()
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestServeChildren_ReceivesChildEntries")
+	cmd.Env = append(os.Environ(), "TEST_FANIN_CHILD=1", ParentEnv+"="+path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("child failed: %v\n%s", err, out)
+	}
+
+	want := "disk low free=5% child=worker child_pid="
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(buf.String(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %q from the child, got: %q", want, buf.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if line := buf.String(); !strings.HasPrefix(line, "[WARN] [logger.TestServeChildren_ReceivesChildEntries:") {
+		t.Fatalf("expected the child's level and caller to be kept, got %q", line)
+	}
+
+	if err := stop(); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected ServeChildren to return net.ErrClosed, got %v", err)
+	}
+}
+
+func TestServeChildren_ChildEntriesUseThePipeline(t *testing.T) {
+	var buf lockedBuffer
+	defer SetOutputs(&buf, &buf)()
+	InitWithConfig(Config{Mode: "production", Sanitize: SanitizeBasic, Fields: []any{"region", "eu"}})
+	defer Init("development", true)
+
+	parent, child := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		serveChild(parent)
+		close(done)
+	}()
+	e := &Entry{Time: time.Now(), Level: WarnLevel, Caller: "worker.go:7", Message: "bad \x1b[31minput", Fields: []any{ChildKey, "worker"}}
+	line, _ := JSONEncoder{}.Encode(e)
+	child.Write(append(line, '\n'))
+	child.Close()
+	<-done
+
+	got := buf.String()
+	if strings.Contains(got, "\x1b") || !strings.Contains(got, "child=worker region=eu") {
+		t.Fatalf("expected the child entry to be sanitized and given global fields, got %q", got)
+	}
+}
+
+func TestConnectParent_WithoutParent(t *testing.T) {
+	t.Setenv(ParentEnv, "")
+	if err := ConnectParent(); !errors.Is(err, ErrNoParent) {
+		t.Fatalf("expected ErrNoParent, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package logger
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -281,3 +282,67 @@ func TestFatal_OutputFormat(t *testing.T) {
 		t.Fatalf("expected caller info in output, got: %q", outputStr)
 	}
 }
+
+// TestCrashMonitor_LogsUnrecoveredPanic verifies that a panic that kills the
+// process is written to the log file by the crash monitor.
+func TestCrashMonitor_LogsUnrecoveredPanic(t *testing.T) {
+	if path := os.Getenv("TEST_CRASH_FILE"); path != "" {
+		InitWithFile("production", false, path)
+		if err := EnableCrashMonitor(); err != nil {
+			t.Fatal(err)
+		}
+		panic("boom")
+	}
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	cmd := exec.Command(os.Args[0], "-test.run=TestCrashMonitor_LogsUnrecoveredPanic")
+	cmd.Env = append(os.Environ(), "TEST_CRASH_FILE="+path)
+	if output, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("expected the panic to kill the process, output: %q", output)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logged := string(data)
+	if !strings.Contains(logged, "[FATAL] [runtime] panic: boom") || !strings.Contains(logged, "source=crash") ||
+		!strings.Contains(logged, "TestCrashMonitor_LogsUnrecoveredPanic") {
+		t.Fatalf("crash report missing from log file: %q", logged)
+	}
+}
+
+// TestCrashFile_ReportReferencedByErrorEntry verifies that a runtime crash is
+// written to the crash file and referenced by an ERROR entry in the log file.
+func TestCrashFile_ReportReferencedByErrorEntry(t *testing.T) {
+	if dir := os.Getenv("TEST_CRASH_DIR"); dir != "" {
+		InitWithFile("production", false, filepath.Join(dir, "app.log"))
+		if err := EnableCrashFile(filepath.Join(dir, "app.crash")); err != nil {
+			t.Fatal(err)
+		}
+		var p *int
+		_ = *p
+	}
+
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=TestCrashFile_ReportReferencedByErrorEntry")
+	cmd.Env = append(os.Environ(), "TEST_CRASH_DIR="+dir)
+	if output, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("expected the nil dereference to kill the process, output: %q", output)
+	}
+
+	crash, err := os.ReadFile(filepath.Join(dir, "app.crash"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(crash), "nil pointer dereference") || !strings.Contains(string(crash), "goroutine") {
+		t.Fatalf("crash file is missing the report: %q", crash)
+	}
+	logged, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(logged), "[ERROR] [runtime] process crashed crash_file="+filepath.Join(dir, "app.crash")) {
+		t.Fatalf("log file has no entry referencing the crash file: %q", logged)
+	}
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFDWatch_WarnsOnceNearLimit(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	var w fdWatch
+	w.report(100, 1024)
+	w.report(820, 1024)
+	w.report(900, 1024)
+	w.report(500, 0)
+	w.report(100, 1024)
+	w.report(1024, 1024)
+
+	want := "[WARN] [logger.WatchFDs] open file descriptors near limit open=820 limit=1024\n" +
+		"[WARN] [logger.WatchFDs] open file descriptors near limit open=1024 limit=1024\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFDWatch_ReportsFileWriteFailures(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	w := fileErrorRecorder{f}
+	w.Write([]byte("a\n"))
+	w.Write([]byte("b\n"))
+
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	var fw fdWatch
+	fw.check()
+	fw.check()
+	if got := buf.String(); !strings.HasPrefix(got, "[WARN] [logger.WatchFDs] log file writes failing failures=2 error=write ") ||
+		strings.Count(got, "\n") != 1 {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestOpenFDs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("not supported")
+	}
+	before, limit, err := openFDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	after, _, err := openFDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before < 3 || after <= before || limit < 0 {
+		t.Fatalf("open %d then %d, limit %d", before, after, limit)
+	}
+}
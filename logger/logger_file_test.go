@@ -1,11 +1,17 @@
 package logger
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFileLogging_Development(t *testing.T) {
@@ -162,6 +168,26 @@ func TestFileLogging_InvalidPath(t *testing.T) {
 	}
 }
 
+func TestFileLogging_ReinitClosesPreviousFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	InitWithFile("production", false, filepath.Join(tmpDir, "first.log"))
+	defer Close()
+	first := logFile
+
+	InitWithFile("production", false, filepath.Join(tmpDir, "second.log"))
+	if _, err := first.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("expected the previous log file closed, got %v", err)
+	}
+	second := logFile
+	Init("production", false)
+	if logFile != nil {
+		t.Fatalf("expected no log file without FilePath, got %v", logFile.Name())
+	}
+	if _, err := second.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("expected the previous log file closed, got %v", err)
+	}
+}
+
 func TestFileLogging_Close(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, "close.log")
@@ -268,3 +294,226 @@ func TestInit_BackwardCompatible(t *testing.T) {
 		t.Errorf("Close() should not error, got: %v", err)
 	}
 }
+
+func TestFileLogging_FlagsNone(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "noflags.log")
+
+	InitWithConfig(Config{Mode: "production", FilePath: logPath, FileFlags: FlagsNone})
+	defer Close()
+
+	Infof("no timestamp in file")
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	first := strings.Split(string(content), "\n")[0]
+	if !strings.HasPrefix(first, "[INFO] [") {
+		t.Fatalf("FileFlags=FlagsNone should drop the timestamp, got: %q", first)
+	}
+}
+
+func TestFileLogging_AsyncWritesAllLines(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "async.log")
+
+	InitWithConfig(Config{Mode: "production", FilePath: logPath, Fallback: FallbackFileOnly, FileAsync: true})
+	defer Init("development", true)
+	defer Close()
+
+	for i := 0; i < 100; i++ {
+		Infof("async line %d", i)
+	}
+	SyncSinks()
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 100 {
+		t.Fatalf("expected 100 lines, got %d", len(lines))
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, fmt.Sprintf("async line %d", i)) {
+			t.Fatalf("line %d out of order: %q", i, line)
+		}
+	}
+}
+
+// blockingWriter counts writes and blocks the first one until release is closed.
+type blockingWriter struct {
+	release chan struct{}
+	buf     bytes.Buffer
+	writes  int
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	if w.writes == 0 {
+		<-w.release
+	}
+	w.writes++
+	return w.buf.Write(p)
+}
+
+func TestBatchWriter_CoalescesQueuedWrites(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	b := newBatchWriter(w)
+
+	b.Write([]byte("first\n"))
+	// wait until the first batch is in flight
+	for {
+		b.mu.Lock()
+		flushing := b.flushing
+		b.mu.Unlock()
+		if flushing {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 50; i++ {
+		b.Write([]byte("queued\n"))
+	}
+	close(w.release)
+	b.Close()
+
+	if w.writes != 2 {
+		t.Fatalf("expected 2 write calls, got %d", w.writes)
+	}
+	if got := strings.Count(w.buf.String(), "\n"); got != 51 {
+		t.Fatalf("expected 51 lines, got %d", got)
+	}
+}
+
+func TestFileLogging_IndependentConsoleFlags(t *testing.T) {
+	var buf strings.Builder
+	oldStdout := outStdout
+	defer func() { outStdout = oldStdout }()
+	outStdout = &buf
+
+	logPath := filepath.Join(t.TempDir(), "flags.log")
+	InitWithConfig(Config{
+		Mode:         "development",
+		FilePath:     logPath,
+		ConsoleFlags: FlagsNone,
+		FileFlags:    log.LstdFlags | log.Lmicroseconds | log.Lmsgprefix,
+	})
+	defer Close()
+
+	Infof("flags differ")
+
+	if got := buf.String(); regexp.MustCompile(`\d{2}:\d{2}:\d{2}`).MatchString(got) {
+		t.Fatalf("console should not carry a timestamp, got: %q", got)
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	first := strings.Split(string(content), "\n")[0]
+	if !regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}\.\d{6} \[INFO\] `).MatchString(first) {
+		t.Fatalf("file should use its own flags, got: %q", first)
+	}
+}
+
+func TestUnderJournald(t *testing.T) {
+	oldStdout := outStdout
+	defer func() { outStdout = oldStdout }()
+	outStdout = os.Stdout
+
+	t.Setenv("JOURNAL_STREAM", "")
+	if underJournald() {
+		t.Fatal("expected no journald detection without JOURNAL_STREAM")
+	}
+	t.Setenv("JOURNAL_STREAM", "8:12345")
+	if !underJournald() {
+		t.Fatal("expected journald detection with JOURNAL_STREAM set")
+	}
+}
+
+func TestJournalStyle_CompactAndBare(t *testing.T) {
+	oldStdout, oldOsStdout := outStdout, os.Stdout
+	defer func() { outStdout, os.Stdout = oldStdout, oldOsStdout }()
+	defer Init("development", true)
+	t.Setenv("JOURNAL_STREAM", "8:12345")
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm")
+
+	for _, tt := range []struct {
+		style JournalStyle
+		want  string
+	}{
+		{JournalDefault, "\x1b[33m[WARN]\x1b[0m [logger.TestJournalStyle_CompactAndBare:"},
+		{JournalCompact, "<4>WARN logger.TestJournalStyle_CompactAndBare:"},
+		{JournalBare, "<4>disk low free=5%\n"},
+	} {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.Stdout, outStdout = w, w
+		InitWithConfig(Config{Mode: "development", Journal: tt.style})
+		WarnKV("disk low", "free", "5%")
+		w.Close()
+		data, _ := io.ReadAll(r)
+		r.Close()
+		if got := string(data); !strings.HasPrefix(got, tt.want) || !strings.HasSuffix(got, "disk low free=5%\n") {
+			t.Errorf("style %d: got %q, want prefix %q", tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestLevelMapping_PerOutput(t *testing.T) {
+	var buf bytes.Buffer
+	oldStdout := outStdout
+	defer func() { outStdout = oldStdout }()
+	defer Init("development", true)
+	outStdout = &buf
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	InitWithConfig(Config{
+		Mode:          "development",
+		FilePath:      path,
+		ConsoleLevels: &LevelMapping{Min: WarnLevel},
+		FileLevels:    &LevelMapping{Min: DebugLevel},
+	})
+	sink := &memorySink{}
+	AddSinkWithLevels(sink, LevelMapping{Min: ErrorLevel, Remap: map[Level]Level{WarnLevel: ErrorLevel}})
+
+	Debugf("debug detail")
+	Infof("info detail")
+	Warnf("disk low")
+	Close()
+
+	console := buf.String()
+	if strings.Contains(console, "detail") || !strings.Contains(console, "disk low") {
+		t.Errorf("console should only show WARN and above, got %q", console)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"[DEBUG]", "debug detail", "info detail", "[WARN]", "disk low"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("file missing %q: %q", want, data)
+		}
+	}
+	if len(sink.lines) != 1 || !strings.HasPrefix(sink.lines[0], "[ERROR] ") || !strings.HasSuffix(sink.lines[0], "disk low") {
+		t.Errorf("sink should receive only the WARN entry upgraded to ERROR, got %q", sink.lines)
+	}
+}
+
+func TestLevelMapping_ConsoleRemapUsesTargetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	oldStdout := outStdout
+	defer func() { outStdout = oldStdout }()
+	defer Init("development", true)
+	outStdout = &buf
+
+	InitWithConfig(Config{Mode: "production", ConsoleLevels: &LevelMapping{Remap: map[Level]Level{InfoLevel: WarnLevel}}})
+	var errBuf bytes.Buffer
+	Warning.SetOutput(&errBuf)
+	Infof("promoted")
+
+	if buf.Len() != 0 || !strings.HasPrefix(errBuf.String(), "[WARN] ") {
+		t.Fatalf("remapped INFO should be written by the WARN logger, stdout=%q warn=%q", buf.String(), errBuf.String())
+	}
+}
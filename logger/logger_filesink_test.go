@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHourlyFileSink_Partitions(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewHourlyFileSink(filepath.Join(dir, "app.log"), TextEncoder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hour := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	for _, e := range []*Entry{
+		{Time: hour.Add(time.Minute), Level: InfoLevel, Message: "first"},
+		{Time: hour.Add(59 * time.Minute), Level: InfoLevel, Message: "second"},
+		{Time: hour.Add(61 * time.Minute), Level: InfoLevel, Message: "third"},
+	} {
+		if err := sink.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string][]string{
+		"app-2024050113.log": {"first", "second"},
+		"app-2024050114.log": {"third"},
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if len(lines) != len(want) {
+			t.Fatalf("%s has %d lines, want %d: %q", name, len(lines), len(want), data)
+		}
+		for i, msg := range want {
+			if !strings.Contains(lines[i], msg) {
+				t.Fatalf("%s line %d = %q, want %q", name, i, lines[i], msg)
+			}
+		}
+	}
+	// path itself is never written
+	if _, err := os.Stat(filepath.Join(dir, "app.log")); !os.IsNotExist(err) {
+		t.Fatalf("expected no unpartitioned file, got %v", err)
+	}
+}
+
+func TestHourlyPath(t *testing.T) {
+	hour := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	for path, want := range map[string]string{
+		"/var/log/app.log": "/var/log/app-2024050109.log",
+		"app.jsonl":        "app-2024050109.jsonl",
+		"app":              "app-2024050109",
+	} {
+		if got := hourlyPath(path, hour); got != want {
+			t.Errorf("hourlyPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
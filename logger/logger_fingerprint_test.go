@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestErrorFingerprint_StableAcrossValues(t *testing.T) {
+	a := errorFingerprint("main.sync:40", `upload "a.txt" failed after 3 attempts (id 9f86d081884c7d65)`, nil)
+	b := errorFingerprint("main.sync:52", `upload "b/c.txt" failed after 12 attempts (id 2c26b46b68ffc68f)`, nil)
+	if a != b || len(a) != 16 {
+		t.Fatalf("fingerprints differ: %s %s", a, b)
+	}
+	if c := errorFingerprint("main.sync:40", "connection refused", nil); c == a {
+		t.Fatal("different messages share a fingerprint")
+	}
+	if c := errorFingerprint("main.other:40", `upload "a.txt" failed after 3 attempts (id 9f86d081884c7d65)`, nil); c == a {
+		t.Fatal("different functions share a fingerprint")
+	}
+	e1 := errorFingerprint("main.run:1", "order 17 rejected", []any{EventKey, "order.rejected"})
+	e2 := errorFingerprint("main.run:1", "payment declined", []any{EventKey, "order.rejected"})
+	if e1 != e2 {
+		t.Fatal("entries of the same event have different fingerprints")
+	}
+}
+
+func TestErrorFingerprints_OnlyOnErrors(t *testing.T) {
+	InitWithConfig(Config{Mode: "development", ErrorFingerprints: true})
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	sink := &entrySink{}
+	AddSink(sink)
+	defer Init("development", true)
+
+	Warnf("retry %d", 1)
+	for i := range 2 {
+		Errorf("job %d failed", i)
+	}
+	SyncSinks()
+	entries := sink.entries
+	if len(entries) != 3 {
+		t.Fatalf("entries = %d, want 3", len(entries))
+	}
+	if fp := entries[0].Fingerprint(); fp != "" {
+		t.Fatalf("WARN entry has fingerprint %q", fp)
+	}
+	if fp := entries[1].Fingerprint(); fp == "" || fp != entries[2].Fingerprint() {
+		t.Fatalf("ERROR fingerprints = %q %q", fp, entries[2].Fingerprint())
+	}
+	if !strings.Contains(buf.String(), "job 1 failed fingerprint="+entries[2].Fingerprint()) {
+		t.Fatalf("missing fingerprint field: %q", buf.String())
+	}
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFlag_EnvAndSet(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	t.Setenv(FlagsEnv, "wire-dump-test, other")
+
+	f := Flag("wire-dump-test")
+	if !f.On() {
+		t.Fatal("expected flag listed in LOGGER_FLAGS to start on")
+	}
+	if Flag("wire-dump-test") != f {
+		t.Fatal("expected Flag to return the same handle")
+	}
+	if Flag("unlisted-test").On() {
+		t.Fatal("expected unlisted flag to start off")
+	}
+
+	f.Set(false)
+	f.Set(false)
+	if f.On() {
+		t.Fatal("expected flag off after Set(false)")
+	}
+	if n := strings.Count(buf.String(), "flag changed"); n != 1 {
+		t.Fatalf("expected one change entry, got %d:\n%s", n, buf.String())
+	}
+	if !strings.Contains(buf.String(), "[logger.TestFlag_EnvAndSet:") ||
+		!strings.Contains(buf.String(), "flag=wire-dump-test on=false") {
+		t.Fatalf("unexpected change entry:\n%s", buf.String())
+	}
+}
+
+func TestFlagsHandler(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	Flag("handler-a").Set(false)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/debug/flags", strings.NewReader("handler-a=true&handler-b=1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	FlagsHandler().ServeHTTP(rec, req)
+	if rec.Code != 200 {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body)
+	}
+	if !Flag("handler-a").On() || !Flag("handler-b").On() {
+		t.Fatal("expected POST to turn flags on")
+	}
+	if !strings.Contains(rec.Body.String(), "handler-a=true\nhandler-b=true\n") {
+		t.Fatalf("unexpected body:\n%s", rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/debug/flags", strings.NewReader("handler-a=maybe"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	FlagsHandler().ServeHTTP(rec, req)
+	if rec.Code != 400 || !Flag("handler-a").On() {
+		t.Fatalf("expected invalid value to be rejected, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetFormat_SwitchesConsoleAndFile(t *testing.T) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() {
+		outStdout, outStderr = oldStdout, oldStderr
+		Init("development", true)
+	}()
+	outStdout, outStderr = &stdoutBuf, &stderrBuf
+	path := filepath.Join(t.TempDir(), "app.log")
+	InitWithConfig(Config{Mode: "production", FilePath: path})
+	defer Close()
+
+	Infof("before")
+	SetFormat(FormatJSON)
+	if CurrentFormat() != FormatJSON {
+		t.Fatal("format not switched")
+	}
+	InfoKV("during", "k", 1)
+	SetFormat(FormatText)
+	Infof("after")
+
+	lines := strings.Split(strings.TrimSpace(stdoutBuf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("stdout lines = %q", lines)
+	}
+	if !strings.HasPrefix(lines[0], "[INFO] [") || !strings.HasSuffix(lines[0], "before") {
+		t.Fatalf("text line = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], `{"time":`) || !strings.Contains(lines[1], `"msg":"log format changed","format":"json"`) {
+		t.Fatalf("switch line = %q", lines[1])
+	}
+	if !strings.Contains(lines[2], `"msg":"during","k":1}`) {
+		t.Fatalf("json line = %q", lines[2])
+	}
+	if !strings.HasPrefix(lines[3], "[INFO] [") || !strings.HasSuffix(lines[4], "after") {
+		t.Fatalf("text lines after switch = %q", lines[3:])
+	}
+
+	SyncSinks()
+	data, _ := os.ReadFile(path)
+	file := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(file) != 5 || !strings.Contains(file[0], "[INFO] [") || !strings.HasPrefix(file[2], `{"time":`) || !strings.Contains(file[4], "[INFO] [") {
+		t.Fatalf("file lines = %q", file)
+	}
+}
+
+func TestSetFormat_RoundTripRestoresConsoleRouting(t *testing.T) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() {
+		outStdout, outStderr = oldStdout, oldStderr
+		Init("development", true)
+	}()
+	outStdout, outStderr = &stdoutBuf, &stderrBuf
+
+	for _, fallback := range []FallbackPolicy{FallbackPlain, FallbackJSON} {
+		stdoutBuf.Reset()
+		stderrBuf.Reset()
+		InitWithConfig(Config{Mode: "production", Fallback: fallback})
+		SetFormat(FormatJSON)
+		Warnf("json warning")
+		SetFormat(FormatText)
+		Warnf("text warning")
+
+		if !strings.Contains(stdoutBuf.String(), "json warning") {
+			t.Fatalf("fallback %d: JSON warnings should go to stdout, got stdout %q", fallback, stdoutBuf.String())
+		}
+		if !strings.Contains(stderrBuf.String(), "text warning") || strings.Contains(stdoutBuf.String(), "text warning") {
+			t.Fatalf("fallback %d: text warnings should go to stderr, got stderr %q", fallback, stderrBuf.String())
+		}
+	}
+}
+
+func TestFormatHandler(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/debug/log-format", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		FormatHandler().ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := post("format=json"); rec.Code != 200 || rec.Body.String() != "json\n" {
+		t.Fatalf("POST json: %d %q", rec.Code, rec.Body)
+	}
+	if !strings.Contains(buf.String(), `"format":"json"`) {
+		t.Fatalf("missing change entry: %q", buf.String())
+	}
+	if rec := post("format=xml"); rec.Code != 400 || CurrentFormat() != FormatJSON {
+		t.Fatalf("invalid format accepted: %d", rec.Code)
+	}
+	rec := httptest.NewRecorder()
+	FormatHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/log-format", nil))
+	if rec.Body.String() != "json\n" {
+		t.Fatalf("GET = %q", rec.Body)
+	}
+	post("format=text")
+	if CurrentFormat() != FormatText {
+		t.Fatal("format not switched back")
+	}
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitPanics polls buf until n panics were logged or a second has passed,
+// returning the entries up to their stack fields. Taking logMutex waits
+// for the last entry to be fully written.
+func waitPanics(buf *lockedBuffer, n int) []string {
+	deadline := time.Now().Add(time.Second)
+	for strings.Count(buf.String(), "goroutine panicked") < n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	logMutex.Lock()
+	logMutex.Unlock()
+	var entries []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, "[ERROR] ") {
+			entries = append(entries, line)
+		}
+	}
+	return entries
+}
+
+func TestGo_RecoversPanic(t *testing.T) {
+	var buf lockedBuffer
+	captureLevels(new(bytes.Buffer))
+	Error = log.New(&buf, "[ERROR] ", 0)
+	defer Init("development", true)
+
+	Go(func() {
+		var s []int
+		_ = s[3]
+	})
+	lines := waitPanics(&buf, 1)
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "[ERROR] [logger.TestGo_RecoversPanic:") ||
+		!strings.Contains(lines[0], "] goroutine panicked panic=runtime error: index out of range [3] with length 0 stack=goroutine ") {
+		t.Fatalf("got %q", buf.String())
+	}
+}
+
+func TestSafeGo_Restarts(t *testing.T) {
+	var buf lockedBuffer
+	captureLevels(new(bytes.Buffer))
+	Error = log.New(&buf, "[ERROR] ", 0)
+	defer Init("development", true)
+
+	runs := 0
+	SafeGo("poller", RestartPolicy{MaxRestarts: 2, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}, func() {
+		runs++
+		panic("connection reset")
+	})
+	lines := waitPanics(&buf, 3)
+	want := []string{
+		"goroutine panicked goroutine=poller panic=connection reset restarts=0 restart_in=1ms stack=",
+		"goroutine panicked goroutine=poller panic=connection reset restarts=1 restart_in=2ms stack=",
+		"goroutine panicked goroutine=poller panic=connection reset restarts=2 stack=",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %q", buf.String())
+	}
+	for i, w := range want {
+		if !strings.Contains(lines[i], "] "+w) {
+			t.Errorf("line %d = %q, want %q", i, lines[i], w)
+		}
+	}
+	if runs != 3 {
+		t.Fatalf("ran %d times", runs)
+	}
+}
+
+func TestSafeGo_NoPanic(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	done := make(chan struct{})
+	SafeGo("once", RestartPolicy{MaxRestarts: -1}, func() { close(done) })
+	<-done
+	if buf.Len() != 0 {
+		t.Fatalf("got %q", buf.String())
+	}
+}
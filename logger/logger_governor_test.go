@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGovernor_EngagesOnQueueDepth(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	var out lockedBuffer
+	defer SetOutputs(&out, &out)()
+	Init("development", true)
+	defer Init("development", true)
+	defer Close()
+	sink := &gatedSink{started: make(chan struct{}), gate: make(chan struct{})}
+	AddSink(sink)
+	Infof("first")
+	<-sink.started
+	for i := range 20 {
+		Debugf("backlog %d", i)
+	}
+
+	stop := StartGovernor(GovernorConfig{QueueDepth: 10, MinLevel: WarnLevel, Interval: 5 * time.Millisecond})
+	defer stop()
+	waitFor(t, "engage", func() bool { return strings.Contains(out.String(), "log pressure, raising minimum level") })
+	if got := out.String(); !strings.Contains(got, "event=logger.governor min_level=WARN queued=20") {
+		t.Fatalf("unexpected engage entry %q", got)
+	}
+	Infof("suppressed")
+	Debugf("suppressed")
+	Warnf("kept")
+	if got := out.String(); strings.Contains(got, "suppressed\n") || !strings.Contains(got, "kept") {
+		t.Fatalf("expected entries below WARN suppressed, got %q", got)
+	}
+
+	close(sink.gate)
+	waitFor(t, "disengage", func() bool { return strings.Contains(out.String(), "log pressure relieved") })
+	if got := out.String(); !strings.Contains(got, "log pressure relieved event=logger.governor suppressed=2") {
+		t.Fatalf("unexpected disengage entry %q", got)
+	}
+	Debugf("debug again")
+	if !strings.Contains(out.String(), "debug again") {
+		t.Fatal("expected DEBUG after the governor disengaged")
+	}
+}
+
+func TestGovernor_EngagesOnWriteLatency(t *testing.T) {
+	g := &governor{cfg: GovernorConfig{WriteLatency: time.Millisecond, MinLevel: InfoLevel},
+		nanos: sinkWriteNanos.Load(), writes: sinkWrites.Load()}
+	defer governorMin.Store(int32(DebugLevel))
+	sinkWriteNanos.Add(int64(4 * time.Millisecond))
+	sinkWrites.Add(2)
+	g.check()
+	if !g.engaged || governorMin.Load() != int32(InfoLevel) {
+		t.Fatal("expected the governor to engage on 2ms average writes")
+	}
+	g.check()
+	if g.engaged {
+		t.Fatal("expected the governor to disengage without further slow writes")
+	}
+}
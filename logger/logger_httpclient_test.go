@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSelfSigned writes a self-signed certificate and key for 127.0.0.1 as
+// PEM files and returns their paths.
+func writeSelfSigned(t *testing.T, name string, usage x509.ExtKeyUsage) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, name+".pem"), filepath.Join(dir, name+"-key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	return certFile, keyFile
+}
+
+func TestNewHTTPClient_MutualTLS(t *testing.T) {
+	serverCert, serverKey := writeSelfSigned(t, "collector", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := writeSelfSigned(t, "app", x509.ExtKeyUsageClientAuth)
+
+	srvPair, err := tls.LoadX509KeyPair(serverCert, serverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientPEM, _ := os.ReadFile(clientCert)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(clientPEM)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{srvPair}, ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+
+	client, err := NewHTTPClient(HTTPClientConfig{Proxy: "direct", CAFile: serverCert, CertFile: clientCert, KeyFile: clientKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+
+	noCert, err := NewHTTPClient(HTTPClientConfig{Proxy: "direct", CAFile: serverCert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := noCert.Get(srv.URL); err == nil {
+		resp.Body.Close()
+		t.Fatal("expected the server to reject a client without a certificate")
+	}
+
+	for _, cfg := range []HTTPClientConfig{
+		{CertFile: clientCert},
+		{CAFile: clientKey},
+		{CertFile: clientCert, KeyFile: serverKey},
+		{Proxy: "not a url"},
+	} {
+		if _, err := NewHTTPClient(cfg); err == nil {
+			t.Errorf("expected an error for %+v", cfg)
+		}
+	}
+}
+
+func TestNewHTTPClient_CAFileLeavesCallerPoolUnchanged(t *testing.T) {
+	serverCert, _ := writeSelfSigned(t, "collector", x509.ExtKeyUsageServerAuth)
+	pool := x509.NewCertPool()
+	base := &tls.Config{RootCAs: pool}
+
+	client, err := NewHTTPClient(HTTPClientConfig{CAFile: serverCert, TLSConfig: base})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pool.Equal(x509.NewCertPool()) || base.RootCAs != pool {
+		t.Fatal("expected the caller's RootCAs pool to be left unchanged")
+	}
+	if got := client.Transport.(*http.Transport).TLSClientConfig.RootCAs; got == pool || got.Equal(pool) {
+		t.Fatal("expected the CA bundle in a copy of the caller's pool")
+	}
+}
+
+func TestNewHTTPClient_Proxy(t *testing.T) {
+	hosts := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts <- r.URL.Host
+	}))
+	defer proxy.Close()
+
+	client, err := NewHTTPClient(HTTPClientConfig{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get("http://loki.internal:3100/loki/api/v1/push")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if host := <-hosts; host != "loki.internal:3100" {
+		t.Fatalf("expected the request to go through the proxy, got host %q", host)
+	}
+
+	custom := &http.Client{}
+	if got, _ := NewHTTPClient(HTTPClientConfig{Client: custom}); got != custom {
+		t.Fatal("expected a configured Client to be returned as is")
+	}
+}
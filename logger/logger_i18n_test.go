@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocale_TranslatesConsoleOnly(t *testing.T) {
+	var buf strings.Builder
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout, outStderr = &buf, &buf
+
+	RegisterCatalog("de", map[string]string{
+		"Checking for updates": "Suche nach Updates",
+		"download_done":        "{file} heruntergeladen",
+	})
+	logPath := filepath.Join(t.TempDir(), "i18n.log")
+	InitWithConfig(Config{Mode: "production", FilePath: logPath, Locale: "de_AT"})
+	defer Init("development", true)
+	defer Close()
+
+	Infof("Checking for updates")
+	RegisterEvent("download_done", InfoLevel, "downloaded {file}")
+	Event("download_done", "file", "app.tar.gz")
+	Errorf("Checking for updates")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 console lines, got:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "[INFO] ") || !strings.HasSuffix(lines[0], "] Suche nach Updates") {
+		t.Errorf("expected a translated INFO message, got %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "] app.tar.gz heruntergeladen event=download_done file=app.tar.gz") {
+		t.Errorf("expected a translated event, got %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "[ERROR] ") || !strings.HasSuffix(lines[2], "] Checking for updates") {
+		t.Errorf("expected ERROR messages to stay untranslated, got %q", lines[2])
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if file := string(content); strings.Contains(file, "Suche") || !strings.Contains(file, "downloaded app.tar.gz") {
+		t.Errorf("expected canonical English in the log file, got:\n%s", file)
+	}
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "pt_BR.UTF-8")
+	if got := LocaleFromEnv(); got != "pt_BR" {
+		t.Fatalf("LocaleFromEnv() = %q, want pt_BR", got)
+	}
+	t.Setenv("LC_ALL", "C.UTF-8")
+	if got := LocaleFromEnv(); got != "" {
+		t.Fatalf("LocaleFromEnv() = %q for the C locale, want empty", got)
+	}
+}
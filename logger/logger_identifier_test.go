@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bufio"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdentifier_ConfigAndEnv(t *testing.T) {
+	t.Setenv("LOGGER_IDENTIFIER", "")
+	t.Setenv("LOGGER_INSTANCE", "")
+	defer Init("development", true)
+
+	Init("development", true)
+	if got := Identifier(); got != programName() {
+		t.Fatalf("Identifier() = %q, want the program name %q", got, programName())
+	}
+
+	t.Setenv("LOGGER_INSTANCE", "eu-1")
+	InitWithConfig(Config{Mode: "production", Identifier: "billing"})
+	if got := Identifier(); got != "billing@eu-1" {
+		t.Fatalf("Identifier() = %q, want billing@eu-1", got)
+	}
+	InitWithConfig(Config{Mode: "production", Identifier: "billing", Instance: "us-2"})
+	if got := Identifier(); got != "billing@us-2" {
+		t.Fatalf("Identifier() = %q, want Config.Instance to win over LOGGER_INSTANCE", got)
+	}
+}
+
+func TestIdentifier_ConcurrentWithInit(t *testing.T) {
+	t.Setenv("LOGGER_IDENTIFIER", "")
+	t.Setenv("LOGGER_INSTANCE", "")
+	defer Init("development", true)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			Identifier()
+		}
+	}()
+	for range 10 {
+		InitWithConfig(Config{Mode: "production", Identifier: "billing"})
+	}
+	<-done
+	if got := Identifier(); got != "billing" {
+		t.Fatalf("Identifier() = %q, want billing", got)
+	}
+}
+
+func TestIdentifier_OpensJournalStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stdout")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	oldPath := journalStreamPath
+	defer func() { journalStreamPath = oldPath }()
+	journalStreamPath = path
+	t.Setenv("JOURNAL_STREAM", "8:1234")
+
+	InitWithConfig(Config{Mode: "production", Identifier: "worker", Instance: "3"})
+	defer Init("development", true)
+	defer Close()
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	Infof("hello from the worker")
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	r := bufio.NewReader(conn)
+	var lines []string
+	for len(lines) < 8 {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading journal stream: %v (got %q)", err, lines)
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	if got := strings.Join(lines[:7], "|"); got != "worker@3||6|1|0|0|0" {
+		t.Fatalf("unexpected stream header %q", got)
+	}
+	if !strings.HasSuffix(lines[7], "] hello from the worker") {
+		t.Fatalf("unexpected journal line %q", lines[7])
+	}
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+// sequentialIDs numbers entries and spans in order.
+type sequentialIDs struct{ entries, spans *int }
+
+func (g sequentialIDs) EntryID(time.Time) string {
+	*g.entries++
+	return fmt.Sprintf("entry-%d", *g.entries)
+}
+
+func (g sequentialIDs) SpanID() string {
+	*g.spans++
+	return fmt.Sprintf("span-%d", *g.spans)
+}
+
+func TestSetIDGenerator(t *testing.T) {
+	defer SetOutputs(io.Discard, io.Discard)()
+	Init("development", true)
+	defer Init("development", true)
+
+	var entries, spans int
+	restore := SetIDGenerator(sequentialIDs{&entries, &spans})
+	if got := newEntryID(time.Now()); got != "entry-1" {
+		t.Fatalf("entry ID = %q", got)
+	}
+	if got := Span("sync").ID(); got != "span-1" {
+		t.Fatalf("span ID = %q", got)
+	}
+	if got := Conn("websocket", "10.0.0.7:51234").ID(); got != "span-2" {
+		t.Fatalf("conn ID = %q", got)
+	}
+	restore()
+	if got := newEntryID(time.Now()); len(got) != 36 || got[14] != '7' {
+		t.Fatalf("expected a UUIDv7 after restore, got %q", got)
+	}
+}
+
+func TestSetRandomSource_Reproducible(t *testing.T) {
+	at := time.Date(2026, 3, 2, 10, 14, 7, 0, time.UTC)
+	ids := func() (string, string) {
+		defer SetRandomSource(rand.NewChaCha8([32]byte{1}))()
+		return newEntryID(at), newSpanID()
+	}
+	entry1, span1 := ids()
+	entry2, span2 := ids()
+	if entry1 != entry2 || span1 != span2 {
+		t.Fatalf("expected the same IDs from the same seed, got %s %s and %s %s", entry1, span1, entry2, span2)
+	}
+	if entry1[:15] != "019cae0a-7598-7" {
+		t.Fatalf("expected the time bits kept, got %q", entry1)
+	}
+	if newSpanID() == span1 {
+		t.Fatal("expected crypto/rand restored")
+	}
+}
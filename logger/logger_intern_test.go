@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestGetCallerInfo_CachedPerCallSite(t *testing.T) {
+	before := ReadInternStats()
+	var callers []string
+	for i := 0; i < 3; i++ {
+		callers = append(callers, getCallerInfo(1))
+	}
+	after := ReadInternStats()
+	if callers[0] != callers[2] || unsafe.StringData(callers[0]) != unsafe.StringData(callers[2]) {
+		t.Fatalf("expected the cached caller string to be reused, got %q and %q", callers[0], callers[2])
+	}
+	if after.CallerHits-before.CallerHits < 2 {
+		t.Fatalf("expected at least 2 cache hits, got %d", after.CallerHits-before.CallerHits)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { getCallerInfo(1) }); allocs != 0 {
+		t.Fatalf("cached caller lookup allocated %v times per call", allocs)
+	}
+}
+
+func TestParseLine_InternsKeys(t *testing.T) {
+	a, err := ParseLine("[INFO] [svc.Run:1] started request_id=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseLine(`{"level":"INFO","caller":"svc.Run:1","msg":"started","request_id":2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ka, kb := a.Fields[0].(string), b.Fields[0].(string)
+	if ka != "request_id" || unsafe.StringData(ka) != unsafe.StringData(kb) {
+		t.Fatalf("expected text and JSON keys to share one interned copy, got %q and %q", ka, kb)
+	}
+	if unsafe.StringData(a.Caller) != unsafe.StringData(b.Caller) {
+		t.Fatal("expected parsed callers to be interned")
+	}
+	if s := ReadInternStats(); s.KeyHits == 0 || s.Keys == 0 {
+		t.Fatalf("expected interning stats to be recorded, got %+v", s)
+	}
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJournalIdentity_InheritedByChildren(t *testing.T) {
+	defer func() {
+		namedMu.Lock()
+		journalIDs = map[string]journalID{}
+		namedMu.Unlock()
+	}()
+	Get("scheduler").SetJournalIdentifier("myapp-scheduler")
+	Get("scheduler").SetJournalUnit("scheduler")
+	Get("scheduler.cron").SetJournalIdentifier("myapp-cron")
+
+	for _, tc := range []struct {
+		logger, identifier, unit string
+	}{
+		{"scheduler", "myapp-scheduler", "scheduler"},
+		{"scheduler.cron", "myapp-cron", "scheduler"},
+		{"scheduler.queue.retry", "myapp-scheduler", "scheduler"},
+		{"http", Identifier(), ""},
+	} {
+		data, err := JournalEncoder{}.Encode(&Entry{Level: InfoLevel, Message: "tick", Fields: []any{LoggerKey, tc.logger}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := string(data)
+		if !strings.Contains(got, "SYSLOG_IDENTIFIER="+tc.identifier+"\n") {
+			t.Errorf("%s: expected identifier %s, got %q", tc.logger, tc.identifier, got)
+		}
+		if hasUnit := strings.Contains(got, "UNIT="+tc.unit+"\n"); hasUnit != (tc.unit != "") {
+			t.Errorf("%s: expected unit %q, got %q", tc.logger, tc.unit, got)
+		}
+	}
+
+	Get("scheduler.cron").SetJournalIdentifier("")
+	if id, _ := journalIdentity(&Entry{Fields: []any{LoggerKey, "scheduler.cron"}}); id != "myapp-scheduler" {
+		t.Fatalf("expected the parent's identifier after reset, got %q", id)
+	}
+}
+
+func TestSendJournal_WritesNativeDatagram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	pc, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	saved := journalSocketPath
+	journalSocketPath = path
+	defer func() {
+		journalSocketPath = saved
+		namedMu.Lock()
+		journalIDs = map[string]journalID{}
+		namedMu.Unlock()
+		logMutex.Lock()
+		closeJournalStream()
+		logMutex.Unlock()
+	}()
+	Get("worker").SetJournalIdentifier("myapp-worker")
+
+	logMutex.Lock()
+	ok := sendJournal(&Entry{Level: ErrorLevel, Caller: "worker.run:9", Message: "job failed", Fields: []any{LoggerKey, "worker"}})
+	logMutex.Unlock()
+	if !ok {
+		t.Fatal("expected the datagram to be sent")
+	}
+	buf := make([]byte, 4096)
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "MESSAGE=job failed\nPRIORITY=3\nSYSLOG_IDENTIFIER=myapp-worker\nCODE_FUNC=worker.run:9\nLOGGER=worker\n"
+	if got := string(buf[:n]); got != want {
+		t.Fatalf("got %q\nwant %q", got, want)
+	}
+}
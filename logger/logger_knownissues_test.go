@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegisterKnownIssue_OncePerDayAcrossRestarts(t *testing.T) {
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	path := filepath.Join(t.TempDir(), "known-issues.json")
+	cfg := Config{Mode: "production", KnownIssuesFile: path}
+	InitWithConfig(cfg)
+	defer Init("development", true)
+
+	fp := errorFingerprint("logger.TestRegisterKnownIssue_OncePerDayAcrossRestarts", "connection reset", nil)
+	RegisterKnownIssue(fp, "upstream resets idle connections")
+	defer func() {
+		logMutex.Lock()
+		delete(knownIssueReasons, fp)
+		logMutex.Unlock()
+	}()
+
+	for range 3 {
+		ErrorKV("connection reset", "peer", "10.0.0.7")
+	}
+	ErrorKV("disk full")
+	out := buf.String()
+	if n := strings.Count(out, "connection reset"); n != 1 {
+		t.Fatalf("expected the known issue logged once, got %d: %q", n, out)
+	}
+	if !strings.Contains(out, "fingerprint="+fp+" known_issue=upstream resets idle connections suppressed=0") {
+		t.Fatalf("missing known issue fields: %q", out)
+	}
+	if !strings.Contains(out, "disk full") {
+		t.Fatalf("expected other errors written, got %q", out)
+	}
+
+	// a restart on the same day keeps dropping it
+	logMutex.Lock()
+	saveKnownIssues(time.Now())
+	logMutex.Unlock()
+	knownIssuesFile = ""
+	InitWithConfig(cfg)
+	buf.Reset()
+	ErrorKV("connection reset", "peer", "10.0.0.8")
+	if buf.Len() != 0 {
+		t.Fatalf("expected the issue dropped after a restart, got %q", buf.String())
+	}
+
+	// on a later day it is logged again with the count
+	logMutex.Lock()
+	knownIssueStates[fp].Day = "2000-01-01"
+	saveKnownIssues(time.Now())
+	logMutex.Unlock()
+	knownIssuesFile = ""
+	InitWithConfig(cfg)
+	ErrorKV("connection reset", "peer", "10.0.0.9")
+	if got := buf.String(); !strings.Contains(got, "known_issue=upstream resets idle connections suppressed=3") {
+		t.Fatalf("expected the issue logged with its count, got %q", got)
+	}
+	var saved map[string]knownIssueState
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &saved); err != nil || saved[fp].Day != time.Now().UTC().Format(time.DateOnly) || saved[fp].Suppressed != 0 {
+		t.Fatalf("unexpected saved state %s: %v", data, err)
+	}
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestLastErrors(t *testing.T) {
+	InitWithConfig(Config{Mode: "development", LastErrors: 3})
+	captureLevels(new(bytes.Buffer))
+	defer Init("development", true)
+
+	WarnKV("not an error")
+	for i := range 4 {
+		ErrorKV(fmt.Sprintf("failure %d", i), "attempt", i)
+	}
+
+	got := LastErrors(2)
+	if len(got) != 2 || got[0].Message != "failure 3" || got[1].Message != "failure 2" {
+		t.Fatalf("LastErrors(2) = %v", got)
+	}
+	all := LastErrors(0)
+	if len(all) != 3 || all[2].Message != "failure 1" {
+		t.Fatalf("LastErrors(0) returned %d entries", len(all))
+	}
+	for _, e := range all {
+		if len(e.ID()) != 36 {
+			t.Fatalf("entry %q has no entry_id", e.Message)
+		}
+	}
+}
+
+func TestLastErrors_Off(t *testing.T) {
+	captureLevels(new(bytes.Buffer))
+	defer Init("development", true)
+	Errorf("failure")
+	if got := LastErrors(5); got != nil {
+		t.Fatalf("LastErrors without Config.LastErrors = %v", got)
+	}
+}
@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLayout_RenderTokens(t *testing.T) {
+	l := parseLayout("{level} {caller}: {msg} {fields}", "", false)
+	e := &Entry{Time: time.Now(), Level: WarnLevel, Caller: "main.run:12", Message: "disk low", Fields: []any{"free_mb", 12}}
+
+	if got, want := l.render(e), "WARN main.run:12: disk low free_mb=12"; got != want {
+		t.Fatalf("unexpected render: got %q want %q", got, want)
+	}
+
+	e.Fields = nil
+	if got, want := l.render(e), "WARN main.run:12: disk low"; got != want {
+		t.Fatalf("empty fields should not leave trailing space: got %q want %q", got, want)
+	}
+}
+
+func TestLayout_UnknownTokenIsLiteral(t *testing.T) {
+	l := parseLayout("{host} {msg}", "", false)
+	e := &Entry{Level: InfoLevel, Message: "hello"}
+
+	if got, want := l.render(e), "{host} hello"; got != want {
+		t.Fatalf("unknown tokens should be kept literally: got %q want %q", got, want)
+	}
+}
+
+func TestLayout_ColorOnlyOnConsole(t *testing.T) {
+	var buf bytes.Buffer
+	oldStdout := outStdout
+	defer func() { outStdout = oldStdout }()
+	outStdout = &buf
+	defer Init("development", true)
+
+	logPath := filepath.Join(t.TempDir(), "layout.log")
+	InitWithConfig(Config{
+		Mode:       "development",
+		FilePath:   logPath,
+		Layout:     "{color}{level}{reset} {caller} {msg} {fields}",
+		ColorDepth: ColorDepth8,
+	})
+	defer Close()
+
+	InfoKV("layout message", "k", "v")
+
+	if got := buf.String(); !strings.Contains(got, "\033[32mINFO\033[0m") {
+		t.Fatalf("console output should colorize the level, got: %q", got)
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	line := strings.TrimSpace(string(content))
+	if !regexp.MustCompile(`^INFO logger\.TestLayout_ColorOnlyOnConsole:\d+ layout message k=v$`).MatchString(line) {
+		t.Fatalf("file output should follow the layout without colors, got: %q", line)
+	}
+}
+
+func TestLayout_ProductionTimeFirst(t *testing.T) {
+	var stdoutBuf bytes.Buffer
+	oldStdout := outStdout
+	defer func() { outStdout = oldStdout }()
+	outStdout = &stdoutBuf
+	defer Init("development", true)
+
+	InitWithConfig(Config{
+		Mode:       "production",
+		Layout:     "{time} {level} {msg}",
+		TimeFormat: time.DateOnly,
+	})
+	Infoln("reordered")
+
+	line := strings.TrimSpace(stdoutBuf.String())
+	if !regexp.MustCompile(`^\d{4}-\d{2}-\d{2} INFO reordered$`).MatchString(line) {
+		t.Fatalf("expected time before level without brackets, got: %q", line)
+	}
+}
+
+func TestLayoutResolution(t *testing.T) {
+	tests := map[string]int64{
+		DefaultTimeFormat:             int64(time.Second),
+		time.Kitchen:                  int64(time.Second),
+		"15:04:05.000":                int64(time.Millisecond),
+		"2006-01-02T15:04:05,9Z07:00": int64(100 * time.Millisecond),
+		time.StampMicro:               0,
+		time.RFC3339Nano:              0,
+	}
+	for layout, want := range tests {
+		if got := layoutResolution(layout); got != want {
+			t.Errorf("layoutResolution(%q) = %d, want %d", layout, got, want)
+		}
+	}
+}
+
+func TestFormatTime_MatchesTimeFormat(t *testing.T) {
+	base := time.Date(2025, 10, 26, 10, 30, 45, 0, time.UTC)
+	offsets := []time.Duration{0, time.Millisecond, 999 * time.Millisecond, time.Second, time.Second + 1500*time.Microsecond, time.Hour}
+	for _, layout := range []string{DefaultTimeFormat, "15:04:05.000", time.RFC3339Nano} {
+		for _, d := range offsets {
+			for _, loc := range []*time.Location{time.UTC, time.FixedZone("X", 3600)} {
+				ts := base.Add(d).In(loc)
+				if got, want := formatTime(ts, layout), ts.Format(layout); got != want {
+					t.Errorf("formatTime(%v, %q) = %q, want %q", ts, layout, got, want)
+				}
+			}
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { formatTime(base, DefaultTimeFormat) }); allocs != 0 {
+		t.Errorf("cached timestamp allocated %v times per call", allocs)
+	}
+}
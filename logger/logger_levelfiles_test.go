@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevelFileSink_RetentionPerLevel(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	old := func(level string, days int) string {
+		path := filepath.Join(dir, "app-"+level+"-"+now.AddDate(0, 0, -days).Format(time.DateOnly)+".log")
+		os.WriteFile(path, []byte("old\n"), 0644)
+		return path
+	}
+	errorOld := old("error", 30)
+	debugOld := old("debug", 3)
+	infoOld := old("info", 3)
+
+	sink, err := NewLevelFileSink(LevelFilesConfig{
+		Dir:       dir,
+		Name:      "app",
+		Retention: map[Level]time.Duration{ErrorLevel: 90 * 24 * time.Hour, DebugLevel: 48 * time.Hour},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, kept := range map[string]bool{errorOld: true, debugOld: false, infoOld: true} {
+		if _, err := os.Stat(path); (err == nil) != kept {
+			t.Errorf("%s: kept = %v, want %v", filepath.Base(path), err == nil, kept)
+		}
+	}
+
+	sink.WriteEntry(&Entry{Time: now, Level: ErrorLevel, Message: "boom"})
+	sink.WriteEntry(&Entry{Time: now, Level: DebugLevel, Message: "detail"})
+	sink.WriteEntry(&Entry{Time: now, Level: ErrorLevel, Message: "again"})
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "app-error-"+now.Format(time.DateOnly)+".log"))
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 || !strings.HasSuffix(lines[1], "again") {
+		t.Fatalf("error file = %q", data)
+	}
+}
+
+func TestLevelFileSink_RotatesDaily(t *testing.T) {
+	dir := t.TempDir()
+	sink, _ := NewLevelFileSink(LevelFilesConfig{Dir: dir, Name: "app", DefaultRetention: 5 * 24 * time.Hour})
+	defer sink.Close()
+	start := time.Now().AddDate(0, 0, -10)
+	for day := range 10 {
+		sink.WriteEntry(&Entry{Time: start.AddDate(0, 0, day), Level: InfoLevel, Message: "tick"})
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "app-info-*.log"))
+	// the current day and the five before it
+	if len(files) != 6 {
+		t.Fatalf("files = %v", files)
+	}
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestParseLevelSpec_Grammar(t *testing.T) {
+	spec, errs := parseLevelSpec(" >=warn , DEBUG, http.client=debug, pkg:store = ERROR ")
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !spec.levels[DebugLevel] || spec.levels[InfoLevel] || !spec.levels[WarnLevel] || !spec.levels[FatalLevel] {
+		t.Errorf("unexpected levels %v", spec.levels)
+	}
+	if spec.loggers["http.client"] != DebugLevel || spec.packages["store"] != ErrorLevel {
+		t.Errorf("unexpected rules %v %v", spec.loggers, spec.packages)
+	}
+	if got, want := spec.String(), "DEBUG,WARN,ERROR,FATAL,http.client=DEBUG,pkg:store=ERROR"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	// rules alone leave every level enabled
+	spec, _ = parseLevelSpec("pkg:store=WARN")
+	if len(spec.levels) != 5 {
+		t.Errorf("expected all levels enabled, got %v", spec.levels)
+	}
+}
+
+func TestParseLevelSpec_ReportsInvalidTerms(t *testing.T) {
+	spec, errs := parseLevelSpec("INFO,,LOUD,>=,.http=INFO,pkg:a/b=WARN,db=NOISY")
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	got := strings.Join(msgs, "\n")
+	for _, want := range []string{
+		`LOGGER_LEVELS term "": empty term`,
+		`LOGGER_LEVELS has unknown level "LOUD"`,
+		`LOGGER_LEVELS has unknown level ""`,
+		`LOGGER_LEVELS term ".http=INFO": malformed logger name ".http"`,
+		`LOGGER_LEVELS term "pkg:a/b=WARN": malformed package name "a/b"`,
+		`LOGGER_LEVELS has unknown level "NOISY"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if len(errs) != 6 {
+		t.Errorf("expected 6 errors, got %d:\n%s", len(errs), got)
+	}
+	// valid terms still apply
+	if !spec.levels[InfoLevel] || spec.levels[WarnLevel] {
+		t.Errorf("unexpected levels %v", spec.levels)
+	}
+}
+
+func TestLevelSpec_PackageAndLoggerRules(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "pkg:logger=ERROR,spec.db=WARN")
+	Init("development", true)
+	defer func() {
+		t.Setenv("LOGGER_LEVELS", "")
+		setLoggerLevels(nil)
+		setLevels(allLevels(), nil)
+		Init("development", true)
+	}()
+	var buf bytes.Buffer
+	captureLevels(&buf)
+
+	Infof("dropped by package rule")
+	Errorf("kept by package rule")
+	if strings.Contains(buf.String(), "dropped") || !strings.Contains(buf.String(), "kept") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	if Get("spec.db.pool").Level() != WarnLevel {
+		t.Fatalf("expected logger rule to apply to children, got %v", Get("spec.db.pool").Level())
+	}
+	if rc := EffectiveConfig(); rc.PackageLevels["logger"] != "ERROR" {
+		t.Fatalf("expected package rule in EffectiveConfig, got %v", rc.PackageLevels)
+	}
+}
+
+func FuzzParseLevelSpec(f *testing.F) {
+	for _, seed := range []string{
+		"", "INFO,ERROR", ">=WARN", "http.client=DEBUG", "pkg:store=ERROR",
+		"info, DeBuG ,warning", ",,", ">=", "a=b=c", "pkg:=INFO", "x>=y=INFO", "pkg: s =fatal",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		spec, _ := parseLevelSpec(s)
+		if len(spec.levels) == 0 {
+			t.Fatalf("%q enables no levels", s)
+		}
+		canonical := spec.String()
+		again, errs := parseLevelSpec(canonical)
+		if len(errs) != 0 {
+			t.Fatalf("canonical form %q of %q does not parse: %v", canonical, s, errs)
+		}
+		if !maps.Equal(spec.levels, again.levels) || !maps.Equal(spec.loggers, again.loggers) ||
+			!maps.Equal(spec.packages, again.packages) {
+			t.Fatalf("%q -> %q changed the spec: %+v != %+v", s, canonical, spec, again)
+		}
+	})
+}
+
+func TestInit_WarnsOnUnrecognizedLevelTerms(t *testing.T) {
+	var buf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout, outStderr = &buf, &buf
+	t.Setenv("JOURNAL_STREAM", "")
+	t.Setenv("LOGGER_LEVELS", "ERROR,INF, pkg:a/b=WARN")
+	defer func() {
+		t.Setenv("LOGGER_LEVELS", "")
+		setLevels(allLevels(), nil)
+		Init("development", true)
+	}()
+
+	Init("production", false)
+	want := "[WARN] [logger.InitWithConfig] ignoring unrecognized LOGGER_LEVELS terms terms=INF,pkg:a/b=WARN levels=ERROR\n"
+	if buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
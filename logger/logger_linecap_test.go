@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMaxLineBytes_SplitsTextLines(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	InitWithConfig(Config{Mode: "development", Verbose: true, MaxLineBytes: 300})
+	defer Init("development", true)
+
+	msg := strings.Repeat("é", 400) + " end"
+	InfoKV(msg, "user", "alice")
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected the entry split into several lines, got %d", len(lines))
+	}
+	var joined strings.Builder
+	for i, line := range lines {
+		if len(line) > 300 {
+			t.Fatalf("line %d has %d bytes", i+1, len(line))
+		}
+		marker := fmt.Sprintf(" %s=%d/%d", LinePartKey, i+1, len(lines))
+		body, ok := strings.CutSuffix(line, marker)
+		if !ok {
+			t.Fatalf("line %d = %q, want suffix %q", i+1, line, marker)
+		}
+		// drop the prefix, timestamp, and caller of the part
+		_, piece, _ := strings.Cut(body, "SplitsTextLines:")
+		_, piece, _ = strings.Cut(piece, "] ")
+		joined.WriteString(piece)
+	}
+	if !strings.HasSuffix(joined.String(), msg+" user=alice") {
+		t.Fatalf("joined parts = %q", joined.String())
+	}
+
+	buf.Reset()
+	Infof("short")
+	if got := buf.String(); strings.Contains(got, LinePartKey) {
+		t.Fatalf("expected a short line unchanged, got %q", got)
+	}
+}
+
+func TestMaxLineBytes_SplitsJSONIntoValidEntries(t *testing.T) {
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	InitWithConfig(Config{Mode: "production", Fallback: FallbackJSON, MaxLineBytes: 256})
+	defer Init("development", true)
+
+	payload := strings.Repeat(`{"k":"v"} `, 100)
+	ErrorKV("upload failed", "body", payload)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	var joined strings.Builder
+	for i, line := range lines {
+		if len(line) > 256 {
+			t.Fatalf("line %d has %d bytes", i+1, len(line))
+		}
+		var part map[string]any
+		if err := json.Unmarshal([]byte(line), &part); err != nil {
+			t.Fatalf("line %d is not JSON: %v: %q", i+1, err, line)
+		}
+		if part[LinePartKey] != fmt.Sprintf("%d/%d", i+1, len(lines)) || part["level"] != "ERROR" {
+			t.Fatalf("line %d = %v", i+1, part)
+		}
+		joined.WriteString(part["msg"].(string))
+	}
+	var original map[string]any
+	if err := json.Unmarshal([]byte(joined.String()), &original); err != nil {
+		t.Fatalf("joined parts are not JSON: %v", err)
+	}
+	if original["msg"] != "upload failed" || original["body"] != payload {
+		t.Fatalf("joined entry = %v", original)
+	}
+}
+
+func TestValidate_MaxLineBytes(t *testing.T) {
+	err := Validate(Config{Mode: "development", MaxLineBytes: 100})
+	if err == nil || !strings.Contains(err.Error(), "MaxLineBytes 100") {
+		t.Fatalf("Validate = %v", err)
+	}
+}
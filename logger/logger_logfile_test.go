@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readAll(t *testing.T, lr *LogReader) []*Entry {
+	t.Helper()
+	var entries []*Entry
+	for {
+		e, err := lr.Next()
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+func TestOpenLogFile_BinaryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.msgpack")
+	at := time.Date(2026, 3, 4, 5, 6, 7, 890, time.UTC)
+	in := []*Entry{
+		{Time: at, Level: WarnLevel, Caller: "api.Login:10", Message: "multi\nline", Fields: []any{
+			"n", -300, "big", uint64(1 << 63), "f", 1.5, "ok", true, "raw", []byte{0, 1}, "list", []string{"a"}, "nested", map[string]int{"x": 1}, "nil", nil,
+		}},
+		{Time: time.Date(2500, 1, 1, 0, 0, 0, 0, time.UTC), Level: ErrorLevel, Message: strings.Repeat("x", 70000)},
+	}
+	for _, batch := range [][]*Entry{in[:1], in[1:]} {
+		// reopening appends after the existing header
+		sink, err := NewFileSink(path, MsgpackEncoder{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range batch {
+			sink.WriteEntry(e)
+		}
+		sink.Close()
+	}
+
+	lr, err := OpenLogFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lr.Close()
+	out := readAll(t, lr)
+	if len(out) != 2 {
+		t.Fatalf("read %d entries", len(out))
+	}
+	for i := range out {
+		if !out[i].Time.Equal(in[i].Time) || out[i].Level != in[i].Level || out[i].Caller != in[i].Caller || out[i].Message != in[i].Message {
+			t.Fatalf("entry %d = %+v", i, out[i])
+		}
+	}
+	wantFields := []any{
+		"n", int64(-300), "big", uint64(1 << 63), "f", 1.5, "ok", true, "raw", []byte{0, 1}, "list", []any{"a"}, "nested", map[string]any{"x": int64(1)}, "nil", nil,
+	}
+	if !reflect.DeepEqual(out[0].Fields, wantFields) {
+		t.Fatalf("fields = %#v", out[0].Fields)
+	}
+}
+
+func TestOpenLogFile_TextAndJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte("[INFO] [main.run:1] started port=80\nnot a log line\n\n"+`{"time":"2026-03-04T05:06:07Z","level":"ERROR","caller":"x","msg":"boom"}`), 0644)
+	lr, err := OpenLogFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lr.Close()
+	out := readAll(t, lr)
+	if len(out) != 2 || out[0].Message != "started" || out[1].Message != "boom" {
+		t.Fatalf("entries = %+v", out)
+	}
+	if lr.Skipped() != 1 {
+		t.Fatalf("skipped = %d", lr.Skipped())
+	}
+}
+
+func TestLogReader_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.msgpack")
+	sink, _ := NewFileSink(path, MsgpackEncoder{})
+	sink.WriteEntry(&Entry{Message: "m"})
+	sink.Close()
+	data, _ := os.ReadFile(path)
+
+	lr, _ := NewLogReader(strings.NewReader(string(data[:len(data)-2])))
+	if _, err := lr.Next(); err == nil || errors.Is(err, io.EOF) {
+		t.Fatalf("truncated record: err = %v", err)
+	}
+
+	if _, err := NewLogReader(strings.NewReader(binaryLogMagic + "\x04cbor")); err == nil {
+		t.Fatal("expected an unsupported encoding error")
+	}
+
+	json := filepath.Join(t.TempDir(), "entries.jsonl")
+	os.WriteFile(json, []byte("{}\n"), 0644)
+	if _, err := NewFileSink(json, MsgpackEncoder{}); err == nil {
+		t.Fatal("expected an error appending binary records to a text file")
+	}
+}
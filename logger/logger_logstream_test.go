@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLogStreamServer_StreamsFilteredEntries(t *testing.T) {
+	stream := NewLogStreamServer()
+	srv := httptest.NewUnstartedServer(stream)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+LogStreamMethod,
+		bytes.NewReader(grpcFrame(appendProtoString(nil, 1, "warn"))))
+	req.Header.Set("Content-Type", "application/grpc")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 || resp.Header.Get("Grpc-Status") != "" {
+		t.Fatalf("unexpected response: proto=%s grpc-status=%q", resp.Proto, resp.Header.Get("Grpc-Status"))
+	}
+
+	for deadline := time.Now().Add(time.Second); ; {
+		stream.mu.Lock()
+		n := len(stream.subs)
+		stream.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("subscriber was not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	info := testEntry()
+	info.Level = InfoLevel
+	info.Message = "filtered out"
+	stream.WriteEntry(info)
+	stream.WriteEntry(testEntry())
+
+	msg, err := readGRPCMessage(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := protoStrings(msg)
+	if fields[2][0] != "ERROR" || fields[4][0] != "disk full" || len(fields[5]) != 1 {
+		t.Fatalf("unexpected entry: %q", fields)
+	}
+	if kv := protoStrings([]byte(fields[5][0])); kv[1][0] != "path" || kv[2][0] != "/var" {
+		t.Fatalf("unexpected field: %q", kv)
+	}
+
+	stream.Close()
+	if _, err := readGRPCMessage(resp.Body); err == nil {
+		t.Fatal("expected the stream to end after Close")
+	}
+	if got := resp.Trailer.Get("Grpc-Status"); got != "0" {
+		t.Fatalf("grpc-status trailer = %q", got)
+	}
+}
+
+func TestLogStreamServer_UnknownMethod(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/logger.v1.LogStream/Other", nil)
+	req.Header.Set("Content-Type", "application/grpc")
+	NewLogStreamServer().ServeHTTP(rec, req)
+	if got := rec.Header().Get("Grpc-Status"); got != "12" {
+		t.Fatalf("grpc-status = %q, want 12 (UNIMPLEMENTED)", got)
+	}
+}
@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevel_JSONUsesNames(t *testing.T) {
+	cfg := struct {
+		Min   Level
+		Remap map[Level]Level
+	}{WarnLevel, map[Level]Level{FatalLevel: ErrorLevel}}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Min":"WARN","Remap":{"FATAL":"ERROR"}}`; string(data) != want {
+		t.Fatalf("got %s, want %s", data, want)
+	}
+
+	var back struct {
+		Min   Level
+		Remap map[Level]Level
+	}
+	if err := json.Unmarshal([]byte(`{"Min":"warning","Remap":{"fatal":"error"}}`), &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Min != WarnLevel || back.Remap[FatalLevel] != ErrorLevel {
+		t.Fatalf("unexpected decode: %+v", back)
+	}
+	if err := json.Unmarshal([]byte(`{"Min":"loud"}`), &back); err == nil {
+		t.Fatal("unknown level name should fail to decode")
+	}
+	if _, err := Level(9).MarshalText(); err == nil {
+		t.Fatal("out-of-range level should fail to encode")
+	}
+}
+
+func TestEntry_JSONRoundTrip(t *testing.T) {
+	e := &Entry{
+		Time:    time.Date(2025, 3, 4, 5, 6, 7, 890000000, time.UTC),
+		Level:   ErrorLevel,
+		Caller:  "shop.Checkout:57",
+		Message: "charge failed",
+		Fields:  []any{"order", 1042, "retry", true},
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"time":"2025-03-04T05:06:07.89Z","level":"ERROR","caller":"shop.Checkout:57","msg":"charge failed","order":1042,"retry":true}`; string(data) != want {
+		t.Fatalf("got %s, want %s", data, want)
+	}
+
+	var back Entry
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !back.Time.Equal(e.Time) || back.Level != e.Level || back.Caller != e.Caller || back.Message != e.Message {
+		t.Fatalf("unexpected decode: %+v", back)
+	}
+	if want := []any{"order", json.Number("1042"), "retry", true}; !reflect.DeepEqual(back.Fields, want) {
+		t.Fatalf("fields = %#v, want %#v", back.Fields, want)
+	}
+}
+
+func TestEntry_TextRoundTrip(t *testing.T) {
+	e := Entry{
+		Time:    time.Date(2025, 3, 4, 5, 6, 7, 123456000, time.Local),
+		Level:   WarnLevel,
+		Caller:  "main.run:12",
+		Message: "disk almost full",
+		Fields:  []any{"free", "2%"},
+	}
+	text, err := e.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2025/03/04 05:06:07.123456 [WARN] [main.run:12] disk almost full free=2%"; string(text) != want {
+		t.Fatalf("got %q, want %q", text, want)
+	}
+	var back Entry
+	if err := back.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if !back.Time.Equal(e.Time) || back.Level != e.Level || back.Message != e.Message || !reflect.DeepEqual(back.Fields, e.Fields) {
+		t.Fatalf("unexpected decode: %+v", back)
+	}
+
+	text, _ = Entry{Level: InfoLevel, Caller: "main.main:3", Message: "hi"}.MarshalText()
+	if strings.HasPrefix(string(text), "0001") {
+		t.Fatalf("zero time should be omitted, got %q", text)
+	}
+	if err := back.UnmarshalText([]byte("not a log line")); err == nil {
+		t.Fatal("garbage should fail to decode")
+	}
+}
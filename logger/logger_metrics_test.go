@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics_CountMatchingEntries(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	defer func() { metrics = nil }()
+
+	if err := AddMetric(Metric{
+		Name:     "db_timeouts_total",
+		Help:     "Database timeouts.",
+		MinLevel: ErrorLevel,
+		Fields:   map[string]string{"code": "DB_TIMEOUT"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddMetric(Metric{Name: "errors_total", MinLevel: ErrorLevel, Labels: []string{"code"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ErrorKV("query failed", "code", "DB_TIMEOUT")
+	ErrorKV("query failed", "code", "DB_TIMEOUT")
+	ErrorKV("bad input", "code", `say "hi"`)
+	WarnKV("slow query", "code", "DB_TIMEOUT")
+	Errorf("no code")
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	want := `# HELP db_timeouts_total Database timeouts.
+# TYPE db_timeouts_total counter
+db_timeouts_total 2
+# TYPE errors_total counter
+errors_total{code=""} 1
+errors_total{code="DB_TIMEOUT"} 2
+errors_total{code="say \"hi\""} 1
+`
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("unexpected metrics output:\n%s\nwant:\n%s", got, want)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+}
+
+func TestAddMetric_Validation(t *testing.T) {
+	defer func() { metrics = nil }()
+
+	if err := AddMetric(Metric{Name: "ok_total"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []Metric{
+		{Name: "ok_total"},
+		{Name: "bad-name"},
+		{Name: "labels_total", Labels: []string{"__reserved"}},
+	} {
+		if err := AddMetric(m); err == nil {
+			t.Errorf("expected an error for %+v", m)
+		}
+	}
+
+	var buf bytes.Buffer
+	WriteMetrics(&buf)
+	if !strings.Contains(buf.String(), "ok_total 0\n") {
+		t.Fatalf("expected an unlabeled counter to start at 0, got %q", buf.String())
+	}
+}
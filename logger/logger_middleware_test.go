@@ -0,0 +1,283 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func serve(h http.Handler, path string) {
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+}
+
+func TestMiddleware_AccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+
+	h := Middleware(MiddlewareConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	serve(h, "/missing")
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "[WARN] [http] GET /missing") {
+		t.Fatalf("expected WARN access entry for 404, got: %q", out)
+	}
+	if !strings.Contains(out, "status=404") || !strings.Contains(out, "duration_ms=") {
+		t.Fatalf("expected status and duration fields, got: %q", out)
+	}
+}
+
+func TestMiddleware_BufferDiscardedOnSuccess(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+
+	h := Middleware(MiddlewareConfig{Buffer: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		DebugContext(r.Context(), "handler detail")
+	}))
+	serve(h, "/ok")
+
+	out := buf.String()
+	if strings.Contains(out, "handler detail") {
+		t.Fatalf("buffered entries should be discarded for successful requests, got: %q", out)
+	}
+	if !strings.Contains(out, "[INFO] [http] GET /ok") {
+		t.Fatalf("access entry should still be written, got: %q", out)
+	}
+}
+
+func TestMiddleware_FlushRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    MiddlewareConfig
+		status int
+		sleep  time.Duration
+		reason string
+	}{
+		{"server error", MiddlewareConfig{Buffer: true}, 503, 0, "flush=error"},
+		{"listed status", MiddlewareConfig{Buffer: true, FlushStatus: []int{429}}, 429, 0, "flush=status"},
+		{"slow request", MiddlewareConfig{Buffer: true, FlushSlowerThan: time.Millisecond}, 200, 5 * time.Millisecond, "flush=slow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			captureLevels(&buf)
+
+			h := Middleware(tt.cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				InfoContext(r.Context(), "handler detail")
+				time.Sleep(tt.sleep)
+				w.WriteHeader(tt.status)
+			}))
+			serve(h, "/rule")
+
+			out := buf.String()
+			if !strings.Contains(out, "handler detail") || !strings.Contains(out, tt.reason) {
+				t.Fatalf("expected flushed entries with %s, got: %q", tt.reason, out)
+			}
+			if strings.Index(out, "handler detail") > strings.Index(out, "[http]") {
+				t.Fatalf("flushed entries should precede the access entry, got: %q", out)
+			}
+		})
+	}
+}
+
+func TestLatencyTracker_Percentile(t *testing.T) {
+	tracker := newLatencyTracker(latencyWindow)
+	for i := 0; i < 200; i++ {
+		if tracker.observe(time.Millisecond, 0.99) {
+			t.Fatalf("uniform latencies should not exceed p99 (sample %d)", i)
+		}
+	}
+	if !tracker.observe(time.Second, 0.99) {
+		t.Fatal("an outlier should exceed p99")
+	}
+	if tracker.observe(time.Second, 0) {
+		t.Fatal("percentile 0 should disable flushing")
+	}
+}
+
+func TestMiddleware_RoutePatterns(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("example.com/static/", func(w http.ResponseWriter, r *http.Request) {})
+	h := Middleware(MiddlewareConfig{RoutePatterns: true})(mux)
+	serve(h, "/users/42")
+	serve(h, "http://example.com/static/app.js")
+	serve(h, "/nowhere")
+
+	durations := regexp.MustCompile(`duration_ms=\d+`)
+	lines := strings.Split(strings.TrimSuffix(durations.ReplaceAllString(buf.String(), "duration_ms=0"), "\n"), "\n")
+	want := []string{
+		"[INFO] [http] GET /users/{id} method=GET path=/users/{id} status=200 duration_ms=0 raw_path=/users/42",
+		"[INFO] [http] GET /static/ method=GET path=/static/ status=200 duration_ms=0 raw_path=/static/app.js",
+		"[WARN] [http] GET /nowhere method=GET path=/nowhere status=404 duration_ms=0",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+
+	buf.Reset()
+	custom := Middleware(MiddlewareConfig{RoutePattern: func(r *http.Request) string { return "/custom/{x}" }})(mux)
+	serve(custom, "/users/7")
+	if !strings.HasPrefix(buf.String(), "[INFO] [http] GET /custom/{x} ") {
+		t.Fatalf("RoutePattern not used: %q", buf.String())
+	}
+}
+
+func TestMiddleware_Sizes(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	h := Middleware(MiddlewareConfig{Sizes: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte("hello, "))
+		io.WriteString(w, "world")
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", 1000))))
+	if !strings.Contains(buf.String(), " request_bytes=1000 response_bytes=12\n") {
+		t.Fatalf("got %q", buf.String())
+	}
+
+	buf.Reset()
+	serve(h, "/empty")
+	if strings.Contains(buf.String(), "request_bytes") || !strings.Contains(buf.String(), " response_bytes=12\n") {
+		t.Fatalf("got %q", buf.String())
+	}
+}
+
+func TestMiddleware_SlowThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	delay := 0 * time.Millisecond
+	h := Middleware(MiddlewareConfig{SlowThreshold: 10 * time.Millisecond})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	serve(h, "/fast")
+	delay = 15 * time.Millisecond
+	serve(h, "/slow")
+	serve(h, "/fail")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "[INFO] [http] GET /fast") || strings.Contains(lines[0], "slow=") {
+		t.Errorf("fast request: %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "[WARN] [http] GET /slow") || !strings.HasSuffix(lines[1], " slow=true") {
+		t.Errorf("slow request: %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "[ERROR] [http] GET /fail") || !strings.HasSuffix(lines[2], " slow=true") {
+		t.Errorf("slow failing request: %q", lines[2])
+	}
+}
+
+func TestMiddleware_SkipProbes(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	h := Middleware(MiddlewareConfig{
+		SkipPaths:      []string{"/healthz", "/metrics/*"},
+		SkipUserAgents: []string{"kube-probe"},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" && r.URL.Query().Has("down") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	serve(h, "/healthz")
+	serve(h, "/metrics/go")
+	probe := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	probe.Header.Set("User-Agent", "kube-probe/1.30")
+	h.ServeHTTP(httptest.NewRecorder(), probe)
+	serve(h, "/healthz?down")
+	serve(h, "/healthzz")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "[ERROR] [http] GET /healthz ") || !strings.HasPrefix(lines[1], "[INFO] [http] GET /healthzz ") {
+		t.Fatalf("got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	sampled := Middleware(MiddlewareConfig{SkipPaths: []string{"/healthz"}, SkipSample: 3})(http.NotFoundHandler())
+	for range 7 {
+		serve(sampled, "/healthz")
+	}
+	if n := strings.Count(buf.String(), "GET /healthz"); n != 3 {
+		t.Fatalf("sampled %d of 7 probes, want 3:\n%s", n, buf.String())
+	}
+}
+
+func TestMiddleware_FlushAndHijack(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+
+	var hijacked bool
+	h := Middleware(MiddlewareConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("expected the writer to implement http.Flusher")
+		}
+		io.WriteString(w, "data: 1\n\n")
+		f.Flush()
+		_, _, err := w.(http.Hijacker).Hijack()
+		hijacked = err == nil
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+	if !rec.Flushed {
+		t.Fatal("expected Flush to reach the underlying writer")
+	}
+	if hijacked {
+		t.Fatal("expected Hijack to fail on a writer that cannot be hijacked")
+	}
+
+	upgrade := Middleware(MiddlewareConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: close\r\n\r\n")
+		rw.Flush()
+	}))
+	logged := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrade.ServeHTTP(w, r)
+		close(logged)
+	}))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected the hijacked connection's response, got %s", resp.Status)
+	}
+	<-logged
+	if !strings.Contains(buf.String(), "status=101") || !strings.Contains(buf.String(), "hijacked=true") {
+		t.Fatalf("expected the hijacked request logged with status 101, got:\n%s", buf.String())
+	}
+}
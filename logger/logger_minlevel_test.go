@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetMinLevel_EnablesDebugOutputAndRestores(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	t.Setenv("NO_COLOR", "1")
+	Init("development", false)
+	defer Init("development", true)
+	var buf bytes.Buffer
+	Info.SetOutput(&buf)
+
+	restore := SetMinLevel(DebugLevel)
+	Debugf("cache miss")
+	restore()
+	Debugf("hidden")
+
+	if got := buf.String(); !strings.HasPrefix(got, "[DEBUG] ") || !strings.HasSuffix(got, "cache miss\n") {
+		t.Fatalf("expected one DEBUG entry on the INFO writer, got %q", got)
+	}
+	if debugOutput || Debug.Writer() != io.Discard {
+		t.Fatal("expected DEBUG output off after restore")
+	}
+}
+
+func TestSetMinLevel_DisablesLowerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	restore := SetMinLevel(ErrorLevel)
+	Infof("quiet")
+	Warnf("quiet")
+	Errorf("loud")
+	restore()
+	Infof("back")
+
+	if got := buf.String(); strings.Contains(got, "quiet") || !strings.Contains(got, "loud") || !strings.Contains(got, "back") {
+		t.Fatalf("unexpected output %q", got)
+	}
+	if !enabledLevels()[DebugLevel] || !enabledLevels()[WarnLevel] {
+		t.Fatalf("levels not restored: %v", enabledLevels())
+	}
+}
+
+func TestSetMinLevel_ConcurrentWithLogging(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 1000 {
+			InfoKV("tick")
+		}
+	}()
+	for range 100 {
+		SetMinLevel(WarnLevel)()
+	}
+	<-done
+	if !enabledLevels()[InfoLevel] {
+		t.Fatal("expected INFO enabled after restore")
+	}
+}
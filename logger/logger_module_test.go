@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"bytes"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestModuleFor(t *testing.T) {
+	deps := []*debug.Module{
+		{Path: "github.com/acme/db", Version: "v1.4.0"},
+		{Path: "github.com/acme/db/v2", Version: "v2.1.3"},
+		{Path: "golang.org/x/net", Version: "v0.30.0", Replace: &debug.Module{Path: "../net", Version: ""}},
+		{Path: "example.com/forked", Version: "v1.0.0", Replace: &debug.Module{Path: "example.com/fork", Version: "v1.0.1-fix"}},
+	}
+	for _, tc := range []struct{ function, path, version string }{
+		{"github.com/acme/db.(*Conn).Query", "github.com/acme/db", "v1.4.0"},
+		{"github.com/acme/db/v2/pool.Get", "github.com/acme/db/v2", "v2.1.3"},
+		{"github.com/acme/dbx.Open", "", ""},
+		{"golang.org/x/net/http2.(*Framer).ReadFrame", "golang.org/x/net", "v0.30.0"},
+		{"example.com/forked/api.Call.func1", "example.com/forked", "v1.0.1-fix"},
+		{"example.com/app/internal/store.Save", "", ""},
+		{"main.main", "", ""},
+		{"net/http.(*conn).serve", "", ""},
+	} {
+		path, version := moduleFor(packagePath(tc.function), "example.com/app", deps)
+		if path != tc.path || version != tc.version {
+			t.Errorf("%s: got %q %q, want %q %q", tc.function, path, version, tc.path, tc.version)
+		}
+	}
+}
+
+func TestCallerModule_MainModuleHasNoFields(t *testing.T) {
+	InitWithConfig(Config{Mode: "development", CallerModule: true})
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	Errorf("own failure")
+	if strings.Contains(buf.String(), ModuleKey+"=") {
+		t.Fatalf("main module entry has module fields: %q", buf.String())
+	}
+}
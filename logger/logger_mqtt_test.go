@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+// mqttBroker accepts one connection, acknowledges CONNECT and QoS 1
+// PUBLISH packets, and reports the will topic and published payloads.
+func mqttBroker(ln net.Listener, will chan<- string, published chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		typ, body, err := readMQTTPacket(r)
+		if err != nil {
+			close(published)
+			return
+		}
+		switch typ >> 4 {
+		case 1: // CONNECT: protocol name, level, flags, keep alive, client id
+			pos := 10
+			n := int(body[pos])<<8 | int(body[pos+1])
+			pos += 2 + n
+			if body[7]&0x04 != 0 {
+				n = int(body[pos])<<8 | int(body[pos+1])
+				will <- string(body[pos+2 : pos+2+n])
+			}
+			conn.Write([]byte{0x20, 0x02, 0x00, 0x00})
+		case 3:
+			n := int(body[0])<<8 | int(body[1])
+			topic := string(body[2 : 2+n])
+			payload := body[2+n:]
+			if typ&0x06 != 0 {
+				conn.Write([]byte{0x40, 0x02, payload[0], payload[1]})
+				payload = payload[2:]
+			}
+			published <- topic + " " + string(payload)
+		case 14:
+			close(published)
+			return
+		}
+	}
+}
+
+func TestMQTTSink_PublishWithQoS1AndWill(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	will := make(chan string, 1)
+	published := make(chan string, 4)
+	go mqttBroker(ln, will, published)
+
+	s, err := NewMQTTSink(MQTTConfig{Address: ln.Addr().String(), Topic: "edge/1/logs", QoS: 1,
+		WillTopic: "edge/1/status", WillMessage: "offline"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteEntry(testEntry()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := <-will; got != "edge/1/status" {
+		t.Fatalf("will topic = %q", got)
+	}
+	msg := <-published
+	if !strings.HasPrefix(msg, "edge/1/logs {") || !strings.Contains(msg, `"msg":"disk full"`) {
+		t.Fatalf("unexpected publish: %q", msg)
+	}
+	if _, ok := <-published; ok {
+		t.Fatal("expected DISCONNECT after the single publish")
+	}
+}
+
+func TestMQTTSink_OfflineBuffering(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	s, err := NewMQTTSink(MQTTConfig{Address: addr, BufferSize: 2, Encoder: TextEncoder{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.retryEvery = 0
+	for _, msg := range []string{"first", "second", "third"} {
+		e := testEntry()
+		e.Message = msg
+		if err := s.WriteEntry(e); err == nil {
+			t.Fatal("expected an error while the broker is offline")
+		}
+	}
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot rebind %s: %v", addr, err)
+	}
+	defer ln.Close()
+	published := make(chan string, 4)
+	go mqttBroker(ln, make(chan string, 1), published)
+
+	e := testEntry()
+	e.Message = "fourth"
+	if err := s.WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	var got []string
+	for msg := range published {
+		got = append(got, strings.Fields(msg)[3])
+	}
+	// BufferSize 2: "first" and "second" were dropped as newer entries arrived.
+	if strings.Join(got, ",") != "third,fourth" {
+		t.Fatalf("published %q, want third,fourth", got)
+	}
+}
+
+func TestMQTTSink_PasswordRequiresUsername(t *testing.T) {
+	if _, err := NewMQTTSink(MQTTConfig{Address: "127.0.0.1:1883", Password: "secret"}); err == nil {
+		t.Fatal("expected a password without a username to be rejected")
+	}
+	if _, err := NewMQTTSink(MQTTConfig{Address: "127.0.0.1:1883", Username: "edge", Password: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMsgpackEncoder_Encoding(t *testing.T) {
+	e := &Entry{
+		Time:    time.Unix(1, 5).UTC(),
+		Level:   WarnLevel,
+		Caller:  "c",
+		Message: "m",
+		Fields:  []any{"n", -1, "ok", true, "err", errors.New("x"), 7, "skipped", "list", []int{300}},
+	}
+	got, _ := MsgpackEncoder{}.Encode(e)
+	want := []byte{0x88,
+		0xa4, 't', 'i', 'm', 'e', 0xd7, 0xff, 0, 0, 0, 0x14, 0, 0, 0, 1,
+		0xa5, 'l', 'e', 'v', 'e', 'l', 0xa4, 'W', 'A', 'R', 'N',
+		0xa6, 'c', 'a', 'l', 'l', 'e', 'r', 0xa1, 'c',
+		0xa3, 'm', 's', 'g', 0xa1, 'm',
+		0xa1, 'n', 0xff,
+		0xa2, 'o', 'k', 0xc3,
+		0xa3, 'e', 'r', 'r', 0xa1, 'x',
+		0xa4, 'l', 'i', 's', 't', 0x91, 0xcd, 0x01, 0x2c,
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got  % x\nwant % x", got, want)
+	}
+}
+
+func TestMsgpackEncoder_SmallerThanJSON(t *testing.T) {
+	e := &Entry{
+		Time:    time.Now(),
+		Level:   InfoLevel,
+		Caller:  "api.Handle:42",
+		Message: "request",
+		Fields:  []any{"status", 200, "bytes", 51234, "duration_ms", 12.5, "cached", false},
+	}
+	packed, _ := MsgpackEncoder{}.Encode(e)
+	text, _ := JSONEncoder{}.Encode(e)
+	if len(packed) >= len(text)*3/4 {
+		t.Fatalf("msgpack %d bytes, json %d bytes", len(packed), len(text))
+	}
+}
+
+func TestFileSink_Framing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries")
+	sink, err := NewFileSink(path, MsgpackEncoder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Entry{Time: time.Unix(1, 0), Message: "multi\nline"}
+	sink.WriteEntry(e)
+	sink.WriteEntry(e)
+	sink.Close()
+
+	data, _ := os.ReadFile(path)
+	header := binaryLogMagic + "\x07msgpack"
+	if !bytes.HasPrefix(data, []byte(header)) {
+		t.Fatalf("header = %q", data[:len(header)])
+	}
+	data = data[len(header):]
+	record, _ := MsgpackEncoder{}.Encode(e)
+	for range 2 {
+		n := binary.BigEndian.Uint32(data)
+		if !bytes.Equal(data[4:4+n], record) {
+			t.Fatalf("record = % x", data[4:4+n])
+		}
+		data = data[4+n:]
+	}
+	if len(data) != 0 {
+		t.Fatalf("trailing bytes % x", data)
+	}
+
+	path = filepath.Join(t.TempDir(), "entries.jsonl")
+	sink, _ = NewFileSink(path, nil)
+	sink.WriteEntry(e)
+	sink.Close()
+	if data, _ := os.ReadFile(path); !bytes.HasSuffix(data, []byte("\"msg\":\"multi\\nline\"}\n")) {
+		t.Fatalf("json file = %q", data)
+	}
+}
@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNamedLogger_LevelInheritance(t *testing.T) {
+	defer setLoggerLevels(nil)
+	InitWithConfig(Config{
+		Mode:         "development",
+		Verbose:      true,
+		LoggerLevels: map[string]Level{"": WarnLevel, "http.client": DebugLevel},
+	})
+	defer Init("development", true)
+
+	tests := map[string]Level{
+		"":                  WarnLevel,
+		"db":                WarnLevel,
+		"http":              WarnLevel,
+		"http.client":       DebugLevel,
+		"http.client.retry": DebugLevel,
+		"http.clients":      WarnLevel,
+	}
+	for name, want := range tests {
+		if got := Get(name).Level(); got != want {
+			t.Errorf("Get(%q).Level() = %v, want %v", name, got, want)
+		}
+	}
+
+	Get("http").SetLevel(ErrorLevel)
+	if got := Get("http.server").Level(); got != ErrorLevel {
+		t.Errorf("expected http.server to inherit ERROR from http, got %v", got)
+	}
+	if got := Get("http.client").Level(); got != DebugLevel {
+		t.Errorf("expected the http.client override to win, got %v", got)
+	}
+	Get("http").ResetLevel()
+	if got := Get("http.server").Level(); got != WarnLevel {
+		t.Errorf("expected http.server to fall back to the root level, got %v", got)
+	}
+}
+
+func TestNamedLogger_Output(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	defer setLoggerLevels(nil)
+
+	Get("http").SetLevel(WarnLevel)
+	if Get("http") != Get("http") {
+		t.Fatal("expected Get to return the same logger for a name")
+	}
+
+	Get("http.client").Info("suppressed", "url", "/a")
+	Get("http.client").Warn("retrying", "url", "/a")
+	Get("").Info("from root")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "[WARN] [logger.TestNamedLogger_Output:") ||
+		!strings.HasSuffix(lines[0], "] retrying logger=http.client url=/a") {
+		t.Errorf("unexpected named logger line %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "] from root") {
+		t.Errorf("expected the root logger to add no logger field, got %q", lines[1])
+	}
+}
+
+func TestNamedLogger_LogSkip(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	defer setLoggerLevels(nil)
+
+	adapter := func(msg string) {
+		Get("jobs").LogSkip(ContextWithFields(context.Background(), "job", 7), 1, WarnLevel, msg, "try", 2)
+	}
+	adapter("retrying")
+	Get("jobs").SetLevel(ErrorLevel)
+	adapter("suppressed")
+
+	if got := strings.TrimSpace(buf.String()); !strings.HasPrefix(got, "[WARN] [logger.TestNamedLogger_LogSkip:") ||
+		!strings.HasSuffix(got, "] retrying logger=jobs try=2 job=7") {
+		t.Fatalf("expected one entry attributed to the adapter's caller, got %q", got)
+	}
+}
+
+func TestNamedLogger_LogSkipReleasesLockOnPanic(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	oldExtractors := contextExtractors
+	defer func() { contextExtractors = oldExtractors }()
+	AddContextExtractor(func(ctx context.Context) []any {
+		if ctx.Value(traceKey{}) != nil {
+			panic("extractor failed")
+		}
+		return nil
+	})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected the extractor's panic to reach the caller")
+			}
+		}()
+		Get("jobs").LogSkip(context.WithValue(context.Background(), traceKey{}, "x"), 0, WarnLevel, "lost")
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		Get("jobs").LogSkip(context.Background(), 0, WarnLevel, "after panic")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("LogSkip deadlocked after a panic left logMutex held")
+	}
+	if !strings.Contains(buf.String(), "after panic") {
+		t.Errorf("expected the entry logged after the panic, got %q", buf.String())
+	}
+}
+
+func TestNamedLogger_SinksAndAdditivity(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	defer Close()
+
+	auditSink, loginSink, httpSink := &memorySink{}, &memorySink{}, &memorySink{}
+	Get("audit").AddSink(auditSink)
+	Get("audit").SetAdditive(false)
+	Get("audit.login").AddSink(loginSink)
+	Get("http").AddSink(httpSink)
+
+	Get("audit.login").Info("user signed in", "user", "alice")
+	Get("http.server").Info("request served")
+	InfoKV("plain entry")
+	SyncSinks()
+
+	if len(loginSink.lines) != 1 || len(auditSink.lines) != 1 || !strings.HasSuffix(auditSink.lines[0], "user signed in logger=audit.login user=alice") {
+		t.Fatalf("expected the audit entry in both audit sinks, got %q and %q", loginSink.lines, auditSink.lines)
+	}
+	if len(httpSink.lines) != 1 || !strings.HasSuffix(httpSink.lines[0], "request served logger=http.server") {
+		t.Fatalf("expected http.server to inherit the http sink, got %q", httpSink.lines)
+	}
+	console := buf.String()
+	if strings.Contains(console, "user signed in") {
+		t.Fatalf("a non-additive logger should keep entries off the console, got:\n%s", console)
+	}
+	if !strings.Contains(console, "request served") || !strings.Contains(console, "plain entry") {
+		t.Fatalf("additive loggers should still reach the console, got:\n%s", console)
+	}
+
+	Close()
+	if !auditSink.closed || !httpSink.closed {
+		t.Fatal("expected Close to close logger sinks")
+	}
+	if len(loggerOutputs) != 0 {
+		t.Fatal("expected Close to clear logger outputs")
+	}
+}
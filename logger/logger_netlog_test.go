@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestLoggedDialer(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	d := NewLoggedDialer(nil)
+	conn, err := d.Dial("tcp", "localhost:"+port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	got := buf.String()
+	if !strings.Contains(got, "[DEBUG] [logger.LoggedResolver] dns lookup host=localhost addrs=") {
+		t.Fatalf("missing lookup entry: %q", got)
+	}
+	if !strings.Contains(got, "[DEBUG] [logger.LoggedDialer] connected target=localhost:"+port+" network=tcp addr=127.0.0.1:"+port+" duration=") {
+		t.Fatalf("missing connect entry: %q", got)
+	}
+
+	ln.Close()
+	buf.Reset()
+	if _, err := d.Dial("tcp", "127.0.0.1:"+port); err == nil {
+		t.Fatal("expected a connect error")
+	}
+	got = buf.String()
+	if strings.Contains(got, "dns lookup") || !strings.Contains(got, "connect failed target=127.0.0.1:"+port) || !strings.Contains(got, "error=") {
+		t.Fatalf("got %q", got)
+	}
+}
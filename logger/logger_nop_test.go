@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNop_DiscardsEverything(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	sink := &entrySink{}
+	Get("").AddSink(sink)
+	defer Close()
+
+	l := Nop()
+	l.SetLevel(FatalLevel)
+	l.SetAdditive(false)
+	l.AddSink(&entrySink{})
+	l.Info("hidden")
+	l.Error("hidden", "k", "v")
+	SyncSinks()
+	if buf.Len() != 0 || len(sink.entries) != 0 || l.Enabled(ErrorLevel) {
+		t.Fatalf("Nop logger wrote %q and %d sink entries", buf.String(), len(sink.entries))
+	}
+	if Get("").Level() != DebugLevel {
+		t.Fatal("Nop().SetLevel should not change the root logger")
+	}
+}
+
+func TestDisableGlobal(t *testing.T) {
+	var buf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	captureLevels(&buf)
+	defer Init("development", true)
+	sink := &entrySink{}
+	AddSink(sink)
+	defer Close()
+
+	restore := DisableGlobal()
+	var initOut bytes.Buffer
+	outStdout, outStderr = &initOut, &initOut
+	InitWithConfig(Config{Mode: "production", StartupEntry: true})
+	Infof("silenced")
+	ErrorKV("silenced", "k", "v")
+	Get("lib").Warn("silenced")
+	SyncSinks()
+	if buf.Len() != 0 || initOut.Len() != 0 || len(sink.entries) != 0 {
+		t.Fatalf("disabled logger wrote %q, %q, and %d sink entries", buf.String(), initOut.String(), len(sink.entries))
+	}
+
+	restore()
+	Infof("back on")
+	SyncSinks()
+	if !bytes.Contains(buf.Bytes(), []byte("back on")) || len(sink.entries) != 1 {
+		t.Fatalf("restore should re-enable logging, got %q and %d sink entries", buf.String(), len(sink.entries))
+	}
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLine_TextLayouts(t *testing.T) {
+	tests := []struct {
+		line   string
+		level  Level
+		msg    string
+		fields []any
+		timed  bool
+	}{
+		{"[INFO] 2025/01/02 15:04:05 [main.main:15] server started port=8080", InfoLevel, "server started", []any{"port", "8080"}, true},
+		{"2025/01/02 15:04:05 [WARN] [main.run:42] disk low", WarnLevel, "disk low", nil, true},
+		{"<3>[ERROR] [main.run:7] failed to connect host=db retries=3", ErrorLevel, "failed to connect", []any{"host", "db", "retries", "3"}, false},
+		{"[DEBUG] 2025/01/02 15:04:05.123456 [pkg.F:1] detail", DebugLevel, "detail", nil, true},
+	}
+	for _, tt := range tests {
+		e, err := ParseLine(tt.line)
+		if err != nil {
+			t.Fatalf("ParseLine(%q): %v", tt.line, err)
+		}
+		if e.Level != tt.level || e.Message != tt.msg || encodeFields(e.Fields...) != encodeFields(tt.fields...) || e.Time.IsZero() == tt.timed {
+			t.Errorf("ParseLine(%q) = %+v", tt.line, e)
+		}
+	}
+	if _, err := ParseLine("not a log line"); err == nil {
+		t.Error("expected an error for an unrecognized line")
+	}
+}
+
+func TestParseLine_RoundTripsFileOutput(t *testing.T) {
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	defer Init("development", true)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	for _, cfg := range []Config{
+		{Mode: "development", Verbose: true, FilePath: path},
+		{Mode: "production", FilePath: path},
+		{Mode: "production", FilePath: path, Fallback: FallbackJSON},
+	} {
+		os.Remove(path)
+		var buf bytes.Buffer
+		outStdout, outStderr = &buf, &buf
+		InitWithConfig(cfg)
+		InfoKV("user login", "user", "alice", "attempt", 2)
+		Close()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := ParseLine(strings.TrimSpace(string(data)))
+		if err != nil {
+			t.Fatalf("%+v: %v", cfg, err)
+		}
+		if e.Level != InfoLevel || e.Message != "user login" || e.Time.IsZero() ||
+			!strings.HasPrefix(e.Caller, "logger.TestParseLine_RoundTripsFileOutput") ||
+			encodeFields(e.Fields...) != " user=alice attempt=2" {
+			t.Errorf("%+v: parsed %+v", cfg, e)
+		}
+	}
+}
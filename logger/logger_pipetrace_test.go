@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTracePipeline_RecordsSteps(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	InitWithConfig(Config{Mode: "development", Verbose: true, MaxEntriesPerSecond: 1})
+	defer Init("development", true)
+	defer func() {
+		logMutex.Lock()
+		closeSinks()
+		logMutex.Unlock()
+	}()
+	sink := &memorySink{}
+	AddSink(sink)
+
+	stop := TracePipeline(2)
+	InfoKV("first")
+	InfoKV("second")
+	stop()
+	InfoKV("untraced")
+	SyncSinks()
+
+	// the rate limiter's own warning is traced after the dropped entry
+	traces := PipelineTraces()
+	if len(traces) != 2 || traces[0].Message != "second" {
+		t.Fatalf("expected the last two traces kept, got %+v", traces)
+	}
+	if s := traces[0].Steps; len(s) != 1 || s[0].Stage != "rate limit" || s[0].Result != "dropped" {
+		t.Fatalf("expected a rate limit drop, got %+v", s)
+	}
+
+	stop = TracePipeline(10)
+	defer stop()
+	InitWithConfig(Config{Mode: "development", Verbose: true})
+	sink = &memorySink{}
+	AddSink(sink)
+	WarnKV("traced")
+	SyncSinks()
+
+	var dump bytes.Buffer
+	if err := DumpPipelineTraces(&dump); err != nil {
+		t.Fatal(err)
+	}
+	got := dump.String()
+	for _, want := range []string{
+		"WARN [",
+		"] traced\n",
+		"console WARN: written\n",
+		"queue *logger.memorySink: queued\n",
+		"sink *logger.memorySink: delivered\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in dump:\n%s", want, got)
+		}
+	}
+}
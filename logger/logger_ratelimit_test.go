@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimit_SummarizesBurst(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	maxEntriesPerSecond, rate = 3, rateWindow{}
+
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	logAt := func(at time.Time, level Level, caller, msg string) {
+		logMutex.Lock()
+		defer logMutex.Unlock()
+		writeEntry(loggerFor(level), &Entry{Time: at, Level: level, Caller: caller, Message: msg})
+	}
+	for i := range 6 {
+		logAt(base.Add(time.Duration(i)*time.Millisecond), InfoLevel, "app.loop:10", fmt.Sprint("tick ", i))
+	}
+	logAt(base.Add(10*time.Millisecond), ErrorLevel, "app.fail:20", "still written")
+	logAt(base.Add(20*time.Millisecond), DebugLevel, "app.other:30", "dropped too")
+
+	// next second: still over the limit, so no second WARN
+	next := base.Add(time.Second)
+	for i := range 5 {
+		logAt(next.Add(time.Duration(i)*time.Millisecond), InfoLevel, "app.loop:10", "again")
+	}
+	// a second under the limit ends the episode once it is over
+	logAt(base.Add(2*time.Second), InfoLevel, "app.loop:10", "calm")
+
+	out := buf.String()
+	for _, want := range []string{
+		"[INFO] [app.loop:10] tick 2\n",
+		"[WARN] [logger.ratelimit] log rate limit exceeded; summarizing entries below ERROR event=logger.rate_limited max_per_sec=3\n",
+		"[ERROR] [app.fail:20] still written\n",
+		"[INFO] [logger.ratelimit] log entries suppressed event=logger.rate_summary suppressed=4 second=2025-01-02T03:04:05Z top_caller=app.loop:10 top_caller_count=3\n",
+		"[INFO] [logger.ratelimit] log entries suppressed event=logger.rate_summary suppressed=2 second=2025-01-02T03:04:06Z top_caller=app.loop:10 top_caller_count=2\n",
+		"[INFO] [app.loop:10] calm\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "tick 3") || strings.Contains(out, "dropped too") {
+		t.Fatalf("entries over the limit were written:\n%s", out)
+	}
+	if n := strings.Count(out, "event=logger.rate_limited"); n != 1 {
+		t.Fatalf("expected a single WARN, got %d:\n%s", n, out)
+	}
+	if !strings.HasSuffix(out, "calm\n") {
+		t.Fatalf("the summary should precede the next second's first entry:\n%s", out)
+	}
+
+	// a burst after that second starts a new episode
+	for i := range 4 {
+		logAt(base.Add(3*time.Second+time.Duration(i)*time.Millisecond), InfoLevel, "app.loop:10", "burst")
+	}
+	if n := strings.Count(buf.String(), "event=logger.rate_limited"); n != 2 {
+		t.Fatalf("expected a WARN for the new episode, got %d", n)
+	}
+}
+
+func TestRateLimit_CloseFlushesSummary(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	maxEntriesPerSecond, rate = 1, rateWindow{}
+
+	now := time.Now()
+	logMutex.Lock()
+	for range 3 {
+		writeEntry(Info, &Entry{Time: now, Level: InfoLevel, Caller: "app.loop:10", Message: "x"})
+	}
+	logMutex.Unlock()
+	Close()
+	if !strings.Contains(buf.String(), "event=logger.rate_summary suppressed=2 ") {
+		t.Fatalf("Close should write the pending summary, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCaptureRegion(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	Infof("before")
+	var inner *Region
+	outer := CaptureRegion(func() {
+		Infof("step one")
+		inner = CaptureRegion(func() {
+			WarnKV("retrying", "attempt", 2)
+		})
+		Errorf("migration failed")
+	})
+	Infof("after")
+
+	if len(outer.Entries) != 3 || len(inner.Entries) != 1 {
+		t.Fatalf("captured %d and %d entries, want 3 and 1", len(outer.Entries), len(inner.Entries))
+	}
+	lines := strings.Split(strings.TrimSuffix(outer.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "[WARN] [logger.TestCaptureRegion.func1.1:") || !strings.HasSuffix(lines[1], "retrying attempt=2") {
+		t.Fatalf("region text = %q", outer.String())
+	}
+	if !strings.Contains(buf.String(), "migration failed") {
+		t.Fatal("captured entries were not written to the console")
+	}
+	if len(regions) != 0 {
+		t.Fatalf("%d regions still active", len(regions))
+	}
+}
+
+func TestCaptureRegion_EndsOnPanic(t *testing.T) {
+	captureLevels(new(bytes.Buffer))
+	defer Init("development", true)
+	func() {
+		defer func() { recover() }()
+		CaptureRegion(func() { panic("boom") })
+	}()
+	if len(regions) != 0 {
+		t.Fatalf("%d regions still active after a panic", len(regions))
+	}
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// runRelay starts fn in a goroutine and returns a function that cancels it
+// and waits for it to return.
+func runRelay(t *testing.T, fn func(ctx context.Context) error) (stop func()) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := fn(ctx); err != nil {
+			t.Errorf("relay: %v", err)
+		}
+	}()
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+func TestRelay_FollowsFileAcrossRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	positions := filepath.Join(dir, "positions.json")
+	appendTo := func(text string) {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(text)
+		f.Close()
+	}
+	appendTo("[INFO] 2026/03/02 10:14:07 [main.run:12] started port=8080\nnot a log line\n")
+
+	sink := &memorySink{}
+	relay, err := NewRelay(RelayConfig{Sink: sink, PositionFile: positions, PollInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := runRelay(t, func(ctx context.Context) error { return relay.FollowFile(ctx, path) })
+	waitFor(t, "the first entry", func() bool { return relay.Forwarded() == 1 })
+
+	appendTo(`{"time":"2026-03-02T10:14:08Z","level":"WARN","caller":"main.run:20","msg":"slow","ms":"250"}` + "\n[ERROR] [main.run:30] half")
+	waitFor(t, "the JSON entry", func() bool { return relay.Forwarded() == 2 })
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendTo("[ERROR] [main.run:31] after rotation\n")
+	waitFor(t, "the rotated file", func() bool { return relay.Forwarded() == 4 })
+	stop()
+	if err := relay.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"[INFO] [main.run:12] started port=8080",
+		"[WARN] [main.run:20] slow ms=250",
+		"[ERROR] [main.run:30] half",
+		"[ERROR] [main.run:31] after rotation",
+	}
+	if strings.Join(sink.lines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected %q, got %q", want, sink.lines)
+	}
+	if relay.Skipped() != 1 || !sink.closed {
+		t.Fatalf("expected one skipped line and a closed sink, got %d, %v", relay.Skipped(), sink.closed)
+	}
+
+	// a restarted relay resumes after the forwarded lines
+	appendTo("[INFO] [main.run:40] resumed\n")
+	sink = &memorySink{}
+	relay, err = NewRelay(RelayConfig{Sink: sink, PositionFile: positions, PollInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop = runRelay(t, func(ctx context.Context) error { return relay.FollowFile(ctx, path) })
+	waitFor(t, "the resumed entry", func() bool { return relay.Forwarded() == 1 })
+	stop()
+	if len(sink.lines) != 1 || sink.lines[0] != "[INFO] [main.run:40] resumed" {
+		t.Fatalf("expected only the new entry, got %q", sink.lines)
+	}
+}
+
+func TestRelay_ServesSocketSinks(t *testing.T) {
+	for _, tc := range []struct {
+		network string
+		enc     Encoder
+	}{
+		{"unixgram", nil},
+		{"unixgram", MsgpackEncoder{}},
+		{"unix", nil},
+		{"unix", MsgpackEncoder{}},
+	} {
+		path := filepath.Join(t.TempDir(), "relay.sock")
+		sink := &memorySink{}
+		relay, err := NewRelay(RelayConfig{Sink: sink})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stop := runRelay(t, func(ctx context.Context) error { return relay.ServeSocket(ctx, tc.network, path) })
+		var client *SocketSink
+		waitFor(t, "the socket", func() bool {
+			client, err = NewSocketSink(tc.network, path, tc.enc)
+			return err == nil
+		})
+		client.WriteEntry(&Entry{Time: time.Now(), Level: WarnLevel, Caller: "app.main:7", Message: "disk low", Fields: []any{"free", "3%"}})
+		client.WriteEntry(&Entry{Time: time.Now(), Level: InfoLevel, Caller: "app.main:9", Message: "ok"})
+		waitFor(t, "the entries", func() bool { return relay.Forwarded() == 2 })
+		client.Close()
+		stop()
+		if got := strings.Join(sink.lines, "\n"); got != "[WARN] [app.main:7] disk low free=3%\n[INFO] [app.main:9] ok" {
+			t.Errorf("%s %T: unexpected entries %q", tc.network, tc.enc, got)
+		}
+	}
+}
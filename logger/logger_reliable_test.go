@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// downSink rejects entries while down is set.
+type downSink struct {
+	memorySink
+	down bool
+}
+
+func (d *downSink) WriteEntry(e *Entry) error {
+	if d.down {
+		return errors.New("collector unreachable")
+	}
+	return d.memorySink.WriteEntry(e)
+}
+
+func TestReliableSink_RetriesAndSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	target := &downSink{down: true}
+	var acked []uint64
+	onAck := func(seq uint64, e *Entry) { acked = append(acked, seq) }
+
+	r, err := NewReliableSink(target, ReliableConfig{Dir: dir, OnAck: onAck, Sync: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"login", "grant", "revoke"} {
+		if err := r.WriteEntry(&Entry{Time: time.Now(), Level: InfoLevel, Caller: "audit.go:1", Message: msg, Fields: []any{"user", "ana"}}); err == nil {
+			t.Fatal("expected the sink error to be returned")
+		}
+	}
+	if r.Pending() != 3 || len(acked) != 0 {
+		t.Fatalf("expected 3 pending entries, got %d (acked %v)", r.Pending(), acked)
+	}
+	r.Close()
+
+	// the next run delivers the spooled entries first
+	target.down = false
+	r, err = NewReliableSink(target, ReliableConfig{Dir: dir, OnAck: onAck})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Pending() != 0 || len(target.lines) != 3 {
+		t.Fatalf("expected replayed entries to be delivered, pending %d, got %q", r.Pending(), target.lines)
+	}
+	if !strings.HasSuffix(target.lines[0], "[INFO] [audit.go:1] login user=ana") {
+		t.Fatalf("unexpected replayed entry %q", target.lines[0])
+	}
+	if err := r.WriteEntry(testEntry()); err != nil {
+		t.Fatal(err)
+	}
+	if len(acked) != 4 || acked[0] != 1 || acked[3] != 4 {
+		t.Fatalf("unexpected acknowledgments %v", acked)
+	}
+	if info, err := os.Stat(filepath.Join(dir, reliableSpoolFile)); err != nil || info.Size() != 0 {
+		t.Fatalf("expected an empty spool once everything is delivered: %v %v", info, err)
+	}
+	r.Close()
+
+	// delivered entries are not replayed
+	fresh := &memorySink{}
+	r, err = NewReliableSink(fresh, ReliableConfig{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if len(fresh.lines) != 0 || r.next != 5 {
+		t.Fatalf("expected nothing to replay and sequence 5 next, got %q and %d", fresh.lines, r.next)
+	}
+}
+
+func TestReliableSink_WorkerNeverDrops(t *testing.T) {
+	var r Sink = &ReliableSink{}
+	if _, ok := r.(losslessSink); !ok {
+		t.Fatal("expected ReliableSink to be lossless")
+	}
+	if _, ok := Sink(&memorySink{}).(losslessSink); ok {
+		t.Fatal("expected ordinary sinks to drop when full")
+	}
+}
+
+// stalledSink is a lossless sink whose writes wait for release.
+type stalledSink struct {
+	memorySink
+	release chan struct{}
+}
+
+func (s *stalledSink) lossless() {}
+
+func (s *stalledSink) WriteEntry(e *Entry) error {
+	<-s.release
+	return s.memorySink.WriteEntry(e)
+}
+
+func TestLosslessSink_FullQueueDoesNotHoldLogMutex(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	sink := &stalledSink{release: make(chan struct{})}
+	AddSink(sink)
+
+	total := DefaultSinkQueueSize + 3
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range total {
+			InfoKV("queued", "i", i)
+		}
+	}()
+
+	// wait until the logging goroutine is blocked on the full queue
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		select {
+		case <-done:
+			t.Fatal("logging should block while the lossless sink is stalled")
+		default:
+		}
+		if logMutex.TryLock() {
+			full := len(workers[sink].queue) == DefaultSinkQueueSize
+			logMutex.Unlock()
+			if full {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("lossless sink queue never filled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// other logging must proceed while that goroutine waits
+	time.Sleep(10 * time.Millisecond)
+	if !logMutex.TryLock() {
+		t.Fatal("a stalled lossless sink should not hold logMutex")
+	}
+	logMutex.Unlock()
+
+	close(sink.release)
+	<-done
+	if err := Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if len(sink.lines) != total {
+		t.Fatalf("expected %d entries, got %d", total, len(sink.lines))
+	}
+}
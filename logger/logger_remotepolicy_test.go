@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchRemotePolicy_AppliesWithETag(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer func() {
+		logMutex.Lock()
+		policy, policyNamed = nil, nil
+		logMutex.Unlock()
+		Get("http.client").ResetLevel()
+		Init("development", true)
+	}()
+
+	var mu sync.Mutex
+	body, version := `{"levels": ">=INFO", "sample": {"INFO": 0.5}, "drop": ["health check"]}`, 1
+	var notModified atomic.Int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		etag := fmt.Sprintf(`"v%d"`, version)
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	stop, err := WatchRemotePolicy(RemotePolicyConfig{URL: srv.URL, Interval: 5 * time.Millisecond, HTTP: HTTPClientConfig{Client: srv.Client()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	waitFor(t, "not-modified fetch", func() bool { return notModified.Load() > 0 })
+
+	logMutex.Lock()
+	applied := policy != nil
+	logMutex.Unlock()
+	if !applied {
+		t.Fatal("expected policy applied")
+	}
+	buf.Reset()
+	Debugf("hidden debug")
+	for i := range 4 {
+		Infof("request %d", i)
+	}
+	Warnf("health check slow")
+	Errorf("health check failed")
+	got := buf.String()
+	if strings.Contains(got, "hidden debug") || strings.Contains(got, "health check slow") {
+		t.Errorf("expected DEBUG and dropped WARN filtered, got %q", got)
+	}
+	if n := strings.Count(got, "request "); n != 2 {
+		t.Errorf("expected half of the INFO entries kept, got %d: %q", n, got)
+	}
+	if !strings.Contains(got, "health check failed") {
+		t.Errorf("expected ERROR never dropped, got %q", got)
+	}
+
+	mu.Lock()
+	body, version = `{"levels": "http.client=WARN"}`, 2
+	mu.Unlock()
+	waitFor(t, "second policy", func() bool {
+		logMutex.Lock()
+		defer logMutex.Unlock()
+		return len(policy.Drop) == 0
+	})
+	if Get("http.client").Enabled(InfoLevel) {
+		t.Error("expected named logger level from policy")
+	}
+}
+
+func TestWatchRemotePolicy_RejectsBadPolicyAndURL(t *testing.T) {
+	if _, err := WatchRemotePolicy(RemotePolicyConfig{URL: "http://config.example.com/p.json"}); err == nil {
+		t.Fatal("expected plain http URL to be rejected")
+	}
+
+	var out lockedBuffer
+	defer SetOutputs(&out, &out)()
+	Init("development", true)
+	defer Init("development", true)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"sample": {"DEBUG": 2}}`)
+	}))
+	defer srv.Close()
+	stop, err := WatchRemotePolicy(RemotePolicyConfig{URL: srv.URL, Interval: time.Hour, HTTP: HTTPClientConfig{Client: srv.Client()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "warning", func() bool { return strings.Contains(out.String(), "remote policy fetch failed") })
+	stop()
+	if policy != nil {
+		t.Fatal("invalid policy applied")
+	}
+}
+
+func TestApplyPolicy_SwitchesLevelsWhileLogging(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	base := levelFilter.Load()
+	defer func() {
+		logMutex.Lock()
+		policy, policyNamed = nil, nil
+		logMutex.Unlock()
+		setLevels(allLevels(), nil)
+	}()
+
+	const levels = ">=WARN,pkg:logger=ERROR"
+	spec, _ := parseLevelSpec(levels)
+	quiet := &activePolicy{Policy: Policy{Levels: levels}, spec: spec, seen: map[Level]uint64{}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 1000 {
+			InfoKV("tick")
+		}
+	}()
+	for range 100 {
+		applyPolicy(quiet, base)
+		applyPolicy(&activePolicy{seen: map[Level]uint64{}}, base)
+	}
+	<-done
+
+	applyPolicy(quiet, base)
+	if got := levelFilter.Load(); got.enabled[InfoLevel] || got.packages["logger"] != ErrorLevel {
+		t.Fatalf("expected levels and package levels from the policy, got %+v", got)
+	}
+	buf.Reset()
+	Warnf("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("expected WARN from package logger filtered, got %q", buf.String())
+	}
+}
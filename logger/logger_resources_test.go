@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeCgroup points the cgroup readers at a temporary v2 hierarchy holding
+// files until the test ends.
+func fakeCgroup(t *testing.T, files map[string]string) {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "app.slice")
+	os.MkdirAll(dir, 0755)
+	for name, data := range files {
+		os.WriteFile(filepath.Join(dir, name), []byte(data), 0644)
+	}
+	self := filepath.Join(root, "self-cgroup")
+	os.WriteFile(self, []byte("0::/app.slice\n"), 0644)
+	oldRoot, oldSelf := cgroupRoot, cgroupPath
+	t.Cleanup(func() { cgroupRoot, cgroupPath = oldRoot, oldSelf })
+	cgroupRoot, cgroupPath = root, self
+}
+
+func TestResourceAdvisory(t *testing.T) {
+	var buf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout, outStderr = &buf, &buf
+	t.Setenv("JOURNAL_STREAM", "")
+	defer Init("development", true)
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	fakeCgroup(t, map[string]string{
+		"cpu.max":        "150000 100000\n",
+		"memory.max":     "1000\n",
+		"memory.current": "950\n",
+	})
+	InitWithConfig(Config{Mode: "production", ResourceAdvisory: true})
+	out := buf.String()
+	if !strings.Contains(out, "[WARN] [logger.InitWithConfig] GOMAXPROCS exceeds the CPU quota; the process will be throttled event=logger.resources gomaxprocs=4 cpu_quota=1.5\n") {
+		t.Fatalf("missing CPU advisory: %q", out)
+	}
+	if !strings.Contains(out, "memory usage is near the cgroup limit event=logger.resources memory_usage=950 memory_limit=1000") {
+		t.Fatalf("missing memory advisory: %q", out)
+	}
+
+	buf.Reset()
+	fakeCgroup(t, map[string]string{
+		"cpu.max":        "max 100000\n",
+		"memory.max":     "max\n",
+		"memory.current": "950\n",
+	})
+	InitWithConfig(Config{Mode: "production", ResourceAdvisory: true})
+	if buf.Len() != 0 {
+		t.Fatalf("unlimited cgroup should give no advisory, got %q", buf.String())
+	}
+
+	fakeCgroup(t, map[string]string{"cpu.max": "100000 100000\n"})
+	InitWithConfig(Config{Mode: "production"})
+	if buf.Len() != 0 {
+		t.Fatalf("advisory written without ResourceAdvisory: %q", buf.String())
+	}
+}
+
+func TestCgroupV1Limits(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "cpu"), 0755)
+	os.MkdirAll(filepath.Join(root, "memory"), 0755)
+	os.WriteFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"), []byte("200000\n"), 0644)
+	os.WriteFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"), []byte("100000\n"), 0644)
+	os.WriteFile(filepath.Join(root, "memory", "memory.limit_in_bytes"), []byte("9223372036854771712\n"), 0644)
+	os.WriteFile(filepath.Join(root, "memory", "memory.usage_in_bytes"), []byte("4096\n"), 0644)
+	oldRoot, oldSelf := cgroupRoot, cgroupPath
+	defer func() { cgroupRoot, cgroupPath = oldRoot, oldSelf }()
+	cgroupRoot, cgroupPath = root, filepath.Join(root, "missing")
+
+	if cpus, ok := cgroupCPUQuota(); !ok || cpus != 2 {
+		t.Fatalf("cpu quota = %v, %v; want 2", cpus, ok)
+	}
+	if _, _, ok := cgroupMemory(); ok {
+		t.Fatal("v1 unlimited memory should report no limit")
+	}
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryNotify_EscalatesLevels(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	notify := RetryNotify("fetch-config")
+	err := errors.New("connection refused")
+	notify(err, 100*time.Millisecond)
+	notify(err, 200*time.Millisecond)
+	notify(err, 400*time.Millisecond)
+	notify(err, -1)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 entries, got:\n%s", buf.String())
+	}
+	for i, want := range []string{
+		"[DEBUG] [logger.TestRetryNotify_EscalatesLevels:",
+		"[DEBUG] ",
+		"[WARN] ",
+		"[ERROR] ",
+	} {
+		if !strings.HasPrefix(lines[i], want) {
+			t.Errorf("line %d: expected prefix %q, got %q", i, want, lines[i])
+		}
+	}
+	if !strings.HasSuffix(lines[2], "fetch-config failed, retrying op=fetch-config attempt=3 delay=400ms error=connection refused") {
+		t.Errorf("unexpected retry entry %q", lines[2])
+	}
+	if !strings.HasSuffix(lines[3], "fetch-config failed after 4 attempts op=fetch-config attempts=4 error=connection refused") {
+		t.Errorf("unexpected final entry %q", lines[3])
+	}
+}
+
+func TestRetryNotifyConfig_MaxAttempts(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	notify := RetryNotifyConfig("publish", RetryConfig{WarnAfter: 1, MaxAttempts: 2})
+	notify(errors.New("timeout"), time.Second)
+	notify(errors.New("timeout"), time.Second)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "[WARN] ") || !strings.HasPrefix(lines[1], "[ERROR] ") ||
+		!strings.Contains(lines[1], "publish failed after 2 attempts") {
+		t.Fatalf("unexpected entries:\n%s", buf.String())
+	}
+}
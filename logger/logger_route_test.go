@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRoute_FieldLevelAndPackageMatching(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	audit, alerts, pkg := &memorySink{}, &memorySink{}, &memorySink{}
+	AddRoute(Route{Fields: map[string]string{"category": "audit"}, Sinks: []Sink{audit}, Exclusive: true})
+	AddRoute(Route{MinLevel: ErrorLevel, Sinks: []Sink{alerts}})
+	AddRoute(Route{Package: "logger", MinLevel: WarnLevel, Sinks: []Sink{pkg}})
+	defer Close()
+
+	InfoKV("user deleted", "category", "audit", "user", 7)
+	Infof("routine")
+	Warnf("slow query")
+	ErrorKV("query failed", "attempt", 3)
+	SyncSinks()
+
+	if len(audit.lines) != 1 || !strings.Contains(audit.lines[0], "user deleted") {
+		t.Errorf("audit route got %q", audit.lines)
+	}
+	if strings.Contains(buf.String(), "user deleted") {
+		t.Errorf("exclusive route should keep audit entries off the console: %q", buf.String())
+	}
+	if len(alerts.lines) != 1 || !strings.Contains(alerts.lines[0], "query failed") {
+		t.Errorf("level route got %q", alerts.lines)
+	}
+	if len(pkg.lines) != 2 {
+		t.Errorf("package route got %q", pkg.lines)
+	}
+	if !strings.Contains(buf.String(), "routine") || !strings.Contains(buf.String(), "query failed") {
+		t.Errorf("non-exclusive entries should still reach the console: %q", buf.String())
+	}
+}
+
+func TestRoute_LoggerHierarchyAndFinal(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	http, rest := &memorySink{}, &memorySink{}
+	AddRoute(Route{Logger: "http", Sinks: []Sink{http}, Final: true})
+	AddRoute(Route{Sinks: []Sink{rest}})
+
+	InfoKV("dial", LoggerKey, "http.client")
+	InfoKV("other", LoggerKey, "httpx")
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(http.lines) != 1 || !strings.Contains(http.lines[0], "dial") {
+		t.Errorf("http route got %q", http.lines)
+	}
+	if len(rest.lines) != 1 || !strings.Contains(rest.lines[0], "other") {
+		t.Errorf("Final should stop later routes, catch-all got %q", rest.lines)
+	}
+	if !http.closed || !rest.closed || len(routes) != 0 {
+		t.Error("Close should close route sinks and clear the table")
+	}
+}
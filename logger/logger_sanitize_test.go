@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeString(t *testing.T) {
+	for in, want := range map[string]string{
+		"plain\ttext":                 "plain\ttext",
+		"héllo":                       "héllo",
+		"user\n[ERROR] forged":        `user\n[ERROR] forged`,
+		"a\r\nb":                      `a\r\nb`,
+		"bell\a":                      `bell\x07`,
+		"\033[31mred\033[0m":          "red",
+		"\033]0;pwned\atitle":         "title",
+		"\033]0;pwned\033\\title":     "title",
+		"bad\xffbyte":                 "bad�byte",
+		"c1\u0085next":                `c1\x85next`,
+		"\033":                        "",
+		"\033[2J\033[Hcleared screen": "cleared screen",
+	} {
+		if got := sanitizeString(in); got != want {
+			t.Errorf("sanitizeString(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSanitizeBasic(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	InitWithConfig(Config{Mode: "development", Verbose: true, Sanitize: SanitizeBasic})
+	defer Init("development", true)
+
+	keyvals := []any{"user", "alice\n2026/01/01 00:00:00 [INFO] admin logged in", "attempts", 3}
+	WarnKV("login failed\033[2K", keyvals...)
+	got := buf.String()
+	if strings.Count(got, "\n") != 1 || strings.Contains(got, "\033[2K") {
+		t.Fatalf("expected a single sanitized line, got %q", got)
+	}
+	if !strings.Contains(got, `user=alice\n2026/01/01 00:00:00 [INFO] admin logged in attempts=3`) {
+		t.Fatalf("expected the newline escaped, got %q", got)
+	}
+	if keyvals[1] != "alice\n2026/01/01 00:00:00 [INFO] admin logged in" {
+		t.Fatalf("caller's keyvals were modified: %q", keyvals[1])
+	}
+}
+
+func TestSanitizeStrict(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	InitWithConfig(Config{Mode: "development", Verbose: true, Sanitize: SanitizeStrict})
+	defer Init("development", true)
+
+	InfoKV("request", "path", "/index.html", "status", 200)
+	if got := buf.String(); strings.Contains(got, InjectionKey) {
+		t.Fatalf("expected a clean entry unmarked, got %q", got)
+	}
+
+	buf.Reset()
+	err := errors.New("bad header\r\n127.0.0.1 - - \"GET /admin\" 200")
+	ErrorKV("request", "path", "/login", "error", err)
+	got := buf.String()
+	if strings.Count(got, "\n") != 1 || !strings.Contains(got, `error=bad header\r\n127.0.0.1`) {
+		t.Fatalf("expected the error value escaped, got %q", got)
+	}
+	if !strings.Contains(got, InjectionKey+"=true") {
+		t.Fatalf("expected the entry marked, got %q", got)
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failingWriteSink rejects every entry, like a collector answering 503.
+type failingWriteSink struct{}
+
+func (failingWriteSink) WriteEntry(*Entry) error { return errors.New("503 Service Unavailable") }
+func (failingWriteSink) Close() error            { return nil }
+
+func TestSelfTest(t *testing.T) {
+	t.Setenv("JOURNAL_STREAM", "")
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	dir := t.TempDir()
+	InitWithConfig(Config{Mode: "production", FilePath: filepath.Join(dir, "app.log")})
+	defer Init("development", true)
+	defer Close()
+
+	jsonSink, err := NewFileSink(filepath.Join(dir, "entries.jsonl"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	AddSink(jsonSink)
+	AddSink(&memorySink{})
+	AddSink(failingWriteSink{})
+
+	results, err := SelfTest()
+	if err == nil || !strings.Contains(err.Error(), "logger.failingWriteSink: 503 Service Unavailable") {
+		t.Fatalf("expected the failing sink reported, got %v", err)
+	}
+	var report []string
+	for _, r := range results {
+		report = append(report, r.String())
+	}
+	want := []string{
+		"written console",
+		"verified file " + filepath.Join(dir, "app.log"),
+		"verified *logger.FileSink " + filepath.Join(dir, "entries.jsonl"),
+		"written *logger.memorySink",
+		"FAILED logger.failingWriteSink: 503 Service Unavailable",
+	}
+	if strings.Join(report, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("report:\n%s\nwant:\n%s", strings.Join(report, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.Contains(buf.String(), "logger self test event=logger.selftest token=") {
+		t.Fatalf("missing console entry: %q", buf.String())
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logger
+
+import "testing"
+
+// writeSignalFD reports false: this platform has no signal message pipe.
+func writeSignalFD(t *testing.T, id byte) bool {
+	return false
+}
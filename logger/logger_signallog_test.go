@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSignalMessage_LoggedFromPipe(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	t.Setenv("NO_COLOR", "1")
+	var out lockedBuffer
+	defer SetOutputs(&out, &out)()
+	Init("development", true)
+	defer Init("development", true)
+
+	defer StopSignalMessages()
+
+	hup, err := RegisterSignalMessage(InfoLevel, "SIGHUP received, reloading")
+	if err != nil {
+		t.Fatal(err)
+	}
+	term, err := RegisterSignalMessage(WarnLevel, "SIGTERM received")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allocs := testing.AllocsPerRun(10, hup.Log); allocs != 0 {
+		t.Fatalf("Log allocated %v times", allocs)
+	}
+	term.Log()
+	waitFor(t, "signal entries", func() bool { return strings.Count(out.String(), "SIGHUP received") == 11 })
+	if got := out.String(); !strings.Contains(got, "[WARN] ") || !strings.Contains(got, "[logger.signal] SIGTERM received\n") {
+		t.Fatalf("unexpected entries %q", got)
+	}
+
+	// C signal handlers write the ID to SignalFD themselves
+	if writeSignalFD(t, byte(term)) {
+		waitFor(t, "raw write", func() bool { return strings.Count(out.String(), "SIGTERM received") == 2 })
+	}
+}
+
+func TestStopSignalMessages_DrainsAndUnregisters(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	t.Setenv("NO_COLOR", "1")
+	var out lockedBuffer
+	defer SetOutputs(&out, &out)()
+	Init("development", true)
+	defer Init("development", true)
+
+	usr1, err := RegisterSignalMessage(InfoLevel, "SIGUSR1 received")
+	if err != nil {
+		t.Fatal(err)
+	}
+	usr1.Log()
+	StopSignalMessages()
+	if got := out.String(); !strings.Contains(got, "SIGUSR1 received") {
+		t.Fatalf("expected the pending message logged before returning, got %q", got)
+	}
+	if SignalFD() != -1 {
+		t.Fatalf("expected the pipe closed, got fd %d", SignalFD())
+	}
+	usr1.Log()
+	StopSignalMessages()
+
+	usr2, err := RegisterSignalMessage(InfoLevel, "SIGUSR2 received")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer StopSignalMessages()
+	if usr2 != 0 {
+		t.Fatalf("expected registration to start over, got ID %d", usr2)
+	}
+	usr2.Log()
+	waitFor(t, "message after re-registering", func() bool { return strings.Contains(out.String(), "SIGUSR2 received") })
+}
+
+func TestRegisterSignalMessage_Limit(t *testing.T) {
+	signalMu.Lock()
+	saved := signalCount
+	signalCount = len(signalTable)
+	signalMu.Unlock()
+	defer func() {
+		signalMu.Lock()
+		signalCount = saved
+		signalMu.Unlock()
+	}()
+	if _, err := RegisterSignalMessage(InfoLevel, "one too many"); err == nil {
+		t.Fatal("expected an error past 256 messages")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"syscall"
+	"testing"
+)
+
+// writeSignalFD writes id to SignalFD as a C signal handler would.
+func writeSignalFD(t *testing.T, id byte) bool {
+	t.Helper()
+	if _, err := syscall.Write(SignalFD(), []byte{id}); err != nil {
+		t.Fatal(err)
+	}
+	return true
+}
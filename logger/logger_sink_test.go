@@ -0,0 +1,681 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySink records encoded entries.
+type memorySink struct {
+	lines  []string
+	closed bool
+}
+
+func (m *memorySink) WriteEntry(e *Entry) error {
+	b, _ := TextEncoder{}.Encode(e)
+	m.lines = append(m.lines, string(b))
+	return nil
+}
+
+func (m *memorySink) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestAddSink_ReceivesEntries(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	sink := &memorySink{}
+	AddSink(sink)
+
+	InfoKV("sink check", "k", "v")
+	SyncSinks()
+	if len(sink.lines) != 1 || !strings.HasSuffix(sink.lines[0], "sink check k=v") ||
+		!strings.HasPrefix(sink.lines[0], "[INFO] [") {
+		t.Fatalf("unexpected sink lines: %q", sink.lines)
+	}
+	if !strings.Contains(buf.String(), "sink check") {
+		t.Fatalf("console output missing: %q", buf.String())
+	}
+
+	if err := Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !sink.closed {
+		t.Fatal("Close should close registered sinks")
+	}
+}
+
+// readOctetFrame reads one "LEN SP MSG" frame.
+func readOctetFrame(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	size, err := r.ReadString(' ')
+	if err != nil {
+		t.Fatalf("read frame length: %v", err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(size))
+	if err != nil {
+		t.Fatalf("bad frame length %q", size)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+	return string(msg)
+}
+
+func testEntry() *Entry {
+	return &Entry{Time: time.Now(), Level: ErrorLevel, Caller: "main.go:1", Message: "disk full", Fields: []any{"path", "/var"}}
+}
+
+func TestSyslogSink_TCPOctetCounting(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	got := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		got <- readOctetFrame(t, bufio.NewReader(conn))
+	}()
+
+	s, err := NewSyslogSink(SyslogConfig{Network: "tcp", Address: ln.Addr().String(), Tag: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.WriteEntry(testEntry()); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := <-got
+	// user facility (1) * 8 + err (3)
+	if !strings.HasPrefix(msg, "<11>1 ") || !strings.Contains(msg, " app ") ||
+		!strings.HasSuffix(msg, "[main.go:1] disk full path=/var") {
+		t.Fatalf("unexpected message: %q", msg)
+	}
+}
+
+func selfSignedTLS(t *testing.T) (*tls.Config, *tls.Config) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	server := &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	return server, &tls.Config{RootCAs: pool}
+}
+
+func TestSyslogSink_TLS(t *testing.T) {
+	serverConf, clientConf := selfSignedTLS(t)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	got := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		got <- readOctetFrame(t, bufio.NewReader(conn))
+	}()
+
+	if _, err := NewSyslogSink(SyslogConfig{Network: "tls", Address: ln.Addr().String()}); err == nil {
+		t.Fatal("tls without TLSConfig should fail")
+	}
+	s, err := NewSyslogSink(SyslogConfig{Network: "tls", Address: ln.Addr().String(), TLSConfig: clientConf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.WriteEntry(testEntry()); err != nil {
+		t.Fatal(err)
+	}
+	if msg := <-got; !strings.HasSuffix(msg, "disk full path=/var") {
+		t.Fatalf("unexpected message: %q", msg)
+	}
+}
+
+func TestSyslogSink_RELP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	commands := make(chan string, 8)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			txnr, cmd, data, err := readRELPFrame(r)
+			if err != nil {
+				close(commands)
+				return
+			}
+			commands <- cmd + " " + data
+			io.WriteString(conn, formatRELPFrame(txnr, "rsp", "200 OK"))
+		}
+	}()
+
+	s, err := NewSyslogSink(SyslogConfig{Network: "relp", Address: ln.Addr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteEntry(testEntry()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for c := range commands {
+		got = append(got, c)
+	}
+	if len(got) != 3 || !strings.HasPrefix(got[0], "open relp_version=0") ||
+		!strings.HasSuffix(got[1], "disk full path=/var") || got[2] != "close " {
+		t.Fatalf("unexpected RELP session: %q", got)
+	}
+}
+
+func TestSyslogSink_LocalSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skipf("unixgram unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	s, err := NewSyslogSink(SyslogConfig{Address: path, Tag: "app", Facility: FacilityDaemon})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.WriteEntry(testEntry()); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// daemon facility (3) * 8 + err (3)
+	if msg := string(buf[:n]); !strings.HasPrefix(msg, "<27>") || !strings.Contains(msg, " app[") {
+		t.Fatalf("unexpected message: %q", msg)
+	}
+}
+
+func TestSyslogSink_LocalStreamSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer ln.Close()
+	lines := make(chan string, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			lines <- line
+		}
+	}()
+
+	s, err := NewSyslogSink(SyslogConfig{Address: path, Tag: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	for range 2 {
+		if err := s.WriteEntry(testEntry()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for range 2 {
+		select {
+		case line := <-lines:
+			if !strings.HasPrefix(line, "<11>") || !strings.HasSuffix(line, "disk full path=/var\n") {
+				t.Fatalf("expected one LF-terminated message per entry, got %q", line)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected two messages on the stream socket")
+		}
+	}
+}
+
+func TestSocketSink_Datagram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skipf("unixgram unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	s, err := NewSocketSink("unixgram", path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.WriteEntry(testEntry()); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := string(buf[:n]); !strings.HasPrefix(msg, "{") || !strings.Contains(msg, `"msg":"disk full"`) {
+		t.Fatalf("unexpected datagram: %q", msg)
+	}
+}
+
+func TestSocketSink_StreamReconnects(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer ln.Close()
+	lines := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			lines <- line
+			conn.Close()
+		}
+	}()
+
+	s, err := NewSocketSink("unix", path, TextEncoder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.WriteEntry(testEntry()); err != nil {
+		t.Fatal(err)
+	}
+	if line := <-lines; line != "[ERROR] [main.go:1] disk full path=/var\n" {
+		t.Fatalf("unexpected line: %q", line)
+	}
+
+	// The server closed the first connection; keep writing until the sink
+	// notices and reconnects.
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		s.WriteEntry(testEntry())
+		select {
+		case <-lines:
+			return
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	t.Fatal("sink did not reconnect")
+}
+
+// zmtpSubscribe connects to p as a ZMTP 3.0 SUB socket, with the greeting
+// and frames spelled out byte by byte, and subscribes to topic.
+func zmtpSubscribe(t *testing.T, p *PubSink, topic string) *bufio.Reader {
+	t.Helper()
+	conn, err := net.Dial("tcp", p.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+	greeting := append([]byte{0xff, 0, 0, 0, 0, 0, 0, 0, 0, 0x7f, 3, 0, 'N', 'U', 'L', 'L'}, make([]byte, 48)...)
+	ready := "\x04\x19\x05READY\x0bSocket-Type\x00\x00\x00\x03SUB"
+	sub := "\x00" + string([]byte{byte(len(topic) + 1)}) + "\x01" + topic
+	if _, err := conn.Write(append(greeting, ready+sub...)); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(conn)
+	got := make([]byte, 64+27)
+	if _, err := io.ReadFull(r, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got[:64], greeting) {
+		t.Fatalf("unexpected greeting % x", got[:64])
+	}
+	if want := "\x04\x19\x05READY\x0bSocket-Type\x00\x00\x00\x03PUB"; string(got[64:]) != want {
+		t.Fatalf("unexpected READY %q", got[64:])
+	}
+	return r
+}
+
+// readZMTPMessage reads a two-frame message written by PubSink.
+func readZMTPMessage(t *testing.T, r *bufio.Reader) (topic, body string) {
+	t.Helper()
+	var frames []string
+	for more := true; more; {
+		flags, err := r.ReadByte()
+		if err != nil {
+			t.Fatal(err)
+		}
+		size, err := r.ReadByte()
+		if err != nil || flags&^0x01 != 0 {
+			t.Fatalf("unexpected frame flags %#x, %v", flags, err)
+		}
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			t.Fatal(err)
+		}
+		frames = append(frames, string(frame))
+		more = flags&0x01 != 0
+	}
+	if len(frames) != 2 {
+		t.Fatalf("expected a topic and a body frame, got %q", frames)
+	}
+	return frames[0], frames[1]
+}
+
+func TestPubSink_PublishesToZMTPSubscribers(t *testing.T) {
+	p, err := NewPubSink("tcp", "127.0.0.1:0", TextEncoder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	all := zmtpSubscribe(t, p, "")
+	warn := zmtpSubscribe(t, p, "WARN")
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+		p.mu.Lock()
+		n := 0
+		for sub := range p.subs {
+			n += len(sub.topics)
+		}
+		p.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("subscriptions were not received")
+		}
+	}
+
+	if err := p.WriteEntry(testEntry()); err != nil {
+		t.Fatal(err)
+	}
+	e := testEntry()
+	e.Level, e.Message = WarnLevel, "disk low"
+	if err := p.WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+
+	if topic, body := readZMTPMessage(t, all); topic != "ERROR" || body != "[ERROR] [main.go:1] disk full path=/var" {
+		t.Fatalf("unexpected message %q %q", topic, body)
+	}
+	if topic, _ := readZMTPMessage(t, all); topic != "WARN" {
+		t.Fatalf("expected the WARN entry next, got topic %q", topic)
+	}
+	if topic, body := readZMTPMessage(t, warn); topic != "WARN" || !strings.Contains(body, "disk low") {
+		t.Fatalf("expected only the WARN entry for a WARN subscription, got %q %q", topic, body)
+	}
+}
+
+func TestPubSink_RejectsNonSubscriberSockets(t *testing.T) {
+	p, err := NewPubSink("tcp", "127.0.0.1:0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	conn, err := net.Dial("tcp", p.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+	greeting := append([]byte{0xff, 0, 0, 0, 0, 0, 0, 0, 0, 0x7f, 3, 0, 'N', 'U', 'L', 'L'}, make([]byte, 48)...)
+	conn.Write(append(greeting, "\x04\x1a\x05READY\x0bSocket-Type\x00\x00\x00\x04PULL"...))
+	data, _ := io.ReadAll(conn)
+	if !strings.Contains(string(data), "\x05ERROR") || p.Subscribers() != 0 {
+		t.Fatalf("expected an ERROR command and no subscriber, got %q", data)
+	}
+}
+
+func TestFallbackAuto_PrefersLocalSyslogWithoutSystemd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skipf("unixgram unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	oldPaths, oldBooted := localSyslogPaths, systemdBooted
+	defer func() {
+		outStdout, outStderr = oldStdout, oldStderr
+		localSyslogPaths, systemdBooted = oldPaths, oldBooted
+	}()
+	defer Init("development", true)
+	outStdout, outStderr = &stdoutBuf, &stderrBuf
+	localSyslogPaths = []string{path}
+
+	systemdBooted = func() bool { return true }
+	InitWithConfig(Config{Mode: "production", Fallback: FallbackAuto})
+	Infof("to stdout")
+	if !strings.Contains(stdoutBuf.String(), "to stdout") || autoSyslog != nil {
+		t.Fatalf("systemd hosts should keep stdout, got %q", stdoutBuf.String())
+	}
+
+	systemdBooted = func() bool { return false }
+	InitWithConfig(Config{Mode: "production", Fallback: FallbackAuto})
+	Warnf("to syslog")
+	if strings.Contains(stdoutBuf.String()+stderrBuf.String(), "to syslog") {
+		t.Fatal("console output should be replaced by syslog")
+	}
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := string(buf[:n]); !strings.HasPrefix(msg, "<12>") || !strings.HasSuffix(msg, "to syslog") {
+		t.Fatalf("unexpected syslog message: %q", msg)
+	}
+
+	Init("development", true)
+	if autoSyslog != nil || len(sinks) != 0 {
+		t.Fatal("re-initializing should remove the automatic syslog sink")
+	}
+}
+
+// slowSink blocks every write until release is closed.
+type slowSink struct {
+	release chan struct{}
+	n       int
+}
+
+func (s *slowSink) WriteEntry(e *Entry) error {
+	<-s.release
+	s.n++
+	return nil
+}
+
+func (s *slowSink) Close() error { return nil }
+
+func TestSinks_SlowSinkDoesNotBlockOthers(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	slow := &slowSink{release: make(chan struct{})}
+	fast := &memorySink{}
+	AddSink(slow)
+	AddSink(fast)
+	defer Close()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			Infof("entry %d", i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("logging blocked on a slow sink")
+	}
+
+	close(slow.release)
+	SyncSinks()
+	if slow.n != 10 || len(fast.lines) != 10 {
+		t.Fatalf("slow got %d, fast got %d entries; want 10 each", slow.n, len(fast.lines))
+	}
+	for i, line := range fast.lines {
+		if !strings.HasSuffix(line, fmt.Sprintf("entry %d", i)) {
+			t.Fatalf("entries out of order at %d: %q", i, line)
+		}
+	}
+}
+
+// failingCloseSink returns err from Close.
+type failingCloseSink struct {
+	memorySink
+	err error
+}
+
+func (f *failingCloseSink) Close() error {
+	f.closed = true
+	return f.err
+}
+
+func TestClose_JoinsSinkErrorsAndIsIdempotent(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	errA, errB := errors.New("flush failed"), errors.New("connection reset")
+	a, b, ok := &failingCloseSink{err: errA}, &failingCloseSink{err: errB}, &memorySink{}
+	AddSink(a)
+	AddSink(ok)
+	Get("audit").AddSink(b)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Go(func() { errs[i] = Close() })
+	}
+	wg.Wait()
+
+	var got error
+	for _, err := range errs {
+		if err != nil {
+			if got != nil {
+				t.Fatalf("expected one Close to report the errors, got %v and %v", got, err)
+			}
+			got = err
+		}
+	}
+	if !errors.Is(got, errA) || !errors.Is(got, errB) || !strings.Contains(got.Error(), "closing *logger.failingCloseSink") {
+		t.Fatalf("expected both sink errors, got %v", got)
+	}
+	if !a.closed || !b.closed || !ok.closed {
+		t.Fatal("expected every sink closed")
+	}
+}
+
+// gatedSink blocks in WriteEntry until gate is closed, recording messages.
+type gatedSink struct {
+	memorySink
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func (g *gatedSink) WriteEntry(e *Entry) error {
+	if len(g.lines) == 0 {
+		close(g.started)
+		<-g.gate
+	}
+	g.lines = append(g.lines, e.Message)
+	return nil
+}
+
+func TestSinkWorker_UrgentEntriesBypassBacklog(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Close()
+	sink := &gatedSink{started: make(chan struct{}), gate: make(chan struct{})}
+	AddSink(sink)
+
+	Infof("first")
+	<-sink.started
+	for i := range SinkBacklog {
+		Debugf("backlog %d", i)
+	}
+	Warnf("disk slow")
+	Errorf("disk failed")
+	Infof("after")
+	close(sink.gate)
+	SyncSinks()
+
+	if got := strings.Join(sink.lines[:4], ","); got != "first,disk slow,disk failed,backlog 0" {
+		t.Fatalf("expected WARN and ERROR ahead of the backlog, got %s", got)
+	}
+	if got := sink.lines[len(sink.lines)-1]; got != "after" || len(sink.lines) != SinkBacklog+4 {
+		t.Fatalf("expected %d entries ending with INFO, got %d ending with %q", SinkBacklog+4, len(sink.lines), got)
+	}
+}
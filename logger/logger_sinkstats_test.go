@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+type slowFailingSink struct{ n int }
+
+func (s *slowFailingSink) WriteEntry(*Entry) error {
+	s.n++
+	time.Sleep(time.Millisecond)
+	if s.n%2 == 0 {
+		return errors.New("unavailable")
+	}
+	return nil
+}
+
+func (s *slowFailingSink) Close() error { return nil }
+
+func TestSinkStats_CountsAndLatency(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer SetOutputs(&buf, &buf)()
+	logMutex.Lock()
+	closeSinks()
+	logMutex.Unlock()
+	fast, slow := &memorySink{}, &slowFailingSink{}
+	AddSink(fast)
+	AddSink(slow)
+	defer func() {
+		logMutex.Lock()
+		closeSinks()
+		logMutex.Unlock()
+	}()
+
+	for range 4 {
+		InfoKV("stats")
+	}
+	SyncSinks()
+
+	stats := SinkStats()
+	if len(stats) != 2 || stats[0].Sink != fast || stats[1].Sink != slow {
+		t.Fatalf("expected both sinks ordered by name, got %+v", stats)
+	}
+	if s := stats[0]; s.Name != "*logger.memorySink" || s.Enqueued != 4 || s.Errors != 0 || s.Depth != 0 {
+		t.Fatalf("unexpected memory sink stats %+v", s)
+	}
+	s := stats[1]
+	if s.Enqueued != 4 || s.Errors != 2 || s.Dropped != 0 {
+		t.Fatalf("unexpected slow sink stats %+v", s)
+	}
+	if s.P50 < time.Millisecond || s.P50 > s.P90 || s.P90 > s.P99 || s.P99 > s.Max {
+		t.Fatalf("unexpected latency percentiles %+v", s)
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	l := slog.New(NewSlogHandler()).With("svc", "api").WithGroup("req")
+	l.Warn("slow request", "method", "GET", slog.Group("timing", "ms", 812), slog.Attr{})
+	ctx := ContextWithFields(context.Background(), "request_id", "r-1")
+	slog.New(NewSlogHandler()).ErrorContext(ctx, "failed", "code", 500)
+	slog.New(NewSlogHandler()).Log(context.Background(), slog.LevelDebug-2, "trace")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	for i, want := range []struct{ prefix, suffix string }{
+		{"[WARN] [logger.TestSlogHandler:", "] slow request svc=api req.method=GET req.timing.ms=812"},
+		{"[ERROR] [logger.TestSlogHandler:", "] failed code=500 request_id=r-1"},
+		{"[DEBUG] [logger.TestSlogHandler:", "] trace"},
+	} {
+		if !strings.HasPrefix(lines[i], want.prefix) || !strings.HasSuffix(lines[i], want.suffix) {
+			t.Errorf("line %d = %q, want %s...%s", i, lines[i], want.prefix, want.suffix)
+		}
+	}
+}
+
+func TestHijackSlog(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	prev := slog.Default()
+	prevOut := log.Writer()
+
+	restore := HijackSlog()
+	slog.Info("from a dependency", "n", 1)
+	restore()
+
+	if !strings.Contains(buf.String(), "] from a dependency n=1") {
+		t.Fatalf("slog output not routed: %q", buf.String())
+	}
+	if slog.Default() != prev || log.Writer() != prevOut {
+		t.Fatal("slog and log defaults not restored")
+	}
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSourceContext_AddsCallSiteToErrors(t *testing.T) {
+	InitWithConfig(Config{Mode: "development", SourceContext: true})
+	sink := &entrySink{}
+	captureLevels(new(bytes.Buffer))
+	AddSink(sink)
+	defer Init("development", true)
+
+	Warnf("not for warnings")
+	failed := true
+	if failed {
+		Errorf("upload failed") // marked line
+	}
+	SyncSinks()
+
+	if len(sink.entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(sink.entries))
+	}
+	if _, ok := fieldValue(sink.entries[0].Fields, SourceContextKey); ok {
+		t.Fatal("WARN entry has source context")
+	}
+	src, _ := fieldValue(sink.entries[1].Fields, SourceContextKey)
+	lines := strings.Split(src, "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "| \tif failed {") ||
+		!strings.HasPrefix(lines[1], "> ") || !strings.HasSuffix(lines[1], `| 		Errorf("upload failed") // marked line`) ||
+		!strings.HasSuffix(lines[2], "| \t}") {
+		t.Fatalf("source context = %q", src)
+	}
+}
+
+func TestSourceContext_OffInProduction(t *testing.T) {
+	InitWithConfig(Config{Mode: "production", SourceContext: true})
+	defer Init("development", true)
+	if sourceContext {
+		t.Fatal("source context enabled in production")
+	}
+	if got := sourceLines("logger.unknownCaller:12"); got != "" {
+		t.Fatalf("unknown caller has source %q", got)
+	}
+}
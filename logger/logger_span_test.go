@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSpan_BeginAndEnd(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	sp := Span("sync-users")
+	sp.End("users", 42)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected begin and end entries, got:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "[DEBUG] [logger.TestSpan_BeginAndEnd:") ||
+		!strings.HasSuffix(lines[0], "] sync-users started span=sync-users span_id="+sp.ID()) {
+		t.Errorf("unexpected begin entry %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "[INFO] [logger.TestSpan_BeginAndEnd:") ||
+		!strings.Contains(lines[1], "] sync-users finished span=sync-users span_id="+sp.ID()+" duration=") ||
+		!strings.HasSuffix(lines[1], " users=42") {
+		t.Errorf("unexpected end entry %q", lines[1])
+	}
+
+	buf.Reset()
+	Span("import").EndErr(errors.New("disk full"))
+	if !strings.Contains(buf.String(), "[ERROR] ") || !strings.Contains(buf.String(), "import failed span=import") ||
+		!strings.Contains(buf.String(), "error=disk full") {
+		t.Errorf("expected EndErr to log a failure, got:\n%s", buf.String())
+	}
+}
+
+func TestSpanContext_Nesting(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	ctx, outer := SpanContext(context.Background(), "request")
+	ctx, inner := SpanContext(ctx, "query")
+	InfoContext(ctx, "rows read")
+	inner.End()
+	outer.End()
+
+	out := buf.String()
+	if !strings.Contains(out, "query started span=query span_id="+inner.ID()+" parent_span_id="+outer.ID()+"\n") {
+		t.Errorf("expected the inner span to record its parent, got:\n%s", out)
+	}
+	if !strings.Contains(out, "rows read span_id="+inner.ID()+"\n") {
+		t.Errorf("expected context entries to carry the innermost span_id once, got:\n%s", out)
+	}
+	if strings.Count(out, " span_id="+outer.ID()) != 2 {
+		t.Errorf("expected only the outer span's own entries to carry its span_id, got:\n%s", out)
+	}
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStartupEntry(t *testing.T) {
+	var buf bytes.Buffer
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout, outStderr = &buf, &buf
+	t.Setenv("JOURNAL_STREAM", "")
+	t.Setenv("LOGGER_LEVELS", "ERROR,FATAL")
+	defer func() {
+		t.Setenv("LOGGER_LEVELS", "")
+		setEnabledLevels(allLevels())
+		Init("development", true)
+	}()
+
+	InitWithConfig(Config{Mode: "production", StartupEntry: true, Version: "1.4.2", Identifier: "svc"})
+	want := fmt.Sprintf("[INFO] [logger.InitWithConfig] logger initialized event=logger.initialized mode=production levels=ERROR,FATAL console=plain sinks=%d pid=%d identifier=svc version=1.4.2\n", len(sinks), os.Getpid())
+	if buf.String() != want {
+		t.Fatalf("got  %q\nwant %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	InitWithConfig(Config{Mode: "production", Fallback: FallbackJSON, StartupEntry: true})
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected one JSON entry, got %q: %v", buf.String(), err)
+	}
+	if entry["event"] != StartupEvent || entry["mode"] != "production" || entry["console"] != "json" {
+		t.Fatalf("unexpected startup entry %v", entry)
+	}
+
+	buf.Reset()
+	InitWithConfig(Config{Mode: "production"})
+	if strings.Contains(buf.String(), "logger initialized") {
+		t.Fatalf("startup entry written without StartupEntry: %q", buf.String())
+	}
+}
+
+func TestStartupEntry_SkippedInCrashMonitor(t *testing.T) {
+	var buf bytes.Buffer
+	defer SetOutputs(&buf, &buf)()
+	defer Init("development", true)
+	t.Setenv(crashMonitorEnv, "1")
+
+	InitWithConfig(Config{Mode: "production", StartupEntry: true})
+	if strings.Contains(buf.String(), "logger initialized") {
+		t.Fatalf("expected no startup entry from the crash monitor, got %q", buf.String())
+	}
+}
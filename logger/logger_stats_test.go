@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchStats_ReportsRatesSincePreviousReport(t *testing.T) {
+	Close() // no sink queues left by other tests
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	w := &statsWatch{}
+	start := time.Now()
+	w.snapshot(start)
+	for range 4 {
+		Warnf("slow")
+	}
+	Errorf("failed")
+	dropped := droppedEntries.Load()
+	droppedEntries.Add(3)
+	defer droppedEntries.Store(dropped)
+	buf.Reset()
+
+	w.report(start.Add(2 * time.Second))
+	want := "[INFO] [logger.WatchStats] logger stats event=logger.stats debug_per_sec=0.00 info_per_sec=0.00 warn_per_sec=2.00 error_per_sec=0.50 fatal_per_sec=0.00 queued=0 dropped=3 write_errors=0\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	w.report(start.Add(3 * time.Second))
+	if !strings.Contains(buf.String(), " info_per_sec=0.00 ") || !strings.Contains(buf.String(), " dropped=0 ") {
+		t.Fatalf("second report counted its own entry or old drops: %s", buf.String())
+	}
+}
+
+func TestWatchStats_Stops(t *testing.T) {
+	var buf lockedBuffer
+	captureLevels(new(bytes.Buffer))
+	Info.SetOutput(&buf)
+	defer Init("development", true)
+
+	stop := WatchStats(time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	stop()
+	logMutex.Lock()
+	logMutex.Unlock()
+	if !strings.Contains(buf.String(), "logger stats") {
+		t.Fatalf("no stats entry: %q", buf.String())
+	}
+}
@@ -0,0 +1,64 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStdcapture_LogsRawWrites(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+
+	if err := StdcaptureStart(); err != nil {
+		t.Fatal(err)
+	}
+	defer StdcaptureStop()
+	if err := StdcaptureStart(); err == nil {
+		t.Fatal("a second StdcaptureStart should fail")
+	}
+
+	fmt.Println("legacy print")
+	fmt.Fprintln(os.Stderr, "native library warning")
+	if err := StdcaptureStop(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.String()
+	for _, want := range []string{
+		"[WARN] [stdcapture] legacy print source=stderr-capture stream=stdout",
+		"[WARN] [stdcapture] native library warning source=stderr-capture stream=stderr",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in output:\n%s", want, got)
+		}
+	}
+}
+
+func TestStdcapture_ConsoleOutputBypassesCapture(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	Info.SetOutput(os.Stdout)
+	defer Info.SetOutput(&buf)
+
+	if err := StdcaptureStart(); err != nil {
+		t.Fatal(err)
+	}
+	if Info.Writer() == os.Stdout {
+		StdcaptureStop()
+		t.Fatal("console logger still writes to the captured stdout")
+	}
+	Infof("not captured")
+	StdcaptureStop()
+
+	if Info.Writer() != os.Stdout {
+		t.Fatal("console logger was not restored to stdout")
+	}
+	if strings.Contains(buf.String(), "not captured") {
+		t.Fatalf("logger output was captured: %q", buf.String())
+	}
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestHijackStdlog(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	var prev bytes.Buffer
+	log.SetOutput(&prev)
+	log.SetFlags(log.LstdFlags)
+	defer log.SetOutput(log.Default().Writer())
+
+	restore := HijackStdlog(WarnLevel)
+	log.Printf("cache miss for %s", "user:42")
+	restore()
+	log.Print("after restore")
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "[WARN] [logger_stdlog_test.go:") || !strings.HasSuffix(got, "] cache miss for user:42 source=stdlog\n") {
+		t.Fatalf("got %q", got)
+	}
+	if !strings.HasSuffix(prev.String(), "after restore\n") || strings.Contains(prev.String(), "cache miss") {
+		t.Fatalf("previous output = %q", prev.String())
+	}
+	if log.Flags() != log.LstdFlags {
+		t.Fatalf("flags not restored: %d", log.Flags())
+	}
+}
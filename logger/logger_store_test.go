@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLogStore_QueryAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLogStore(StoreConfig{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	write := func(ago time.Duration, level Level, msg string, kv ...any) {
+		t.Helper()
+		if err := store.WriteEntry(&Entry{Time: now.Add(-ago), Level: level, Message: msg, Fields: kv}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(3*time.Hour, ErrorLevel, "db timeout")
+	write(50*time.Minute, ErrorLevel, "upstream timeout", "host", "a")
+	write(40*time.Minute, InfoLevel, "request timeout ignored")
+	write(30*time.Minute, ErrorLevel, "disk full")
+	write(20*time.Minute, WarnLevel, "slow", "reason", "timeout")
+	write(10*time.Minute, ErrorLevel, "late", "reason", "timeout")
+
+	check := func(s *LogStore) {
+		t.Helper()
+		got, err := s.Query(now.Add(-time.Hour), WarnLevel, "timeout")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var msgs []string
+		for _, e := range got {
+			msgs = append(msgs, e.Message)
+		}
+		if want := []string{"upstream timeout", "slow", "late"}; !slices.Equal(msgs, want) {
+			t.Fatalf("query = %q, want %q", msgs, want)
+		}
+		all, _ := s.Query(time.Time{}, DebugLevel, "")
+		if len(all) != 6 {
+			t.Fatalf("query all = %d entries", len(all))
+		}
+	}
+	check(store)
+	store.Close()
+
+	// a crash mid-write leaves a partial record at the end of a segment
+	segments, _ := filepath.Glob(filepath.Join(dir, "*.msgpack"))
+	last := segments[len(segments)-1]
+	f, _ := os.OpenFile(last, os.O_WRONLY|os.O_APPEND, 0)
+	f.Write([]byte{0, 0, 0, 9, 0x81})
+	f.Close()
+
+	reopened, err := NewLogStore(StoreConfig{Dir: dir, QueryLimit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	got, _ := reopened.Query(time.Time{}, ErrorLevel, "")
+	if len(got) != 2 || got[0].Message != "disk full" || got[1].Message != "late" {
+		t.Fatalf("limited query = %+v", got)
+	}
+
+	// the repaired segment takes new entries and stays readable as a log file
+	if err := reopened.WriteEntry(&Entry{Time: now.Add(-10*time.Minute + time.Second), Level: ErrorLevel, Message: "after restart"}); err != nil {
+		t.Fatal(err)
+	}
+	lf, err := OpenLogFile(last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if entries := readAll(t, lf); entries[len(entries)-1].Message != "after restart" {
+		t.Fatalf("segment entries = %+v", entries)
+	}
+}
+
+func TestLogStore_Expiry(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, time.Now().UTC().Add(-3*time.Hour).Format(storeSegmentLayout)+".msgpack")
+	os.WriteFile(old, appendBinaryLogHeader(nil, "msgpack"), 0600)
+	store, err := NewLogStore(StoreConfig{Dir: dir, Retention: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatalf("expired segment still present: %v", err)
+	}
+}
+
+func TestQuery_RegisteredStore(t *testing.T) {
+	defer Init("development", true)
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	if _, err := Query(time.Time{}, DebugLevel, ""); err == nil {
+		t.Fatal("expected an error without a store")
+	}
+
+	store, err := NewLogStore(StoreConfig{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	AddSink(store)
+	defer Close()
+	ErrorKV("payment failed", "order", 42)
+	InfoKV("payment ok", "order", 43)
+
+	got, err := Query(time.Now().Add(-time.Minute), ErrorLevel, "order=42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Message != "payment failed" {
+		t.Fatalf("query = %+v", got)
+	}
+}
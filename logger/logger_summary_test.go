@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// failingSink rejects every entry.
+type failingSink struct{}
+
+func (failingSink) WriteEntry(*Entry) error { return errors.New("disk full") }
+func (failingSink) Close() error            { return nil }
+
+func TestClose_ShutdownSummary(t *testing.T) {
+	InitWithConfig(Config{Mode: "development", Verbose: true, ShutdownSummary: true})
+	defer Init("development", true)
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	AddSink(failingSink{})
+
+	warns, errs, failed := entryCounts[WarnLevel].Load(), entryCounts[ErrorLevel].Load(), writeErrors.Load()
+	Warnf("disk nearly full")
+	Warnf("disk nearly full")
+	Errorf("write failed")
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, "[INFO] [logger.Close] logger shutdown event=logger.shutdown uptime=") {
+		t.Fatalf("expected summary entry last, got:\n%s", buf.String())
+	}
+	for _, want := range []string{
+		fmt.Sprintf(" warn=%d ", warns+2),
+		fmt.Sprintf(" error=%d ", errs+1),
+		fmt.Sprintf(" write_errors=%d", failed+3),
+		" dropped=",
+	} {
+		if !strings.Contains(last, want) {
+			t.Errorf("expected %q in %q", want, last)
+		}
+	}
+}
+
+func TestClose_NoSummaryByDefault(t *testing.T) {
+	Init("development", true)
+	defer Init("development", true)
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	Close()
+	if strings.Contains(buf.String(), "logger shutdown") {
+		t.Fatalf("unexpected summary: %q", buf.String())
+	}
+}
+
+func TestClose_NoSummaryInCrashMonitor(t *testing.T) {
+	InitWithConfig(Config{Mode: "development", Verbose: true, ShutdownSummary: true})
+	defer Init("development", true)
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	t.Setenv(crashMonitorEnv, "1")
+	Close()
+	if strings.Contains(buf.String(), "logger shutdown") {
+		t.Fatalf("expected no summary from the crash monitor, got %q", buf.String())
+	}
+}
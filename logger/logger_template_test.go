@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTemplateEncoder_FixedWidth(t *testing.T) {
+	enc, err := NewTemplateEncoder(`{{time .Time "060102150405"}}|{{pad 5 (level .Level)}}|{{lpad 6 (field . "code")}}|{{pad 8 .Caller}}|{{.Message}}{{fields .Fields}}` + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Entry{
+		Time:    time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		Level:   WarnLevel,
+		Caller:  "store.Save:42",
+		Message: "slow write",
+		Fields:  []any{"code", 504, "path", "/var"},
+	}
+	got, err := enc.Encode(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "260304050607|WARN |   504|store.Sa|slow write code=504 path=/var"; string(got) != want {
+		t.Fatalf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestTemplateEncoder_FuncsAndErrors(t *testing.T) {
+	enc, err := NewTemplateEncoder(`{{upper .Message}} {{quote (field . "user")}} {{json (field . "missing")}} {{lower (level .Level)}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := enc.Encode(&Entry{Level: ErrorLevel, Message: "denied", Fields: []any{"user", `ana "a"`}})
+	if want := `DENIED "ana \"a\"" "" error`; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	if _, err := NewTemplateEncoder(`{{.Message`); err == nil {
+		t.Fatal("expected a parse error")
+	}
+	enc, _ = NewTemplateEncoder(`{{.Nope}}`)
+	if _, err := enc.Encode(testEntry()); err == nil {
+		t.Fatal("expected an execution error for an unknown field")
+	}
+}
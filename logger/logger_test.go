@@ -2,6 +2,8 @@ package logger
 
 import (
 	"bytes"
+	"flag"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -12,7 +14,7 @@ func TestCallerTagging_DebugfIncludesFunction(t *testing.T) {
 	var buf bytes.Buffer
 	// Replace the Debug logger to capture output
 	Debug = log.New(&buf, "", 0)
-	enabledLevels[DebugLevel] = true
+	enableLevels(DebugLevel)
 
 	Debugf("hello")
 
@@ -25,7 +27,7 @@ func TestCallerTagging_DebugfIncludesFunction(t *testing.T) {
 func TestStructuredLogging_InfoKV(t *testing.T) {
 	var buf bytes.Buffer
 	Info = log.New(&buf, "", 0)
-	enabledLevels[InfoLevel] = true
+	enableLevels(InfoLevel)
 
 	InfoKV("test message", "key1", "value1", "key2", 42)
 
@@ -44,7 +46,7 @@ func TestStructuredLogging_InfoKV(t *testing.T) {
 func TestStructuredLogging_ErrorKV(t *testing.T) {
 	var buf bytes.Buffer
 	Error = log.New(&buf, "", 0)
-	enabledLevels[ErrorLevel] = true
+	enableLevels(ErrorLevel)
 
 	ErrorKV("connection failed", "host", "localhost", "port", 5432)
 
@@ -66,12 +68,12 @@ func TestLevelFiltering_DisableDebug(t *testing.T) {
 	Info = log.New(&buf, "", 0)
 
 	// Disable DEBUG level
-	enabledLevels = map[Level]bool{
+	setEnabledLevels(map[Level]bool{
 		DebugLevel: false,
 		InfoLevel:  true,
 		WarnLevel:  true,
 		ErrorLevel: true,
-	}
+	})
 
 	Debugf("should not appear")
 	Infof("should appear")
@@ -93,12 +95,12 @@ func TestLevelFiltering_OnlyErrors(t *testing.T) {
 	Error = log.New(&buf, "", 0)
 
 	// Only ERROR level enabled
-	enabledLevels = map[Level]bool{
+	setEnabledLevels(map[Level]bool{
 		DebugLevel: false,
 		InfoLevel:  false,
 		WarnLevel:  false,
 		ErrorLevel: true,
-	}
+	})
 
 	Debugf("debug msg")
 	Infof("info msg")
@@ -135,6 +137,37 @@ func TestParseLevels_CaseInsensitive(t *testing.T) {
 	}
 }
 
+func TestParseLevel_RoundTripsString(t *testing.T) {
+	for l := DebugLevel; l <= FatalLevel; l++ {
+		got, err := ParseLevel(strings.ToLower(l.String()))
+		if err != nil || got != l {
+			t.Fatalf("ParseLevel(%q) = %v, %v; want %v", l.String(), got, err, l)
+		}
+	}
+	if got, err := ParseLevel(" Warning "); err != nil || got != WarnLevel {
+		t.Fatalf("WARNING should parse as WARN, got %v, %v", got, err)
+	}
+	if _, err := ParseLevel("verbose"); err == nil || !strings.Contains(err.Error(), `"verbose"`) {
+		t.Fatalf("expected an error naming the unknown level, got %v", err)
+	}
+	if s := Level(7).String(); s != "Level(7)" {
+		t.Fatalf("out-of-range level should print its value, got %q", s)
+	}
+}
+
+func TestLevel_FlagValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	min := InfoLevel
+	fs.Var(&min, "min-level", "")
+	if err := fs.Parse([]string{"-min-level", "error"}); err != nil || min != ErrorLevel {
+		t.Fatalf("flag should set ERROR, got %v, %v", min, err)
+	}
+	if err := fs.Parse([]string{"-min-level", "loud"}); err == nil || min != ErrorLevel {
+		t.Fatalf("invalid flag value should fail and keep the level, got %v, %v", min, err)
+	}
+}
+
 func TestEnvironmentLevelFiltering(t *testing.T) {
 	// Set environment variable
 	os.Setenv("LOGGER_LEVELS", "ERROR")
@@ -165,7 +198,7 @@ func TestEnvironmentLevelFiltering(t *testing.T) {
 func TestCallerInfo_IncludesLineNumber(t *testing.T) {
 	var buf bytes.Buffer
 	Info = log.New(&buf, "", 0)
-	enabledLevels[InfoLevel] = true
+	enableLevels(InfoLevel)
 
 	Infof("test message")
 
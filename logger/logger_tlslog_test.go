@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestLogTLS_HandshakeAndExpiry(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	serverConf, clientConf := selfSignedTLS(t)
+	serverConf.NextProtos = []string{"h2"}
+	clientConf.NextProtos = []string{"h2"}
+	called := false
+	clientConf.VerifyConnection = func(tls.ConnectionState) error { called = true; return nil }
+
+	// the server's own certificate expires within the hour
+	server := LogTLS(serverConf)
+	if got := buf.String(); !strings.HasPrefix(got, "[WARN] [logger.LogTLS] certificate expires soon subject=CN=localhost expires=") || !strings.HasSuffix(got, " days_left=0\n") {
+		t.Fatalf("expiry warning = %q", got)
+	}
+	buf.Reset()
+
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+	done := make(chan error, 1)
+	go func() { done <- tls.Server(b, server).Handshake() }()
+	clientConf.ServerName = "127.0.0.1"
+	if err := tls.Client(a, LogTLS(clientConf)).Handshake(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Fatal("original VerifyConnection not called")
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "[DEBUG] [logger.LogTLS] tls handshake version=TLS 1.3 cipher=TLS_") ||
+		!strings.Contains(got, " alpn=h2 peer=CN=localhost expires=") {
+		t.Fatalf("handshake entry = %q", got)
+	}
+	if strings.Contains(got, "expires soon") {
+		t.Fatalf("expiry warned twice: %q", got)
+	}
+}
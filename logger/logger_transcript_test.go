@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readTranscripts(t *testing.T, path string) []transcriptRecord {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []transcriptRecord
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var r transcriptRecord
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("bad record %q: %v", line, err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestTranscriptSink_AppendsOneRecordPerInvocation(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	path := filepath.Join(t.TempDir(), "mytool", "history.jsonl")
+
+	s, err := NewTranscriptSink(TranscriptConfig{Path: path, Args: []string{"deploy", "--token", "s3cret", "--env=prod"}, Redact: []string{"--token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	AddSink(s)
+	Infof("uploading")
+	Errorf("upload failed")
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = NewTranscriptSink(TranscriptConfig{Path: path, Args: []string{"status"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	AddSink(s)
+	s.SetExitStatus(3)
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readTranscripts(t, path)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %+v", records)
+	}
+	first := records[0]
+	if strings.Join(first.Args, " ") != "deploy --token [REDACTED] --env=prod" || first.Exit != 1 ||
+		first.Errors != 1 || first.Error != "upload failed" || first.Cmd == "" || first.Time.IsZero() {
+		t.Errorf("unexpected first record %+v", first)
+	}
+	if second := records[1]; second.Exit != 3 || second.Errors != 0 || second.Error != "" {
+		t.Errorf("unexpected second record %+v", second)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected a file readable only by the user, got %v %v", info.Mode(), err)
+	}
+}
+
+func TestTranscriptSink_FatalExitWritesOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	s, err := NewTranscriptSink(TranscriptConfig{Path: path, Args: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.fatalExit()
+	s.Close()
+	if records := readTranscripts(t, path); len(records) != 1 || records[0].Exit != 1 {
+		t.Fatalf("expected one record with exit 1, got %+v", records)
+	}
+}
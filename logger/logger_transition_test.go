@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTransition_Fields(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	Transition("worker", "idle", "busy", "job", 42)
+	if got := buf.String(); !strings.HasPrefix(got, "[INFO] [logger.TestTransition_Fields:") ||
+		!strings.HasSuffix(got, "] state transition state_machine=worker state_from=idle state_to=busy job=42\n") {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestTransition_Validator(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	SetTransitionValidator(AllowTransitions(map[string]map[any][]any{
+		"worker": {"idle": {"busy"}, "busy": {"idle"}},
+	}))
+	defer SetTransitionValidator(nil)
+
+	Transition("worker", "idle", "busy")
+	Transition("worker", "idle", "stopped")
+	Transition("conn", "open", "closed")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines = %q", lines)
+	}
+	if !strings.HasPrefix(lines[0], "[INFO] ") || !strings.HasPrefix(lines[2], "[INFO] ") {
+		t.Fatalf("allowed transitions = %q", lines)
+	}
+	if !strings.HasPrefix(lines[1], "[WARN] ") || !strings.HasSuffix(lines[1], "invalid state transition state_machine=worker state_from=idle state_to=stopped error=idle -> stopped is not an allowed transition") {
+		t.Fatalf("rejected transition = %q", lines[1])
+	}
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"bytes"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWarnUnclosed_NamesPendingOutputs(t *testing.T) {
+	var buf bytes.Buffer
+	warnUnclosed(&buf)
+	if buf.Len() != 0 {
+		t.Fatalf("expected no warning without sinks, got %q", buf.String())
+	}
+
+	s := &entrySink{}
+	AddSink(s)
+	defer Close()
+	warnUnclosed(&buf)
+	got := buf.String()
+	if !strings.HasPrefix(got, "[WARN] ") || !strings.Contains(got, "[logger.Close] exiting without Close") ||
+		!strings.HasSuffix(got, "event=logger.unclosed sinks=1 queued=0\n") {
+		t.Fatalf("unexpected warning %q", got)
+	}
+}
+
+func TestFileSink_WarnsWhenCollectedWithoutClose(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+	dir := t.TempDir()
+
+	closed, err := NewFileSink(filepath.Join(dir, "closed.jsonl"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+	if _, err := NewFileSink(filepath.Join(dir, "leaked.jsonl"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		runtime.GC()
+		logMutex.Lock()
+		got := buf.String()
+		logMutex.Unlock()
+		if strings.Contains(got, "leaked.jsonl") {
+			if strings.Contains(got, "closed.jsonl") {
+				t.Fatalf("closed sink reported: %q", got)
+			}
+			if !strings.Contains(got, "file sink garbage collected without Close event=logger.unclosed") {
+				t.Fatalf("unexpected warning %q", got)
+			}
+			return
+		}
+	}
+	t.Fatal("no warning for the leaked FileSink")
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate_CleanConfig(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	cfg := Config{Mode: "production", FilePath: filepath.Join(t.TempDir(), "app.log"), Layout: "{time} {level} {msg}"}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected no problems, got: %v", err)
+	}
+}
+
+func TestValidate_ReportsProblems(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "INFO,VERBOSE")
+	err := Validate(Config{
+		Mode:          "prod",
+		Fallback:      FallbackJSON,
+		Layout:        "{time} {lvl} {msg}",
+		FilePath:      "/nonexistent/dir/app.log",
+		ConsoleLevels: &LevelMapping{Min: Level(9)},
+		LoggerLevels:  map[string]Level{"http.": WarnLevel},
+		Locale:        "xx",
+		Colors:        "sepia",
+	})
+	if err == nil {
+		t.Fatal("expected problems to be reported")
+	}
+	for _, want := range []string{
+		`unknown mode "prod"`,
+		"Fallback is ignored in development mode",
+		"unknown Layout placeholder {lvl}",
+		"log file directory",
+		"ConsoleLevels.Min has unknown level 9",
+		`malformed logger name "http."`,
+		`no catalog registered for Locale "xx"`,
+		`Colors has unknown color profile "sepia"`,
+		`LOGGER_LEVELS has unknown level "VERBOSE"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in:\n%v", want, err)
+		}
+	}
+}
+
+func TestInit_DiagnosticsWithLoggerDebug(t *testing.T) {
+	var buf strings.Builder
+	oldStdout, oldStderr := outStdout, outStderr
+	defer func() { outStdout, outStderr = oldStdout, oldStderr }()
+	outStdout, outStderr = &buf, &buf
+	t.Setenv("LOGGER_DEBUG", "1")
+	t.Setenv("JOURNAL_STREAM", "")
+	t.Setenv("LOGGER_LEVELS", "DEBUG,INFO,WARN,ERROR,FATAL")
+
+	logPath := filepath.Join(t.TempDir(), "diag.log")
+	InitWithConfig(Config{Mode: "production", FilePath: logPath, Fallback: FallbackFileOnly, FileAsync: true, TimeFormat: "15:04"})
+	defer Init("development", true)
+	defer Close()
+
+	got := buf.String()
+	for _, want := range []string{
+		"logger: mode=production verbose=false levels=DEBUG,INFO,WARN,ERROR,FATAL identifier=",
+		"logger: console: discard\n",
+		"logger: journald: false (JOURNAL_STREAM unset), style default\n",
+		"logger: file: " + logPath + " (async)\n",
+		"logger: sinks=0 routes=0 metrics=0\n",
+		"logger: problem: TimeFormat is only used with Layout\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in diagnostics:\n%s", want, got)
+		}
+	}
+}
+
+func TestEffectiveConfig(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "INFO,WARN,ERROR,FATAL")
+	defer setLoggerLevels(nil)
+	InitWithConfig(Config{
+		Mode:         "production",
+		Fallback:     FallbackJSON,
+		Severity:     SeverityField,
+		LoggerLevels: map[string]Level{"http": WarnLevel},
+	})
+	defer Init("development", true)
+
+	rc := EffectiveConfig()
+	if rc.Console != "json" || rc.Severity != "field" || rc.File != "" || len(rc.Problems) != 0 {
+		t.Fatalf("unexpected effective config: %+v", rc)
+	}
+	if strings.Join(rc.Levels, ",") != "INFO,WARN,ERROR,FATAL" || rc.LoggerLevels["http"] != "WARN" {
+		t.Fatalf("unexpected levels: %v %v", rc.Levels, rc.LoggerLevels)
+	}
+	data, err := json.Marshal(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"console":"json"`) || !strings.Contains(string(data), `"logger_levels":{"http":"WARN"}`) {
+		t.Fatalf("unexpected JSON: %s", data)
+	}
+}
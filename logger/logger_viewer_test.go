@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ringWith(levels ...Level) *RingSink {
+	r := NewRingSink(0)
+	t0 := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for i, l := range levels {
+		r.WriteEntry(&Entry{Time: t0.Add(time.Duration(i) * time.Second), Level: l, Caller: "main.run", Message: fmt.Sprintf("entry %d", i)})
+	}
+	return r
+}
+
+func TestRingSink_KeepsNewestEntries(t *testing.T) {
+	r := NewRingSink(3)
+	for i := range 5 {
+		r.WriteEntry(&Entry{Message: fmt.Sprint(i)})
+	}
+	var got []string
+	for _, e := range r.Entries() {
+		got = append(got, e.Message)
+	}
+	if strings.Join(got, ",") != "2,3,4" {
+		t.Fatalf("entries = %v, want 2,3,4", got)
+	}
+	if r.Total() != 5 {
+		t.Fatalf("total = %d, want 5", r.Total())
+	}
+}
+
+func TestViewer_FiltersByLevelAndRegexp(t *testing.T) {
+	v := newViewer(ringWith(InfoLevel, ErrorLevel, WarnLevel, ErrorLevel))
+	v.handleKey("3")
+	if lines, _ := v.lines(); len(lines) != 3 {
+		t.Fatalf("WARN and above: %d lines, want 3", len(lines))
+	}
+	for _, k := range []string{"/", "e", "n", "t", "r", "y", " ", "[", "1", "3", "]", "enter"} {
+		v.handleKey(k)
+	}
+	lines, _ := v.lines()
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "entry 1") || !strings.HasSuffix(lines[1], "entry 3") {
+		t.Fatalf("filtered lines = %q", lines)
+	}
+	v.handleKey("/")
+	v.handleKey("esc")
+	if lines, _ := v.lines(); len(lines) != 3 {
+		t.Fatalf("after clearing filter: %d lines, want 3", len(lines))
+	}
+
+	v.handleKey("/")
+	v.handleKey("backspace")
+	v.handleKey("(")
+	v.handleKey("enter")
+	if v.filter != nil || !strings.Contains(v.status(0), "invalid filter") {
+		t.Fatalf("invalid filter accepted: %q", v.status(0))
+	}
+}
+
+func TestViewer_JumpsBetweenErrors(t *testing.T) {
+	v := newViewer(ringWith(InfoLevel, ErrorLevel, InfoLevel, FatalLevel, InfoLevel))
+	v.handleKey("e")
+	if !v.paused || v.bottom != 4 {
+		t.Fatalf("first e: paused=%v bottom=%d, want true 4", v.paused, v.bottom)
+	}
+	v.handleKey("e")
+	if v.bottom != 2 {
+		t.Fatalf("second e: bottom=%d, want 2", v.bottom)
+	}
+	v.handleKey("e")
+	if v.bottom != 2 || !strings.Contains(v.status(0), "no more errors") {
+		t.Fatalf("past first error: bottom=%d status=%q", v.bottom, v.status(0))
+	}
+	v.handleKey("E")
+	if v.bottom != 4 {
+		t.Fatalf("E: bottom=%d, want 4", v.bottom)
+	}
+	v.handleKey("G")
+	if v.paused {
+		t.Fatal("G did not resume following")
+	}
+	if !v.handleKey("q") {
+		t.Fatal("q did not quit")
+	}
+}
+
+func TestViewer_RendersPageAndStatus(t *testing.T) {
+	textFormat.color = false
+	defer Init("development", true)
+	v := newViewer(ringWith(InfoLevel, InfoLevel, InfoLevel, InfoLevel, InfoLevel))
+
+	var b strings.Builder
+	v.render(&b, 4, 40)
+	out := b.String()
+	if strings.Contains(out, "entry 1") || !strings.Contains(out, "entry 2") || !strings.Contains(out, "entry 4") {
+		t.Fatalf("following view should show the last 3 entries:\n%q", out)
+	}
+	if !strings.Contains(out, "09:00:04.000 [INFO] [main.run] entry 4\r\n") {
+		t.Fatalf("missing rendered line:\n%q", out)
+	}
+	if !strings.Contains(out, "\033[4;1H\033[7m FOLLOW") {
+		t.Fatalf("missing status bar:\n%q", out)
+	}
+
+	v.handleKey("g")
+	b.Reset()
+	v.render(&b, 4, 40)
+	if out := b.String(); !strings.Contains(out, "entry 0") || strings.Contains(out, "entry 3") || !strings.Contains(out, "PAUSED") {
+		t.Fatalf("top of paused view:\n%q", out)
+	}
+}
+
+func TestReadKeys_DecodesEscapeSequences(t *testing.T) {
+	keys := make(chan string)
+	go readKeys(bufio.NewReader(strings.NewReader("q\x1b[A\x1b[6~\r\x7f\x03")), keys)
+	want := []string{"q", "up", "pgdn", "enter", "backspace", "q"}
+	for _, w := range want {
+		if got := <-keys; got != w {
+			t.Fatalf("key = %q, want %q", got, w)
+		}
+	}
+}
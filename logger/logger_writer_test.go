@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLineWriter_SplitsAndBuffersLines(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	w := NewWriter(WarnLevel, "source", "tool")
+	fmt.Fprint(w, "first line\r\nsecond ")
+	if got := strings.Count(buf.String(), "\n"); got != 1 {
+		t.Fatalf("partial line should be held, got %q", buf.String())
+	}
+	fmt.Fprint(w, "line\n\n  \nthird")
+	w.Close()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{"first line source=tool", "second line source=tool", "third source=tool"}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d entries, got %q", len(want), buf.String())
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "[WARN] [logger.TestLineWriter_SplitsAndBuffersLines:") || !strings.HasSuffix(line, "] "+want[i]) {
+			t.Errorf("entry %d = %q, want %q from the NewWriter call site", i, line, want[i])
+		}
+	}
+}
+
+func TestLineWriter_LongPartialLine(t *testing.T) {
+	var buf bytes.Buffer
+	captureLevels(&buf)
+	defer Init("development", true)
+
+	w := NewWriter(InfoLevel)
+	n, err := w.Write(bytes.Repeat([]byte("x"), WriterMaxLine+1))
+	if n != WriterMaxLine+1 || err != nil {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Fatalf("an overlong partial line should be logged without waiting for its newline")
+	}
+	w.Write([]byte("\n"))
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Fatalf("the newline ending it should not add an empty entry, got %d entries", strings.Count(buf.String(), "\n"))
+	}
+}
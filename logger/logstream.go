@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// LogStreamMethod is the gRPC method path served by LogStreamServer.
+const LogStreamMethod = "/logger.v1.LogStream/Subscribe"
+
+// LogStreamServer is a gRPC service that streams live entries to
+// subscribers. It implements the service in logstream.proto using only the
+// standard library: register it as a sink with AddSink and serve it over
+// HTTP/2 (TLS, or cleartext with http.Server.Protocols):
+//
+//	stream := logger.NewLogStreamServer()
+//	logger.AddSink(stream)
+//	var protocols http.Protocols
+//	protocols.SetUnencryptedHTTP2(true)
+//	srv := &http.Server{Addr: ":9090", Handler: stream, Protocols: &protocols}
+//	go srv.ListenAndServe()
+//
+// Subscribers pass a minimum level in SubscribeRequest.min_level and only
+// receive entries at or above it. Entries are dropped for a subscriber
+// that falls behind rather than blocking the logger.
+type LogStreamServer struct {
+	mu     sync.Mutex
+	subs   map[chan []byte]Level
+	closed chan struct{}
+	once   sync.Once
+}
+
+// logStreamBacklog is the number of entries queued per subscriber.
+const logStreamBacklog = 256
+
+// NewLogStreamServer returns a server with no subscribers.
+func NewLogStreamServer() *LogStreamServer {
+	return &LogStreamServer{subs: make(map[chan []byte]Level), closed: make(chan struct{})}
+}
+
+// WriteEntry fans e out to subscribers whose minimum level it meets.
+func (s *LogStreamServer) WriteEntry(e *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var msg []byte
+	for ch, min := range s.subs {
+		if e.Level < min {
+			continue
+		}
+		if msg == nil {
+			msg = grpcFrame(encodeLogStreamEntry(e))
+		}
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+	return nil
+}
+
+// Close ends all open streams with an OK status.
+func (s *LogStreamServer) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
+
+// ServeHTTP handles gRPC requests for LogStreamMethod.
+func (s *LogStreamServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC requests only", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+	if r.URL.Path != LogStreamMethod {
+		grpcTrailersOnly(w, 12, "unknown method "+r.URL.Path) // UNIMPLEMENTED
+		return
+	}
+	req, err := readGRPCMessage(r.Body)
+	if err != nil {
+		grpcTrailersOnly(w, 3, err.Error()) // INVALID_ARGUMENT
+		return
+	}
+	min := DebugLevel
+	if names := protoStrings(req)[1]; len(names) > 0 && names[0] != "" {
+		level, err := ParseLevel(names[0])
+		if err != nil {
+			grpcTrailersOnly(w, 3, "unknown level "+names[0])
+			return
+		}
+		min = level
+	}
+
+	ch := make(chan []byte, logStreamBacklog)
+	s.mu.Lock()
+	s.subs[ch] = min
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subs, ch)
+		s.mu.Unlock()
+	}()
+
+	flusher, _ := w.(http.Flusher)
+	w.WriteHeader(http.StatusOK)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	for {
+		select {
+		case msg := <-ch:
+			if _, err := w.Write(msg); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		case <-r.Context().Done():
+			return
+		case <-s.closed:
+			w.Header().Set(http.TrailerPrefix+"Grpc-Status", "0")
+			return
+		}
+	}
+}
+
+// grpcTrailersOnly ends a call without messages, carrying the status in the headers.
+func grpcTrailersOnly(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Grpc-Status", fmt.Sprint(code))
+	w.Header().Set("Grpc-Message", msg)
+	w.WriteHeader(http.StatusOK)
+}
+
+// grpcFrame prefixes an uncompressed message with the gRPC length header.
+func grpcFrame(msg []byte) []byte {
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	return append(frame, msg...)
+}
+
+// readGRPCMessage reads one length-prefixed gRPC message.
+func readGRPCMessage(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("reading request: %w", err)
+	}
+	if header[0] != 0 {
+		return nil, errors.New("compressed requests are not supported")
+	}
+	n := binary.BigEndian.Uint32(header[1:])
+	if n > 1<<20 {
+		return nil, errors.New("request too large")
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, fmt.Errorf("reading request: %w", err)
+	}
+	return msg, nil
+}
+
+// encodeLogStreamEntry encodes e as a logger.v1.LogEntry protobuf message.
+func encodeLogStreamEntry(e *Entry) []byte {
+	var b []byte
+	b = binary.AppendUvarint(b, 1<<3|0)
+	b = binary.AppendUvarint(b, uint64(e.Time.UnixNano()))
+	b = appendProtoString(b, 2, levelNames[e.Level])
+	b = appendProtoString(b, 3, e.Caller)
+	b = appendProtoString(b, 4, e.Message)
+	for i := 0; i+1 < len(e.Fields); i += 2 {
+		key, ok := e.Fields[i].(string)
+		if !ok {
+			continue
+		}
+		field := appendProtoString(nil, 1, key)
+		field = appendProtoString(field, 2, fmt.Sprint(e.Fields[i+1]))
+		b = appendProtoBytes(b, 5, field)
+	}
+	return b
+}
+
+func appendProtoString(b []byte, num int, s string) []byte {
+	return appendProtoBytes(b, num, []byte(s))
+}
+
+func appendProtoBytes(b []byte, num int, data []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// protoStrings returns the length-delimited fields of a protobuf message by
+// field number, skipping other wire types. Malformed input ends the scan.
+func protoStrings(b []byte) map[int][]string {
+	fields := map[int][]string{}
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			break
+		}
+		b = b[n:]
+		num, wire := int(tag>>3), tag&7
+		switch wire {
+		case 0:
+			if _, n = binary.Uvarint(b); n <= 0 {
+				return fields
+			}
+			b = b[n:]
+		case 1:
+			if len(b) < 8 {
+				return fields
+			}
+			b = b[8:]
+		case 2:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < size {
+				return fields
+			}
+			fields[num] = append(fields[num], string(b[n:n+int(size)]))
+			b = b[n+int(size):]
+		case 5:
+			if len(b) < 4 {
+				return fields
+			}
+			b = b[4:]
+		default:
+			return fields
+		}
+	}
+	return fields
+}
@@ -0,0 +1,108 @@
+package logtest
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mordilloSan/go_logger/logger"
+)
+
+// bridgeKey is the context key of the test an entry belongs to.
+type bridgeKey struct{}
+
+var (
+	bridgeMu sync.Mutex
+	// bridged holds the tests of running Bridge calls
+	bridged = map[testing.TB]bool{}
+	// consoleWriters holds the console writers replaced while bridged is
+	// not empty
+	consoleWriters []io.Writer
+	// bridgeRegistered is true while the shared bridgeSink is registered
+	bridgeRegistered atomic.Bool
+)
+
+// Bridge routes log entries to t.Log until the test ends, so they are shown
+// inline with the test's failures (and with go test -v) instead of on the
+// console. Entries logged with the returned context, or a context derived
+// from it, reach t only, which keeps parallel tests apart; entries logged
+// without one reach every bridged test. Each entry is written as
+// "[LEVEL] [caller] message key=value", naming the code that logged it:
+//
+//	func TestCheckout(t *testing.T) {
+//	    t.Parallel()
+//	    ctx := logtest.Bridge(t)
+//	    checkout(ctx, cart) // logs with logger.InfoContext(ctx, ...)
+//	}
+//	// [INFO] [shop.checkout:57] charge created amount=1299
+//
+// Console output is discarded while any test is bridged. Bridge registers
+// a sink, so call it after logger.Init.
+func Bridge(t testing.TB) context.Context {
+	t.Helper()
+	if bridgeRegistered.CompareAndSwap(false, true) {
+		logger.AddSink(&bridgeSink{})
+	}
+	bridgeMu.Lock()
+	if len(bridged) == 0 {
+		consoleWriters = consoleWriters[:0]
+		for _, l := range consoleLoggers() {
+			consoleWriters = append(consoleWriters, l.Writer())
+			l.SetOutput(io.Discard)
+		}
+	}
+	bridged[t] = true
+	bridgeMu.Unlock()
+
+	t.Cleanup(func() {
+		// deliver the test's entries before t stops accepting them
+		logger.SyncSinks()
+		bridgeMu.Lock()
+		defer bridgeMu.Unlock()
+		delete(bridged, t)
+		if len(bridged) == 0 {
+			for i, l := range consoleLoggers() {
+				if l.Writer() == io.Discard {
+					l.SetOutput(consoleWriters[i])
+				}
+			}
+		}
+	})
+	return context.WithValue(context.Background(), bridgeKey{}, t)
+}
+
+func consoleLoggers() []*log.Logger {
+	return []*log.Logger{logger.Debug, logger.Info, logger.Warning, logger.Error, logger.Fatal}
+}
+
+// bridgeSink hands entries to the bridged tests.
+type bridgeSink struct{}
+
+func (*bridgeSink) WriteEntry(e *logger.Entry) error {
+	line, err := logger.TextEncoder{}.Encode(e)
+	if err != nil {
+		return err
+	}
+	bridgeMu.Lock()
+	defer bridgeMu.Unlock()
+	if t, ok := e.Context().Value(bridgeKey{}).(testing.TB); ok {
+		if bridged[t] {
+			t.Log(string(line))
+		}
+		return nil
+	}
+	for t := range bridged {
+		t.Log(string(line))
+	}
+	return nil
+}
+
+// Close runs when logger.Close closes the sinks; the next Bridge call
+// registers a new one.
+func (*bridgeSink) Close() error {
+	bridgeRegistered.Store(false)
+	return nil
+}
@@ -0,0 +1,85 @@
+package logtest
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden rewrite
+// golden files instead of comparing against them:
+//
+//	LOGTEST_UPDATE=1 go test ./...
+const UpdateEnv = "LOGTEST_UPDATE"
+
+var (
+	// timestamps in the classic layout, RFC 3339 (JSON output), and
+	// development clock-only layouts
+	timestampPattern = regexp.MustCompile(
+		`\d{4}[/-]\d{2}[/-]\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})?|\b\d{2}:\d{2}:\d{2}\.\d+\b`)
+	// the line number of a "[pkg.Func:42]" or JSON "caller":"pkg.Func:42"
+	callerLinePattern = regexp.MustCompile(`(\[[^\]\s]+|"caller":"[^"]*):\d+([\]"])`)
+)
+
+// Normalize replaces what changes between runs and edits in s: timestamps
+// become "TIME" and caller line numbers become "N", so
+//
+//	2025/03/04 05:06:07 [INFO] [main.run:42] started
+//
+// becomes
+//
+//	TIME [INFO] [main.run:N] started
+func Normalize(s string) string {
+	s = timestampPattern.ReplaceAllString(s, "TIME")
+	return callerLinePattern.ReplaceAllString(s, "${1}:N${2}")
+}
+
+// AssertGolden compares Normalize(got) with the golden file at path, such as
+// "testdata/startup.golden", and fails t with the first differing line. With
+// LOGTEST_UPDATE set, it writes the file instead, creating its directory,
+// so a changed logging contract is reviewed as a diff of the golden file:
+//
+//	rec := logtest.Capture(t)
+//	run()
+//	logtest.AssertGolden(t, rec.Plain(), "testdata/run.golden")
+func AssertGolden(t testing.TB, got, path string) {
+	t.Helper()
+	got = Normalize(got)
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("logtest: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("logtest: %v", err)
+		}
+		return
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("logtest: golden file %s does not exist; run with %s=1 to create it", path, UpdateEnv)
+		return
+	} else if err != nil {
+		t.Fatalf("logtest: %v", err)
+		return
+	}
+	want := string(data)
+	if got == want {
+		return
+	}
+	gotLines, wantLines := strings.Split(got, "\n"), strings.Split(want, "\n")
+	for i := range max(len(gotLines), len(wantLines)) {
+		var g, w string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g != w {
+			t.Errorf("logtest: output differs from %s at line %d (run with %s=1 to update)\ngot:  %q\nwant: %q", path, i+1, UpdateEnv, g, w)
+			return
+		}
+	}
+}
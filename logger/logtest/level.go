@@ -0,0 +1,23 @@
+package logtest
+
+import (
+	"testing"
+
+	"github.com/mordilloSan/go_logger/logger"
+)
+
+// WithLevel enables level and the levels above it until the test ends,
+// turning DEBUG output on when level is logger.DebugLevel. The previous
+// levels are restored by t.Cleanup:
+//
+//	func TestRetry(t *testing.T) {
+//	    rec := logtest.Capture(t)
+//	    logtest.WithLevel(t, logger.DebugLevel)
+//	    retry()
+//	}
+//
+// It changes package-wide state, so tests using it must not call t.Parallel.
+func WithLevel(t testing.TB, level logger.Level) {
+	t.Helper()
+	t.Cleanup(logger.SetMinLevel(level))
+}
@@ -0,0 +1,128 @@
+// Package logtest captures console output of the logger package in tests.
+//
+// A Recorder keeps every entry both as written, with development-mode color
+// codes, and as plain text, so golden-file tests can compare either form
+// without stripping ANSI sequences themselves. AssertGolden compares the
+// output with a golden file after normalizing timestamps and caller lines:
+//
+//	func TestStartup(t *testing.T) {
+//	    logger.Init("development", false)
+//	    rec := logtest.Capture(t)
+//	    run()
+//	    logtest.AssertGolden(t, rec.Plain(), "testdata/startup.golden")
+//	}
+package logtest
+
+import (
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mordilloSan/go_logger/logger"
+)
+
+// Entry is one console entry.
+type Entry struct {
+	// Raw is the entry as written to the console, including color codes.
+	Raw string
+	// Plain is Raw with ANSI escape sequences removed.
+	Plain string
+}
+
+// Recorder is an io.Writer that records each write as an Entry. The level
+// loggers write each entry with a single call, so entries are never split.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Capture points the console loggers at a new Recorder until the test ends.
+// Call it after logger.Init, which replaces the console loggers. Levels
+// whose output is off, such as DEBUG in development mode without verbose,
+// stay off; see WithLevel.
+func Capture(t testing.TB) *Recorder {
+	t.Helper()
+	rec := &Recorder{}
+	loggers := []*log.Logger{logger.Debug, logger.Info, logger.Warning, logger.Error, logger.Fatal}
+	saved := make([]io.Writer, len(loggers))
+	for i, l := range loggers {
+		saved[i] = l.Writer()
+		if saved[i] != io.Discard {
+			l.SetOutput(rec)
+		}
+	}
+	t.Cleanup(func() {
+		for i, l := range loggers {
+			if saved[i] != io.Discard {
+				l.SetOutput(saved[i])
+			}
+		}
+	})
+	return rec
+}
+
+// Write records p as one entry.
+func (r *Recorder) Write(p []byte) (int, error) {
+	raw := string(p)
+	r.mu.Lock()
+	r.entries = append(r.entries, Entry{Raw: raw, Plain: StripANSI(raw)})
+	r.mu.Unlock()
+	return len(p), nil
+}
+
+// Entries returns the entries recorded so far.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// Raw returns the recorded output as written.
+func (r *Recorder) Raw() string {
+	return r.join(func(e Entry) string { return e.Raw })
+}
+
+// Plain returns the recorded output without color codes.
+func (r *Recorder) Plain() string {
+	return r.join(func(e Entry) string { return e.Plain })
+}
+
+// Reset discards the recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.entries = nil
+	r.mu.Unlock()
+}
+
+func (r *Recorder) join(field func(Entry) string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var b strings.Builder
+	for _, e := range r.entries {
+		b.WriteString(field(e))
+	}
+	return b.String()
+}
+
+// StripANSI removes CSI escape sequences such as "\033[36m" from s.
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\033[") {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\033' && i+1 < len(s) && s[i+1] == '[' {
+			// parameters and intermediates end at a final byte in @-~
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+				i++
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
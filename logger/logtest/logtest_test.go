@@ -0,0 +1,214 @@
+package logtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mordilloSan/go_logger/logger"
+)
+
+func TestCapture_RawAndPlain(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm")
+	logger.Init("development", true)
+	defer logger.Init("development", true)
+	rec := Capture(t)
+
+	logger.Infof("server started")
+	logger.WarnKV("slow request", "path", "/api")
+
+	entries := rec.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %q", len(entries), rec.Raw())
+	}
+	if !strings.HasPrefix(entries[0].Raw, "\033[32m[INFO]\033[0m ") {
+		t.Errorf("expected colored raw entry, got %q", entries[0].Raw)
+	}
+	if !strings.HasPrefix(entries[0].Plain, "[INFO] ") || strings.Contains(entries[0].Plain, "\033") {
+		t.Errorf("expected plain entry, got %q", entries[0].Plain)
+	}
+	if !strings.HasSuffix(entries[1].Plain, "slow request path=/api\n") {
+		t.Errorf("unexpected plain entry %q", entries[1].Plain)
+	}
+	if rec.Plain() != entries[0].Plain+entries[1].Plain {
+		t.Errorf("Plain does not join entries: %q", rec.Plain())
+	}
+
+	rec.Reset()
+	if len(rec.Entries()) != 0 {
+		t.Fatal("expected Reset to discard entries")
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	for in, want := range map[string]string{
+		"plain":                         "plain",
+		"\033[31m[ERROR]\033[0m failed": "[ERROR] failed",
+		"\033[1;38;5;208mbold\033[m":    "bold",
+		"trailing \033[":                "trailing ",
+	} {
+		if got := StripANSI(in); got != want {
+			t.Errorf("StripANSI(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// fakeTB records the failures and cleanups of a test run inside a test.
+type fakeTB struct {
+	testing.TB
+	cleanups []func()
+	errors   []string
+
+	mu   sync.Mutex
+	logs []string
+}
+
+func (f *fakeTB) Log(args ...any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logs = append(f.logs, fmt.Sprint(args...))
+}
+
+func (f *fakeTB) logged() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.logs...)
+}
+
+func (f *fakeTB) Helper()           {}
+func (f *fakeTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) finish() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestFailOnError(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	logger.Init("development", true)
+	defer logger.Init("development", true)
+	rec := Capture(t)
+
+	tb := &fakeTB{TB: t}
+	strict := FailOnError(tb)
+	strict.Expect(`retrying .* attempt=1`)
+	logger.Warnf("not an error")
+	logger.ErrorKV("retrying upload", "attempt", 1)
+	logger.Errorf("disk full")
+	tb.finish()
+
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "disk full") {
+		t.Fatalf("expected one failure for the unexpected entry, got %q", tb.errors)
+	}
+	if !strings.Contains(rec.Plain(), "retrying upload attempt=1") || !strings.Contains(rec.Plain(), "disk full") {
+		t.Fatalf("entries should still reach the recorder, got %q", rec.Plain())
+	}
+
+	logger.Errorf("after the test")
+	if len(tb.errors) != 1 {
+		t.Fatalf("entries after the test ended should not be checked, got %q", tb.errors)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"2025/03/04 05:06:07 [INFO] [main.run:42] started addr=db.local:5432\n":                   "TIME [INFO] [main.run:N] started addr=db.local:5432\n",
+		"[WARN] 2025/03/04 05:06:07.123456 [(*Server).serve:7] slow\n":                            "[WARN] TIME [(*Server).serve:N] slow\n",
+		`{"time":"2025-03-04T05:06:07.89+01:00","level":"INFO","caller":"main.run:42","msg":"x"}`: `{"time":"TIME","level":"INFO","caller":"main.run:N","msg":"x"}`,
+	} {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "run.golden")
+	out := "2025/03/04 05:06:07 [INFO] [main.run:42] started\n"
+
+	tb := &fakeTB{TB: t}
+	t.Setenv(UpdateEnv, "1")
+	AssertGolden(tb, out, path)
+	if data, err := os.ReadFile(path); err != nil || string(data) != "TIME [INFO] [main.run:N] started\n" {
+		t.Fatalf("update mode should write the normalized output, got %q, %v", data, err)
+	}
+
+	t.Setenv(UpdateEnv, "")
+	AssertGolden(tb, "2026/01/01 00:00:00 [INFO] [main.run:57] started\n", path)
+	if len(tb.errors) != 0 {
+		t.Fatalf("moved lines and new timestamps should match, got %q", tb.errors)
+	}
+	AssertGolden(tb, "2026/01/01 00:00:00 [INFO] [main.run:57] stopped\n", path)
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "line 1") || !strings.Contains(tb.errors[0], "stopped") {
+		t.Fatalf("expected a failure naming the differing line, got %q", tb.errors)
+	}
+}
+
+func TestBridge_RoutesEntriesToTheirTest(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	logger.Init("development", true)
+	defer logger.Init("development", true)
+	rec := Capture(t)
+
+	a, b := &fakeTB{TB: t}, &fakeTB{TB: t}
+	ctxA, ctxB := Bridge(a), Bridge(b)
+	logger.InfoContext(ctxA, "for a", "n", 1)
+	logger.WarnContext(ctxB, "for b")
+	logger.Errorf("for everyone")
+	a.finish()
+	logger.InfoContext(ctxA, "after a ended")
+	b.finish()
+
+	if got := a.logged(); len(got) != 2 || !strings.HasPrefix(got[0], "[INFO] [logtest.TestBridge_RoutesEntriesToTheirTest:") || !strings.HasSuffix(got[0], "] for a n=1") || !strings.HasSuffix(got[1], "] for everyone") {
+		t.Fatalf("unexpected entries for a: %q", got)
+	}
+	if got := b.logged(); len(got) != 2 || !strings.HasSuffix(got[0], "] for b") || !strings.HasSuffix(got[1], "] for everyone") {
+		t.Fatalf("unexpected entries for b: %q", got)
+	}
+	if rec.Plain() != "" {
+		t.Fatalf("console output should be discarded while bridged, got %q", rec.Plain())
+	}
+	logger.Infof("console again")
+	if !strings.Contains(rec.Plain(), "console again") {
+		t.Fatalf("console output should be restored after the last bridged test, got %q", rec.Plain())
+	}
+}
+
+func TestWithLevel(t *testing.T) {
+	t.Setenv("LOGGER_LEVELS", "")
+	t.Setenv("NO_COLOR", "1")
+	logger.Init("development", false)
+	defer logger.Init("development", true)
+	rec := Capture(t)
+
+	logger.Debugf("hidden")
+	ft := &fakeTB{TB: t}
+	WithLevel(ft, logger.DebugLevel)
+	logger.Debugf("cache miss")
+	ft.finish()
+	logger.Debugf("hidden again")
+
+	if got := rec.Plain(); !strings.Contains(got, "[DEBUG]") || !strings.HasSuffix(got, "cache miss\n") || strings.Contains(got, "hidden") {
+		t.Errorf("expected only the DEBUG entry logged under WithLevel, got %q", got)
+	}
+
+	rec.Reset()
+	ft = &fakeTB{TB: t}
+	WithLevel(ft, logger.ErrorLevel)
+	logger.Warnf("quiet")
+	logger.Errorf("loud")
+	ft.finish()
+	logger.Warnf("back")
+	if got := rec.Plain(); strings.Contains(got, "quiet") || !strings.Contains(got, "loud") || !strings.Contains(got, "back") {
+		t.Errorf("expected WARN off only under WithLevel(ErrorLevel), got %q", got)
+	}
+}
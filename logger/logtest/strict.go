@@ -0,0 +1,95 @@
+package logtest
+
+import (
+	"io"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mordilloSan/go_logger/logger"
+)
+
+// Strict fails a test that logs an ERROR or FATAL entry it did not expect.
+type Strict struct {
+	mu       sync.Mutex
+	expected []*regexp.Regexp
+	logged   []string
+}
+
+// FailOnError makes t fail when an ERROR or FATAL entry is written to the
+// console before the test ends, unless the entry matches a pattern passed
+// to Expect, so error paths swallowed by the code under test show up in CI:
+//
+//	func TestSync(t *testing.T) {
+//	    strict := logtest.FailOnError(t)
+//	    strict.Expect(`retrying .* attempt=1`)
+//	    sync()
+//	}
+//
+// Entries are still written to the console or a Recorder. Call it after
+// logger.Init and Capture, which replace the writers it wraps. Unexpected
+// entries are reported when the test ends.
+func FailOnError(t testing.TB) *Strict {
+	t.Helper()
+	s := &Strict{}
+	loggers := []*log.Logger{logger.Error, logger.Fatal}
+	saved := make([]io.Writer, len(loggers))
+	for i, l := range loggers {
+		saved[i] = l.Writer()
+		l.SetOutput(strictWriter{s, saved[i]})
+	}
+	t.Cleanup(func() {
+		for i, l := range loggers {
+			l.SetOutput(saved[i])
+		}
+		for _, line := range s.unexpected() {
+			t.Errorf("unexpected log entry: %s", line)
+		}
+	})
+	return s
+}
+
+// Expect allows ERROR and FATAL entries whose plain text matches the
+// regular expression pattern. It panics if pattern does not compile.
+func (s *Strict) Expect(pattern string) {
+	re := regexp.MustCompile(pattern)
+	s.mu.Lock()
+	s.expected = append(s.expected, re)
+	s.mu.Unlock()
+}
+
+// unexpected returns the logged entries no expectation matches.
+func (s *Strict) unexpected() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for _, line := range s.logged {
+		allowed := false
+		for _, re := range s.expected {
+			if re.MatchString(line) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// strictWriter records each entry written to an ERROR or FATAL logger and
+// passes it on.
+type strictWriter struct {
+	s    *Strict
+	next io.Writer
+}
+
+func (w strictWriter) Write(p []byte) (int, error) {
+	w.s.mu.Lock()
+	w.s.logged = append(w.s.logged, strings.TrimRight(StripANSI(string(p)), "\n"))
+	w.s.mu.Unlock()
+	return w.next.Write(p)
+}
@@ -0,0 +1,45 @@
+package logger
+
+// entryTextTime is the timestamp layout of Entry.MarshalText, the classic
+// layout with the microseconds ParseLine reads back.
+const entryTextTime = DefaultTimeFormat + ".000000"
+
+// MarshalJSON encodes e as the single-line JSON object written by
+// JSONEncoder, so an Entry can be embedded in other JSON documents.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	return []byte(encodeJSON(&e)), nil
+}
+
+// UnmarshalJSON decodes an object written by MarshalJSON or JSONEncoder,
+// keeping the order of the fields. Field values keep their JSON types, with
+// numbers as json.Number.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	parsed, err := parseJSONLine(string(data))
+	if err != nil {
+		return err
+	}
+	*e = *parsed
+	return nil
+}
+
+// MarshalText encodes e as a "date time [LEVEL] [caller] message key=value"
+// line, omitting the timestamp when Time is zero. Times are written in the
+// local time zone with microseconds.
+func (e Entry) MarshalText() ([]byte, error) {
+	enc := TextEncoder{}
+	if !e.Time.IsZero() {
+		enc.TimeFormat, e.Time = entryTextTime, e.Time.Local()
+	}
+	return enc.Encode(&e)
+}
+
+// UnmarshalText parses a line with ParseLine. Text lines do not keep field
+// types, so field values decode as strings.
+func (e *Entry) UnmarshalText(text []byte) error {
+	parsed, err := ParseLine(string(text))
+	if err != nil {
+		return err
+	}
+	*e = *parsed
+	return nil
+}
@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Metric derives a Prometheus counter from log entries, replacing a
+// separate log-tailing exporter such as mtail:
+//
+//	logger.AddMetric(logger.Metric{
+//	    Name:     "db_timeouts_total",
+//	    Help:     "Database timeouts.",
+//	    MinLevel: logger.ErrorLevel,
+//	    Fields:   map[string]string{"error_code": "DB_TIMEOUT"},
+//	})
+//	http.Handle("/metrics", logger.MetricsHandler())
+//
+// Conditions match like those of a Route. Entries are counted when they are
+// written, so entries filtered by LOGGER_LEVELS or discarded by a
+// RequestBuffer are not counted.
+type Metric struct {
+	// Name is the Prometheus metric name, e.g. "db_timeouts_total".
+	Name string
+	// Help is the metric description.
+	Help string
+	// MinLevel is the lowest level counted.
+	MinLevel Level
+	// Logger matches entries from this logger or its children.
+	Logger string
+	// Package matches the caller's package name.
+	Package string
+	// Fields match entries carrying each key with the given value.
+	Fields map[string]string
+	// Labels lists field keys whose values become labels, e.g. "error_code"
+	// to count errors per code. Entries without the field get an empty label.
+	Labels []string
+}
+
+// metricState holds the counters of one Metric, keyed by label values.
+type metricState struct {
+	Metric
+	match  Route
+	series map[string]*metricSeries
+}
+
+type metricSeries struct {
+	values []string
+	count  uint64
+}
+
+var (
+	// metrics are evaluated by writeEntry for every written entry
+	metrics []*metricState
+
+	metricNamePattern  = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	metricLabelPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// AddMetric registers m. It returns an error for invalid or duplicate metric
+// names and invalid label names. Counters live for the life of the process.
+func AddMetric(m Metric) error {
+	if !metricNamePattern.MatchString(m.Name) {
+		return fmt.Errorf("logger: invalid metric name %q", m.Name)
+	}
+	for _, l := range m.Labels {
+		if !metricLabelPattern.MatchString(l) || strings.HasPrefix(l, "__") {
+			return fmt.Errorf("logger: invalid label name %q for metric %s", l, m.Name)
+		}
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	for _, ms := range metrics {
+		if ms.Name == m.Name {
+			return fmt.Errorf("logger: metric %s already registered", m.Name)
+		}
+	}
+	m.Labels = slices.Clone(m.Labels)
+	metrics = append(metrics, &metricState{
+		Metric: m,
+		match:  Route{MinLevel: m.MinLevel, Logger: m.Logger, Package: m.Package, Fields: m.Fields},
+		series: make(map[string]*metricSeries),
+	})
+	return nil
+}
+
+// countEntry increments the counters matching e. Callers must hold logMutex.
+func countEntry(e *Entry) {
+	for _, ms := range metrics {
+		if !ms.match.matches(e) {
+			continue
+		}
+		values := make([]string, len(ms.Labels))
+		for i, l := range ms.Labels {
+			values[i], _ = fieldValue(e.Fields, l)
+		}
+		key := strings.Join(values, "\xff")
+		s := ms.series[key]
+		if s == nil {
+			s = &metricSeries{values: values}
+			ms.series[key] = s
+		}
+		s.count++
+	}
+}
+
+// WriteMetrics writes every registered metric to w in the Prometheus text
+// exposition format.
+func WriteMetrics(w io.Writer) error {
+	var b bytes.Buffer
+	logMutex.Lock()
+	for _, ms := range metrics {
+		if ms.Help != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n", ms.Name, escapeMetricText(ms.Help, false))
+		}
+		fmt.Fprintf(&b, "# TYPE %s counter\n", ms.Name)
+		if len(ms.series) == 0 && len(ms.Labels) == 0 {
+			fmt.Fprintf(&b, "%s 0\n", ms.Name)
+		}
+		keys := make([]string, 0, len(ms.series))
+		for k := range ms.series {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			s := ms.series[k]
+			b.WriteString(ms.Name)
+			if len(ms.Labels) > 0 {
+				b.WriteByte('{')
+				for i, l := range ms.Labels {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					fmt.Fprintf(&b, "%s=\"%s\"", l, escapeMetricText(s.values[i], true))
+				}
+				b.WriteByte('}')
+			}
+			fmt.Fprintf(&b, " %d\n", s.count)
+		}
+	}
+	logMutex.Unlock()
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// MetricsHandler serves WriteMetrics for Prometheus scrapes.
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteMetrics(w)
+	})
+}
+
+// escapeMetricText escapes backslashes and newlines, plus double quotes in
+// label values.
+func escapeMetricText(s string, quote bool) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	if quote {
+		s = strings.ReplaceAll(s, `"`, `\"`)
+	}
+	return s
+}
@@ -0,0 +1,344 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MiddlewareConfig configures Middleware.
+type MiddlewareConfig struct {
+	// Buffer attaches a RequestBuffer to each request context so DEBUG/INFO
+	// entries logged with the *Context functions are only written when the
+	// request is flushed (5xx responses or one of the rules below).
+	Buffer bool
+	// BufferSize limits buffered entries per request (0 uses DefaultRequestBufferSize).
+	BufferSize int
+	// FlushStatus lists extra status codes (e.g. 401, 429) that flush the buffer.
+	FlushStatus []int
+	// FlushSlowerThan flushes the buffer for requests taking at least this long.
+	FlushSlowerThan time.Duration
+	// FlushPercentile flushes the buffer for requests slower than this latency
+	// percentile of recent traffic, e.g. 0.99 for p99. Zero disables it.
+	FlushPercentile float64
+	// Baggage reads the W3C baggage header into the request context, so
+	// entries logged with the *Context functions, the access log entry,
+	// and requests sent through BaggageTransport carry the caller's
+	// correlation fields.
+	Baggage bool
+	// RoutePatterns logs the matched route pattern, such as /users/{id},
+	// as the path and message, and the request path as raw_path, keeping
+	// cardinality low for metrics derived from logs. The pattern comes from
+	// RoutePattern or from a net/http ServeMux inside the middleware.
+	// Requests matching no pattern keep their path.
+	RoutePatterns bool
+	// RoutePattern returns the pattern for other routers, called after the
+	// handler returned. Setting it enables RoutePatterns:
+	//
+	//	// chi, with the middleware added by r.Use
+	//	RoutePattern: func(r *http.Request) string { return chi.RouteContext(r.Context()).RoutePattern() }
+	RoutePattern func(*http.Request) string
+	// ClientIP logs the client address as client_ip. It is the connection's
+	// remote address unless that is one of TrustedProxies, in which case
+	// X-Forwarded-For is read from the right, skipping trusted proxies, or
+	// X-Real-IP is used when there is no X-Forwarded-For.
+	ClientIP bool
+	// TrustedProxies lists the load balancers and proxies, as CIDRs or
+	// single addresses, whose forwarding headers ClientIP believes.
+	TrustedProxies []string
+	// Sizes logs the request body bytes the handler read as request_bytes
+	// and the response body bytes it wrote as response_bytes, for bandwidth
+	// accounting from logs. Zero sizes are left out.
+	Sizes bool
+	// SlowThreshold, when positive, logs requests taking at least this
+	// long at WARN, or ERROR for 5xx responses, with slow=true, so latency
+	// problems stand out even when the status is 200.
+	SlowThreshold time.Duration
+	// SkipPaths and SkipUserAgents select requests, such as health checks
+	// and probes, whose access entries are dropped unless they failed with
+	// a 5xx status. Paths match exactly, or by prefix when they end in "*";
+	// user agents match as substrings, e.g. "kube-probe".
+	SkipPaths      []string
+	SkipUserAgents []string
+	// SkipSample, when positive, still logs one in every SkipSample skipped
+	// requests, so probes remain visible at a fraction of the volume.
+	SkipSample int
+}
+
+// Middleware returns net/http middleware that writes one access log entry per
+// request (method, path, status, duration) at a level chosen from the status
+// code like Api, and optionally buffers per-request logs with tail sampling.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	handler := logger.Middleware(logger.MiddlewareConfig{
+//	    Buffer:          true,
+//	    FlushPercentile: 0.99,
+//	})(mux)
+func Middleware(cfg MiddlewareConfig) func(http.Handler) http.Handler {
+	tracker := newLatencyTracker(latencyWindow)
+	trusted := parseTrustedProxies(cfg.TrustedProxies)
+	var skipped atomic.Int64
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx := r.Context()
+			if cfg.Baggage {
+				if members := parseBaggage(r.Header.Get(BaggageHeader)); len(members) > 0 {
+					ctx = context.WithValue(ctx, ctxBaggageKey{}, members)
+				}
+			}
+			var rb *RequestBuffer
+			if cfg.Buffer {
+				rb = NewRequestBuffer(cfg.BufferSize)
+				ctx = ContextWithBuffer(ctx, rb)
+			}
+			rec := &statusRecorder{ResponseWriter: w}
+			req := r.WithContext(ctx)
+			var body *countingBody
+			if cfg.Sizes && r.Body != nil {
+				body = &countingBody{ReadCloser: r.Body}
+				req.Body = body
+			}
+			next.ServeHTTP(rec, req)
+
+			status := rec.statusCode()
+			duration := time.Since(start)
+			slowerThanPercentile := tracker.observe(duration, cfg.FlushPercentile)
+
+			access := AccessRecord{
+				Method:        r.Method,
+				Path:          r.URL.Path,
+				Route:         cfg.route(req),
+				Status:        status,
+				Duration:      duration,
+				Hijacked:      rec.hijacked,
+				SlowThreshold: cfg.SlowThreshold,
+			}
+			if cfg.ClientIP {
+				access.ClientIP = clientIP(r, trusted)
+			}
+			if cfg.Sizes {
+				access.ResponseBytes = rec.written
+				if body != nil {
+					access.RequestBytes = body.n
+				}
+			}
+			if status < 500 && cfg.skip(r) {
+				access.Skip = cfg.SkipSample <= 0 || (skipped.Add(1)-1)%int64(cfg.SkipSample) != 0
+			}
+			level, msg, keyvals, ok := access.entry()
+			if rb != nil {
+				if reason := cfg.flushReason(status, duration, slowerThanPercentile); reason != "" {
+					rb.Flush()
+					keyvals = append(keyvals, "flush", reason)
+				}
+				rb.Discard()
+			}
+			if ok {
+				logAccess(ctx, level, msg, keyvals)
+			}
+		})
+	}
+}
+
+// route returns the route pattern r matched, or "" when RoutePatterns is
+// off or no pattern is known.
+func (cfg MiddlewareConfig) route(r *http.Request) string {
+	if cfg.RoutePattern != nil {
+		return cfg.RoutePattern(r)
+	}
+	if !cfg.RoutePatterns || r.Pattern == "" {
+		return ""
+	}
+	// a ServeMux pattern is [METHOD ][HOST]/[PATH]
+	pattern := r.Pattern
+	if _, rest, ok := strings.Cut(pattern, " "); ok {
+		pattern = strings.TrimLeft(rest, " \t")
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
+
+// skip reports whether r matches SkipPaths or SkipUserAgents.
+func (cfg MiddlewareConfig) skip(r *http.Request) bool {
+	for _, p := range cfg.SkipPaths {
+		if prefix, ok := strings.CutSuffix(p, "*"); (ok && strings.HasPrefix(r.URL.Path, prefix)) || p == r.URL.Path {
+			return true
+		}
+	}
+	ua := r.UserAgent()
+	for _, s := range cfg.SkipUserAgents {
+		if s != "" && strings.Contains(ua, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// flushReason reports why a request's buffer should be flushed, or "".
+func (cfg MiddlewareConfig) flushReason(status int, duration time.Duration, slowerThanPercentile bool) string {
+	switch {
+	case status >= 500:
+		return "error"
+	case slices.Contains(cfg.FlushStatus, status):
+		return "status"
+	case cfg.FlushSlowerThan > 0 && duration >= cfg.FlushSlowerThan:
+		return "slow"
+	case slowerThanPercentile:
+		return "percentile"
+	}
+	return ""
+}
+
+// logAccess writes an access log entry tagged with the "http" caller.
+// The request buffer is bypassed so the entry is never held back.
+func logAccess(ctx context.Context, level Level, msg string, keyvals []any) {
+	if !isLevelEnabled(level) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	ctx = context.WithValue(ctx, ctxBufferKey{}, (*RequestBuffer)(nil))
+	outputContext(ctx, loggerFor(level), level, "http", msg, withContextFields(ctx, keyvals))
+}
+
+// statusRecorder captures the status code and body size written by a
+// handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status   int
+	written  int64
+	hijacked bool
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.written += int64(n)
+	return n, err
+}
+
+// Flush sends buffered data to the client, for handlers streaming
+// responses such as server-sent events. It does nothing when the
+// underlying writer cannot flush.
+func (s *statusRecorder) Flush() {
+	f, ok := s.ResponseWriter.(http.Flusher)
+	if !ok {
+		return
+	}
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	f.Flush()
+}
+
+// Hijack hands the connection over to the handler, as for websockets. It
+// fails with http.ErrNotSupported when the underlying writer cannot be
+// hijacked. A hijacked request is logged with status 101 unless a status
+// was written before, and with hijacked=true.
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		s.hijacked = true
+		if s.status == 0 {
+			s.status = http.StatusSwitchingProtocols
+		}
+	}
+	return conn, rw, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+func (s *statusRecorder) statusCode() int {
+	if s.status == 0 {
+		return http.StatusOK
+	}
+	return s.status
+}
+
+// countingBody counts the bytes read from a request body.
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// latencyWindow is the number of recent requests used for percentile flushing.
+const latencyWindow = 1000
+
+// latencyTracker keeps a window of recent request durations and caches the
+// requested percentile, recomputing it every latencyRecompute samples.
+type latencyTracker struct {
+	mu        sync.Mutex
+	samples   []time.Duration
+	next      int
+	seen      int
+	threshold time.Duration
+}
+
+const (
+	latencyRecompute  = 100
+	latencyMinSamples = 100
+)
+
+func newLatencyTracker(size int) *latencyTracker {
+	return &latencyTracker{samples: make([]time.Duration, 0, size)}
+}
+
+// observe records d and reports whether it exceeds percentile p of the
+// window. It reports false until enough samples have been collected.
+func (t *latencyTracker) observe(d time.Duration, p float64) bool {
+	if p <= 0 || p >= 1 {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	slower := t.seen >= latencyMinSamples && d > t.threshold
+	if len(t.samples) < cap(t.samples) {
+		t.samples = append(t.samples, d)
+	} else {
+		t.samples[t.next] = d
+		t.next = (t.next + 1) % len(t.samples)
+	}
+	t.seen++
+	if t.seen%latencyRecompute == 0 {
+		sorted := slices.Clone(t.samples)
+		slices.Sort(sorted)
+		t.threshold = sorted[int(p*float64(len(sorted)-1))]
+	}
+	return slower
+}
@@ -0,0 +1,44 @@
+package logger
+
+// SetMinLevel enables level and the levels above it and disables the rest,
+// like LOGGER_LEVELS=">=level" set after Init. Setting DebugLevel also turns
+// DEBUG output on in development mode without verbose, writing it to the
+// INFO console writer and to sinks. The returned function restores the
+// previous levels and DEBUG output, e.g. at the end of a test:
+//
+//	defer logger.SetMinLevel(logger.DebugLevel)()
+func SetMinLevel(level Level) (restore func()) {
+	if !globalDisabled.Load() {
+		ensureInit()
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	savedLevels, savedDebugOutput := enabledLevels(), debugOutput
+	debug := Debug
+	savedOut, savedPrefix, savedFlags := debug.Writer(), debug.Prefix(), debug.Flags()
+
+	levels := make(map[Level]bool, len(savedLevels))
+	for l := DebugLevel; l <= FatalLevel; l++ {
+		levels[l] = l >= level
+	}
+	setEnabledLevels(levels)
+	if level == DebugLevel && !debugOutput {
+		debugOutput = true
+		debug.SetOutput(Info.Writer())
+		if !jsonOutput && layout == nil && journalStyle == JournalDefault {
+			debug.SetPrefix(levelPrefix(DebugLevel, textFormat.color))
+			debug.SetFlags(textFormat.consoleFlags)
+		}
+	}
+
+	return func() {
+		logMutex.Lock()
+		defer logMutex.Unlock()
+		setEnabledLevels(savedLevels)
+		debugOutput = savedDebugOutput
+		debug.SetOutput(savedOut)
+		debug.SetPrefix(savedPrefix)
+		debug.SetFlags(savedFlags)
+	}
+}
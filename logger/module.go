@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// Field names used by Config.CallerModule.
+const (
+	ModuleKey        = "module"
+	ModuleVersionKey = "module_version"
+)
+
+// callerModule is Config.CallerModule
+var callerModule bool
+
+// buildModules returns the main module path and the dependencies of the
+// binary, read once.
+var buildModules = sync.OnceValues(func() (string, []*debug.Module) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", nil
+	}
+	return info.Main.Path, info.Deps
+})
+
+// callerModuleFields returns the module and module_version fields for an
+// entry logged by caller from a dependency, or nil when caller belongs to
+// the main module, the standard library, or an unknown module.
+func callerModuleFields(caller string) []any {
+	site, ok := callerSites.Load(caller)
+	if !ok {
+		return nil
+	}
+	main, deps := buildModules()
+	path, version := moduleFor(packagePath(site.(callerSite).function), main, deps)
+	if path == "" {
+		return nil
+	}
+	return []any{ModuleKey, path, ModuleVersionKey, version}
+}
+
+// packagePath returns the import path of the package declaring function,
+// a full name such as "example.com/pkg.(*T).Method".
+func packagePath(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	if dot := strings.IndexByte(function[slash+1:], '.'); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
+
+// moduleFor returns the path and version of the dependency in deps
+// providing pkg, or "" when pkg belongs to main or to none of them.
+// Replaced modules report the replacement's version.
+func moduleFor(pkg, main string, deps []*debug.Module) (path, version string) {
+	if within(pkg, main) {
+		return "", ""
+	}
+	for _, m := range deps {
+		if within(pkg, m.Path) && len(m.Path) > len(path) {
+			path, version = m.Path, m.Version
+			if m.Replace != nil && m.Replace.Version != "" {
+				version = m.Replace.Version
+			}
+		}
+	}
+	return path, version
+}
+
+// within reports whether pkg is module or one of its subpackages.
+func within(pkg, module string) bool {
+	return module != "" && (pkg == module || strings.HasPrefix(pkg, module+"/"))
+}
@@ -0,0 +1,306 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultMQTTBufferSize is the number of messages an MQTTSink holds while
+// the broker is unreachable when MQTTConfig.BufferSize is zero.
+const DefaultMQTTBufferSize = 1000
+
+// MQTTConfig configures NewMQTTSink.
+type MQTTConfig struct {
+	// Address is the broker host:port.
+	Address string
+	// TLSConfig enables TLS when set.
+	TLSConfig *tls.Config
+	// ClientID defaults to "go_logger-<pid>".
+	ClientID string
+	Username string
+	// Password requires Username.
+	Password string
+	// Topic entries are published to. Defaults to "logs".
+	Topic string
+	// QoS is 0 (at most once) or 1 (at least once, waits for PUBACK).
+	QoS byte
+	// WillTopic and WillMessage set the Last Will and Testament the broker
+	// publishes if the connection drops without a clean disconnect.
+	WillTopic   string
+	WillMessage string
+	WillRetain  bool
+	// BufferSize is the number of messages held while offline; the oldest are
+	// dropped when full. Defaults to DefaultMQTTBufferSize.
+	BufferSize int
+	// Encoder defaults to JSONEncoder.
+	Encoder Encoder
+	// Timeout bounds dialing and each broker round trip. Defaults to 5 seconds.
+	Timeout time.Duration
+	// DNSRefresh, when set, resolves the broker host again when its DNS
+	// records expire, at least this often, and rotates connections across
+	// its addresses, reconnecting once a connection is older than
+	// DNSRefresh.
+	DNSRefresh time.Duration
+}
+
+// MQTTSink publishes entries to an MQTT 3.1.1 broker. While the broker is
+// unreachable, messages are held in memory and published in order once a
+// reconnect succeeds. Reconnects are attempted at most once per second.
+type MQTTSink struct {
+	cfg        MQTTConfig
+	conn       net.Conn
+	r          *bufio.Reader
+	packetID   uint16
+	pending    [][]byte
+	lastDial   time.Time
+	retryEvery time.Duration
+	dialer     *rotatingDialer
+}
+
+// NewMQTTSink returns a sink publishing to the broker at cfg.Address. The
+// connection is made on the first entry, so devices that start offline keep
+// their logs until the broker is reachable.
+func NewMQTTSink(cfg MQTTConfig) (*MQTTSink, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("logger: mqtt sink requires an address")
+	}
+	if cfg.QoS > 1 {
+		return nil, fmt.Errorf("logger: unsupported mqtt QoS %d", cfg.QoS)
+	}
+	if cfg.Password != "" && cfg.Username == "" {
+		// MQTT 3.1.1 only allows a password after a user name
+		return nil, errors.New("logger: mqtt password requires a username")
+	}
+	if cfg.ClientID == "" {
+		cfg.ClientID = "go_logger-" + strconv.Itoa(os.Getpid())
+	}
+	if cfg.Topic == "" {
+		cfg.Topic = "logs"
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultMQTTBufferSize
+	}
+	if cfg.Encoder == nil {
+		cfg.Encoder = JSONEncoder{}
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	dialer, err := newRotatingDialer(cfg.Address, cfg.DNSRefresh)
+	if err != nil {
+		return nil, err
+	}
+	return &MQTTSink{cfg: cfg, retryEvery: time.Second, dialer: dialer}, nil
+}
+
+// WriteEntry publishes e, or holds it if the broker is unreachable.
+func (s *MQTTSink) WriteEntry(e *Entry) error {
+	data, err := s.cfg.Encoder.Encode(e)
+	if err != nil {
+		return err
+	}
+	if len(s.pending) >= s.cfg.BufferSize {
+		s.pending = s.pending[1:]
+	}
+	s.pending = append(s.pending, data)
+
+	if s.conn != nil && s.dialer.expired() {
+		// move on to the next address with a clean disconnect
+		s.Close()
+	}
+	if s.conn == nil {
+		if time.Since(s.lastDial) < s.retryEvery {
+			return errors.New("logger: mqtt broker offline")
+		}
+		s.lastDial = time.Now()
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+	for len(s.pending) > 0 {
+		if err := s.publish(s.pending[0]); err != nil {
+			s.disconnect()
+			return err
+		}
+		s.pending = s.pending[1:]
+	}
+	return nil
+}
+
+// Close sends DISCONNECT, so the broker does not publish the will message.
+// Messages still held offline are dropped.
+func (s *MQTTSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(s.cfg.Timeout))
+	s.conn.Write([]byte{0xE0, 0x00})
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// Remote reports true: brokers are reached over the network.
+func (s *MQTTSink) Remote() bool { return true }
+
+func (s *MQTTSink) disconnect() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+func (s *MQTTSink) connect() error {
+	var conn net.Conn
+	var err error
+	switch {
+	case s.dialer != nil && s.cfg.TLSConfig != nil:
+		conn, err = s.dialer.dial("tls", s.cfg.TLSConfig, s.cfg.Timeout)
+	case s.dialer != nil:
+		conn, err = s.dialer.dial("tcp", nil, s.cfg.Timeout)
+	case s.cfg.TLSConfig != nil:
+		dialer := &net.Dialer{Timeout: s.cfg.Timeout}
+		conn, err = tls.DialWithDialer(dialer, "tcp", s.cfg.Address, s.cfg.TLSConfig)
+	default:
+		dialer := &net.Dialer{Timeout: s.cfg.Timeout}
+		conn, err = dialer.Dial("tcp", s.cfg.Address)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keep alive is disabled (0) since the sink only writes when logging.
+	flags := byte(0x02) // clean session
+	var payload []byte
+	payload = appendMQTTString(payload, s.cfg.ClientID)
+	if s.cfg.WillTopic != "" {
+		flags |= 0x04 | s.cfg.QoS<<3
+		if s.cfg.WillRetain {
+			flags |= 0x20
+		}
+		payload = appendMQTTString(payload, s.cfg.WillTopic)
+		payload = appendMQTTString(payload, s.cfg.WillMessage)
+	}
+	if s.cfg.Username != "" {
+		flags |= 0x80
+		payload = appendMQTTString(payload, s.cfg.Username)
+	}
+	if s.cfg.Password != "" {
+		flags |= 0x40
+		payload = appendMQTTString(payload, s.cfg.Password)
+	}
+	body := appendMQTTString(nil, "MQTT")
+	body = append(body, 4, flags, 0, 0)
+	body = append(body, payload...)
+
+	conn.SetDeadline(time.Now().Add(s.cfg.Timeout))
+	if _, err := conn.Write(mqttPacket(0x10, body)); err != nil {
+		conn.Close()
+		return err
+	}
+	r := bufio.NewReader(conn)
+	typ, ack, err := readMQTTPacket(r)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if typ>>4 != 2 || len(ack) != 2 {
+		conn.Close()
+		return errors.New("logger: mqtt broker sent no CONNACK")
+	}
+	if ack[1] != 0 {
+		conn.Close()
+		return fmt.Errorf("logger: mqtt connection refused (code %d)", ack[1])
+	}
+	s.conn, s.r = conn, r
+	return nil
+}
+
+// publish sends one PUBLISH and, for QoS 1, waits for its PUBACK.
+func (s *MQTTSink) publish(data []byte) error {
+	body := appendMQTTString(nil, s.cfg.Topic)
+	if s.cfg.QoS == 1 {
+		s.packetID++
+		if s.packetID == 0 {
+			s.packetID = 1
+		}
+		body = append(body, byte(s.packetID>>8), byte(s.packetID))
+	}
+	body = append(body, data...)
+
+	s.conn.SetDeadline(time.Now().Add(s.cfg.Timeout))
+	if _, err := s.conn.Write(mqttPacket(0x30|s.cfg.QoS<<1, body)); err != nil {
+		return err
+	}
+	if s.cfg.QoS == 0 {
+		return nil
+	}
+	for {
+		typ, ack, err := readMQTTPacket(s.r)
+		if err != nil {
+			return err
+		}
+		if typ>>4 == 4 && len(ack) == 2 && uint16(ack[0])<<8|uint16(ack[1]) == s.packetID {
+			return nil
+		}
+	}
+}
+
+// mqttPacket prefixes body with a fixed header of the given first byte and
+// the variable-length remaining length.
+func mqttPacket(header byte, body []byte) []byte {
+	pkt := []byte{header}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		pkt = append(pkt, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(pkt, body...)
+}
+
+func appendMQTTString(b []byte, s string) []byte {
+	b = append(b, byte(len(s)>>8), byte(len(s)))
+	return append(b, s...)
+}
+
+// readMQTTPacket reads one packet, returning its first header byte and body.
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, shift := 0, 0
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(b&0x7F) << shift
+		if b&0x80 == 0 {
+			break
+		}
+		shift += 7
+		if shift > 21 {
+			return 0, nil, errors.New("logger: malformed mqtt packet length")
+		}
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
@@ -0,0 +1,423 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// MsgpackEncoder renders entries as MessagePack maps with the same keys as
+// JSONEncoder: time (a MessagePack timestamp), level, caller, and msg,
+// followed by the entry fields. Numbers, booleans, byte slices, times,
+// slices, and maps keep their types; structs are converted as they would be
+// for JSON, and other values become strings. Records are typically half the
+// size of JSON, which matters for high-rate structured logs on the network
+// and on disk:
+//
+//	sink, err := logger.NewFileSink("/var/log/app/entries.msgpack", logger.MsgpackEncoder{})
+//
+// Stream sinks write each record with a 4-byte big-endian length prefix
+// instead of a trailing newline.
+type MsgpackEncoder struct{}
+
+func (MsgpackEncoder) Encode(e *Entry) ([]byte, error) {
+	n := 4
+	for i := 0; i+1 < len(e.Fields); i += 2 {
+		if _, ok := e.Fields[i].(string); ok {
+			n++
+		}
+	}
+	b := appendMsgpackMapHeader(nil, n)
+	b = appendMsgpackString(b, "time")
+	b = appendMsgpackTime(b, e.Time)
+	b = appendMsgpackString(b, "level")
+	b = appendMsgpackString(b, levelNames[e.Level])
+	b = appendMsgpackString(b, "caller")
+	b = appendMsgpackString(b, e.Caller)
+	b = appendMsgpackString(b, "msg")
+	b = appendMsgpackString(b, e.Message)
+	for i := 0; i+1 < len(e.Fields); i += 2 {
+		key, ok := e.Fields[i].(string)
+		if !ok {
+			continue
+		}
+		b = appendMsgpackString(b, key)
+		b = appendMsgpackValue(b, e.Fields[i+1])
+	}
+	return b, nil
+}
+
+func (MsgpackEncoder) binaryFormat() string { return "msgpack" }
+
+// binaryEncoder is implemented by encoders whose output may contain
+// newlines, such as MsgpackEncoder. binaryFormat names the encoding in
+// binary log file headers.
+type binaryEncoder interface {
+	binaryFormat() string
+}
+
+// appendFrame appends data to b framed for a byte stream: newline-terminated
+// for text encoders, behind a 4-byte big-endian length for binary ones.
+func appendFrame(b []byte, enc Encoder, data []byte) []byte {
+	if _, ok := enc.(binaryEncoder); ok {
+		b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+		return append(b, data...)
+	}
+	b = append(b, data...)
+	return append(b, '\n')
+}
+
+// appendMsgpackValue appends v, falling back to its JSON form for structs
+// and to fmt.Sprint for values MessagePack cannot represent.
+func appendMsgpackValue(b []byte, v any) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case error:
+		return appendMsgpackString(b, v.Error())
+	case time.Time:
+		return appendMsgpackTime(b, v)
+	case string:
+		return appendMsgpackString(b, v)
+	case []byte:
+		return appendMsgpackBinary(b, v)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendMsgpackInt(b, rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendMsgpackUint(b, rv.Uint())
+	case reflect.Float32:
+		b = append(b, 0xca)
+		return binary.BigEndian.AppendUint32(b, math.Float32bits(float32(rv.Float())))
+	case reflect.Float64:
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(rv.Float()))
+	case reflect.String:
+		return appendMsgpackString(b, rv.String())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return append(b, 0xc0)
+		}
+		b = appendMsgpackArrayHeader(b, rv.Len())
+		for i := range rv.Len() {
+			b = appendMsgpackValue(b, rv.Index(i).Interface())
+		}
+		return b
+	case reflect.Map:
+		if rv.IsNil() {
+			return append(b, 0xc0)
+		}
+		b = appendMsgpackMapHeader(b, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			b = appendMsgpackString(b, fmt.Sprint(iter.Key().Interface()))
+			b = appendMsgpackValue(b, iter.Value().Interface())
+		}
+		return b
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return append(b, 0xc0)
+		}
+		return appendMsgpackValue(b, rv.Elem().Interface())
+	case reflect.Struct:
+		var decoded any
+		if data, err := json.Marshal(v); err == nil && json.Unmarshal(data, &decoded) == nil {
+			return appendMsgpackValue(b, decoded)
+		}
+	}
+	return appendMsgpackString(b, fmt.Sprint(v))
+}
+
+func appendMsgpackInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendMsgpackUint(b, uint64(v))
+	case v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(v))
+}
+
+func appendMsgpackUint(b []byte, v uint64) []byte {
+	switch {
+	case v <= 0x7f:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xcf), v)
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+func appendMsgpackBinary(b []byte, p []byte) []byte {
+	switch n := len(p); {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xc5), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xc6), uint32(n))
+	}
+	return append(b, p...)
+}
+
+func appendMsgpackArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xdc), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xdd), uint32(n))
+}
+
+func appendMsgpackMapHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xde), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xdf), uint32(n))
+}
+
+// appendMsgpackTime appends t as a timestamp extension (type -1), in the
+// 64-bit form when the seconds fit in 34 bits.
+func appendMsgpackTime(b []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	if sec >= 0 && sec < 1<<34 {
+		b = append(b, 0xd7, 0xff)
+		return binary.BigEndian.AppendUint64(b, nsec<<34|uint64(sec))
+	}
+	b = append(b, 0xc7, 12, 0xff)
+	b = binary.BigEndian.AppendUint32(b, uint32(nsec))
+	return binary.BigEndian.AppendUint64(b, uint64(sec))
+}
+
+// decodeMsgpackEntry decodes a record written by MsgpackEncoder, keeping
+// field order. Integers decode as int64 (uint64 above the int64 range),
+// binary as []byte, timestamps as time.Time, and nested maps as
+// map[string]any.
+func decodeMsgpackEntry(data []byte) (*Entry, error) {
+	d := &msgpackDecoder{b: data}
+	n, ok := d.mapHeader()
+	if !ok {
+		return nil, errors.New("logger: msgpack record is not a map")
+	}
+	e := &Entry{}
+	levelSeen := false
+	for range n {
+		key, ok := d.value().(string)
+		value := d.value()
+		if d.err != nil {
+			break
+		}
+		if !ok {
+			continue
+		}
+		s, _ := value.(string)
+		switch key {
+		case "time":
+			e.Time, _ = value.(time.Time)
+		case "level":
+			level, err := ParseLevel(s)
+			if err != nil {
+				return nil, err
+			}
+			e.Level, levelSeen = level, true
+		case "caller":
+			e.Caller = intern(s)
+		case "msg":
+			e.Message = s
+		default:
+			e.Fields = append(e.Fields, intern(key), value)
+		}
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("logger: bad msgpack record: %w", d.err)
+	}
+	if !levelSeen {
+		return nil, errors.New("logger: msgpack record has no level")
+	}
+	return e, nil
+}
+
+// msgpackDecoder reads MessagePack values from b, recording the first
+// error in err.
+type msgpackDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *msgpackDecoder) next(n int) []byte {
+	if d.err != nil || n < 0 || n > len(d.b) {
+		if d.err == nil {
+			d.err = errors.New("truncated")
+		}
+		return make([]byte, min(max(n, 1), 16))
+	}
+	p := d.b[:n]
+	d.b = d.b[n:]
+	return p
+}
+
+func (d *msgpackDecoder) uint(n int) uint64 {
+	var v uint64
+	for _, c := range d.next(n) {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+func (d *msgpackDecoder) mapHeader() (int, bool) {
+	c := d.next(1)[0]
+	switch {
+	case c&0xf0 == 0x80:
+		return int(c & 0x0f), true
+	case c == 0xde:
+		return int(d.uint(2)), true
+	case c == 0xdf:
+		return int(d.uint(4)), true
+	}
+	return 0, false
+}
+
+func (d *msgpackDecoder) value() any {
+	if d.err != nil {
+		return nil
+	}
+	c := d.next(1)[0]
+	switch {
+	case c <= 0x7f:
+		return int64(c)
+	case c >= 0xe0:
+		return int64(int8(c))
+	case c&0xe0 == 0xa0:
+		return string(d.next(int(c & 0x1f)))
+	case c&0xf0 == 0x90:
+		return d.array(int(c & 0x0f))
+	case c&0xf0 == 0x80:
+		return d.mapValue(int(c & 0x0f))
+	}
+	switch c {
+	case 0xc0:
+		return nil
+	case 0xc2:
+		return false
+	case 0xc3:
+		return true
+	case 0xc4, 0xc5, 0xc6:
+		return bytes.Clone(d.next(int(d.uint(1 << (c - 0xc4)))))
+	case 0xc7, 0xc8, 0xc9:
+		n := int(d.uint(1 << (c - 0xc7)))
+		return d.ext(int8(d.next(1)[0]), d.next(n))
+	case 0xca:
+		return float64(math.Float32frombits(uint32(d.uint(4))))
+	case 0xcb:
+		return math.Float64frombits(d.uint(8))
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v := d.uint(1 << (c - 0xcc))
+		if v > math.MaxInt64 {
+			return v
+		}
+		return int64(v)
+	case 0xd0:
+		return int64(int8(d.uint(1)))
+	case 0xd1:
+		return int64(int16(d.uint(2)))
+	case 0xd2:
+		return int64(int32(d.uint(4)))
+	case 0xd3:
+		return int64(d.uint(8))
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		typ := int8(d.next(1)[0])
+		return d.ext(typ, d.next(1<<(c-0xd4)))
+	case 0xd9, 0xda, 0xdb:
+		return string(d.next(int(d.uint(1 << (c - 0xd9)))))
+	case 0xdc, 0xdd:
+		return d.array(int(d.uint(2 << (c - 0xdc))))
+	case 0xde, 0xdf:
+		return d.mapValue(int(d.uint(2 << (c - 0xde))))
+	}
+	d.err = fmt.Errorf("unknown type byte 0x%02x", c)
+	return nil
+}
+
+func (d *msgpackDecoder) array(n int) []any {
+	if n > len(d.b) {
+		d.err = errors.New("truncated")
+		return nil
+	}
+	list := make([]any, n)
+	for i := range list {
+		list[i] = d.value()
+	}
+	return list
+}
+
+func (d *msgpackDecoder) mapValue(n int) map[string]any {
+	if n > len(d.b) {
+		d.err = errors.New("truncated")
+		return nil
+	}
+	m := make(map[string]any, n)
+	for range n {
+		key := d.value()
+		m[fmt.Sprint(key)] = d.value()
+	}
+	return m
+}
+
+// ext decodes timestamps and returns other extensions as raw bytes.
+func (d *msgpackDecoder) ext(typ int8, data []byte) any {
+	if typ != -1 {
+		return bytes.Clone(data)
+	}
+	switch len(data) {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0)
+	case 8:
+		v := binary.BigEndian.Uint64(data)
+		return time.Unix(int64(v&(1<<34-1)), int64(v>>34))
+	case 12:
+		return time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(binary.BigEndian.Uint32(data)))
+	}
+	d.err = errors.New("bad timestamp")
+	return nil
+}
@@ -0,0 +1,270 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Logger is a named logger obtained with Get. Names form a dot-separated
+// hierarchy: "http.client" is a child of "http", which is a child of the
+// root logger "". Entries carry the name in the logger field (LoggerKey),
+// so routes and metrics can select them.
+type Logger struct {
+	name string
+	nop  bool
+}
+
+var (
+	namedMu sync.RWMutex
+	// named caches loggers by name so Get returns the same *Logger
+	named = map[string]*Logger{}
+	// namedLevels holds levels set with SetLevel or Config.LoggerLevels
+	namedLevels = map[string]Level{}
+)
+
+// Get returns the logger called name, creating it on first use. Get("")
+// returns the root logger, whose level applies to every named logger
+// without a more specific one.
+func Get(name string) *Logger {
+	namedMu.RLock()
+	l, ok := named[name]
+	namedMu.RUnlock()
+	if ok {
+		return l
+	}
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	if l, ok = named[name]; !ok {
+		l = &Logger{name: name}
+		named[name] = l
+	}
+	return l
+}
+
+// Name returns the logger's name.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// SetLevel sets the lowest level l and its children log, unless a child
+// sets its own.
+func (l *Logger) SetLevel(level Level) {
+	if l.nop {
+		return
+	}
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	namedLevels[l.name] = level
+}
+
+// ResetLevel removes the level set on l, so it inherits its parent's again.
+func (l *Logger) ResetLevel() {
+	if l.nop {
+		return
+	}
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	delete(namedLevels, l.name)
+}
+
+// Level returns the effective level of l: its own if set, otherwise that of
+// the nearest ancestor with one, or DebugLevel when none is set.
+func (l *Logger) Level() Level {
+	namedMu.RLock()
+	defer namedMu.RUnlock()
+	name := l.name
+	for {
+		if level, ok := namedLevels[name]; ok {
+			return level
+		}
+		if name == "" {
+			return DebugLevel
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			name = ""
+		} else {
+			name = name[:i]
+		}
+	}
+}
+
+// Enabled reports whether l logs entries at level. Entries must also pass
+// LOGGER_LEVELS and the mode's DEBUG setting.
+func (l *Logger) Enabled(level Level) bool {
+	return !l.nop && level >= l.Level() && isLevelEnabled(level)
+}
+
+// setLoggerLevels replaces all named logger levels with levels.
+func setLoggerLevels(levels map[string]Level) {
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	namedLevels = make(map[string]Level, len(levels))
+	for name, level := range levels {
+		namedLevels[name] = level
+	}
+}
+
+// Debug logs a debug message with structured key-value pairs.
+func (l *Logger) Debug(msg string, keyvals ...any) {
+	l.log(DebugLevel, msg, keyvals)
+}
+
+// Info logs an informational message with structured key-value pairs.
+func (l *Logger) Info(msg string, keyvals ...any) {
+	l.log(InfoLevel, msg, keyvals)
+}
+
+// Warn logs a warning message with structured key-value pairs.
+func (l *Logger) Warn(msg string, keyvals ...any) {
+	l.log(WarnLevel, msg, keyvals)
+}
+
+// Error logs an error message with structured key-value pairs.
+func (l *Logger) Error(msg string, keyvals ...any) {
+	l.log(ErrorLevel, msg, keyvals)
+}
+
+// Fatal logs a fatal message with structured key-value pairs and then calls
+// os.Exit(1).
+func (l *Logger) Fatal(msg string, keyvals ...any) {
+	l.log(FatalLevel, msg, keyvals)
+	exitFatal()
+}
+
+// LogSkip logs an entry at level with fields from ctx, like the *Context
+// functions, for adapters that wrap l, such as the v2 module's facade. skip
+// is the number of stack frames between the adapter and the function the
+// entry should be attributed to, as for ApiSkip. A FATAL entry exits once
+// written.
+func (l *Logger) LogSkip(ctx context.Context, skip int, level Level, msg string, keyvals ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if l.logs(level) || (level <= InfoLevel && !l.nop && BufferFromContext(ctx) != nil) {
+		l.logContext(ctx, skip, level, msg, keyvals)
+	}
+	if level == FatalLevel {
+		exitFatal()
+	}
+}
+
+// logContext writes one entry for LogSkip. The caller depth skips
+// logContext, LogSkip, and skip more frames.
+func (l *Logger) logContext(ctx context.Context, skip int, level Level, msg string, keyvals []any) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(3 + max(skip, 0))
+	if l.name != "" {
+		keyvals = append([]any{LoggerKey, l.name}, keyvals...)
+	}
+	outputContext(ctx, loggerFor(level), level, caller, msg, withContextFields(ctx, keyvals))
+}
+
+// log writes one entry for l. The caller depth skips log and the exported
+// method that called it.
+func (l *Logger) log(level Level, msg string, keyvals []any) {
+	if !l.logs(level) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	caller := getCallerInfo(3)
+	if l.name != "" {
+		keyvals = append([]any{LoggerKey, l.name}, keyvals...)
+	}
+	output(loggerFor(level), level, caller, msg, keyvals)
+}
+
+// logs reports whether l writes entries at level, including DEBUG entries
+// held for Config.DebugWindow.
+func (l *Logger) logs(level Level) bool {
+	return l.Enabled(level) || (level == DebugLevel && !l.nop && level >= l.Level() && debugEnabled())
+}
+
+// loggerOutput holds the sinks and additivity of one named logger.
+type loggerOutput struct {
+	workers     []*sinkWorker
+	nonAdditive bool
+}
+
+// loggerOutputs are keyed by logger name and guarded by logMutex
+var loggerOutputs = map[string]*loggerOutput{}
+
+// outputFor returns the output settings of name, creating them if needed.
+// Callers must hold logMutex.
+func outputFor(name string) *loggerOutput {
+	o := loggerOutputs[name]
+	if o == nil {
+		o = &loggerOutput{}
+		loggerOutputs[name] = o
+	}
+	return o
+}
+
+// AddSink sends entries from l and its children to s, in addition to the
+// outputs they inherit. The sink is closed by Close:
+//
+//	audit := logger.Get("audit")
+//	audit.AddSink(auditFile)
+//	audit.SetAdditive(false) // audit entries skip the console and log file
+func (l *Logger) AddSink(s Sink) {
+	if l.nop {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	o := outputFor(l.name)
+	o.workers = append(o.workers, workerFor(s))
+}
+
+// SetAdditive controls whether entries from l and its children also reach
+// the outputs of l's ancestors, ending with the console, the log file, and
+// sinks added with the package-level AddSink. Loggers are additive by
+// default; entries stopped by a non-additive logger still reach its own
+// sinks and those of the child that logged them. It has no effect on the
+// root logger.
+func (l *Logger) SetAdditive(additive bool) {
+	if l.name == "" || l.nop {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	outputFor(l.name).nonAdditive = !additive
+}
+
+// writeLoggerSinks delivers e to the sinks of its logger and that logger's
+// ancestors, stopping at the first non-additive one, and reports whether it
+// stopped there so the default outputs are skipped. Callers must hold
+// logMutex.
+func writeLoggerSinks(e *Entry) (stopped bool) {
+	if len(loggerOutputs) == 0 {
+		return false
+	}
+	name, ok := fieldValue(e.Fields, LoggerKey)
+	if !ok {
+		name = ""
+	}
+	for {
+		if o := loggerOutputs[name]; o != nil {
+			for _, w := range o.workers {
+				w.enqueue(e)
+			}
+			if o.nonAdditive && name != "" {
+				e.trace.step("logger", name, "non-additive, default outputs skipped")
+				return true
+			}
+		}
+		if name == "" {
+			return false
+		}
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			name = name[:i]
+		} else {
+			name = ""
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// Callers shown on entries from LoggedDialer and LoggedResolver, which run
+// inside library code such as http.Transport.
+const (
+	dialerCaller   = "logger.LoggedDialer"
+	resolverCaller = "logger.LoggedResolver"
+)
+
+// LoggedResolver wraps a net.Resolver, logging each lookup at DEBUG with
+// the host, the addresses found, and the DNS latency, or the error.
+type LoggedResolver struct {
+	r *net.Resolver
+}
+
+// NewLoggedResolver wraps r, or net.DefaultResolver when r is nil.
+func NewLoggedResolver(r *net.Resolver) *LoggedResolver {
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	return &LoggedResolver{r: r}
+}
+
+// LookupHost resolves host like net.Resolver.LookupHost.
+func (lr *LoggedResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	start := time.Now()
+	addrs, err := lr.r.LookupHost(ctx, host)
+	d := time.Since(start)
+	if err != nil {
+		netDebug(resolverCaller, "dns lookup failed", "host", host, DurationKey, d, "error", err)
+	} else {
+		netDebug(resolverCaller, "dns lookup", "host", host, "addrs", strings.Join(addrs, ","), DurationKey, d)
+	}
+	return addrs, err
+}
+
+// LoggedDialer wraps a net.Dialer, logging at DEBUG the DNS lookup of the
+// target and every connect attempt with its address and duration, for
+// diagnosing flaky connectivity from field deployments:
+//
+//	d := logger.NewLoggedDialer(&net.Dialer{Timeout: 5 * time.Second})
+//	client := &http.Client{Transport: &http.Transport{DialContext: d.DialContext}}
+//	// [DEBUG] [logger.LoggedResolver] dns lookup host=api.example.com addrs=203.0.113.5,203.0.113.6 duration=14ms
+//	// [DEBUG] [logger.LoggedDialer] connect failed target=api.example.com:443 network=tcp addr=203.0.113.5:443 duration=5s error=i/o timeout
+//	// [DEBUG] [logger.LoggedDialer] connected target=api.example.com:443 network=tcp addr=203.0.113.6:443 duration=31ms
+//
+// Host names are resolved once and their addresses tried in order, each
+// within the dialer's Timeout, rather than raced as net.Dialer does.
+type LoggedDialer struct {
+	d        *net.Dialer
+	resolver *LoggedResolver
+}
+
+// NewLoggedDialer wraps d, or a zero net.Dialer when d is nil. Lookups use
+// d.Resolver when set.
+func NewLoggedDialer(d *net.Dialer) *LoggedDialer {
+	if d == nil {
+		d = &net.Dialer{}
+	}
+	return &LoggedDialer{d: d, resolver: NewLoggedResolver(d.Resolver)}
+}
+
+// Dial connects like net.Dialer.Dial.
+func (ld *LoggedDialer) Dial(network, address string) (net.Conn, error) {
+	return ld.DialContext(context.Background(), network, address)
+}
+
+// DialContext connects like net.Dialer.DialContext.
+func (ld *LoggedDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	targets := []string{address}
+	if strings.HasPrefix(network, "tcp") || strings.HasPrefix(network, "udp") {
+		host, port, err := net.SplitHostPort(address)
+		if err == nil && host != "" && net.ParseIP(host) == nil {
+			addrs, err := ld.resolver.LookupHost(ctx, host)
+			if err != nil {
+				return nil, err
+			}
+			targets = targets[:0]
+			for _, addr := range addrs {
+				targets = append(targets, net.JoinHostPort(addr, port))
+			}
+		}
+	}
+
+	var errs []error
+	for _, target := range targets {
+		start := time.Now()
+		conn, err := ld.d.DialContext(ctx, network, target)
+		d := time.Since(start)
+		if err == nil {
+			netDebug(dialerCaller, "connected", "target", address, "network", network, "addr", target, DurationKey, d)
+			return conn, nil
+		}
+		netDebug(dialerCaller, "connect failed", "target", address, "network", network, "addr", target, DurationKey, d, "error", err)
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+func netDebug(caller, msg string, keyvals ...any) {
+	if !debugEnabled() {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(DebugLevel), DebugLevel, caller, msg, keyvals)
+}
@@ -0,0 +1,35 @@
+package logger
+
+import "sync/atomic"
+
+// globalDisabled is set by DisableGlobal
+var globalDisabled atomic.Bool
+
+// Nop returns a Logger that discards every entry, for libraries that accept
+// a *Logger and callers that want them quiet:
+//
+//	client := storage.NewClient(storage.Options{Log: logger.Nop()})
+//
+// Its Enabled always reports false, and SetLevel, AddSink, and SetAdditive
+// do nothing. Fatal still calls os.Exit(1).
+func Nop() *Logger {
+	return &Logger{nop: true}
+}
+
+// DisableGlobal silences the whole package for host applications that use
+// a different logging stack but link libraries logging through this one:
+// entries are discarded before reaching the console, the log file, or any
+// sink; the first log call no longer initializes the logger; and Init
+// calls made by libraries leave the process's stdout, files, and journal
+// stream alone. Fatal functions still exit. The returned function turns
+// logging back on, e.g. at the end of a test.
+//
+//	func main() {
+//	    logger.DisableGlobal()
+//	    zapLogger := zap.Must(zap.NewProduction())
+//	    ...
+//	}
+func DisableGlobal() (restore func()) {
+	globalDisabled.Store(true)
+	return func() { globalDisabled.Store(false) }
+}
@@ -0,0 +1,396 @@
+package logger
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for ParquetConfig.
+const (
+	DefaultParquetFlushInterval = time.Minute
+	DefaultParquetMaxRows       = 100000
+)
+
+// ParquetConfig configures NewParquetSink.
+type ParquetConfig struct {
+	// Dir is the root of the partitioned dataset. Required unless Upload is
+	// set.
+	Dir string
+	// Upload, when set, receives each finished file instead of it being
+	// written under Dir, e.g. to put it into an object store bucket. name is
+	// the partitioned path relative to the dataset root.
+	Upload func(name string, data []byte) error
+	// FlushInterval is how often buffered entries are written out.
+	// Defaults to DefaultParquetFlushInterval.
+	FlushInterval time.Duration
+	// MaxRows writes a file early once this many entries are buffered.
+	// Defaults to DefaultParquetMaxRows.
+	MaxRows int
+}
+
+// ParquetSink buffers entries and writes them as Parquet files partitioned
+// by hour, so logs can be queried in place by DuckDB, Athena, or Spark
+// without an ingestion pipeline:
+//
+//	sink, err := logger.NewParquetSink(logger.ParquetConfig{Dir: "/var/log/app/parquet"})
+//	...
+//	logger.AddSink(sink)
+//
+//	-- duckdb
+//	SELECT level, count(*) FROM read_parquet('/var/log/app/parquet/**/*.parquet', hive_partitioning = true)
+//	WHERE date = '2026-03-04' GROUP BY level;
+//
+// Files are named date=YYYY-MM-DD/hour=HH/part-<nanos>.parquet after the
+// UTC time of their entries. Each has the columns time (timestamp,
+// microseconds), level, caller, msg, and fields, which holds the entry
+// fields as a JSON object. Files are written uncompressed, at most every
+// FlushInterval, when MaxRows entries are buffered, when the hour changes,
+// and on Close. Entries buffered when the process dies are lost.
+type ParquetSink struct {
+	cfg ParquetConfig
+
+	mu      sync.Mutex
+	rows    []*Entry
+	hour    time.Time
+	lastErr error
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewParquetSink returns a sink writing Parquet files as described by cfg.
+func NewParquetSink(cfg ParquetConfig) (*ParquetSink, error) {
+	if cfg.Dir == "" && cfg.Upload == nil {
+		return nil, errors.New("logger: parquet sink requires a directory or an upload function")
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultParquetFlushInterval
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = DefaultParquetMaxRows
+	}
+	p := &ParquetSink{cfg: cfg, stop: make(chan struct{}), done: make(chan struct{})}
+	go p.run()
+	return p, nil
+}
+
+func (p *ParquetSink) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.mu.Lock()
+			if err := p.flush(); err != nil {
+				// reported by the next WriteEntry
+				p.lastErr = err
+			}
+			p.mu.Unlock()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// WriteEntry buffers e, first writing out the buffered entries of an
+// earlier hour.
+func (p *ParquetSink) WriteEntry(e *Entry) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	err := p.lastErr
+	p.lastErr = nil
+	hour := e.Time.UTC().Truncate(time.Hour)
+	if len(p.rows) > 0 && !hour.Equal(p.hour) {
+		if ferr := p.flush(); err == nil {
+			err = ferr
+		}
+	}
+	p.hour = hour
+	p.rows = append(p.rows, e)
+	if len(p.rows) >= p.cfg.MaxRows {
+		if ferr := p.flush(); err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
+
+// flush writes the buffered entries as one file. On failure the entries are
+// dropped rather than retried, so a broken destination cannot exhaust
+// memory. Callers must hold p.mu.
+func (p *ParquetSink) flush() error {
+	if len(p.rows) == 0 {
+		return nil
+	}
+	rows := p.rows
+	p.rows = nil
+	name := fmt.Sprintf("date=%s/hour=%02d/part-%d.parquet", p.hour.Format(time.DateOnly), p.hour.Hour(), time.Now().UnixNano())
+	data := encodeParquet(rows)
+	if p.cfg.Upload != nil {
+		return p.cfg.Upload(name, data)
+	}
+	path := filepath.Join(p.cfg.Dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// query engines skip files starting with "."
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path))
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Flush writes the buffered entries out now.
+func (p *ParquetSink) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.flush()
+}
+
+// Close stops the periodic flush and writes the remaining entries. Later
+// calls return nil.
+func (p *ParquetSink) Close() error {
+	p.stopOnce.Do(func() { close(p.stop) })
+	<-p.done
+	return p.Flush()
+}
+
+// Parquet format constants used by encodeParquet.
+const (
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetRequired        = 0
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+
+	parquetPlain = 0
+	parquetRLE   = 3
+)
+
+// parquetColumn is one column of the file written by encodeParquet.
+type parquetColumn struct {
+	name      string
+	typ       int32
+	converted int32
+	values    func(rows []*Entry) []byte // PLAIN-encoded values
+}
+
+var parquetColumns = []parquetColumn{
+	{"time", parquetInt64, parquetTimestampMicros, func(rows []*Entry) []byte {
+		b := make([]byte, 0, 8*len(rows))
+		for _, e := range rows {
+			b = binary.LittleEndian.AppendUint64(b, uint64(e.Time.UnixMicro()))
+		}
+		return b
+	}},
+	{"level", parquetByteArray, parquetUTF8, parquetStrings(func(e *Entry) string { return levelNames[e.Level] })},
+	{"caller", parquetByteArray, parquetUTF8, parquetStrings(func(e *Entry) string { return e.Caller })},
+	{"msg", parquetByteArray, parquetUTF8, parquetStrings(func(e *Entry) string { return e.Message })},
+	{"fields", parquetByteArray, parquetUTF8, parquetStrings(encodeJSONFields)},
+}
+
+func parquetStrings(value func(e *Entry) string) func(rows []*Entry) []byte {
+	return func(rows []*Entry) []byte {
+		var b []byte
+		for _, e := range rows {
+			s := value(e)
+			b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+			b = append(b, s...)
+		}
+		return b
+	}
+}
+
+// encodeJSONFields renders the entry fields as a JSON object.
+func encodeJSONFields(e *Entry) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(e.Fields); i += 2 {
+		key, ok := e.Fields[i].(string)
+		if !ok {
+			continue
+		}
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		writeJSONValue(&b, key)
+		b.WriteByte(':')
+		writeJSONValue(&b, e.Fields[i+1])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// encodeParquet renders rows as a Parquet file with a single row group and
+// one uncompressed PLAIN data page per column. All columns are required,
+// so pages carry no repetition or definition levels.
+func encodeParquet(rows []*Entry) []byte {
+	buf := []byte("PAR1")
+	type chunk struct {
+		offset, size int64
+	}
+	chunks := make([]chunk, len(parquetColumns))
+	for i, col := range parquetColumns {
+		values := col.values(rows)
+		var h thriftWriter
+		h.i32(1, 0) // DATA_PAGE
+		h.i32(2, int32(len(values)))
+		h.i32(3, int32(len(values)))
+		h.beginStruct(5)
+		h.i32(1, int32(len(rows)))
+		h.i32(2, parquetPlain)
+		h.i32(3, parquetRLE)
+		h.i32(4, parquetRLE)
+		h.endStruct()
+		h.endStruct()
+
+		chunks[i] = chunk{offset: int64(len(buf)), size: int64(len(h.b) + len(values))}
+		buf = append(buf, h.b...)
+		buf = append(buf, values...)
+	}
+
+	var m thriftWriter
+	m.i32(1, 1) // version
+	m.beginList(2, thriftStruct, len(parquetColumns)+1)
+	m.beginElem()
+	m.str(4, "schema")
+	m.i32(5, int32(len(parquetColumns)))
+	m.endStruct()
+	for _, col := range parquetColumns {
+		m.beginElem()
+		m.i32(1, col.typ)
+		m.i32(3, parquetRequired)
+		m.str(4, col.name)
+		m.i32(6, col.converted)
+		m.endStruct()
+	}
+	m.i64(3, int64(len(rows)))
+	m.beginList(4, thriftStruct, 1)
+	m.beginElem()
+	m.beginList(1, thriftStruct, len(parquetColumns))
+	var total int64
+	for i, col := range parquetColumns {
+		c := chunks[i]
+		total += c.size
+		m.beginElem()
+		m.i64(2, c.offset)
+		m.beginStruct(3)
+		m.i32(1, col.typ)
+		m.beginList(2, thriftI32, 2)
+		m.b = appendZigzag(m.b, parquetPlain)
+		m.b = appendZigzag(m.b, parquetRLE)
+		m.beginList(3, thriftBinary, 1)
+		m.b = appendVarint(m.b, uint64(len(col.name)))
+		m.b = append(m.b, col.name...)
+		m.i32(4, 0) // UNCOMPRESSED
+		m.i64(5, int64(len(rows)))
+		m.i64(6, c.size)
+		m.i64(7, c.size)
+		m.i64(9, c.offset)
+		m.endStruct()
+		m.endStruct()
+	}
+	m.i64(2, total)
+	m.i64(3, int64(len(rows)))
+	m.endStruct()
+	m.str(6, "go_logger")
+	m.endStruct()
+
+	buf = append(buf, m.b...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(m.b)))
+	return append(buf, "PAR1"...)
+}
+
+// Thrift compact protocol type codes.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter writes the Thrift compact protocol encoding of a struct, as
+// used by Parquet metadata. The outermost struct is open from the start
+// and closed with endStruct like nested ones.
+type thriftWriter struct {
+	b    []byte
+	last []int16 // field ID of each enclosing struct
+	id   int16
+}
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	if delta := id - w.id; delta > 0 && delta <= 15 {
+		w.b = append(w.b, byte(delta)<<4|typ)
+	} else {
+		w.b = append(w.b, typ)
+		w.b = appendZigzag(w.b, int64(id))
+	}
+	w.id = id
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.b = appendZigzag(w.b, int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.b = appendZigzag(w.b, v)
+}
+
+func (w *thriftWriter) str(id int16, s string) {
+	w.field(id, thriftBinary)
+	w.b = appendVarint(w.b, uint64(len(s)))
+	w.b = append(w.b, s...)
+}
+
+// beginStruct opens a struct-valued field.
+func (w *thriftWriter) beginStruct(id int16) {
+	w.field(id, thriftStruct)
+	w.beginElem()
+}
+
+// beginElem opens a struct that is a list element.
+func (w *thriftWriter) beginElem() {
+	w.last = append(w.last, w.id)
+	w.id = 0
+}
+
+func (w *thriftWriter) endStruct() {
+	w.b = append(w.b, 0)
+	if n := len(w.last); n > 0 {
+		w.id = w.last[n-1]
+		w.last = w.last[:n-1]
+	}
+}
+
+// beginList writes the header of a list field with n elements of type
+// elem; the elements follow.
+func (w *thriftWriter) beginList(id int16, elem byte, n int) {
+	w.field(id, thriftList)
+	if n < 15 {
+		w.b = append(w.b, byte(n)<<4|elem)
+		return
+	}
+	w.b = append(w.b, 0xf0|elem)
+	w.b = appendVarint(w.b, uint64(n))
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	return binary.AppendUvarint(b, v)
+}
+
+func appendZigzag(b []byte, v int64) []byte {
+	return binary.AppendUvarint(b, uint64(v<<1^v>>63))
+}
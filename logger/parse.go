@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// textLinePattern matches the classic text layout written to log files and
+// the console, in development ("[LEVEL] date time [caller] msg") and
+// production ("date time [LEVEL] [caller] msg") order, with an optional
+// "<N>" severity prefix and optional timestamp.
+var textLinePattern = regexp.MustCompile(
+	`^(?:<\d>)?(?:\[([A-Z]+)\] )?(?:(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)?) )?(?:\[([A-Z]+)\] )?\[([^\]]*)\] ?(.*)$`)
+
+// fieldPattern matches a trailing key=value token.
+var fieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]*=\S*$`)
+
+// ParseLine parses a line written by this logger in the classic text layout
+// or as JSON (FallbackJSON) back into an Entry. Text timestamps are read in
+// the local time zone; lines without one get a zero Time. Trailing key=value
+// tokens of text lines become fields, so messages that themselves end in
+// key=value text are split the same way.
+func ParseLine(line string) (*Entry, error) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, "{") {
+		return parseJSONLine(line)
+	}
+	m := textLinePattern.FindStringSubmatch(line)
+	if m == nil {
+		return nil, errors.New("logger: unrecognized line format")
+	}
+	name := m[1]
+	if name == "" {
+		name = m[3]
+	}
+	level, err := ParseLevel(name)
+	if err != nil {
+		return nil, err
+	}
+	e := &Entry{Level: level, Caller: intern(m[4])}
+	if m[2] != "" {
+		layout := "2006/01/02 15:04:05"
+		if strings.Contains(m[2], ".") {
+			layout += ".000000"
+		}
+		t, err := time.ParseInLocation(layout, m[2], time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("logger: bad timestamp: %w", err)
+		}
+		e.Time = t
+	}
+
+	words := strings.Split(m[5], " ")
+	i := len(words)
+	for i > 1 && fieldPattern.MatchString(words[i-1]) {
+		i--
+	}
+	e.Message = strings.Join(words[:i], " ")
+	for _, w := range words[i:] {
+		key, value, _ := strings.Cut(w, "=")
+		e.Fields = append(e.Fields, intern(key), value)
+	}
+	return e, nil
+}
+
+// parseJSONLine decodes a JSON entry, keeping field order.
+func parseJSONLine(line string) (*Entry, error) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("logger: bad JSON line: %w", err)
+	}
+	e := &Entry{}
+	levelSeen := false
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("logger: bad JSON line: %w", err)
+		}
+		key, _ := tok.(string)
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("logger: bad JSON line: %w", err)
+		}
+		s, _ := value.(string)
+		switch key {
+		case "time":
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, fmt.Errorf("logger: bad timestamp: %w", err)
+			}
+			e.Time = t
+		case "level":
+			level, err := ParseLevel(s)
+			if err != nil {
+				return nil, err
+			}
+			e.Level, levelSeen = level, true
+		case "caller":
+			e.Caller = intern(s)
+		case "msg":
+			e.Message = s
+		default:
+			e.Fields = append(e.Fields, intern(key), value)
+		}
+	}
+	if !levelSeen {
+		return nil, errors.New("logger: JSON line has no level")
+	}
+	return e, nil
+}
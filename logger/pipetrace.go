@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PipelineStep is one decision or delivery in the journey of a traced
+// entry.
+type PipelineStep struct {
+	// Stage is "filter", "governor", "remote policy", "rate limit",
+	// "route", "logger", "console", "file", "queue", or "sink".
+	Stage string
+	// Target names the route, logger, level output, or sink type, if any.
+	Target string
+	// Result is what happened, e.g. "written", "dropped", or "failed: ...".
+	Result string
+	// At is the time since the entry was logged.
+	At time.Duration
+}
+
+// PipelineTrace is the journey of one entry recorded by TracePipeline.
+type PipelineTrace struct {
+	Time    time.Time
+	Level   Level
+	Caller  string
+	Message string
+	Steps   []PipelineStep
+}
+
+// entryTrace collects the steps of one entry. Sink workers add steps
+// concurrently with the logging goroutine.
+type entryTrace struct {
+	mu sync.Mutex
+	t  PipelineTrace
+}
+
+var (
+	// tracing is set while TracePipeline runs
+	tracing atomic.Bool
+
+	// traces holds the most recent entry traces, oldest first once next
+	// wraps, guarded by tracesMu
+	traces   []*entryTrace
+	next     int
+	tracesMu sync.Mutex
+)
+
+// TracePipeline records the journey of each entry through the logger's
+// filters, routes, outputs, sink queues, and sinks, with timings, keeping
+// the most recent n traces for PipelineTraces and DumpPipelineTraces. It is
+// meant for debugging misrouted or dropped entries in complex
+// configurations, not for production use:
+//
+//	stop := logger.TracePipeline(100)
+//	defer stop()
+//	...
+//	logger.DumpPipelineTraces(os.Stderr)
+//	// 2026-03-02T10:14:07.1234Z INFO [billing.Charge:88] charge failed
+//	//	      +41µs rate limit: dropped
+//
+// The returned function stops recording; the traces kept stay available.
+// Entries held by a RequestBuffer or Config.DebugWindow are traced once
+// they are written.
+func TracePipeline(n int) (stop func()) {
+	if n <= 0 {
+		n = 100
+	}
+	tracesMu.Lock()
+	traces, next = make([]*entryTrace, 0, n), 0
+	tracesMu.Unlock()
+	tracing.Store(true)
+	return func() { tracing.Store(false) }
+}
+
+// PipelineTraces returns copies of the kept traces, oldest first.
+func PipelineTraces() []PipelineTrace {
+	tracesMu.Lock()
+	kept := append(append([]*entryTrace(nil), traces[next:]...), traces[:next]...)
+	tracesMu.Unlock()
+	out := make([]PipelineTrace, len(kept))
+	for i, tr := range kept {
+		tr.mu.Lock()
+		out[i] = tr.t
+		out[i].Steps = append([]PipelineStep(nil), tr.t.Steps...)
+		tr.mu.Unlock()
+	}
+	return out
+}
+
+// DumpPipelineTraces writes the kept traces to w, oldest first, one line
+// per entry followed by an indented line per step.
+func DumpPipelineTraces(w io.Writer) error {
+	for _, t := range PipelineTraces() {
+		if _, err := fmt.Fprintf(w, "%s %s [%s] %s\n", t.Time.Format(time.RFC3339Nano), t.Level, t.Caller, t.Message); err != nil {
+			return err
+		}
+		for _, s := range t.Steps {
+			stage := s.Stage
+			if s.Target != "" {
+				stage += " " + s.Target
+			}
+			if _, err := fmt.Fprintf(w, "\t%10s %s: %s\n", "+"+s.At.String(), stage, s.Result); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// traceEntry starts the trace of e while TracePipeline runs. Callers must
+// hold logMutex.
+func traceEntry(e *Entry) {
+	if !tracing.Load() || e.trace != nil {
+		return
+	}
+	e.trace = &entryTrace{t: PipelineTrace{Time: e.Time, Level: e.Level, Caller: e.Caller, Message: e.Message}}
+	tracesMu.Lock()
+	if len(traces) < cap(traces) {
+		traces = append(traces, e.trace)
+	} else {
+		traces[next] = e.trace
+		next = (next + 1) % len(traces)
+	}
+	tracesMu.Unlock()
+}
+
+// step adds a step to the trace; it does nothing for untraced entries.
+func (tr *entryTrace) step(stage, target, result string) {
+	if tr == nil {
+		return
+	}
+	tr.mu.Lock()
+	tr.t.Steps = append(tr.t.Steps, PipelineStep{Stage: stage, Target: target, Result: result, At: time.Since(tr.t.Time)})
+	tr.mu.Unlock()
+}
+
+// sinkStep adds a step naming the type of s.
+func (tr *entryTrace) sinkStep(stage string, s Sink, result string) {
+	if tr != nil {
+		tr.step(stage, fmt.Sprintf("%T", s), result)
+	}
+}
@@ -0,0 +1,185 @@
+package logger
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PubSink is a ZeroMQ PUB socket: ZeroMQ SUB sockets (libzmq, pyzmq,
+// JeroMQ, ...) connect to it over TCP or IPC and receive entries matching
+// their subscriptions. It speaks ZMTP 3.0 with the NULL security mechanism.
+//
+// Each entry is a two-frame message: the level name ("ERROR") as the topic
+// frame, then the encoded entry. Subscribers filter with topic prefixes as
+// usual, e.g. "" for every entry or "WARN" for warnings only. Subscribers
+// attach and detach at any time, entries nobody subscribed to are dropped,
+// and a subscriber that cannot keep up is disconnected rather than slowing
+// down logging.
+type PubSink struct {
+	ln  net.Listener
+	enc Encoder
+
+	mu     sync.Mutex
+	subs   map[*pubSubscriber]struct{}
+	closed bool
+}
+
+// pubSubscriber is one connected SUB socket and its topic subscriptions,
+// counted like libzmq so that a repeated subscribe needs as many cancels.
+type pubSubscriber struct {
+	conn   net.Conn
+	topics map[string]int
+}
+
+const (
+	// pubWriteTimeout is how long a subscriber may block a write before it is dropped.
+	pubWriteTimeout = 100 * time.Millisecond
+	// pubHandshakeTimeout bounds the ZMTP greeting and READY exchange.
+	pubHandshakeTimeout = 5 * time.Second
+)
+
+// NewPubSink listens on network ("tcp" or "unix") at addr and publishes
+// entries to SUB sockets connecting to it, such as "tcp://127.0.0.1:5556"
+// or "ipc:///run/app/log.sock" in ZeroMQ terms. A nil enc uses JSONEncoder.
+func NewPubSink(network, addr string, enc Encoder) (*PubSink, error) {
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	if enc == nil {
+		enc = JSONEncoder{}
+	}
+	p := &PubSink{ln: ln, enc: enc, subs: make(map[*pubSubscriber]struct{})}
+	go p.accept()
+	return p, nil
+}
+
+// Addr returns the listening address, useful when addr used port 0.
+func (p *PubSink) Addr() net.Addr {
+	return p.ln.Addr()
+}
+
+// Subscribers returns the number of connected subscribers.
+func (p *PubSink) Subscribers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.subs)
+}
+
+func (p *PubSink) accept() {
+	for {
+		conn, err := p.ln.Accept()
+		if err != nil {
+			return
+		}
+		go p.serve(conn)
+	}
+}
+
+// serve completes the ZMTP handshake on conn, registers the subscriber, and
+// applies its subscriptions until it disconnects.
+func (p *PubSink) serve(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(pubHandshakeTimeout))
+	if err := zmtpHandshake(conn, r, "PUB", "SUB", "XSUB"); err != nil {
+		conn.Close()
+		return
+	}
+	conn.SetDeadline(time.Time{})
+
+	sub := &pubSubscriber{conn: conn, topics: make(map[string]int)}
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		conn.Close()
+		return
+	}
+	p.subs[sub] = struct{}{}
+	p.mu.Unlock()
+
+	more := false
+	for {
+		flags, body, err := readZMTPFrame(r)
+		if err != nil {
+			break
+		}
+		// only the first frame of a message can be a subscription
+		continued := more
+		if flags&zmtpCommand == 0 {
+			more = flags&zmtpMore != 0
+		}
+		if continued {
+			continue
+		}
+		topic, subscribe, ok := zmtpSubscription(flags, body)
+		if !ok {
+			continue
+		}
+		p.mu.Lock()
+		if subscribe {
+			sub.topics[topic]++
+		} else if sub.topics[topic] > 1 {
+			sub.topics[topic]--
+		} else {
+			delete(sub.topics, topic)
+		}
+		p.mu.Unlock()
+	}
+	p.mu.Lock()
+	delete(p.subs, sub)
+	p.mu.Unlock()
+	conn.Close()
+}
+
+// subscribed reports whether s subscribed to a prefix of topic. Callers
+// must hold p.mu.
+func (s *pubSubscriber) subscribed(topic string) bool {
+	for t := range s.topics {
+		if strings.HasPrefix(topic, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteEntry sends e to every subscriber whose subscriptions match its
+// level.
+func (p *PubSink) WriteEntry(e *Entry) error {
+	data, err := p.enc.Encode(e)
+	if err != nil {
+		return err
+	}
+	topic := e.Level.String()
+	msg := appendZMTPFrame(nil, zmtpMore, []byte(topic))
+	msg = appendZMTPFrame(msg, 0, data)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for sub := range p.subs {
+		if !sub.subscribed(topic) {
+			continue
+		}
+		sub.conn.SetWriteDeadline(time.Now().Add(pubWriteTimeout))
+		if _, err := sub.conn.Write(msg); err != nil {
+			sub.conn.Close()
+			delete(p.subs, sub)
+		}
+	}
+	return nil
+}
+
+// Close stops listening and disconnects all subscribers.
+func (p *PubSink) Close() error {
+	err := p.ln.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for sub := range p.subs {
+		sub.conn.Close()
+		delete(p.subs, sub)
+	}
+	return err
+}
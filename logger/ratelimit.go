@@ -0,0 +1,93 @@
+package logger
+
+import "time"
+
+// Event IDs of the entries written by Config.MaxEntriesPerSecond.
+const (
+	RateLimitEvent   = "logger.rate_limited"
+	RateSummaryEvent = "logger.rate_summary"
+)
+
+var (
+	// maxEntriesPerSecond is Config.MaxEntriesPerSecond
+	maxEntriesPerSecond int
+	rate                rateWindow
+)
+
+// rateWindow counts the entries of the current second for the
+// MaxEntriesPerSecond guardrail.
+type rateWindow struct {
+	second     time.Time
+	count      int
+	suppressed int
+	callers    map[string]int // suppressed entries per caller
+	limiting   bool           // the WARN of the current episode was written
+	internal   bool           // writing the guardrail's own entries
+}
+
+// rateLimited reports whether e exceeds Config.MaxEntriesPerSecond and must
+// be dropped. ERROR and FATAL entries are never dropped. The first dropped
+// entry of an episode is preceded by a single WARN, and every second with
+// dropped entries is summarized by an INFO entry written before the first
+// entry of a later second, or by Close. An episode ends with the first
+// second under the limit. Callers must hold logMutex.
+func rateLimited(e *Entry) bool {
+	if maxEntriesPerSecond <= 0 || rate.internal || e.Level >= ErrorLevel {
+		return false
+	}
+	if second := e.Time.Truncate(time.Second); !second.Equal(rate.second) {
+		flushRateSummary(e.Time)
+		if rate.count <= maxEntriesPerSecond || second.Sub(rate.second) > time.Second {
+			rate.limiting = false
+		}
+		rate.second, rate.count = second, 0
+	}
+	rate.count++
+	if rate.count <= maxEntriesPerSecond {
+		return false
+	}
+	if !rate.limiting {
+		rate.limiting = true
+		writeRateEntry(&Entry{Time: e.Time, Level: WarnLevel, Caller: "logger.ratelimit",
+			Message: "log rate limit exceeded; summarizing entries below ERROR",
+			Fields:  []any{EventKey, RateLimitEvent, "max_per_sec", maxEntriesPerSecond}})
+	}
+	rate.suppressed++
+	if rate.callers == nil {
+		rate.callers = map[string]int{}
+	}
+	rate.callers[e.Caller]++
+	return true
+}
+
+// flushRateSummary writes the summary of the entries dropped in the current
+// second, if any. Callers must hold logMutex.
+func flushRateSummary(now time.Time) {
+	if rate.suppressed == 0 {
+		return
+	}
+	top, topCount := "", 0
+	for caller, n := range rate.callers {
+		if n > topCount || (n == topCount && caller < top) {
+			top, topCount = caller, n
+		}
+	}
+	writeRateEntry(&Entry{Time: now, Level: InfoLevel, Caller: "logger.ratelimit",
+		Message: "log entries suppressed",
+		Fields: []any{
+			EventKey, RateSummaryEvent,
+			"suppressed", rate.suppressed,
+			"second", rate.second.Format(time.RFC3339),
+			"top_caller", top,
+			"top_caller_count", topCount,
+		}})
+	rate.suppressed, rate.callers = 0, nil
+}
+
+// writeRateEntry writes an entry of the guardrail itself, which is never
+// dropped.
+func writeRateEntry(e *Entry) {
+	rate.internal = true
+	defer func() { rate.internal = false }()
+	writeEntry(loggerFor(e.Level), e)
+}
@@ -0,0 +1,69 @@
+package logger
+
+import "bytes"
+
+// regions are the captures of running CaptureRegion calls.
+// Guarded by logMutex.
+var regions []*Region
+
+// Region holds the entries logged while a CaptureRegion function ran.
+type Region struct {
+	Entries []*Entry
+}
+
+// CaptureRegion calls fn and returns the entries logged while it ran, in
+// addition to writing them as usual, e.g. to include the relevant lines in
+// an API error response or to print them on failure in a CLI
+// --verbose-on-error mode:
+//
+//	region := logger.CaptureRegion(func() { err = migrate(db) })
+//	if err != nil {
+//	    os.Stderr.Write(region.Bytes())
+//	}
+//
+// Entries logged by other goroutines during fn are captured too; to collect
+// the entries of a single request, use a RequestBuffer. Regions may be
+// nested.
+func CaptureRegion(fn func()) *Region {
+	r := &Region{}
+	logMutex.Lock()
+	regions = append(regions, r)
+	logMutex.Unlock()
+	defer func() {
+		logMutex.Lock()
+		defer logMutex.Unlock()
+		for i, active := range regions {
+			if active == r {
+				regions = append(regions[:i], regions[i+1:]...)
+				break
+			}
+		}
+	}()
+	fn()
+	return r
+}
+
+// Bytes returns the entries as text lines, rendered by TextEncoder with
+// DefaultTimeFormat.
+func (r *Region) Bytes() []byte {
+	var b bytes.Buffer
+	enc := TextEncoder{TimeFormat: DefaultTimeFormat}
+	for _, e := range r.Entries {
+		line, _ := enc.Encode(e)
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// String returns Bytes as a string.
+func (r *Region) String() string {
+	return string(r.Bytes())
+}
+
+// captureRegions adds e to the running regions. Callers must hold logMutex.
+func captureRegions(e *Entry) {
+	for _, r := range regions {
+		r.Entries = append(r.Entries, e)
+	}
+}
@@ -0,0 +1,413 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults for RelayConfig.
+const (
+	DefaultRelayPollInterval  = 250 * time.Millisecond
+	DefaultRelayRetryInterval = time.Second
+)
+
+// RelayConfig configures a Relay.
+type RelayConfig struct {
+	// Sink receives the forwarded entries, typically a network sink such
+	// as a SyslogSink or MQTTSink. The relay owns it: do not also register
+	// it with AddSink.
+	Sink Sink
+	// PositionFile, when set, records how far each followed file has been
+	// forwarded, so a restarted relay resumes where it stopped instead of
+	// forwarding the files again.
+	PositionFile string
+	// PollInterval is how often followed files are checked for new lines.
+	// Zero uses DefaultRelayPollInterval.
+	PollInterval time.Duration
+	// RetryInterval is the wait between attempts to write an entry the
+	// sink rejected. Zero uses DefaultRelayRetryInterval.
+	RetryInterval time.Duration
+}
+
+// Relay forwards entries written by processes using this package, read
+// from their log files or received on a unix socket from their
+// SocketSink, to one sink, keeping each entry's time, level, caller, and
+// fields. It lets a binary built on this package act as a node-local log
+// shipper:
+//
+//	sink, err := logger.NewSyslogSink(logger.SyslogConfig{Network: "tcp", Address: "logs.example.com:514"})
+//	if err != nil { ... }
+//	relay, err := logger.NewRelay(logger.RelayConfig{Sink: sink, PositionFile: "/var/lib/relay/positions.json"})
+//	if err != nil { ... }
+//	go relay.FollowFile(ctx, "/var/log/app/app.log")
+//	go relay.ServeSocket(ctx, "unixgram", "/run/relay.sock")
+//	<-ctx.Done()
+//	relay.Close()
+//
+// Entries reach the sink in the order each source produced them, one at a
+// time. A write the sink rejects is retried every RetryInterval until it
+// succeeds or the source's context is done, holding back the other
+// sources, so a collector outage delays entries rather than losing them.
+type Relay struct {
+	cfg RelayConfig
+
+	// mu serializes sink writes
+	mu      sync.Mutex
+	failing bool
+
+	posMu     sync.Mutex
+	positions map[string]int64
+	dirty     bool
+
+	forwarded, skipped atomic.Int64
+}
+
+// NewRelay returns a relay forwarding to cfg.Sink, loading the positions
+// recorded in cfg.PositionFile.
+func NewRelay(cfg RelayConfig) (*Relay, error) {
+	if cfg.Sink == nil {
+		return nil, errors.New("logger: relay needs a sink")
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultRelayPollInterval
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRelayRetryInterval
+	}
+	r := &Relay{cfg: cfg, positions: map[string]int64{}}
+	if cfg.PositionFile != "" {
+		data, err := os.ReadFile(cfg.PositionFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &r.positions); err != nil {
+				return nil, fmt.Errorf("logger: %s: %w", cfg.PositionFile, err)
+			}
+		}
+	}
+	return r, nil
+}
+
+// Forwarded returns the number of entries written to the sink.
+func (r *Relay) Forwarded() int64 {
+	return r.forwarded.Load()
+}
+
+// Skipped returns the number of unparseable lines and records dropped.
+func (r *Relay) Skipped() int64 {
+	return r.skipped.Load()
+}
+
+// Close saves the positions of followed files and closes the sink. Call
+// it once the FollowFile and ServeSocket calls have returned.
+func (r *Relay) Close() error {
+	return errors.Join(r.savePositions(), r.cfg.Sink.Close())
+}
+
+// FollowFile forwards the classic text or JSON lines of the log file at
+// path, like "tail -F": it starts at the recorded position, or at the
+// beginning of the file, waits for the file to appear, follows it across
+// rotation and truncation, and returns nil once ctx is done. Binary log
+// files cannot be followed; replay them with cmd/logreplay instead.
+func (r *Relay) FollowFile(ctx context.Context, path string) error {
+	var (
+		f       *os.File
+		off     = r.position(path)
+		partial []byte
+		buf     = make([]byte, 64*1024)
+	)
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if f == nil {
+			var err error
+			if f, off, err = openFollowed(path, off); err != nil {
+				return err
+			}
+		}
+		if f != nil {
+			for {
+				n, err := f.Read(buf)
+				partial = append(partial, buf[:n]...)
+				for {
+					i := bytes.IndexByte(partial, '\n')
+					if i < 0 {
+						break
+					}
+					if err := r.forwardLine(ctx, partial[:i]); err != nil {
+						return nil
+					}
+					off += int64(i + 1)
+					partial = partial[i+1:]
+					r.setPosition(path, off)
+				}
+				if n == 0 || err != nil {
+					break
+				}
+			}
+			switch rotated, truncated, err := followedState(f, path, off); {
+			case err != nil:
+				return err
+			case rotated:
+				// the rest of the old file is a line cut short
+				if len(partial) > 0 && r.forwardLine(ctx, partial) != nil {
+					return nil
+				}
+				f.Close()
+				f, off, partial = nil, 0, nil
+				r.setPosition(path, 0)
+				continue
+			case truncated:
+				off, partial = 0, nil
+				if _, err := f.Seek(0, io.SeekStart); err != nil {
+					return err
+				}
+				r.setPosition(path, 0)
+			}
+		}
+		if err := r.savePositions(); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: relay positions: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// openFollowed opens path at off, or at 0 when the file is now shorter. It
+// returns a nil file while path does not exist.
+func openFollowed(path string, off int64) (*os.File, int64, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	head := make([]byte, len(binaryLogMagic))
+	if n, _ := f.ReadAt(head, 0); n == len(head) && string(head) == binaryLogMagic {
+		f.Close()
+		return nil, 0, fmt.Errorf("logger: %s is a binary log file and cannot be followed", path)
+	}
+	if info, err := f.Stat(); err == nil && info.Size() < off {
+		off = 0
+	}
+	if _, err := f.Seek(off, io.SeekStart); err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, off, nil
+}
+
+// followedState reports whether path was rotated away from f, or f was
+// truncated in place below off.
+func followedState(f *os.File, path string, off int64) (rotated, truncated bool, err error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return false, false, err
+	}
+	pi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return true, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	return !os.SameFile(fi, pi), fi.Size() < off, nil
+}
+
+// forwardLine parses and forwards one line, counting it as skipped when it
+// cannot be parsed. It returns an error only once ctx is done.
+func (r *Relay) forwardLine(ctx context.Context, line []byte) error {
+	if len(line) == 0 || (len(line) == 1 && line[0] == '\r') {
+		return nil
+	}
+	e, err := ParseLine(string(line))
+	if err != nil {
+		r.skipped.Add(1)
+		return nil
+	}
+	return r.forward(ctx, e)
+}
+
+// forward writes e to the sink, retrying until it succeeds or ctx is done.
+// A failing sink is reported on stderr once, and again only after it has
+// recovered.
+func (r *Relay) forward(ctx context.Context, e *Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for {
+		err := r.cfg.Sink.WriteEntry(e)
+		if err == nil {
+			r.failing = false
+			r.forwarded.Add(1)
+			return nil
+		}
+		if !r.failing {
+			fmt.Fprintf(os.Stderr, "logger: relay sink %T failed: %v\n", r.cfg.Sink, err)
+			r.failing = true
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.cfg.RetryInterval):
+		}
+	}
+}
+
+// ServeSocket listens on the unix socket at path for entries from
+// SocketSink clients, "unixgram" for datagrams or "unix" for streams, in
+// any encoding SocketSink writes, and forwards them until ctx is done. A
+// stale socket file left at path is replaced. It returns nil once ctx is
+// done and the entries already received have been forwarded.
+func (r *Relay) ServeSocket(ctx context.Context, network, path string) error {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	switch network {
+	case "unixgram":
+		pc, err := net.ListenPacket(network, path)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(path)
+		stop := context.AfterFunc(ctx, func() { pc.Close() })
+		defer stop()
+		buf := make([]byte, 1<<20)
+		for {
+			n, _, err := pc.ReadFrom(buf)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			e, err := decodeRecord(buf[:n])
+			if err != nil {
+				r.skipped.Add(1)
+				continue
+			}
+			if r.forward(ctx, e) != nil {
+				return nil
+			}
+		}
+	case "unix":
+		ln, err := net.Listen(network, path)
+		if err != nil {
+			return err
+		}
+		stop := context.AfterFunc(ctx, func() { ln.Close() })
+		defer stop()
+		var wg sync.WaitGroup
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				wg.Wait()
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.serveStream(ctx, conn)
+			}()
+		}
+	}
+	return fmt.Errorf("logger: unsupported relay socket network %q", network)
+}
+
+// serveStream forwards the newline-delimited or length-prefixed entries
+// read from conn until it closes or ctx is done.
+func (r *Relay) serveStream(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	br := bufio.NewReaderSize(conn, 64*1024)
+	lr := &LogReader{r: br}
+	if head, err := br.Peek(1); err == nil && head[0] == 0 {
+		// a 4-byte big-endian length below 16 MiB starts with a zero byte
+		lr.format = "msgpack"
+	}
+	defer func() { r.skipped.Add(int64(lr.Skipped())) }()
+	for {
+		e, err := lr.Next()
+		if err != nil {
+			return
+		}
+		if r.forward(ctx, e) != nil {
+			return
+		}
+	}
+}
+
+// decodeRecord decodes one datagram: a MessagePack record, which starts
+// with a map byte, or a text or JSON line.
+func decodeRecord(data []byte) (*Entry, error) {
+	if len(data) > 0 && data[0] >= 0x80 {
+		return decodeMsgpackEntry(data)
+	}
+	return ParseLine(string(data))
+}
+
+func (r *Relay) position(path string) int64 {
+	r.posMu.Lock()
+	defer r.posMu.Unlock()
+	return r.positions[path]
+}
+
+func (r *Relay) setPosition(path string, off int64) {
+	r.posMu.Lock()
+	defer r.posMu.Unlock()
+	r.positions[path] = off
+	r.dirty = true
+}
+
+// savePositions writes the positions to cfg.PositionFile when they
+// changed, replacing it atomically.
+func (r *Relay) savePositions() error {
+	if r.cfg.PositionFile == "" {
+		return nil
+	}
+	r.posMu.Lock()
+	defer r.posMu.Unlock()
+	if !r.dirty {
+		return nil
+	}
+	data, err := json.Marshal(r.positions)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.cfg.PositionFile), 0755); err != nil {
+		return err
+	}
+	tmp := r.cfg.PositionFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, r.cfg.PositionFile); err != nil {
+		return err
+	}
+	r.dirty = false
+	return nil
+}
@@ -0,0 +1,192 @@
+package logger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Files kept in ReliableConfig.Dir.
+const (
+	reliableSpoolFile  = "spool.jsonl"
+	reliableCursorFile = "cursor"
+)
+
+// ReliableConfig configures NewReliableSink.
+type ReliableConfig struct {
+	// Dir holds the spool of undelivered entries and the delivery cursor.
+	// Use one directory per sink. Required.
+	Dir string
+	// OnAck is called after the sink accepted an entry, with the entry's
+	// sequence number. Sequence numbers increase across restarts.
+	OnAck func(seq uint64, e *Entry)
+	// Sync flushes the spool to disk after every entry, so entries survive
+	// a machine crash and not only a process restart.
+	Sync bool
+}
+
+// ReliableSink gives a sink at-least-once delivery for audit and compliance
+// events. Each entry is appended to a spool file before it is handed to the
+// wrapped sink, and a cursor file records the last entry the sink accepted.
+// Entries the sink rejects stay spooled and are retried, in order, with the
+// next entry and on Close; entries left over from a previous run are
+// delivered first by NewReliableSink. An entry can therefore be delivered
+// more than once, e.g. when the process stops between a write and the
+// cursor update, but it is never silently lost:
+//
+//	audit, err := logger.NewSyslogSink(logger.SyslogConfig{Network: "relp", Address: "siem:2514"})
+//	...
+//	sink, err := logger.NewReliableSink(audit, logger.ReliableConfig{Dir: "/var/lib/app/audit-spool"})
+//	...
+//	logger.AddRoute(logger.Route{Fields: map[string]string{"audit": "true"}, Sinks: []logger.Sink{sink}})
+//
+// Logging blocks rather than dropping entries when the sink's queue is full.
+// Undelivered entries are also kept in memory until delivered. Replayed
+// entries are re-read from their JSON form, so field values become strings
+// and numbers.
+type ReliableSink struct {
+	sink   Sink
+	cfg    ReliableConfig
+	spool  *os.File
+	queue  []spooledEntry
+	next   uint64
+	cursor uint64
+}
+
+type spooledEntry struct {
+	seq uint64
+	e   *Entry
+}
+
+// NewReliableSink wraps sink with a spool in cfg.Dir and delivers any
+// entries a previous run left undelivered.
+func NewReliableSink(sink Sink, cfg ReliableConfig) (*ReliableSink, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("logger: reliable sink requires a directory")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0700); err != nil {
+		return nil, err
+	}
+	r := &ReliableSink{sink: sink, cfg: cfg}
+	if data, err := os.ReadFile(filepath.Join(cfg.Dir, reliableCursorFile)); err == nil {
+		if r.cursor, err = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64); err != nil {
+			return nil, fmt.Errorf("logger: reliable sink cursor: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	r.next = r.cursor + 1
+
+	spool, err := os.OpenFile(filepath.Join(cfg.Dir, reliableSpoolFile), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	r.spool = spool
+	if err := r.load(); err != nil {
+		spool.Close()
+		return nil, err
+	}
+	// a failure leaves the entries spooled for the next write
+	r.deliver()
+	return r, nil
+}
+
+// load reads the entries after the cursor from the spool.
+func (r *ReliableSink) load() error {
+	scanner := bufio.NewScanner(r.spool)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		seqText, line, ok := strings.Cut(scanner.Text(), " ")
+		seq, err := strconv.ParseUint(seqText, 10, 64)
+		if !ok || err != nil {
+			// a line cut short by a crash
+			continue
+		}
+		if seq >= r.next {
+			r.next = seq + 1
+		}
+		if seq <= r.cursor {
+			continue
+		}
+		e, err := ParseLine(line)
+		if err != nil {
+			continue
+		}
+		r.queue = append(r.queue, spooledEntry{seq: seq, e: e})
+	}
+	return scanner.Err()
+}
+
+// WriteEntry spools e, then delivers it and any earlier undelivered entries.
+// An error from the wrapped sink is returned, but the entries stay spooled.
+func (r *ReliableSink) WriteEntry(e *Entry) error {
+	seq := r.next
+	if _, err := fmt.Fprintf(r.spool, "%d %s\n", seq, encodeJSON(e)); err != nil {
+		return fmt.Errorf("logger: reliable sink spool: %w", err)
+	}
+	if r.cfg.Sync {
+		if err := r.spool.Sync(); err != nil {
+			return fmt.Errorf("logger: reliable sink spool: %w", err)
+		}
+	}
+	r.next++
+	r.queue = append(r.queue, spooledEntry{seq: seq, e: e})
+	return r.deliver()
+}
+
+func (r *ReliableSink) lossless() {}
+
+// Pending returns the number of spooled entries not yet delivered.
+func (r *ReliableSink) Pending() int {
+	return len(r.queue)
+}
+
+// deliver hands queued entries to the sink in order, advancing the cursor
+// after each one, and empties the spool once nothing is pending.
+func (r *ReliableSink) deliver() error {
+	for len(r.queue) > 0 {
+		se := r.queue[0]
+		if err := r.sink.WriteEntry(se.e); err != nil {
+			return err
+		}
+		if err := r.saveCursor(se.seq); err != nil {
+			return err
+		}
+		r.queue = r.queue[1:]
+		if r.cfg.OnAck != nil {
+			r.cfg.OnAck(se.seq, se.e)
+		}
+	}
+	return r.spool.Truncate(0)
+}
+
+// saveCursor records seq as delivered, replacing the cursor file atomically.
+func (r *ReliableSink) saveCursor(seq uint64) error {
+	path := filepath.Join(r.cfg.Dir, reliableCursorFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatUint(seq, 10)+"\n"), 0600); err != nil {
+		return fmt.Errorf("logger: reliable sink cursor: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("logger: reliable sink cursor: %w", err)
+	}
+	r.cursor = seq
+	return nil
+}
+
+// Close makes a last delivery attempt and closes the spool and the wrapped
+// sink. Undelivered entries stay in the spool for the next run.
+func (r *ReliableSink) Close() error {
+	err := r.deliver()
+	if serr := r.spool.Close(); err == nil {
+		err = serr
+	}
+	if cerr := r.sink.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,199 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// remotePolicyCaller is shown on entries from WatchRemotePolicy.
+const remotePolicyCaller = "logger.WatchRemotePolicy"
+
+// remotePolicyMaxSize bounds the policy documents WatchRemotePolicy reads.
+const remotePolicyMaxSize = 1 << 20
+
+// Policy is the JSON document fetched by WatchRemotePolicy:
+//
+//	{"levels": ">=INFO,http.client=DEBUG", "sample": {"DEBUG": 0.1}, "drop": ["health check"]}
+//
+// ERROR and FATAL entries are never sampled or dropped, so a bad policy
+// cannot hide failures.
+type Policy struct {
+	// Levels uses the LOGGER_LEVELS syntax. Empty keeps the levels in
+	// effect when WatchRemotePolicy was called.
+	Levels string `json:"levels,omitempty"`
+	// Sample is the fraction of entries kept per level, from 0 to 1; every
+	// entry is kept at levels it leaves out.
+	Sample map[Level]float64 `json:"sample,omitempty"`
+	// Drop discards entries whose message contains any of these strings.
+	Drop []string `json:"drop,omitempty"`
+}
+
+// RemotePolicyConfig configures WatchRemotePolicy.
+type RemotePolicyConfig struct {
+	// URL is the https:// address of the policy document.
+	URL string
+	// Interval is the time between fetches. Defaults to one minute.
+	Interval time.Duration
+	// HTTP configures the client, e.g. a CA bundle or client certificate.
+	HTTP HTTPClientConfig
+}
+
+var (
+	// policy is the remote policy in effect, nil when there is none
+	policy *activePolicy
+	// policyNamed lists the named loggers whose level the policy set
+	policyNamed []string
+)
+
+// activePolicy is a validated Policy along with its sampling counters.
+type activePolicy struct {
+	Policy
+	spec levelSpec
+	seen map[Level]uint64
+}
+
+// WatchRemotePolicy fetches a Policy from cfg.URL now and every
+// cfg.Interval, and applies it, letting a fleet's verbosity be adjusted
+// centrally without redeploys:
+//
+//	stop, err := logger.WatchRemotePolicy(logger.RemotePolicyConfig{
+//	    URL:  "https://config.example.com/logging/gateway.json",
+//	    HTTP: logger.HTTPClientConfig{CAFile: "/etc/pki/corp-root.pem"},
+//	})
+//	// [INFO] [logger.WatchRemotePolicy] remote policy applied url=https://config.example.com/logging/gateway.json etag="v7" levels=DEBUG,INFO,WARN,ERROR,FATAL
+//
+// Requests carry the ETag of the last policy in If-None-Match, so an
+// unchanged policy costs a 304 response. A policy that fails to fetch,
+// parse, or validate is logged at WARN and the previous one stays in
+// effect. The returned function stops fetching and waits for a fetch in
+// progress; the last policy stays in effect.
+func WatchRemotePolicy(cfg RemotePolicyConfig) (stop func(), err error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("logger: policy URL: %w", err)
+	}
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("logger: policy URL %q is not https", cfg.URL)
+	}
+	client, err := NewHTTPClient(cfg.HTTP)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if !globalDisabled.Load() {
+		ensureInit()
+	}
+	base := levelFilter.Load()
+
+	var etag string
+	return runWatchdog(cfg.Interval, func() {
+		p, tag, err := fetchPolicy(client, cfg.URL, etag)
+		if err != nil {
+			watchdogLog(WarnLevel, remotePolicyCaller, "remote policy fetch failed", "url", cfg.URL, "error", err)
+			return
+		}
+		if p == nil {
+			return
+		}
+		etag = tag
+		applyPolicy(p, base)
+		watchdogLog(InfoLevel, remotePolicyCaller, "remote policy applied", "url", cfg.URL, "etag", tag,
+			"levels", enabledLevelNames(p.spec.levels))
+	}), nil
+}
+
+// fetchPolicy fetches and validates the policy at url. It returns a nil
+// policy when the server reports that etag is still current.
+func fetchPolicy(client *http.Client, url, etag string) (*activePolicy, string, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil, etag, nil
+	case http.StatusOK:
+	default:
+		return nil, "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var p activePolicy
+	dec := json.NewDecoder(io.LimitReader(resp.Body, remotePolicyMaxSize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p.Policy); err != nil {
+		return nil, "", fmt.Errorf("parsing policy: %w", err)
+	}
+	spec, errs := parseLevelSpec(p.Levels)
+	for level, ratio := range p.Sample {
+		if ratio < 0 || ratio > 1 {
+			errs = append(errs, fmt.Errorf("sample ratio %v for %s is outside 0..1", ratio, level))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, "", errors.Join(errs...)
+	}
+	p.spec, p.seen = spec, map[Level]uint64{}
+	return &p, resp.Header.Get("ETag"), nil
+}
+
+// applyPolicy makes p the policy in effect. An empty Levels restores the
+// levels of base, and named loggers set by the previous policy but not by p
+// inherit their parent's level again. The enabled and package levels are
+// switched together, so entries logged meanwhile see either the old or the
+// new ones.
+func applyPolicy(p *activePolicy, base *levelState) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	if p.Levels == "" {
+		p.spec = levelSpec{levels: base.enabled, packages: base.packages}
+	}
+	setLevels(p.spec.levels, p.spec.packages)
+	for _, name := range policyNamed {
+		if _, ok := p.spec.loggers[name]; !ok {
+			Get(name).ResetLevel()
+		}
+	}
+	policyNamed = policyNamed[:0]
+	for name, level := range p.spec.loggers {
+		Get(name).SetLevel(level)
+		policyNamed = append(policyNamed, name)
+	}
+	policy = p
+}
+
+// policyDropped reports whether the remote policy filters out e. Callers
+// must hold logMutex.
+func policyDropped(e *Entry) bool {
+	if policy == nil || e.Level >= ErrorLevel {
+		return false
+	}
+	for _, s := range policy.Drop {
+		if strings.Contains(e.Message, s) {
+			return true
+		}
+	}
+	ratio, ok := policy.Sample[e.Level]
+	if !ok {
+		return false
+	}
+	// keep the entries that carry the kept count past a whole number
+	n := policy.seen[e.Level]
+	policy.seen[e.Level] = n + 1
+	return uint64(float64(n+1)*ratio) == uint64(float64(n)*ratio)
+}
@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// ResourceEvent is the event ID of the entries written by
+// Config.ResourceAdvisory.
+const ResourceEvent = "logger.resources"
+
+// MemoryAdvisoryRatio is the fraction of the cgroup memory limit in use at
+// which Config.ResourceAdvisory warns.
+const MemoryAdvisoryRatio = 0.9
+
+// cgroupRoot is where the cgroup filesystem is mounted.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// logResourceAdvisory writes the Config.ResourceAdvisory entries: one when
+// GOMAXPROCS exceeds the cgroup CPU quota, which the Go runtime only allows
+// when GOMAXPROCS is set explicitly, and one when memory usage is at
+// MemoryAdvisoryRatio of the cgroup memory limit. Processes outside a
+// limited cgroup, and platforms without cgroups, get no entries. Callers
+// must be InitWithConfig.
+func logResourceAdvisory() {
+	if quota, ok := cgroupCPUQuota(); ok {
+		if procs := runtime.GOMAXPROCS(0); float64(procs) > math.Ceil(quota) {
+			initEntry(WarnLevel, "GOMAXPROCS exceeds the CPU quota; the process will be throttled", []any{
+				EventKey, ResourceEvent,
+				"gomaxprocs", procs,
+				"cpu_quota", strconv.FormatFloat(quota, 'f', -1, 64),
+			})
+		}
+	}
+	if usage, limit, ok := cgroupMemory(); ok && float64(usage) >= MemoryAdvisoryRatio*float64(limit) {
+		keyvals := []any{
+			EventKey, ResourceEvent,
+			"memory_usage", usage,
+			"memory_limit", limit,
+		}
+		if gomemlimit := debug.SetMemoryLimit(-1); gomemlimit != math.MaxInt64 {
+			keyvals = append(keyvals, "gomemlimit", gomemlimit)
+		}
+		initEntry(WarnLevel, "memory usage is near the cgroup limit", keyvals)
+	}
+}
+
+// cgroupCPUQuota returns the CPUs the process's cgroup may use, from
+// cpu.max (cgroup v2) or cpu.cfs_quota_us and cpu.cfs_period_us (v1).
+func cgroupCPUQuota() (cpus float64, ok bool) {
+	if b, err := os.ReadFile(filepath.Join(cgroupDir(), "cpu.max")); err == nil {
+		quota, period, _ := strings.Cut(strings.TrimSpace(string(b)), " ")
+		return cpuRatio(quota, period)
+	}
+	quota, err := os.ReadFile(filepath.Join(cgroupRoot, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, false
+	}
+	period, err := os.ReadFile(filepath.Join(cgroupRoot, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false
+	}
+	return cpuRatio(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+// cpuRatio divides a CFS quota by its period; "max" and negative quotas
+// mean unlimited.
+func cpuRatio(quota, period string) (float64, bool) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, false
+	}
+	return q / p, true
+}
+
+// cgroupMemory returns the memory usage and limit of the process's cgroup,
+// from memory.current and memory.max (cgroup v2) or memory.usage_in_bytes
+// and memory.limit_in_bytes (v1).
+func cgroupMemory() (usage, limit int64, ok bool) {
+	dir, current, max := cgroupDir(), "memory.current", "memory.max"
+	if _, err := os.Stat(filepath.Join(dir, max)); err != nil {
+		dir, current, max = filepath.Join(cgroupRoot, "memory"), "memory.usage_in_bytes", "memory.limit_in_bytes"
+	}
+	limit, ok = readCgroupInt(filepath.Join(dir, max))
+	// cgroup v1 reports no limit as a huge page-aligned number
+	if !ok || limit <= 0 || limit >= 1<<62 {
+		return 0, 0, false
+	}
+	usage, ok = readCgroupInt(filepath.Join(dir, current))
+	return usage, limit, ok
+}
+
+// cgroupDir returns the cgroup v2 directory of the process: its path from
+// /proc/self/cgroup under cgroupRoot, or cgroupRoot itself inside a cgroup
+// namespace, where the path is not visible.
+func cgroupDir() string {
+	f, err := os.Open(cgroupPath)
+	if err != nil {
+		return cgroupRoot
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(scanner.Text(), "0::"); ok {
+			dir := filepath.Join(cgroupRoot, path)
+			if _, err := os.Stat(dir); err == nil {
+				return dir
+			}
+		}
+	}
+	return cgroupRoot
+}
+
+// readCgroupInt reads a cgroup file holding one integer; "max" is not one.
+func readCgroupInt(path string) (int64, bool) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultRetryWarnAfter is the attempt from which RetryNotify logs at WARN.
+const DefaultRetryWarnAfter = 3
+
+// RetryConfig configures RetryNotifyConfig.
+type RetryConfig struct {
+	// WarnAfter is the first failed attempt logged at WARN; earlier ones are
+	// DEBUG. Defaults to DefaultRetryWarnAfter.
+	WarnAfter int
+	// MaxAttempts, when set, logs the failure of that attempt at ERROR as
+	// the final one.
+	MaxAttempts int
+}
+
+// RetryNotify returns a callback for retry libraries that logs each failed
+// attempt of op with attempt, delay, and error fields: DEBUG for the first
+// attempts, WARN from DefaultRetryWarnAfter on, and ERROR for the final
+// failure, signaled by a negative delay:
+//
+//	notify := logger.RetryNotify("fetch-config")
+//	err := backoff.RetryNotify(fetch, backoff.NewExponentialBackOff(), notify)
+//	if err != nil {
+//	    notify(err, -1) // gave up
+//	}
+//
+// Entries report the caller of RetryNotify. The callback is safe for
+// concurrent use; each notifier counts its own attempts.
+func RetryNotify(op string) func(err error, delay time.Duration) {
+	return retryNotify(op, RetryConfig{}, getCallerInfo(2))
+}
+
+// RetryNotifyConfig is RetryNotify with explicit thresholds.
+func RetryNotifyConfig(op string, cfg RetryConfig) func(err error, delay time.Duration) {
+	return retryNotify(op, cfg, getCallerInfo(2))
+}
+
+func retryNotify(op string, cfg RetryConfig, caller string) func(error, time.Duration) {
+	if cfg.WarnAfter <= 0 {
+		cfg.WarnAfter = DefaultRetryWarnAfter
+	}
+	var attempts atomic.Int64
+	return func(err error, delay time.Duration) {
+		attempt := int(attempts.Add(1))
+		level := DebugLevel
+		msg := op + " failed, retrying"
+		keyvals := []any{"op", op, "attempt", attempt, "delay", delay, "error", err}
+		switch {
+		case delay < 0 || (cfg.MaxAttempts > 0 && attempt >= cfg.MaxAttempts):
+			level = ErrorLevel
+			msg = fmt.Sprintf("%s failed after %d attempts", op, attempt)
+			keyvals = []any{"op", op, "attempts", attempt, "error", err}
+		case attempt >= cfg.WarnAfter:
+			level = WarnLevel
+		}
+		if !isLevelEnabled(level) {
+			return
+		}
+		logMutex.Lock()
+		defer logMutex.Unlock()
+		output(loggerFor(level), level, caller, msg, keyvals)
+	}
+}
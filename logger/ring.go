@@ -0,0 +1,72 @@
+package logger
+
+import "sync"
+
+// DefaultRingSize is the number of entries a RingSink keeps when created
+// with a size of 0.
+const DefaultRingSize = 10000
+
+// RingSink keeps the most recent entries in memory, for viewers and bug
+// report bundles that need the last few thousand lines without a log file:
+//
+//	ring := logger.NewRingSink(0)
+//	logger.AddSink(ring)
+//	...
+//	for _, e := range ring.Entries() { ... }
+type RingSink struct {
+	mu      sync.Mutex
+	entries []*Entry
+	next    int    // slot for the next entry once the ring is full
+	total   uint64 // entries written since creation
+
+	// notify receives a value whenever entries are added
+	notify chan struct{}
+}
+
+// NewRingSink returns a ring holding up to size entries.
+func NewRingSink(size int) *RingSink {
+	if size <= 0 {
+		size = DefaultRingSize
+	}
+	return &RingSink{entries: make([]*Entry, 0, size), notify: make(chan struct{}, 1)}
+}
+
+// WriteEntry stores e, replacing the oldest entry once the ring is full.
+func (r *RingSink) WriteEntry(e *Entry) error {
+	r.mu.Lock()
+	if len(r.entries) < cap(r.entries) {
+		r.entries = append(r.entries, e)
+	} else {
+		r.entries[r.next] = e
+		r.next = (r.next + 1) % len(r.entries)
+	}
+	r.total++
+	r.mu.Unlock()
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Close keeps the stored entries available.
+func (r *RingSink) Close() error {
+	return nil
+}
+
+// Entries returns the stored entries, oldest first.
+func (r *RingSink) Entries() []*Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]*Entry, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	return append(out, r.entries[:r.next]...)
+}
+
+// Total returns the number of entries written since the ring was created,
+// including those it no longer holds.
+func (r *RingSink) Total() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.total
+}
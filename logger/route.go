@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LoggerKey is the field naming the logger an entry came from. Route.Logger
+// matches on it.
+const LoggerKey = "logger"
+
+// Route sends entries matching all of its conditions to its own sinks,
+// turning the logger into an in-process log router:
+//
+//	logger.AddRoute(logger.Route{Fields: map[string]string{"category": "audit"}, Sinks: []logger.Sink{auditFile}, Exclusive: true})
+//	logger.AddRoute(logger.Route{MinLevel: logger.ErrorLevel, Sinks: []logger.Sink{alerts}})
+//
+// Routes are evaluated in the order they were added. Empty conditions match
+// every entry. Route sinks are closed by Close; a sink shared by several
+// routes or also added with AddSink still gets each entry once per match.
+type Route struct {
+	// MinLevel is the lowest level the route matches.
+	MinLevel Level
+	// Logger matches entries whose logger field names this logger or one of
+	// its children ("http" matches "http" and "http.client").
+	Logger string
+	// Package matches the caller's package name, e.g. "store" for callers
+	// reported as "store.(*DB).Query:42".
+	Package string
+	// Fields match entries carrying each key with the given value, compared
+	// in fmt.Sprint form.
+	Fields map[string]string
+	// Sinks receive matching entries.
+	Sinks []Sink
+	// Exclusive keeps matching entries away from the console, the log file,
+	// and sinks added with AddSink.
+	Exclusive bool
+	// Final stops evaluation of later routes for matching entries.
+	Final bool
+}
+
+// routeState pairs a Route with the workers feeding its sinks.
+type routeState struct {
+	Route
+	workers []*sinkWorker
+}
+
+// routes are evaluated by writeEntry before the default outputs
+var routes []*routeState
+
+// AddRoute appends r to the routing table.
+func AddRoute(r Route) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	rs := &routeState{Route: r}
+	for _, s := range r.Sinks {
+		rs.workers = append(rs.workers, workerFor(s))
+	}
+	routes = append(routes, rs)
+}
+
+// matches reports whether e satisfies every condition of r.
+func (r *Route) matches(e *Entry) bool {
+	if e.Level < r.MinLevel {
+		return false
+	}
+	if r.Package != "" {
+		pkg, _, _ := strings.Cut(e.Caller, ".")
+		if pkg != r.Package {
+			return false
+		}
+	}
+	if r.Logger != "" {
+		name, ok := fieldValue(e.Fields, LoggerKey)
+		if !ok || (name != r.Logger && !strings.HasPrefix(name, r.Logger+".")) {
+			return false
+		}
+	}
+	for key, want := range r.Fields {
+		if got, ok := fieldValue(e.Fields, key); !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldValue returns the fmt.Sprint form of the first value for key.
+func fieldValue(keyvals []any, key string) (string, bool) {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if k, ok := keyvals[i].(string); ok && k == key {
+			return fmt.Sprint(keyvals[i+1]), true
+		}
+	}
+	return "", false
+}
+
+// routeEntry delivers e to the sinks of matching routes and reports whether
+// a matching route was exclusive. Callers must hold logMutex.
+func routeEntry(e *Entry) (exclusive bool) {
+	for i, r := range routes {
+		if !r.matches(e) {
+			continue
+		}
+		if e.trace != nil {
+			e.trace.step("route", fmt.Sprintf("#%d", i+1), "matched")
+		}
+		for _, w := range r.workers {
+			w.enqueue(e)
+		}
+		exclusive = exclusive || r.Exclusive
+		if r.Final {
+			break
+		}
+	}
+	return exclusive
+}
@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SanitizeMode controls how untrusted text in messages and field values is
+// cleaned before output.
+type SanitizeMode int
+
+const (
+	// SanitizeNone writes messages and values as given (the default).
+	SanitizeNone SanitizeMode = iota
+	// SanitizeBasic cleans the message and string field values of each
+	// entry: invalid UTF-8 becomes U+FFFD, ANSI escape sequences are
+	// removed, and control characters other than tab are escaped, e.g. a
+	// newline as \n, so input from users cannot forge lines or drive the
+	// terminal of whoever reads the logs.
+	SanitizeBasic
+	// SanitizeStrict also cleans every other field value as printed, such
+	// as errors and Stringers, and marks entries whose message or values
+	// held a CR, LF, or escape character with InjectionKey=true, as a
+	// defense against CRLF log forging in access logs.
+	SanitizeStrict
+)
+
+// InjectionKey is the field SanitizeStrict adds to entries that held
+// sequences used to forge log lines.
+const InjectionKey = "log_injection"
+
+// sanitizeMode is set from Config.Sanitize at Init.
+var sanitizeMode SanitizeMode
+
+// sanitizeEntry returns msg and keyvals cleaned according to sanitizeMode.
+// keyvals is copied before it is changed.
+func sanitizeEntry(msg string, keyvals []any) (string, []any) {
+	strict := sanitizeMode == SanitizeStrict
+	suspicious := strict && forgesLines(msg)
+	msg = sanitizeString(msg)
+	copied := false
+	for i, v := range keyvals {
+		s, ok := v.(string)
+		if !ok && strict {
+			s, ok = valueText(v)
+		}
+		if !ok {
+			continue
+		}
+		suspicious = suspicious || (strict && forgesLines(s))
+		if clean := sanitizeString(s); clean != s {
+			if !copied {
+				keyvals, copied = append([]any(nil), keyvals...), true
+			}
+			keyvals[i] = clean
+		}
+	}
+	if suspicious {
+		keyvals = appendMissing(keyvals, []any{InjectionKey, true})
+	}
+	return msg, keyvals
+}
+
+// forgesLines reports whether s holds a CR, LF, or escape character.
+func forgesLines(s string) bool {
+	return strings.ContainsAny(s, "\r\n\033")
+}
+
+// valueText returns v as printed, or false for values whose text cannot
+// hold control characters.
+func valueText(v any) (string, bool) {
+	switch v.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64, time.Duration, time.Time:
+		return "", false
+	}
+	return fmt.Sprint(v), true
+}
+
+// sanitizeString returns s with invalid UTF-8 replaced, ANSI escape
+// sequences removed, and control characters other than tab escaped.
+func sanitizeString(s string) string {
+	if !needsSanitizing(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\033':
+			i += ansiSequenceLen(s[i:])
+			continue
+		case r == utf8.RuneError && size == 1:
+			b.WriteRune(utf8.RuneError)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r != '\t' && unicode.IsControl(r):
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// needsSanitizing reports whether s holds invalid UTF-8 or control
+// characters other than tab.
+func needsSanitizing(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < 0x20 && c != '\t') || c >= 0x7f {
+			return !utf8.ValidString(s[i:]) || strings.ContainsFunc(s[i:], func(r rune) bool {
+				return r != '\t' && unicode.IsControl(r)
+			})
+		}
+	}
+	return false
+}
+
+// ansiSequenceLen returns the length of the escape sequence at the start
+// of s: a CSI sequence such as "\033[31m", an OSC sequence such as a
+// terminal title ending in BEL or ST, or ESC and the character after it.
+func ansiSequenceLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+			if s[i] < 0x20 || s[i] > 0x7e {
+				return i
+			}
+		}
+		return len(s)
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	_, size := utf8.DecodeRuneInString(s[1:])
+	return 1 + size
+}
@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// SelfTestEvent is the event ID of the entry written by SelfTest.
+const SelfTestEvent = "logger.selftest"
+
+// selfTestJournalWait bounds how long SelfTest looks for its entry in the
+// journal, which stores entries asynchronously.
+var selfTestJournalWait = 3 * time.Second
+
+// SelfTestResult reports the delivery of the SelfTest entry to one output.
+type SelfTestResult struct {
+	// Output is "console", "file", "journald", or the type of a sink
+	// added with AddSink, e.g. "*logger.SyslogSink".
+	Output string
+	// Target is the file path or journal identifier, if any.
+	Target string
+	// Verified is true when delivery was confirmed: the file grew or the
+	// journal holds the entry. Otherwise the entry was written without an
+	// error but could not be checked.
+	Verified bool
+	// Err is why delivery failed.
+	Err error
+}
+
+// String formats r as a line of a report, e.g.
+// "verified file /var/log/app.log".
+func (r SelfTestResult) String() string {
+	status := "written"
+	switch {
+	case r.Err != nil:
+		status = "FAILED"
+	case r.Verified:
+		status = "verified"
+	}
+	line := status + " " + r.Output
+	if r.Target != "" {
+		line += " " + r.Target
+	}
+	if r.Err != nil {
+		line += ": " + r.Err.Error()
+	}
+	return line
+}
+
+// SelfTest writes an INFO entry with event=logger.selftest and a unique
+// token to the console, the log file, and every sink added with AddSink,
+// waits for the sinks to process it, and checks its delivery where it can:
+// files must have grown, the journal is searched with journalctl, and
+// other sinks, such as syslog or custom HTTP sinks, must not have returned
+// an error. It returns one result per output and the failures joined with
+// errors.Join, for deployment smoke tests:
+//
+//	results, err := logger.SelfTest()
+//	for _, r := range results {
+//	    fmt.Println(r)
+//	}
+//	if err != nil {
+//	    os.Exit(1)
+//	}
+//
+// cmd/logcheck runs it for a configuration file.
+func SelfTest() ([]SelfTestResult, error) {
+	if !globalDisabled.Load() {
+		ensureInit()
+	}
+	now := time.Now()
+	token := newEntryID(now)
+	e := &Entry{Time: now, Level: InfoLevel, Caller: "logger.SelfTest", Message: "logger self test",
+		Fields: []any{EventKey, SelfTestEvent, "token", token}}
+
+	results, journal := selfTestOutputs(e)
+	if journal {
+		r := SelfTestResult{Output: "journald", Target: Identifier()}
+		r.Verified, r.Err = journalHas(r.Target, token)
+		results = append(results, r)
+	}
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("logger: self test: %s: %w", r.Output, r.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// selfTestOutputs writes e with no other entries in flight and checks the
+// console, log file, and sinks. It reports whether the console goes to the
+// journal, which is checked without logMutex.
+func selfTestOutputs(e *Entry) (results []SelfTestResult, journal bool) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	for _, rs := range sinks {
+		rs.w.pending.Wait()
+	}
+
+	fileSize := func(f *os.File) int64 {
+		info, err := f.Stat()
+		if err != nil {
+			return -1
+		}
+		return info.Size()
+	}
+	type check struct {
+		rs      *registeredSink
+		size    int64
+		dropped int64
+	}
+	var checks []check
+	for _, rs := range sinks {
+		if _, ok := rs.levels.apply(e); !ok {
+			continue
+		}
+		c := check{rs: rs, size: -1, dropped: rs.w.dropped.Load()}
+		if fs, ok := rs.w.sink.(*FileSink); ok {
+			c.size = fileSize(fs.f)
+		}
+		checks = append(checks, c)
+	}
+	var logSize int64 = -1
+	fe, toFile := fileLevels.apply(e)
+	toFile = toFile && logFile != nil && fileLoggers[fe.Level] != nil
+	if toFile {
+		logSize = fileSize(logFile)
+	}
+
+	writeEntry(Info, e)
+	if fileBatch != nil {
+		fileBatch.Flush()
+	}
+	for _, c := range checks {
+		c.rs.w.pending.Wait()
+	}
+
+	if _, ok := consoleLevels.apply(e); ok && Info.Writer() != io.Discard {
+		journal = journalStream != nil || underJournald()
+		if !journal {
+			results = append(results, SelfTestResult{Output: "console"})
+		}
+	}
+	if toFile {
+		r := SelfTestResult{Output: "file", Target: logFile.Name(), Verified: true}
+		if fileSize(logFile) <= logSize {
+			r.Verified, r.Err = false, errors.New("log file did not grow")
+		}
+		results = append(results, r)
+	}
+	for _, c := range checks {
+		w := c.rs.w
+		r := SelfTestResult{Output: fmt.Sprintf("%T", w.sink), Err: w.err}
+		if w.dropped.Load() > c.dropped {
+			r.Err = errors.New("entry dropped from a full queue")
+		}
+		if fs, ok := w.sink.(*FileSink); ok {
+			r.Target = fs.f.Name()
+			if r.Err == nil {
+				r.Verified = fileSize(fs.f) > c.size
+				if !r.Verified {
+					r.Err = errors.New("file did not grow")
+				}
+			}
+		}
+		results = append(results, r)
+	}
+	return results, journal
+}
+
+// journalHas reports whether the journal holds an entry of identifier
+// containing token. Without journalctl, delivery is left unverified.
+func journalHas(identifier, token string) (bool, error) {
+	journalctl, err := exec.LookPath("journalctl")
+	if err != nil {
+		return false, nil
+	}
+	deadline := time.Now().Add(selfTestJournalWait)
+	for {
+		out, err := exec.Command(journalctl, "-q", "-o", "cat", "-t", identifier, "--since", "-5min").Output()
+		if err != nil {
+			return false, fmt.Errorf("journalctl: %w", err)
+		}
+		if strings.Contains(string(out), token) {
+			return true, nil
+		}
+		if time.Now().After(deadline) {
+			return false, errors.New("entry not found in the journal")
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
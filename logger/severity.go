@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"io"
+	"strconv"
+)
+
+// SeverityMode controls whether numeric syslog severities are included in output.
+type SeverityMode int
+
+const (
+	// SeverityNone writes no numeric severity (the default).
+	SeverityNone SeverityMode = iota
+	// SeverityField adds a severity=N field to every entry.
+	SeverityField
+	// SeverityPrefix starts every line with "<N>", the sd-daemon convention
+	// that systemd-journald and many syslog relays parse as the priority.
+	SeverityPrefix
+)
+
+// SeverityKey is the field name used by SeverityField.
+const SeverityKey = "severity"
+
+// syslogSeverities maps levels to RFC 5424 severities
+// (0 emerg ... 7 debug). FATAL maps to 2 (crit).
+var syslogSeverities = map[Level]int{
+	DebugLevel: 7,
+	InfoLevel:  6,
+	WarnLevel:  4,
+	ErrorLevel: 3,
+	FatalLevel: 2,
+}
+
+// severityMode is set from Config.Severity at Init.
+var severityMode SeverityMode
+
+// SyslogSeverity returns the RFC 5424 severity number for level.
+func SyslogSeverity(level Level) int {
+	return syslogSeverities[level]
+}
+
+// prefixWriter prepends a fixed prefix to every write. log.Logger issues one
+// write per line, so each line gets the prefix.
+type prefixWriter struct {
+	prefix []byte
+	w      io.Writer
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	buf := make([]byte, 0, len(p.prefix)+len(data))
+	buf = append(buf, p.prefix...)
+	buf = append(buf, data...)
+	if _, err := p.w.Write(buf); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// withSeverityPrefix wraps w so lines for level start with "<N>" when
+// SeverityPrefix is enabled.
+func withSeverityPrefix(w io.Writer, level Level) io.Writer {
+	if severityMode != SeverityPrefix || w == nil {
+		return w
+	}
+	return &prefixWriter{prefix: severityPrefix(level), w: w}
+}
+
+// severityPrefix returns the "<N>" line prefix for level.
+func severityPrefix(level Level) []byte {
+	return []byte("<" + strconv.Itoa(syslogSeverities[level]) + ">")
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// signalCaller is shown on entries logged through SignalMessage.Log.
+const signalCaller = "logger.signal"
+
+var (
+	// signalMu guards signalTable and signalCount
+	signalMu    sync.Mutex
+	signalTable [256]struct {
+		level Level
+		msg   string
+	}
+	signalCount int
+	// signalDone is closed when the goroutine draining the pipe exits
+	signalDone chan struct{}
+
+	// signalIDs[i] == i, so a message ID is written without allocating
+	signalIDs [256]byte
+
+	// signalDropped counts messages lost to a full pipe
+	signalDropped atomic.Int64
+)
+
+func init() {
+	for i := range signalIDs {
+		signalIDs[i] = byte(i)
+	}
+}
+
+// SignalMessage is a pre-registered message that can be logged from
+// contexts where taking the logger's mutex or allocating is unsafe. Log
+// writes the message's one-byte ID to a pipe without locking or
+// allocating, and a goroutine reads the pipe and logs the entry.
+type SignalMessage uint8
+
+// RegisterSignalMessage registers msg at level for logging with
+// SignalMessage.Log, and starts the goroutine that drains the pipe. Up to
+// 256 messages can be registered; register them at startup:
+//
+//	var hup, _ = logger.RegisterSignalMessage(logger.InfoLevel, "SIGHUP received, reloading")
+//
+//	func onHUP() { hup.Log() } // no lock, no allocation
+func RegisterSignalMessage(level Level, msg string) (SignalMessage, error) {
+	signalMu.Lock()
+	defer signalMu.Unlock()
+	if signalCount == len(signalTable) {
+		return 0, errors.New("logger: too many signal messages")
+	}
+	if signalCount == 0 {
+		if err := openSignalPipe(); err != nil {
+			return 0, err
+		}
+		signalDone = make(chan struct{})
+		go drainSignals(signalDone)
+	}
+	id := signalCount
+	signalTable[id].level, signalTable[id].msg = level, msg
+	signalCount++
+	return SignalMessage(id), nil
+}
+
+// Log queues m for logging. It neither blocks, locks, nor allocates; when
+// the pipe is full the message is dropped and counted, and the drop is
+// reported with the next message logged.
+func (m SignalMessage) Log() {
+	if !writeSignal(byte(m)) {
+		signalDropped.Add(1)
+	}
+}
+
+// StopSignalMessages logs the messages still in the pipe, stops the
+// goroutine draining it, and closes the pipe, so that nothing is logged
+// from the background while the logger is reconfigured or a test ends.
+// Every message is unregistered; RegisterSignalMessage starts over and
+// SignalMessage.Log drops messages in the meantime. C signal handlers must
+// stop writing to SignalFD before StopSignalMessages is called.
+func StopSignalMessages() {
+	signalMu.Lock()
+	done := signalDone
+	if done == nil {
+		signalMu.Unlock()
+		return
+	}
+	closeSignalPipe()
+	signalMu.Unlock()
+	// the goroutine takes signalMu to look up each message
+	<-done
+	signalMu.Lock()
+	signalCount, signalDone = 0, nil
+	signalMu.Unlock()
+}
+
+// drainSignals logs the messages written to the pipe until it is closed,
+// then closes done.
+func drainSignals(done chan struct{}) {
+	defer close(done)
+	buf := make([]byte, 256)
+	for {
+		n, err := readSignals(buf)
+		if err != nil {
+			return
+		}
+		if dropped := signalDropped.Swap(0); dropped > 0 {
+			watchdogLog(WarnLevel, signalCaller, "signal messages dropped, pipe full", "dropped", dropped)
+		}
+		for _, id := range buf[:n] {
+			signalMu.Lock()
+			m := signalTable[id]
+			known := int(id) < signalCount
+			signalMu.Unlock()
+			if known {
+				watchdogLog(m.level, signalCaller, m.msg)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logger
+
+import "io"
+
+var (
+	// signalQueue stands in for the signal message pipe on this platform.
+	signalQueue chan byte
+	// signalClosed is closed by closeSignalPipe
+	signalClosed chan struct{}
+)
+
+func openSignalPipe() error {
+	signalQueue, signalClosed = make(chan byte, 4096), make(chan struct{})
+	return nil
+}
+
+// closeSignalPipe makes readSignals report io.EOF once the queue is drained.
+func closeSignalPipe() {
+	close(signalClosed)
+}
+
+func writeSignal(id byte) bool {
+	select {
+	case signalQueue <- id:
+		return true
+	default:
+		return false
+	}
+}
+
+func readSignals(buf []byte) (int, error) {
+	select {
+	case buf[0] = <-signalQueue:
+	case <-signalClosed:
+		select {
+		case buf[0] = <-signalQueue:
+		default:
+			return 0, io.EOF
+		}
+	}
+	n := 1
+	for n < len(buf) {
+		select {
+		case buf[n] = <-signalQueue:
+			n++
+		default:
+			return n, nil
+		}
+	}
+	return n, nil
+}
+
+// SignalFD returns -1: this platform has no signal message pipe.
+func SignalFD() int {
+	return -1
+}
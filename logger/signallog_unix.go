@@ -0,0 +1,71 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"os"
+	"sync/atomic"
+	"syscall"
+)
+
+var (
+	// signalR and signalW are the ends of the signal message pipe
+	signalR, signalW *os.File
+	// signalFD is the non-blocking write end, -1 while the pipe is closed
+	signalFD atomic.Int32
+)
+
+func init() {
+	signalFD.Store(-1)
+}
+
+func openSignalPipe() error {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	fd := int(w.Fd())
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		r.Close()
+		w.Close()
+		return err
+	}
+	signalR, signalW = r, w
+	signalFD.Store(int32(fd))
+	return nil
+}
+
+// closeSignalPipe closes the write end, so readSignals reports io.EOF once
+// the pipe is drained.
+func closeSignalPipe() {
+	signalFD.Store(-1)
+	signalW.Close()
+}
+
+// writeSignal writes id with a single write(2), which is async-signal-safe.
+func writeSignal(id byte) bool {
+	fd := int(signalFD.Load())
+	if fd < 0 {
+		return false
+	}
+	n, err := syscall.Write(fd, signalIDs[id:id+1])
+	return err == nil && n == 1
+}
+
+func readSignals(buf []byte) (int, error) {
+	n, err := signalR.Read(buf)
+	if err != nil {
+		signalR.Close()
+	}
+	return n, err
+}
+
+// SignalFD returns the write end of the signal message pipe, or -1 before
+// the first RegisterSignalMessage call and after StopSignalMessages. C signal handlers in cgo programs
+// log a registered message by writing its ID as a single byte:
+//
+//	unsigned char id = HUP_MESSAGE_ID;
+//	write(logger_fd, &id, 1);
+func SignalFD() int {
+	return int(signalFD.Load())
+}
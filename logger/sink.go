@@ -0,0 +1,348 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sink receives every entry written by the logger, in addition to the
+// console and log file. Each sink is called from a single goroutine of its
+// own, so implementations need no locking, but they must not log through
+// this package. Sinks are used as map keys and must be comparable
+// (typically pointers). Entries must not be modified.
+type Sink interface {
+	WriteEntry(e *Entry) error
+	Close() error
+}
+
+// Encoder renders an entry for a sink. Encoders return a single line without
+// a trailing newline, or a binary record (MsgpackEncoder); stream-oriented
+// sinks add their own framing.
+type Encoder interface {
+	Encode(e *Entry) ([]byte, error)
+}
+
+// TextEncoder renders "[LEVEL] [caller] message key=value" lines, preceded
+// by the entry time when TimeFormat is set.
+type TextEncoder struct {
+	TimeFormat string
+}
+
+func (t TextEncoder) Encode(e *Entry) ([]byte, error) {
+	var b strings.Builder
+	if t.TimeFormat != "" {
+		b.WriteString(formatTime(e.Time, t.TimeFormat))
+		b.WriteByte(' ')
+	}
+	fmt.Fprintf(&b, "[%s] [%s] %s%s", levelNames[e.Level], e.Caller, e.Message, encodeFields(e.Fields...))
+	return []byte(b.String()), nil
+}
+
+// JSONEncoder renders entries as single-line JSON objects with time, level,
+// caller, and msg keys followed by the entry fields.
+type JSONEncoder struct{}
+
+func (JSONEncoder) Encode(e *Entry) ([]byte, error) {
+	return []byte(encodeJSON(e)), nil
+}
+
+// DefaultSinkQueueSize is the number of entries queued for a sink before
+// further entries for it are dropped.
+const DefaultSinkQueueSize = 1024
+
+// SinkBacklog is the number of entries queued for a sink beyond which WARN,
+// ERROR, and FATAL entries skip ahead of the queued ones.
+const SinkBacklog = 64
+
+// sinkWorker feeds one sink from its own goroutine, so a slow sink cannot
+// hold up the logger or other sinks. Entries reach each sink in the order
+// they were logged, except that once SinkBacklog entries are queued, WARN,
+// ERROR, and FATAL entries go through the urgent queue, which is served
+// first, so they overtake the DEBUG and INFO entries already queued.
+type sinkWorker struct {
+	sink    Sink
+	queue   chan *Entry
+	urgent  chan *Entry
+	pending sync.WaitGroup
+	dropped atomic.Int64
+	done    chan struct{}
+
+	// lossless workers block instead of dropping entries
+	lossless bool
+	// senders counts callers blocked on a full queue without logMutex;
+	// stop waits for them before closing the queues
+	senders sync.WaitGroup
+	// stopped is set by stop, under logMutex
+	stopped bool
+	// slow workers are skipped by the *Deadline functions near a deadline
+	slow bool
+
+	// failing is only used by the worker goroutine
+	failing bool
+	// err is the error of the last write, read by SelfTest once the queue
+	// has drained
+	err error
+
+	stats sinkCounters
+}
+
+// registeredSink is an AddSink registration with its optional level mapping.
+type registeredSink struct {
+	w      *sinkWorker
+	levels *LevelMapping
+}
+
+var (
+	// sinks receive entries after the console and file outputs
+	sinks []*registeredSink
+
+	// workers holds one running worker per sink, shared by AddSink and routes
+	workers = map[Sink]*sinkWorker{}
+
+	// debugOutput is false when DEBUG output is off (development without verbose)
+	debugOutput = true
+)
+
+// AddSink registers s to receive every entry that passes level filtering.
+// Entries are delivered from a goroutine dedicated to s; Close and
+// SyncSinks wait for queued entries. Sinks are closed by Close.
+func AddSink(s Sink) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	sinks = append(sinks, &registeredSink{w: workerFor(s)})
+}
+
+// AddSinkWithLevels registers s like AddSink, filtering and remapping entry
+// levels for it with m. DEBUG entries reach s whenever m.Min allows them.
+func AddSinkWithLevels(s Sink, m LevelMapping) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	sinks = append(sinks, &registeredSink{w: workerFor(s), levels: &m})
+}
+
+// SyncSinks blocks until every entry logged so far has been handed to its
+// sinks and, with Config.FileAsync, written to the log file.
+func SyncSinks() {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	for _, w := range workers {
+		w.pending.Wait()
+	}
+	if fileBatch != nil {
+		fileBatch.Flush()
+	}
+}
+
+// workerFor returns the worker for s, starting one if needed.
+// Callers must hold logMutex.
+func workerFor(s Sink) *sinkWorker {
+	if w, ok := workers[s]; ok {
+		return w
+	}
+	w := &sinkWorker{
+		sink:   s,
+		queue:  make(chan *Entry, DefaultSinkQueueSize),
+		urgent: make(chan *Entry, DefaultSinkQueueSize),
+		done:   make(chan struct{}),
+	}
+	_, w.lossless = s.(losslessSink)
+	w.slow = skipNearDeadline(s)
+	workers[s] = w
+	go w.run()
+	return w
+}
+
+func (w *sinkWorker) run() {
+	defer close(w.done)
+	for e := range w.next {
+		if n := w.dropped.Swap(0); n > 0 {
+			fmt.Fprintf(os.Stderr, "logger: sink %T dropped %d entries (queue full)\n", w.sink, n)
+		}
+		w.write(e)
+		w.pending.Done()
+	}
+}
+
+// next yields queued entries, taking urgent ones first, until both queues
+// are closed and drained.
+func (w *sinkWorker) next(yield func(*Entry) bool) {
+	urgent, queue := w.urgent, w.queue
+	for urgent != nil || queue != nil {
+		var e *Entry
+		var ok bool
+		select {
+		case e, ok = <-urgent:
+			if !ok {
+				urgent = nil
+				continue
+			}
+		default:
+			select {
+			case e, ok = <-urgent:
+				if !ok {
+					urgent = nil
+					continue
+				}
+			case e, ok = <-queue:
+				if !ok {
+					queue = nil
+					continue
+				}
+			}
+		}
+		if !yield(e) {
+			return
+		}
+	}
+}
+
+// write delivers e, reporting a failing sink on stderr once, and again only
+// after it has recovered.
+func (w *sinkWorker) write(e *Entry) {
+	probeQueue(e)
+	start := time.Now()
+	err := w.sink.WriteEntry(e)
+	timeSinkWrite(start)
+	w.stats.observe(time.Since(start))
+	w.err = err
+	if e.trace != nil {
+		result := "delivered"
+		if err != nil {
+			result = "failed: " + err.Error()
+		}
+		e.trace.sinkStep("sink", w.sink, result)
+	}
+	if err != nil {
+		writeErrors.Add(1)
+		w.stats.errors.Add(1)
+		if !w.failing {
+			fmt.Fprintf(os.Stderr, "logger: sink %T failed: %v\n", w.sink, err)
+		}
+		w.failing = true
+		return
+	}
+	w.failing = false
+}
+
+// losslessSink is implemented by sinks whose entries must never be
+// dropped, such as ReliableSink.
+type losslessSink interface {
+	lossless()
+}
+
+// enqueue hands e to the worker, WARN and above through the urgent queue
+// during a backlog while it has room. An entry for a full queue is dropped
+// unless the sink is lossless or the entry matches Config.Exemptions, and
+// one logged near its deadline by a *Deadline function skips slow sinks.
+// Callers must hold logMutex; it is released while waiting for room in a
+// full queue, so a stalled sink holds up only the callers logging to it.
+func (w *sinkWorker) enqueue(e *Entry) {
+	if w.stopped {
+		return
+	}
+	if w.slow && nearDeadline(e) {
+		w.stats.skipped.Add(1)
+		e.trace.sinkStep("queue", w.sink, "skipped near deadline")
+		return
+	}
+	w.pending.Add(1)
+	if e.Level >= WarnLevel && (len(w.queue) >= SinkBacklog || len(w.urgent) > 0) {
+		select {
+		case w.urgent <- e:
+			w.stats.enqueued.Add(1)
+			e.trace.sinkStep("queue", w.sink, "queued as urgent")
+			return
+		default:
+			// a full urgent queue falls back to the regular one
+		}
+	}
+	if w.lossless || (len(exemptions) > 0 && exempt(e)) {
+		w.stats.enqueued.Add(1)
+		e.trace.sinkStep("queue", w.sink, "queued")
+		select {
+		case w.queue <- e:
+		default:
+			w.senders.Add(1)
+			logMutex.Unlock()
+			w.queue <- e
+			w.senders.Done()
+			logMutex.Lock()
+		}
+		return
+	}
+	select {
+	case w.queue <- e:
+		w.stats.enqueued.Add(1)
+		e.trace.sinkStep("queue", w.sink, "queued")
+	default:
+		e.trace.sinkStep("queue", w.sink, "dropped, queue full")
+		w.pending.Done()
+		w.dropped.Add(1)
+		w.stats.dropped.Add(1)
+		droppedEntries.Add(1)
+	}
+}
+
+// queued returns the number of entries waiting in both queues.
+func (w *sinkWorker) queued() int {
+	return len(w.queue) + len(w.urgent)
+}
+
+// stop drains the queue, waits for the goroutine, and closes the sink.
+// Callers must hold logMutex.
+func (w *sinkWorker) stop() error {
+	w.stopped = true
+	w.senders.Wait()
+	close(w.queue)
+	close(w.urgent)
+	<-w.done
+	delete(workers, w.sink)
+	return w.sink.Close()
+}
+
+// removeSink unregisters s, stops its worker, and closes it. It reports
+// whether s was registered. Callers must hold logMutex.
+func removeSink(s Sink) bool {
+	for i, rs := range sinks {
+		if rs.w.sink == s {
+			// copy, as writeSinks may still range over the old slice
+			sinks = append(sinks[:i:i], sinks[i+1:]...)
+			rs.w.stop()
+			return true
+		}
+	}
+	return false
+}
+
+// writeSinks queues e for every registered sink.
+// Callers must hold logMutex.
+func writeSinks(e *Entry) {
+	for _, rs := range sinks {
+		se, ok := rs.levels.apply(e)
+		if !ok || (rs.levels == nil && e.Level == DebugLevel && !debugOutput) {
+			continue
+		}
+		rs.w.enqueue(se)
+	}
+}
+
+// closeSinks unregisters all sinks and routes, waits for their queues to
+// drain, and closes them, returning every error, each naming its sink's
+// type, joined with errors.Join. Callers must hold logMutex.
+func closeSinks() error {
+	var errs []error
+	for _, w := range workers {
+		if err := w.stop(); err != nil {
+			errs = append(errs, fmt.Errorf("logger: closing %T: %w", w.sink, err))
+		}
+	}
+	sinks = nil
+	routes = nil
+	loggerOutputs = map[string]*loggerOutput{}
+	return errors.Join(errs...)
+}
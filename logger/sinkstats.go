@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SinkStat reports the health of one sink's delivery pipeline.
+type SinkStat struct {
+	Sink Sink
+	// Name is the sink's type, e.g. "*logger.SyslogSink".
+	Name string
+	// Enqueued is the number of entries queued for the sink, Dropped the
+	// number dropped because its queue was full, Skipped the number logged
+	// near their deadline that skipped it (see InfoDeadline), and Errors
+	// the number of writes that returned an error.
+	Enqueued int64
+	Dropped  int64
+	Skipped  int64
+	Errors   int64
+	// Depth is the number of entries waiting in its queues.
+	Depth int
+	// P50, P90, and P99 are percentiles of the sink's recent WriteEntry
+	// durations, and Max the longest of them.
+	P50, P90, P99, Max time.Duration
+}
+
+// sinkCounters accumulates a worker's SinkStat totals.
+type sinkCounters struct {
+	enqueued, dropped, skipped, errors atomic.Int64
+
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// observe records the duration of one write.
+func (c *sinkCounters) observe(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.samples) < latencyWindow {
+		c.samples = append(c.samples, d)
+		return
+	}
+	c.samples[c.next] = d
+	c.next = (c.next + 1) % len(c.samples)
+}
+
+// stat returns the totals and percentiles of the recent writes.
+func (c *sinkCounters) stat() SinkStat {
+	s := SinkStat{Enqueued: c.enqueued.Load(), Dropped: c.dropped.Load(), Skipped: c.skipped.Load(), Errors: c.errors.Load()}
+	c.mu.Lock()
+	sorted := slices.Clone(c.samples)
+	c.mu.Unlock()
+	if len(sorted) == 0 {
+		return s
+	}
+	slices.Sort(sorted)
+	at := func(p float64) time.Duration { return sorted[int(p*float64(len(sorted)-1))] }
+	s.P50, s.P90, s.P99, s.Max = at(0.5), at(0.9), at(0.99), sorted[len(sorted)-1]
+	return s
+}
+
+// SinkStats returns the delivery statistics of every running sink,
+// including route sinks, ordered by name, for applications that surface the
+// health of their logging pipeline on their own dashboards:
+//
+//	for _, s := range logger.SinkStats() {
+//	    queueDepth.WithLabelValues(s.Name).Set(float64(s.Depth))
+//	    writeP99.WithLabelValues(s.Name).Set(s.P99.Seconds())
+//	}
+//
+// Latency percentiles cover the last 1000 writes of each sink. Totals
+// restart when a sink is removed and added again.
+func SinkStats() []SinkStat {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	stats := make([]SinkStat, 0, len(workers))
+	for s, w := range workers {
+		st := w.stats.stat()
+		st.Sink, st.Name, st.Depth = s, fmt.Sprintf("%T", s), w.queued()
+		stats = append(stats, st)
+	}
+	slices.SortFunc(stats, func(a, b SinkStat) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), cmp.Compare(b.Enqueued, a.Enqueued))
+	})
+	return stats
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"sync"
+)
+
+// SlogHandler is a slog.Handler writing records through this logger, so
+// they get its levels, filters, context fields, file, and sinks. slog
+// levels below INFO map to DEBUG, below WARN to INFO, below ERROR to WARN,
+// and the rest to ERROR; records never exit the process. Attributes in
+// groups are logged with dotted keys, e.g. "req.method".
+//
+//	slog.New(logger.NewSlogHandler()).Info("cache warmed", "keys", 1200)
+//	// [INFO] [main.warm:31] cache warmed keys=1200
+type SlogHandler struct {
+	attrs  []any
+	prefix string // dotted group names for the next attributes
+}
+
+// NewSlogHandler returns a handler writing through this logger.
+func NewSlogHandler() *SlogHandler {
+	return &SlogHandler{}
+}
+
+// slogLevel maps a slog level to a logger level.
+func slogLevel(l slog.Level) Level {
+	switch {
+	case l < slog.LevelInfo:
+		return DebugLevel
+	case l < slog.LevelWarn:
+		return InfoLevel
+	case l < slog.LevelError:
+		return WarnLevel
+	}
+	return ErrorLevel
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return BufferFromContext(ctx) != nil || isLevelEnabled(slogLevel(l))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	level := slogLevel(r.Level)
+	if BufferFromContext(ctx) == nil && !isLevelEnabled(level) {
+		return nil
+	}
+	keyvals := make([]any, len(h.attrs), len(h.attrs)+2*r.NumAttrs())
+	copy(keyvals, h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		keyvals = appendSlogAttr(keyvals, h.prefix, a)
+		return true
+	})
+	caller := "slog"
+	if r.PC != 0 {
+		caller = callerForPC(r.PC)
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	outputContext(ctx, loggerFor(level), level, caller, r.Message, withContextFields(ctx, keyvals))
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := &SlogHandler{attrs: make([]any, len(h.attrs), len(h.attrs)+2*len(attrs)), prefix: h.prefix}
+	copy(out.attrs, h.attrs)
+	for _, a := range attrs {
+		out.attrs = appendSlogAttr(out.attrs, h.prefix, a)
+	}
+	return out
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+// appendSlogAttr appends a as key-value pairs, flattening groups into
+// dotted keys. Empty attributes are dropped, as slog handlers should.
+func appendSlogAttr(keyvals []any, prefix string, a slog.Attr) []any {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return keyvals
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			keyvals = appendSlogAttr(keyvals, prefix, ga)
+		}
+		return keyvals
+	}
+	return append(keyvals, prefix+a.Key, a.Value.Any())
+}
+
+// HijackSlog installs a SlogHandler as slog.Default, so dependencies using
+// slog route through this logger, and returns a function restoring the
+// previous default. Like slog.SetDefault, it also sends the standard
+// library log package to the handler, at INFO; restore puts back its
+// previous output and flags too.
+//
+//	restore := logger.HijackSlog()
+//	defer restore()
+func HijackSlog() (restore func()) {
+	stdlogMu.Lock()
+	defer stdlogMu.Unlock()
+	prev := slog.Default()
+	std := log.Default()
+	prevOut, prevFlags := std.Writer(), std.Flags()
+	slog.SetDefault(slog.New(NewSlogHandler()))
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			stdlogMu.Lock()
+			defer stdlogMu.Unlock()
+			slog.SetDefault(prev)
+			std.SetOutput(prevOut)
+			std.SetFlags(prevFlags)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// SocketSink writes encoded entries to a unix socket. On "unixgram" sockets
+// each entry is one datagram; on "unix" stream sockets entries are
+// newline-delimited, or length-prefixed for binary encoders. The sink
+// reconnects once per write when the peer has gone away, so agents can
+// restart without losing the sink.
+type SocketSink struct {
+	network string
+	path    string
+	enc     Encoder
+	timeout time.Duration
+	conn    net.Conn
+}
+
+// NewSocketSink connects to the unix socket at path. network is "unixgram"
+// or "unix"; a nil enc uses JSONEncoder.
+func NewSocketSink(network, path string, enc Encoder) (*SocketSink, error) {
+	if network != "unixgram" && network != "unix" {
+		return nil, fmt.Errorf("logger: unsupported socket network %q", network)
+	}
+	if enc == nil {
+		enc = JSONEncoder{}
+	}
+	s := &SocketSink{network: network, path: path, enc: enc, timeout: 5 * time.Second}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SocketSink) connect() error {
+	conn, err := net.DialTimeout(s.network, s.path, s.timeout)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+// WriteEntry encodes e and writes it to the socket.
+func (s *SocketSink) WriteEntry(e *Entry) error {
+	data, err := s.enc.Encode(e)
+	if err != nil {
+		return err
+	}
+	if s.network == "unix" {
+		data = appendFrame(nil, s.enc, data)
+	}
+	if err := s.write(data); err != nil {
+		if s.conn != nil {
+			s.conn.Close()
+			s.conn = nil
+		}
+		if cerr := s.connect(); cerr != nil {
+			return cerr
+		}
+		return s.write(data)
+	}
+	return nil
+}
+
+func (s *SocketSink) write(data []byte) error {
+	if s.conn == nil {
+		return errors.New("logger: socket connection closed")
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	_, err := s.conn.Write(data)
+	return err
+}
+
+// Close closes the socket connection.
+func (s *SocketSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SourceContextKey is the field name used by Config.SourceContext.
+const SourceContextKey = "source_context"
+
+var (
+	// sourceContext is Config.SourceContext, off in production
+	sourceContext bool
+
+	// callerSites maps a caller string to its callerSite, recorded when
+	// getCallerInfo first formats it
+	callerSites sync.Map
+
+	// sourceFiles caches the lines of files read for SourceContext, nil
+	// for files that could not be read. Guarded by logMutex.
+	sourceFiles = map[string][]string{}
+)
+
+// callerSite is the source file and full function name of a call site.
+type callerSite struct {
+	file, function string
+}
+
+// sourceLines returns the source of caller's call site with one line of
+// context on each side, the call line marked with ">":
+//
+//	  41 | if err := upload(f); err != nil {
+//	> 42 |     logger.Errorf("upload failed: %v", err)
+//	  43 | }
+//
+// It returns "" when the file is not available, e.g. for a binary built on
+// another machine or with -trimpath. Callers must hold logMutex.
+func sourceLines(caller string) string {
+	site, ok := callerSites.Load(caller)
+	if !ok {
+		return ""
+	}
+	file := site.(callerSite).file
+	var line int
+	if i := strings.LastIndexByte(caller, ':'); i < 0 {
+		return ""
+	} else if _, err := fmt.Sscan(caller[i+1:], &line); err != nil {
+		return ""
+	}
+	lines, ok := sourceFiles[file]
+	if !ok {
+		if data, err := os.ReadFile(file); err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		sourceFiles[file] = lines
+	}
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	var b strings.Builder
+	for n := max(line-1, 1); n <= min(line+1, len(lines)); n++ {
+		mark := "  "
+		if n == line {
+			mark = "> "
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s%d | %s", mark, n, strings.TrimRight(lines[n-1], "\r"))
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+)
+
+// Field names written for spans.
+const (
+	SpanKey         = "span"
+	SpanIDKey       = "span_id"
+	ParentSpanIDKey = "parent_span_id"
+	DurationKey     = "duration"
+)
+
+type ctxSpanKey struct{}
+
+// SpanHandle is a timed operation started with Span or SpanContext. Its
+// begin and end entries share a span_id, so they can be paired without a
+// tracing backend.
+type SpanHandle struct {
+	ctx    context.Context
+	name   string
+	id     string
+	parent string
+	start  time.Time
+}
+
+// Span logs a DEBUG entry marking the start of the operation name and
+// returns a handle whose End logs its duration:
+//
+//	sp := logger.Span("sync-users")
+//	n, err := syncUsers()
+//	sp.EndErr(err, "users", n)
+//	// [INFO] ... sync-users finished span=sync-users span_id=1f0c... duration=1.2s users=42
+func Span(name string) *SpanHandle {
+	return startSpan(context.Background(), name, getCallerInfo(2))
+}
+
+// SpanContext is Span for code that logs with a context. The returned
+// context carries the span_id as a field for the *Context functions, and
+// spans started from it record this span as parent_span_id.
+func SpanContext(ctx context.Context, name string) (context.Context, *SpanHandle) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sp := startSpan(ctx, name, getCallerInfo(2))
+	return context.WithValue(ctx, ctxSpanKey{}, sp), sp
+}
+
+func startSpan(ctx context.Context, name, caller string) *SpanHandle {
+	sp := &SpanHandle{ctx: ctx, name: name, id: newSpanID(), start: time.Now()}
+	if parent, ok := ctx.Value(ctxSpanKey{}).(*SpanHandle); ok {
+		sp.parent = parent.id
+	}
+	sp.log(DebugLevel, caller, name+" started", nil)
+	return sp
+}
+
+// ID returns the span_id shared by the span's entries.
+func (sp *SpanHandle) ID() string {
+	return sp.id
+}
+
+// End logs an INFO entry with the span's duration and keyvals, and returns
+// the duration.
+func (sp *SpanHandle) End(keyvals ...any) time.Duration {
+	d := time.Since(sp.start)
+	sp.log(InfoLevel, getCallerInfo(2), sp.name+" finished", append([]any{DurationKey, d}, keyvals...))
+	return d
+}
+
+// EndErr is End for operations that can fail: a non-nil err is logged at
+// ERROR as "<name> failed" with an error field.
+func (sp *SpanHandle) EndErr(err error, keyvals ...any) time.Duration {
+	d := time.Since(sp.start)
+	if err == nil {
+		sp.log(InfoLevel, getCallerInfo(2), sp.name+" finished", append([]any{DurationKey, d}, keyvals...))
+		return d
+	}
+	sp.log(ErrorLevel, getCallerInfo(2), sp.name+" failed", append([]any{DurationKey, d, "error", err}, keyvals...))
+	return d
+}
+
+// log writes a span entry with the span fields ahead of keyvals.
+func (sp *SpanHandle) log(level Level, caller, msg string, keyvals []any) {
+	if BufferFromContext(sp.ctx) == nil && !isLevelEnabled(level) {
+		return
+	}
+	fields := []any{SpanKey, sp.name, SpanIDKey, sp.id}
+	if sp.parent != "" {
+		fields = append(fields, ParentSpanIDKey, sp.parent)
+	}
+	fields = append(fields, keyvals...)
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	// the span's own span_id replaces that of an enclosing span
+	outputContext(sp.ctx, loggerFor(level), level, caller, msg, appendMissing(fields, contextFields(sp.ctx)))
+}
+
+// newSpanID returns a span ID from the SetIDGenerator generator, or 16
+// random hex digits.
+func newSpanID() string {
+	if g := idGenerator.Load(); g != nil {
+		return (*g).SpanID()
+	}
+	var b [8]byte
+	readRandom(b[:])
+	return hex.EncodeToString(b[:])
+}
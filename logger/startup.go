@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"os"
+	"runtime/debug"
+	"strings"
+)
+
+// StartupEvent is the event ID of the entry written by Config.StartupEntry.
+const StartupEvent = "logger.initialized"
+
+// logStartup writes the Config.StartupEntry handshake entry. The crash
+// monitor runs the program again up to EnableCrashMonitor and writes none,
+// since its entry would look like a restart. Callers must be InitWithConfig.
+func logStartup(cfg Config, fileErr error) {
+	if os.Getenv(crashMonitorEnv) != "" {
+		return
+	}
+	rc := resolveConfig(cfg, fileErr)
+	version := cfg.Version
+	if version == "" {
+		version = buildVersion()
+	}
+	keyvals := []any{
+		EventKey, StartupEvent,
+		"mode", rc.Mode,
+		"levels", strings.Join(rc.Levels, ","),
+		"console", rc.Console,
+		"sinks", rc.Sinks,
+		"pid", os.Getpid(),
+		"identifier", rc.Identifier,
+	}
+	if version != "" {
+		keyvals = append(keyvals, "version", version)
+	}
+	if rc.File != "" {
+		keyvals = append(keyvals, "file", rc.File)
+	}
+	initEntry(InfoLevel, "logger initialized", keyvals)
+}
+
+// initEntry writes an entry about the logger's own setup. It is written even
+// when LOGGER_LEVELS disables level. Callers must be InitWithConfig, which
+// holds logMutex.
+func initEntry(level Level, msg string, keyvals []any) {
+	output(loggerFor(level), level, "logger.InitWithConfig", msg, keyvals)
+}
+
+// buildVersion returns the main module version from the build info, or the
+// VCS revision for development builds.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value[:min(12, len(s.Value))]
+		}
+	}
+	return ""
+}
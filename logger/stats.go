@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatsEvent is the event ID of the entries written by WatchStats.
+const StatsEvent = "logger.stats"
+
+// WatchStats logs the logger's own health every interval as an INFO entry,
+// so a stalled or lossy log pipeline shows up in the logs themselves, e.g.
+// in journald next to the application's entries. It returns a function
+// that stops the reports. Each entry carries the entries per second written
+// at each level, the entries waiting in sink queues, and the entries
+// dropped by full queues and failed console, file, and sink writes since
+// the previous report:
+//
+//	stop := logger.WatchStats(time.Minute)
+//	defer stop()
+//	// [INFO] [logger.WatchStats] logger stats event=logger.stats debug_per_sec=0.00 info_per_sec=12.40 warn_per_sec=0.05 error_per_sec=0.00 fatal_per_sec=0.00 queued=3 dropped=0 write_errors=0
+//
+// The report's own entry is not counted in the next one.
+func WatchStats(interval time.Duration) (stop func()) {
+	w := &statsWatch{}
+	w.snapshot(time.Now())
+	return runWatchdog(interval, func() {
+		if now := time.Now(); now.Sub(w.at) > 0 {
+			w.report(now)
+		}
+	})
+}
+
+// statsWatch holds the counters at the previous WatchStats report.
+type statsWatch struct {
+	at      time.Time
+	counts  [FatalLevel + 1]int64
+	dropped int64
+	errors  int64
+}
+
+func (w *statsWatch) snapshot(now time.Time) {
+	w.at = now
+	for level := range w.counts {
+		w.counts[level] = entryCounts[level].Load()
+	}
+	w.dropped, w.errors = droppedEntries.Load(), writeErrors.Load()
+}
+
+// report logs the change since the previous report and takes a new
+// snapshot once the entry is written.
+func (w *statsWatch) report(now time.Time) {
+	if !isLevelEnabled(InfoLevel) {
+		w.snapshot(now)
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	seconds := now.Sub(w.at).Seconds()
+	keyvals := []any{EventKey, StatsEvent}
+	for level := DebugLevel; level <= FatalLevel; level++ {
+		rate := float64(entryCounts[level].Load()-w.counts[level]) / seconds
+		keyvals = append(keyvals, strings.ToLower(levelNames[level])+"_per_sec", strconv.FormatFloat(rate, 'f', 2, 64))
+	}
+	queued := 0
+	for _, sw := range workers {
+		queued += sw.queued()
+	}
+	keyvals = append(keyvals,
+		"queued", queued,
+		"dropped", droppedEntries.Load()-w.dropped,
+		"write_errors", writeErrors.Load()-w.errors,
+	)
+	output(Info, InfoLevel, "logger.WatchStats", "logger stats", keyvals)
+	w.snapshot(now)
+}
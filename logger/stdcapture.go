@@ -0,0 +1,169 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// stdCapture holds the state of an active StdcaptureStart.
+type stdCapture struct {
+	// saved are duplicates of the original stdout/stderr descriptors, used
+	// both for the logger's own console output and to restore them
+	saved   [2]*os.File
+	prevOut io.Writer
+	prevErr io.Writer
+	done    sync.WaitGroup
+}
+
+var (
+	captureMu     sync.Mutex
+	activeCapture *stdCapture
+)
+
+// StdcaptureStart redirects the process stdout and stderr file descriptors
+// through pipes and logs every line written to them as a WARN entry tagged
+// source=stderr-capture and stream=stdout|stderr. This catches raw output
+// from C libraries and fmt.Print calls in legacy code that would otherwise
+// bypass the logger. The logger's own console output keeps going to the
+// original descriptors.
+//
+// Call StdcaptureStop to restore the descriptors. Output written by the Go
+// runtime as the process dies (unrecovered panics) may be lost while the
+// capture is active, since the pipe reader dies with the process.
+func StdcaptureStart() error {
+	captureMu.Lock()
+	defer captureMu.Unlock()
+	if activeCapture != nil {
+		return errors.New("logger: stdcapture already started")
+	}
+
+	c := &stdCapture{}
+	var readers [2]*os.File
+	fail := func(n int, err error) error {
+		c.restore(n)
+		for i := 0; i < n; i++ {
+			readers[i].Close()
+			c.saved[i].Close()
+		}
+		return err
+	}
+	for i, std := range []*os.File{os.Stdout, os.Stderr} {
+		fd := i + 1
+		saved, err := syscall.Dup(fd)
+		if err != nil {
+			return fail(i, err)
+		}
+		r, w, err := os.Pipe()
+		if err != nil {
+			syscall.Close(saved)
+			return fail(i, err)
+		}
+		err = dupFd(int(w.Fd()), fd)
+		w.Close()
+		if err != nil {
+			r.Close()
+			syscall.Close(saved)
+			return fail(i, err)
+		}
+		c.saved[i] = os.NewFile(uintptr(saved), std.Name())
+		readers[i] = r
+	}
+
+	logMutex.Lock()
+	c.prevOut, c.prevErr = outStdout, outStderr
+	if outStdout == os.Stdout {
+		outStdout = c.saved[0]
+	}
+	if outStderr == os.Stderr {
+		outStderr = c.saved[1]
+	}
+	redirectConsole(os.Stdout, c.saved[0])
+	redirectConsole(os.Stderr, c.saved[1])
+	logMutex.Unlock()
+
+	for i, stream := range []string{"stdout", "stderr"} {
+		c.done.Add(1)
+		go c.read(readers[i], stream)
+	}
+	activeCapture = c
+	return nil
+}
+
+// StdcaptureStop restores stdout and stderr and waits until everything
+// written to them has been logged. It is a no-op if no capture is active.
+func StdcaptureStop() error {
+	captureMu.Lock()
+	defer captureMu.Unlock()
+	c := activeCapture
+	if c == nil {
+		return nil
+	}
+	activeCapture = nil
+
+	err := c.restore(2)
+	c.done.Wait()
+
+	logMutex.Lock()
+	redirectConsole(c.saved[0], os.Stdout)
+	redirectConsole(c.saved[1], os.Stderr)
+	outStdout, outStderr = c.prevOut, c.prevErr
+	logMutex.Unlock()
+	for _, f := range c.saved {
+		f.Close()
+	}
+	return err
+}
+
+// restore points the first n standard descriptors back at their saved
+// originals, which closes the pipe write ends and lets the readers finish.
+func (c *stdCapture) restore(n int) error {
+	var first error
+	for i := 0; i < n; i++ {
+		if err := dupFd(int(c.saved[i].Fd()), i+1); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// read logs each line from r as a WARN entry.
+func (c *stdCapture) read(r *os.File, stream string) {
+	defer c.done.Done()
+	defer r.Close()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || !isLevelEnabled(WarnLevel) {
+			continue
+		}
+		logMutex.Lock()
+		output(Warning, WarnLevel, "stdcapture", line, []any{"source", "stderr-capture", "stream", stream})
+		logMutex.Unlock()
+	}
+}
+
+// redirectConsole points level loggers writing to from (directly or behind a
+// severity prefix) at to. Callers must hold logMutex.
+func redirectConsole(from, to io.Writer) {
+	for _, l := range []*log.Logger{Debug, Info, Warning, Error, Fatal} {
+		switch w := l.Writer().(type) {
+		case *prefixWriter:
+			if w.w == from {
+				l.SetOutput(&prefixWriter{prefix: w.prefix, w: to})
+			}
+		default:
+			if w == from {
+				l.SetOutput(to)
+			}
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logger
+
+import "errors"
+
+// StdcaptureStart is not supported on this platform.
+func StdcaptureStart() error {
+	return errors.ErrUnsupported
+}
+
+// StdcaptureStop is a no-op on this platform.
+func StdcaptureStop() error {
+	return nil
+}
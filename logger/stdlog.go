@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"log"
+	"strings"
+	"sync"
+)
+
+// stdlogMu serializes HijackStdlog calls and their restore functions.
+var stdlogMu sync.Mutex
+
+// HijackStdlog redirects the standard library's default logger, used by
+// log.Printf and by many dependencies, such as net/http's server errors,
+// into this logger: each line becomes an entry at level tagged
+// source=stdlog, attributed to the file and line that called the log
+// package. It returns a function restoring the previous output, flags, and
+// prefix:
+//
+//	restore := logger.HijackStdlog(logger.WarnLevel)
+//	defer restore()
+//	// log.Printf("cache miss for %s", key) in a dependency logs
+//	// [WARN] [cache.go:88] cache miss for user:42 source=stdlog
+//
+// log.Fatal and log.Panic keep exiting and panicking after the entry is
+// written.
+func HijackStdlog(level Level) (restore func()) {
+	stdlogMu.Lock()
+	defer stdlogMu.Unlock()
+	std := log.Default()
+	prevOut, prevFlags, prevPrefix := std.Writer(), std.Flags(), std.Prefix()
+	std.SetOutput(stdlogWriter{level: level})
+	std.SetFlags(log.Lshortfile)
+	std.SetPrefix("")
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			stdlogMu.Lock()
+			defer stdlogMu.Unlock()
+			std.SetOutput(prevOut)
+			std.SetFlags(prevFlags)
+			std.SetPrefix(prevPrefix)
+		})
+	}
+}
+
+// stdlogWriter logs each line the default logger writes. The log package
+// writes one line per call, starting with "file.go:line: ".
+type stdlogWriter struct {
+	level Level
+}
+
+func (w stdlogWriter) Write(p []byte) (int, error) {
+	if !isLevelEnabled(w.level) {
+		return len(p), nil
+	}
+	msg := strings.TrimSuffix(string(p), "\n")
+	caller := "stdlog"
+	if file, rest, ok := strings.Cut(msg, ": "); ok && strings.Contains(file, ".go:") {
+		caller, msg = file, rest
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(w.level), w.level, caller, msg, []any{"source", "stdlog"})
+	return len(p), nil
+}
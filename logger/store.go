@@ -0,0 +1,293 @@
+package logger
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for StoreConfig.
+const (
+	DefaultStoreRetention = 24 * time.Hour
+	DefaultQueryLimit     = 1000
+)
+
+// storeSegmentLayout names the hourly segment files of a LogStore.
+const storeSegmentLayout = "2006010215"
+
+// StoreConfig configures NewLogStore.
+type StoreConfig struct {
+	// Dir holds the store's segment files. Required.
+	Dir string
+	// Retention is how long entries are kept. Defaults to
+	// DefaultStoreRetention.
+	Retention time.Duration
+	// QueryLimit caps the entries returned by Query, keeping the newest.
+	// Defaults to DefaultQueryLimit.
+	QueryLimit int
+}
+
+// LogStore is an embedded, queryable store of recent entries, for support
+// tooling inside an application that has to answer "what errors happened
+// in the last hour" without shell access:
+//
+//	store, err := logger.NewLogStore(logger.StoreConfig{Dir: "/var/lib/app/logs"})
+//	...
+//	logger.AddSink(store)
+//	...
+//	entries, err := logger.Query(time.Now().Add(-time.Hour), logger.ErrorLevel, "timeout")
+//
+// Entries are kept in hourly segment files in the binary log format of
+// FileSink, so OpenLogFile and logreplay can read them too, and indexed in
+// memory by time and level. Segments older than Retention are deleted.
+type LogStore struct {
+	cfg StoreConfig
+
+	mu       sync.Mutex
+	segments []*storeSegment // oldest first
+	active   *storeSegment   // segment open for appending
+	buf      []byte
+}
+
+// storeSegment is one hour of entries.
+type storeSegment struct {
+	hour  time.Time
+	path  string
+	f     *os.File // open while active
+	size  int64
+	index []storeRecord
+}
+
+// storeRecord locates one entry in its segment file.
+type storeRecord struct {
+	time   int64 // UnixNano
+	level  Level
+	offset int64
+	size   uint32
+}
+
+// NewLogStore opens the store in cfg.Dir, indexing the entries already
+// there and deleting expired segments.
+func NewLogStore(cfg StoreConfig) (*LogStore, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("logger: log store requires a directory")
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultStoreRetention
+	}
+	if cfg.QueryLimit <= 0 {
+		cfg.QueryLimit = DefaultQueryLimit
+	}
+	if err := os.MkdirAll(cfg.Dir, 0700); err != nil {
+		return nil, err
+	}
+	s := &LogStore{cfg: cfg}
+	paths, err := filepath.Glob(filepath.Join(cfg.Dir, "*.msgpack"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		hour, err := time.Parse(storeSegmentLayout, strings.TrimSuffix(filepath.Base(path), ".msgpack"))
+		if err != nil {
+			continue
+		}
+		seg := &storeSegment{hour: hour, path: path}
+		if err := seg.load(); err != nil {
+			return nil, err
+		}
+		s.segments = append(s.segments, seg)
+	}
+	sort.Slice(s.segments, func(i, j int) bool { return s.segments[i].hour.Before(s.segments[j].hour) })
+	s.expire()
+	return s, nil
+}
+
+// load rebuilds the index of an existing segment, cutting off a record
+// left incomplete by a crash.
+func (seg *storeSegment) load() error {
+	data, err := os.ReadFile(seg.path)
+	if err != nil {
+		return err
+	}
+	header := len(appendBinaryLogHeader(nil, "msgpack"))
+	if len(data) < header || string(data[:header]) != string(appendBinaryLogHeader(nil, "msgpack")) {
+		return fmt.Errorf("logger: %s is not a log store segment", seg.path)
+	}
+	offset := int64(header)
+	for offset+4 <= int64(len(data)) {
+		n := binary.BigEndian.Uint32(data[offset:])
+		end := offset + 4 + int64(n)
+		if end > int64(len(data)) {
+			break
+		}
+		e, err := decodeMsgpackEntry(data[offset+4 : end])
+		if err != nil {
+			break
+		}
+		seg.index = append(seg.index, storeRecord{time: e.Time.UnixNano(), level: e.Level, offset: offset + 4, size: n})
+		offset = end
+	}
+	seg.size = offset
+	if offset < int64(len(data)) {
+		return os.Truncate(seg.path, offset)
+	}
+	return nil
+}
+
+// WriteEntry appends e to the segment of its hour.
+func (s *LogStore) WriteEntry(e *Entry) error {
+	record, _ := MsgpackEncoder{}.Encode(e)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seg, err := s.segmentFor(e.Time.UTC().Truncate(time.Hour))
+	if err != nil {
+		return err
+	}
+	s.buf = appendFrame(s.buf[:0], MsgpackEncoder{}, record)
+	if _, err := seg.f.Write(s.buf); err != nil {
+		return err
+	}
+	seg.index = append(seg.index, storeRecord{time: e.Time.UnixNano(), level: e.Level, offset: seg.size + 4, size: uint32(len(record))})
+	seg.size += int64(len(s.buf))
+	return nil
+}
+
+// segmentFor returns the segment for hour, open for appending, creating it
+// and expiring old segments when the hour is new. Callers must hold s.mu.
+func (s *LogStore) segmentFor(hour time.Time) (*storeSegment, error) {
+	if s.active != nil && s.active.hour.Equal(hour) {
+		return s.active, nil
+	}
+	i := sort.Search(len(s.segments), func(i int) bool { return !s.segments[i].hour.Before(hour) })
+	var seg *storeSegment
+	if i < len(s.segments) && s.segments[i].hour.Equal(hour) {
+		seg = s.segments[i]
+	} else {
+		seg = &storeSegment{hour: hour, path: filepath.Join(s.cfg.Dir, hour.Format(storeSegmentLayout)+".msgpack")}
+		s.segments = append(s.segments[:i], append([]*storeSegment{seg}, s.segments[i:]...)...)
+	}
+	f, err := os.OpenFile(seg.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareBinaryLogFile(f, "msgpack"); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if seg.size == 0 {
+		seg.size = int64(len(appendBinaryLogHeader(nil, "msgpack")))
+	}
+	s.closeActive()
+	seg.f, s.active = f, seg
+	s.expire()
+	return seg, nil
+}
+
+func (s *LogStore) closeActive() error {
+	if s.active == nil {
+		return nil
+	}
+	err := s.active.f.Close()
+	s.active.f, s.active = nil, nil
+	return err
+}
+
+// expire deletes the segments older than the retention period.
+// Callers must hold s.mu.
+func (s *LogStore) expire() {
+	cutoff := time.Now().Add(-s.cfg.Retention)
+	for len(s.segments) > 0 && s.segments[0].hour.Add(time.Hour).Before(cutoff) && s.segments[0] != s.active {
+		os.Remove(s.segments[0].path)
+		s.segments = s.segments[1:]
+	}
+}
+
+// Query returns the entries logged at or after since with at least level
+// min whose message or fields contain substr, oldest first. At most
+// StoreConfig.QueryLimit entries are returned, keeping the newest. An
+// empty substr matches every entry.
+func (s *LogStore) Query(since time.Time, min Level, substr string) ([]*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	from := since.UnixNano()
+	var results []*Entry
+	for _, seg := range s.segments {
+		if !seg.hour.Add(time.Hour).After(since) {
+			continue
+		}
+		matches, err := seg.query(from, min, substr)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, matches...)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Time.Before(results[j].Time) })
+	if len(results) > s.cfg.QueryLimit {
+		results = results[len(results)-s.cfg.QueryLimit:]
+	}
+	return results, nil
+}
+
+// query reads the entries of seg that pass the time and level index and
+// contain substr.
+func (seg *storeSegment) query(from int64, min Level, substr string) ([]*Entry, error) {
+	var f *os.File
+	var results []*Entry
+	for _, rec := range seg.index {
+		if rec.time < from || rec.level < min {
+			continue
+		}
+		if f == nil {
+			var err error
+			if f, err = os.Open(seg.path); err != nil {
+				return nil, err
+			}
+			defer f.Close()
+		}
+		data := make([]byte, rec.size)
+		if _, err := f.ReadAt(data, rec.offset); err != nil {
+			return nil, err
+		}
+		e, err := decodeMsgpackEntry(data)
+		if err != nil {
+			return nil, err
+		}
+		if substr == "" || strings.Contains(e.Message, substr) || strings.Contains(encodeFields(e.Fields...), substr) {
+			results = append(results, e)
+		}
+	}
+	return results, nil
+}
+
+// Close closes the segment open for appending.
+func (s *LogStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeActive()
+}
+
+// Query runs LogStore.Query on the LogStore registered as a sink with
+// AddSink, AddRoute, or Logger.AddSink, after the entries logged so far
+// have reached it.
+func Query(since time.Time, min Level, substr string) ([]*Entry, error) {
+	logMutex.Lock()
+	var store *LogStore
+	for s, w := range workers {
+		if ls, ok := s.(*LogStore); ok {
+			w.pending.Wait()
+			store = ls
+			break
+		}
+	}
+	logMutex.Unlock()
+	if store == nil {
+		return nil, errors.New("logger: no LogStore registered")
+	}
+	return store.Query(since, min, substr)
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"io"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// ShutdownEvent is the event ID of the entry written by
+// Config.ShutdownSummary.
+const ShutdownEvent = "logger.shutdown"
+
+var (
+	// processStart is the reference for the summary's uptime
+	processStart = time.Now()
+
+	// entryCounts counts written entries per level since the process started
+	entryCounts [FatalLevel + 1]atomic.Int64
+
+	// droppedEntries counts entries dropped by full sink queues, and
+	// writeErrors failed console, file, and sink writes
+	droppedEntries atomic.Int64
+	writeErrors    atomic.Int64
+)
+
+// countLevel counts e for the shutdown summary.
+func countLevel(e *Entry) {
+	if validLevel(e.Level) {
+		entryCounts[e.Level].Add(1)
+	}
+}
+
+// printLine writes line through l, counting a failed write.
+func printLine(l *log.Logger, line string) {
+	if l.Writer() == io.Discard {
+		return
+	}
+	if err := l.Output(2, line); err != nil {
+		writeErrors.Add(1)
+	}
+}
+
+// logShutdown writes the Config.ShutdownSummary entry. It is written even
+// when LOGGER_LEVELS disables INFO, but not by the crash monitor, whose
+// counts and uptime are not those of the run. Callers must hold logMutex.
+func logShutdown() {
+	if os.Getenv(crashMonitorEnv) != "" {
+		return
+	}
+	for _, w := range workers {
+		// count the errors of entries still queued
+		w.pending.Wait()
+	}
+	output(loggerFor(InfoLevel), InfoLevel, "logger.Close", "logger shutdown", []any{
+		EventKey, ShutdownEvent,
+		"uptime", time.Since(processStart).Round(time.Millisecond),
+		"debug", entryCounts[DebugLevel].Load(),
+		"info", entryCounts[InfoLevel].Load(),
+		"warn", entryCounts[WarnLevel].Load(),
+		"error", entryCounts[ErrorLevel].Load(),
+		"fatal", entryCounts[FatalLevel].Load(),
+		"dropped", droppedEntries.Load(),
+		"write_errors", writeErrors.Load(),
+	})
+}
@@ -0,0 +1,378 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Common syslog facility codes for SyslogConfig.Facility.
+const (
+	FacilityUser   = 1
+	FacilityDaemon = 3
+	FacilityLocal0 = 16
+)
+
+// SyslogConfig configures NewSyslogSink.
+type SyslogConfig struct {
+	// Network selects the transport:
+	//   ""     local syslog socket (/dev/log, /var/run/syslog, /var/run/log)
+	//   "udp"  RFC 5424 over UDP
+	//   "tcp"  RFC 5424 over TCP with octet-counting framing (RFC 6587)
+	//   "tls"  RFC 5425 syslog over TLS
+	//   "relp" RELP, over TLS when TLSConfig is set
+	Network string
+	// Address is host:port for network transports. For the local transport it
+	// overrides the socket path.
+	Address string
+	// Tag is the syslog APP-NAME. Defaults to Identifier().
+	Tag string
+	// Facility is the syslog facility code. Defaults to FacilityUser.
+	Facility int
+	// TLSConfig is required for "tls" and enables TLS for "relp".
+	TLSConfig *tls.Config
+	// Timeout bounds dialing and each write. Defaults to 5 seconds.
+	Timeout time.Duration
+	// DNSRefresh, when set for a network transport, resolves the host of
+	// Address again when its DNS records expire, at least this often, and
+	// rotates connections across its addresses, replacing a connection once
+	// it is older than DNSRefresh, so the sink does not stay pinned to one
+	// backend of a DNS-balanced collector.
+	DNSRefresh time.Duration
+}
+
+// SyslogSink writes entries to a syslog daemon. It reconnects once per write
+// when the connection has dropped.
+type SyslogSink struct {
+	cfg      SyslogConfig
+	hostname string
+	pid      int
+
+	conn   net.Conn
+	relp   *relpClient
+	dialer *rotatingDialer
+	// stream is set when the local transport fell back to a unix stream
+	// socket, whose messages need a terminator.
+	stream bool
+}
+
+// localSyslogPaths are tried in order for the local transport.
+var localSyslogPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// NewSyslogSink connects to a syslog daemon. Add the result with AddSink.
+func NewSyslogSink(cfg SyslogConfig) (*SyslogSink, error) {
+	switch cfg.Network {
+	case "", "udp", "tcp", "relp":
+	case "tls":
+		if cfg.TLSConfig == nil {
+			return nil, errors.New("logger: syslog over tls requires TLSConfig")
+		}
+	default:
+		return nil, fmt.Errorf("logger: unsupported syslog network %q", cfg.Network)
+	}
+	if cfg.Network != "" && cfg.Address == "" {
+		return nil, fmt.Errorf("logger: syslog network %q requires an address", cfg.Network)
+	}
+	if cfg.Tag == "" {
+		cfg.Tag = Identifier()
+	}
+	if cfg.Facility == 0 {
+		cfg.Facility = FacilityUser
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	s := &SyslogSink{cfg: cfg, hostname: hostname, pid: os.Getpid()}
+	if cfg.Network != "" {
+		var err error
+		if s.dialer, err = newRotatingDialer(cfg.Address, cfg.DNSRefresh); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SyslogSink) connect() error {
+	if s.cfg.Network == "" {
+		var err error
+		s.conn, err = dialLocalSyslog(s.cfg.Address, s.cfg.Timeout)
+		s.stream = err == nil && s.conn.RemoteAddr().Network() == "unix"
+		return err
+	}
+	conn, err := s.dial()
+	if err != nil {
+		return err
+	}
+	if s.cfg.Network == "relp" {
+		s.relp, err = openRELP(conn, s.cfg.Timeout)
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+// dial connects to the collector for a network transport.
+func (s *SyslogSink) dial() (net.Conn, error) {
+	network := s.cfg.Network
+	if network == "relp" {
+		network = "tcp"
+		if s.cfg.TLSConfig != nil {
+			network = "tls"
+		}
+	}
+	if s.dialer != nil {
+		return s.dialer.dial(network, s.cfg.TLSConfig, s.cfg.Timeout)
+	}
+	dialer := &net.Dialer{Timeout: s.cfg.Timeout}
+	if network == "tls" {
+		return tls.DialWithDialer(dialer, "tcp", s.cfg.Address, s.cfg.TLSConfig)
+	}
+	return dialer.Dial(network, s.cfg.Address)
+}
+
+// dialLocalSyslog connects to the first reachable local syslog socket,
+// preferring datagrams as syslogd and journald expect.
+func dialLocalSyslog(path string, timeout time.Duration) (net.Conn, error) {
+	paths := localSyslogPaths
+	if path != "" {
+		paths = []string{path}
+	}
+	var lastErr error
+	for _, p := range paths {
+		for _, network := range []string{"unixgram", "unix"} {
+			conn, err := net.DialTimeout(network, p, timeout)
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+	}
+	return nil, fmt.Errorf("logger: no local syslog socket available: %w", lastErr)
+}
+
+// WriteEntry formats e for the configured transport and sends it.
+func (s *SyslogSink) WriteEntry(e *Entry) error {
+	msg := s.format(e)
+	if s.dialer.expired() {
+		// move on to the next address; a failure is retried below
+		s.closeConn()
+		s.connect()
+	}
+	if err := s.send(msg); err != nil {
+		s.closeConn()
+		if cerr := s.connect(); cerr != nil {
+			return cerr
+		}
+		return s.send(msg)
+	}
+	return nil
+}
+
+// Close closes the connection to the syslog daemon.
+func (s *SyslogSink) Close() error {
+	return s.closeConn()
+}
+
+// Remote reports whether the sink sends to a network daemon rather than
+// the local one.
+func (s *SyslogSink) Remote() bool { return s.cfg.Network != "" }
+
+func (s *SyslogSink) closeConn() error {
+	var err error
+	if s.relp != nil {
+		err = s.relp.close()
+		s.relp = nil
+	}
+	if s.conn != nil {
+		err = s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+func (s *SyslogSink) send(msg string) error {
+	if s.relp != nil {
+		return s.relp.send(msg)
+	}
+	if s.conn == nil {
+		return errors.New("logger: syslog connection closed")
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(s.cfg.Timeout))
+	switch s.cfg.Network {
+	case "tcp", "tls":
+		// Octet-counting framing (RFC 6587, RFC 5425)
+		_, err := io.WriteString(s.conn, strconv.Itoa(len(msg))+" "+msg)
+		return err
+	}
+	if s.stream {
+		// syslogd splits a local stream at each LF, as for log/syslog
+		msg += "\n"
+	}
+	_, err := io.WriteString(s.conn, msg)
+	return err
+}
+
+// format renders e as an RFC 3164 line for the local socket or an RFC 5424
+// message for network transports. The message body is "[caller] msg fields".
+func (s *SyslogSink) format(e *Entry) string {
+	pri := s.cfg.Facility*8 + syslogSeverities[e.Level]
+	body := fmt.Sprintf("[%s] %s%s", e.Caller, e.Message, encodeFields(e.Fields...))
+	if s.cfg.Network == "" {
+		return fmt.Sprintf("<%d>%s %s[%d]: %s", pri, formatTime(e.Time, time.Stamp), s.cfg.Tag, s.pid, body)
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %d - - %s", pri,
+		e.Time.Format("2006-01-02T15:04:05.000000Z07:00"), s.hostname, s.cfg.Tag, s.pid, body)
+}
+
+// relpClient is a minimal synchronous RELP client: each syslog command waits
+// for its "rsp" before the next one is sent.
+type relpClient struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	txnr    int
+	timeout time.Duration
+}
+
+// openRELP opens a RELP session on conn, closing conn if that fails.
+func openRELP(conn net.Conn, timeout time.Duration) (*relpClient, error) {
+	c := &relpClient{conn: conn, r: bufio.NewReader(conn), timeout: timeout}
+	if err := c.command("open", "relp_version=0\nrelp_software=go_logger\ncommands=syslog"); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("logger: relp open: %w", err)
+	}
+	return c, nil
+}
+
+func (c *relpClient) send(msg string) error {
+	return c.command("syslog", msg)
+}
+
+func (c *relpClient) close() error {
+	c.command("close", "")
+	return c.conn.Close()
+}
+
+// command sends one RELP frame and waits for the matching response.
+func (c *relpClient) command(cmd, data string) error {
+	c.txnr++
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	if _, err := io.WriteString(c.conn, formatRELPFrame(c.txnr, cmd, data)); err != nil {
+		return err
+	}
+	for {
+		txnr, rcmd, rdata, err := readRELPFrame(c.r)
+		if err != nil {
+			return err
+		}
+		if rcmd == "serverclose" {
+			return errors.New("relp server closed the session")
+		}
+		if txnr != c.txnr || rcmd != "rsp" {
+			continue
+		}
+		if cmd != "close" && !strings.HasPrefix(rdata, "200") {
+			return fmt.Errorf("relp %s rejected: %s", cmd, rdata)
+		}
+		return nil
+	}
+}
+
+// formatRELPFrame builds "TXNR SP COMMAND SP DATALEN [SP DATA] LF".
+func formatRELPFrame(txnr int, cmd, data string) string {
+	if data == "" {
+		return fmt.Sprintf("%d %s 0\n", txnr, cmd)
+	}
+	return fmt.Sprintf("%d %s %d %s\n", txnr, cmd, len(data), data)
+}
+
+// readRELPFrame parses one RELP frame.
+func readRELPFrame(r *bufio.Reader) (txnr int, cmd, data string, err error) {
+	header, err := r.ReadString(' ')
+	if err != nil {
+		return 0, "", "", err
+	}
+	if txnr, err = strconv.Atoi(strings.TrimSpace(header)); err != nil {
+		return 0, "", "", fmt.Errorf("relp: bad txnr %q", header)
+	}
+	if cmd, err = r.ReadString(' '); err != nil {
+		return 0, "", "", err
+	}
+	cmd = strings.TrimSpace(cmd)
+
+	var n int
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, "", "", err
+		}
+		if b == ' ' || b == '\n' {
+			if b == '\n' {
+				return txnr, cmd, "", nil
+			}
+			break
+		}
+		if b < '0' || b > '9' {
+			return 0, "", "", fmt.Errorf("relp: bad data length")
+		}
+		n = n*10 + int(b-'0')
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, "", "", err
+	}
+	if trailer, err := r.ReadByte(); err != nil || trailer != '\n' {
+		return 0, "", "", fmt.Errorf("relp: missing trailer")
+	}
+	return txnr, cmd, string(buf), nil
+}
+
+var (
+	// autoSyslog is the sink added by FallbackAuto; it is replaced on every Init
+	autoSyslog *SyslogSink
+
+	// systemdBooted reports whether the host runs systemd, like sd_booted(3)
+	systemdBooted = func() bool {
+		_, err := os.Stat("/run/systemd/system")
+		return err == nil
+	}
+)
+
+// startAutoSyslog adds a local syslog sink when the host has no systemd and a
+// syslog daemon is listening, and reports whether it did. Callers must hold
+// logMutex.
+func startAutoSyslog() bool {
+	if systemdBooted() {
+		return false
+	}
+	s, err := NewSyslogSink(SyslogConfig{})
+	if err != nil {
+		return false
+	}
+	sinks = append(sinks, &registeredSink{w: workerFor(s)})
+	autoSyslog = s
+	return true
+}
+
+// stopAutoSyslog removes and closes the sink added by startAutoSyslog.
+// Callers must hold logMutex.
+func stopAutoSyslog() {
+	if autoSyslog == nil {
+		return
+	}
+	removeSink(autoSyslog)
+	autoSyslog = nil
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// TemplateEncoder renders entries with a text/template executed on the
+// *Entry, for downstream formats such as fixed-width legacy collectors that
+// would otherwise need a Go Encoder:
+//
+//	enc, err := logger.NewTemplateEncoder(
+//	    `{{time .Time "060102150405"}}{{pad 5 (level .Level)}}{{pad 30 .Caller}}{{.Message}}`)
+//	sink, err := logger.NewSocketSink("unix", "/run/legacy.sock", enc)
+//
+// Besides the Entry fields (.Time, .Level, .Caller, .Message, .Fields), the
+// template can use these functions:
+//
+//	level .Level          level name, e.g. "WARN"
+//	time .Time "layout"   time in a Go layout
+//	field . "key"         value of a field, or "" when the entry has none
+//	fields .Fields        " key=value" pairs as in text output
+//	pad N s, lpad N s     s left- or right-aligned in N runes, cut to fit
+//	upper s, lower s      case conversion
+//	quote s               Go-quoted string
+//	json v                v as JSON
+//
+// A trailing newline produced by the template is removed.
+type TemplateEncoder struct {
+	tmpl *template.Template
+}
+
+// NewTemplateEncoder parses text as an entry template.
+func NewTemplateEncoder(text string) (*TemplateEncoder, error) {
+	tmpl, err := template.New("entry").Option("missingkey=zero").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("logger: %w", err)
+	}
+	return &TemplateEncoder{tmpl: tmpl}, nil
+}
+
+// Encode executes the template on e.
+func (t *TemplateEncoder) Encode(e *Entry) ([]byte, error) {
+	var b bytes.Buffer
+	if err := t.tmpl.Execute(&b, e); err != nil {
+		return nil, fmt.Errorf("logger: %w", err)
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+var templateFuncs = template.FuncMap{
+	"level": func(l Level) string { return levelNames[l] },
+	"time":  formatTime,
+	"field": func(e *Entry, key string) any {
+		for i := 0; i+1 < len(e.Fields); i += 2 {
+			if k, ok := e.Fields[i].(string); ok && k == key {
+				return e.Fields[i+1]
+			}
+		}
+		return ""
+	},
+	"fields": func(fields []any) string { return encodeFields(fields...) },
+	"pad":    func(n int, s any) string { return align(fmt.Sprint(s), n, false) },
+	"lpad":   func(n int, s any) string { return align(fmt.Sprint(s), n, true) },
+	"upper":  strings.ToUpper,
+	"lower":  strings.ToLower,
+	"quote":  func(s any) string { return strconv.Quote(fmt.Sprint(s)) },
+	"json": func(v any) string {
+		var b strings.Builder
+		writeJSONValue(&b, v)
+		return b.String()
+	},
+}
+
+// align pads s with spaces to n runes, on the left when right is set, or
+// cuts it to n runes.
+func align(s string, n int, right bool) string {
+	count := utf8.RuneCountInString(s)
+	if count >= n {
+		return string([]rune(s)[:n])
+	}
+	padding := strings.Repeat(" ", n-count)
+	if right {
+		return padding + s
+	}
+	return s + padding
+}
@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logger
+
+import "errors"
+
+// makeRaw is not supported on this platform.
+func makeRaw(fd int) (restore func() error, err error) {
+	return nil, errors.ErrUnsupported
+}
+
+// terminalSize is not supported on this platform.
+func terminalSize(fd int) (rows, cols int, err error) {
+	return 0, 0, errors.ErrUnsupported
+}
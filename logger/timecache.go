@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// timeCache remembers the last formatted timestamp for one layout and
+// reuses it for every entry logged within the same tick, where a tick is
+// the finest unit the layout prints (a second for "15:04:05", a millisecond
+// for "15:04:05.000"). Layouts printing micro- or nanoseconds change with
+// almost every entry and are formatted directly.
+type timeCache struct {
+	layout string
+	res    int64 // tick length in nanoseconds; 0 disables caching
+	last   atomic.Pointer[cachedTime]
+}
+
+type cachedTime struct {
+	tick int64
+	loc  *time.Location
+	s    string
+}
+
+// timeCaches maps layouts to their caches; there is one per configured format
+var timeCaches sync.Map
+
+// formatTime returns t.Format(layout), reusing the previous result while t
+// stays within the same tick.
+func formatTime(t time.Time, layout string) string {
+	c, ok := timeCaches.Load(layout)
+	if !ok {
+		c, _ = timeCaches.LoadOrStore(layout, &timeCache{layout: layout, res: layoutResolution(layout)})
+	}
+	return c.(*timeCache).format(t)
+}
+
+func (c *timeCache) format(t time.Time) string {
+	if c.res == 0 {
+		return t.Format(c.layout)
+	}
+	tick := t.Unix()*(int64(time.Second)/c.res) + int64(t.Nanosecond())/c.res
+	loc := t.Location()
+	if last := c.last.Load(); last != nil && last.tick == tick && last.loc == loc {
+		return last.s
+	}
+	s := t.Format(c.layout)
+	c.last.Store(&cachedTime{tick: tick, loc: loc, s: s})
+	return s
+}
+
+// layoutResolution returns the tick length of layout in nanoseconds: a
+// second unless the layout has fractional seconds (".000" or ",999"), and 0
+// when it prints finer than a millisecond.
+func layoutResolution(layout string) int64 {
+	digits := 0
+	for i := 0; i+1 < len(layout); i++ {
+		if layout[i] != '.' && layout[i] != ',' {
+			continue
+		}
+		d := layout[i+1]
+		if d != '0' && d != '9' {
+			continue
+		}
+		n := 1
+		for i+1+n < len(layout) && layout[i+1+n] == d {
+			n++
+		}
+		digits = max(digits, n)
+	}
+	if digits > 3 {
+		return 0
+	}
+	res := int64(time.Second)
+	for ; digits > 0; digits-- {
+		res /= 10
+	}
+	return res
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+	"time"
+)
+
+// CertExpiryWarning is how close to expiry a certificate must be for
+// LogTLS to warn about it.
+const CertExpiryWarning = 30 * 24 * time.Hour
+
+// tlsCaller is shown on entries from LogTLS, which run inside crypto/tls.
+const tlsCaller = "logger.LogTLS"
+
+// warnedCerts holds the certificates LogTLS has already warned about, so
+// each is reported once per process rather than on every handshake.
+var warnedCerts sync.Map
+
+// LogTLS returns a copy of cfg that logs every completed handshake at DEBUG
+// with the SNI name and ALPN protocol when present, the negotiated version
+// and cipher suite, and the peer certificate's subject and expiry. A
+// certificate expiring within CertExpiryWarning, whether the peer's or one
+// of cfg.Certificates, is logged once at WARN, since an expired certificate
+// is a common cause of outages. Works for clients and servers; an existing
+// VerifyConnection is still called:
+//
+//	client := &http.Client{Transport: &http.Transport{TLSClientConfig: logger.LogTLS(&tls.Config{})}}
+//	// [DEBUG] [logger.LogTLS] tls handshake sni=api.example.com version=TLS 1.3 cipher=TLS_AES_128_GCM_SHA256 alpn=h2 peer=CN=api.example.com expires=2026-05-01T00:00:00Z
+//	// [WARN] [logger.LogTLS] certificate expires soon subject=CN=api.example.com expires=2026-05-01T00:00:00Z days_left=12
+func LogTLS(cfg *tls.Config) *tls.Config {
+	if cfg == nil {
+		cfg = &tls.Config{}
+	}
+	cfg = cfg.Clone()
+	for _, cert := range cfg.Certificates {
+		if leaf := certLeaf(cert); leaf != nil {
+			warnCertExpiry(leaf)
+		}
+	}
+	verify := cfg.VerifyConnection
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		logHandshake(cs)
+		if verify != nil {
+			return verify(cs)
+		}
+		return nil
+	}
+	return cfg
+}
+
+func logHandshake(cs tls.ConnectionState) {
+	var fields []any
+	if cs.ServerName != "" {
+		fields = append(fields, "sni", cs.ServerName)
+	}
+	fields = append(fields, "version", tls.VersionName(cs.Version), "cipher", tls.CipherSuiteName(cs.CipherSuite))
+	if cs.NegotiatedProtocol != "" {
+		fields = append(fields, "alpn", cs.NegotiatedProtocol)
+	}
+	if len(cs.PeerCertificates) > 0 {
+		peer := cs.PeerCertificates[0]
+		fields = append(fields, "peer", peer.Subject.String(), "expires", peer.NotAfter.UTC().Format(time.RFC3339))
+		warnCertExpiry(peer)
+	}
+	netDebug(tlsCaller, "tls handshake", fields...)
+}
+
+// warnCertExpiry logs cert at WARN the first time it is seen within
+// CertExpiryWarning of its expiry.
+func warnCertExpiry(cert *x509.Certificate) {
+	left := time.Until(cert.NotAfter)
+	if left > CertExpiryWarning || !isLevelEnabled(WarnLevel) {
+		return
+	}
+	if _, seen := warnedCerts.LoadOrStore(string(cert.Raw), true); seen {
+		return
+	}
+	msg := "certificate expires soon"
+	if left <= 0 {
+		msg = "certificate expired"
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(WarnLevel), WarnLevel, tlsCaller, msg, []any{
+		"subject", cert.Subject.String(),
+		"expires", cert.NotAfter.UTC().Format(time.RFC3339),
+		"days_left", int(left.Hours() / 24),
+	})
+}
+
+// certLeaf returns the parsed leaf of cert.
+func certLeaf(cert tls.Certificate) *x509.Certificate {
+	if cert.Leaf != nil {
+		return cert.Leaf
+	}
+	if len(cert.Certificate) == 0 {
+		return nil
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil
+	}
+	return leaf
+}
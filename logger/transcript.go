@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TranscriptConfig configures NewTranscriptSink.
+type TranscriptConfig struct {
+	// Path is the history file. Defaults to history.jsonl in a directory
+	// named after the program under os.UserConfigDir, e.g.
+	// ~/.config/mytool/history.jsonl.
+	Path string
+	// Args are the recorded arguments. Defaults to os.Args[1:].
+	Args []string
+	// Redact lists flags whose values are hidden in the recorded arguments,
+	// as in CommandConfig.Redact.
+	Redact []string
+}
+
+// TranscriptSink records one invocation of a CLI tool: when it is closed,
+// it appends a single JSON line to a per-user history file with the
+// program, its redacted arguments, the duration, the exit status, and the
+// number of ERROR and FATAL entries along with the last one's message:
+//
+//	{"time":"2026-03-02T10:14:07Z","cmd":"mytool","args":["deploy","--token","[REDACTED]"],"duration_ms":1840,"exit":1,"errors":1,"error":"upload failed"}
+//
+// Add it with AddSink and let Close write the record:
+//
+//	transcript, err := logger.NewTranscriptSink(logger.TranscriptConfig{Redact: []string{"--token"}})
+//	if err == nil {
+//	    logger.AddSink(transcript)
+//	}
+//	defer logger.Close()
+//
+// A Fatal function writes the record with exit status 1 before exiting.
+type TranscriptSink struct {
+	f     *os.File
+	start time.Time
+	cmd   string
+	args  []string
+
+	mu        sync.Mutex
+	errors    int
+	lastError string
+	exit      int
+	exitSet   bool
+	written   bool
+}
+
+// transcriptRecord is the JSON line appended by TranscriptSink.
+type transcriptRecord struct {
+	Time       time.Time `json:"time"`
+	Cmd        string    `json:"cmd"`
+	Args       []string  `json:"args"`
+	DurationMS int64     `json:"duration_ms"`
+	Exit       int       `json:"exit"`
+	Errors     int       `json:"errors"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// NewTranscriptSink opens the history file for appending, creating it and
+// its directory, readable only by the user, if needed. The invocation's
+// duration is measured from this call.
+func NewTranscriptSink(cfg TranscriptConfig) (*TranscriptSink, error) {
+	cmd := filepath.Base(os.Args[0])
+	if cfg.Path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		cfg.Path = filepath.Join(dir, cmd, "history.jsonl")
+	}
+	if cfg.Args == nil {
+		cfg.Args = os.Args[1:]
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &TranscriptSink{f: f, start: time.Now(), cmd: cmd, args: redactArgs(cfg.Args, cfg.Redact)}, nil
+}
+
+// WriteEntry counts ERROR and FATAL entries for the record.
+func (s *TranscriptSink) WriteEntry(e *Entry) error {
+	if e.Level < ErrorLevel {
+		return nil
+	}
+	s.mu.Lock()
+	s.errors++
+	s.lastError = e.Message
+	s.mu.Unlock()
+	return nil
+}
+
+// SetExitStatus sets the exit status recorded by Close. Without it, the
+// status is 1 if an ERROR or FATAL entry was logged and 0 otherwise.
+func (s *TranscriptSink) SetExitStatus(code int) {
+	s.mu.Lock()
+	s.exit, s.exitSet = code, true
+	s.mu.Unlock()
+}
+
+// Close appends the record and closes the history file.
+func (s *TranscriptSink) Close() error {
+	err := s.writeRecord()
+	if cerr := s.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// fatalExit records exit status 1 for a Fatal function about to exit.
+func (s *TranscriptSink) fatalExit() {
+	s.SetExitStatus(1)
+	s.writeRecord()
+}
+
+// writeRecord appends the record with a single write, once.
+func (s *TranscriptSink) writeRecord() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.written {
+		return nil
+	}
+	s.written = true
+	exit := s.exit
+	if !s.exitSet && s.errors > 0 {
+		exit = 1
+	}
+	line, err := json.Marshal(transcriptRecord{
+		Time:       s.start.UTC().Truncate(time.Second),
+		Cmd:        s.cmd,
+		Args:       s.args,
+		DurationMS: time.Since(s.start).Milliseconds(),
+		Exit:       exit,
+		Errors:     s.errors,
+		Error:      s.lastError,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.f.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Fields of entries logged with Transition.
+const (
+	StateMachineKey = "state_machine"
+	StateFromKey    = "state_from"
+	StateToKey      = "state_to"
+)
+
+// TransitionValidator reports whether machine may move from one state to
+// another, returning an error that describes a forbidden transition.
+type TransitionValidator func(machine string, from, to any) error
+
+var (
+	transitionMu        sync.RWMutex
+	transitionValidator TransitionValidator
+)
+
+// SetTransitionValidator installs v to check every Transition; nil removes
+// it. AllowTransitions builds a validator from a table.
+func SetTransitionValidator(v TransitionValidator) {
+	transitionMu.Lock()
+	defer transitionMu.Unlock()
+	transitionValidator = v
+}
+
+// AllowTransitions returns a validator permitting, per machine, only the
+// listed moves from each state; machines without a table are not checked:
+//
+//	logger.SetTransitionValidator(logger.AllowTransitions(map[string]map[any][]any{
+//	    "worker": {"idle": {"busy"}, "busy": {"idle", "draining"}, "draining": {"stopped"}},
+//	}))
+func AllowTransitions(tables map[string]map[any][]any) TransitionValidator {
+	return func(machine string, from, to any) error {
+		table, ok := tables[machine]
+		if !ok {
+			return nil
+		}
+		for _, allowed := range table[from] {
+			if allowed == to {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v -> %v is not an allowed transition", from, to)
+	}
+}
+
+// Transition logs that machine moved from one state to another, at INFO
+// with the standard state_machine, state_from, and state_to fields followed
+// by keyvals:
+//
+//	logger.Transition("worker", "idle", "busy", "job", id)
+//	// [INFO] [pool.run:88] state transition state_machine=worker state_from=idle state_to=busy job=42
+//
+// When the validator set with SetTransitionValidator rejects the move, the
+// entry is logged at WARN as "invalid state transition" with the error.
+func Transition(machine string, from, to any, keyvals ...any) {
+	transitionMu.RLock()
+	validate := transitionValidator
+	transitionMu.RUnlock()
+
+	level, msg := InfoLevel, "state transition"
+	fields := []any{StateMachineKey, machine, StateFromKey, from, StateToKey, to}
+	if validate != nil {
+		if err := validate(machine, from, to); err != nil {
+			level, msg = WarnLevel, "invalid state transition"
+			fields = append(fields, "error", err)
+		}
+	}
+	if !isLevelEnabled(level) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(level), level, getCallerInfo(2), msg, append(fields, keyvals...))
+}
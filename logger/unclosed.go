@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// UnclosedEvent is the event ID of the warnings written when entries may be
+// lost because Close or FileSink.Close was never called.
+const UnclosedEvent = "logger.unclosed"
+
+// exitFatal ends the process after a FATAL entry. os.Exit skips deferred
+// calls, so entries still queued for sinks or buffered inside them, which a
+// deferred Close would have written, are lost; a warning counting them is
+// written to stderr first. Config.FileAsync output is already flushed by
+// the FATAL entry.
+func exitFatal() {
+	for _, w := range workers {
+		if s, ok := w.sink.(fatalExiter); ok {
+			w.pending.Wait()
+			s.fatalExit()
+		}
+	}
+	warnUnclosed(outStderr)
+	os.Exit(1)
+}
+
+// fatalExiter is implemented by sinks that must write before a Fatal
+// function exits, such as TranscriptSink. exitFatal waits for their queued
+// entries, including the FATAL one, first.
+type fatalExiter interface {
+	fatalExit()
+}
+
+// warnUnclosed writes a WARN entry to w when sinks may still hold entries.
+// It writes directly, without logMutex, which the Fatal functions hold when
+// exiting.
+func warnUnclosed(w io.Writer) {
+	if len(workers) == 0 {
+		return
+	}
+	queued := 0
+	for _, worker := range workers {
+		queued += worker.queued()
+	}
+	e := &Entry{
+		Time:    time.Now(),
+		Level:   WarnLevel,
+		Caller:  "logger.Close",
+		Message: "exiting without Close, queued entries may be lost",
+		Fields:  []any{EventKey, UnclosedEvent, "sinks", len(workers), "queued", queued},
+	}
+	if b, err := (TextEncoder{}).Encode(e); err == nil {
+		w.Write(append(b, '\n'))
+	}
+}
+
+// warnUnclosedFileSink reports a FileSink that was garbage collected without
+// Close, so a missing defer sink.Close() shows up in the logs.
+func warnUnclosedFileSink(path string) {
+	watchdogLog(WarnLevel, "logger.FileSink", "file sink garbage collected without Close",
+		EventKey, UnclosedEvent, "path", path)
+}
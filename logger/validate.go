@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Validate reports settings in cfg, and in the LOGGER_LEVELS and
+// LOGGER_FIELDS environment variables, that InitWithConfig would accept
+// silently but ignore or replace with a default. It returns nil for a clean configuration and otherwise one
+// error per problem, joined with errors.Join.
+func Validate(cfg Config) error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("logger: "+format, args...))
+	}
+	production := cfg.Mode == "production"
+
+	if cfg.Mode != "development" && !production {
+		add("unknown mode %q, development mode will be used", cfg.Mode)
+	}
+	if cfg.Fallback < FallbackPlain || cfg.Fallback > FallbackAuto {
+		add("unknown Fallback %d", cfg.Fallback)
+	} else if cfg.Fallback != FallbackPlain && !production {
+		add("Fallback is ignored in development mode")
+	}
+	if cfg.Fallback == FallbackFileOnly && cfg.FilePath == "" {
+		add("FallbackFileOnly without FilePath writes to stdout/stderr")
+	}
+	if cfg.Journal < JournalDefault || cfg.Journal > JournalBare {
+		add("unknown Journal style %d", cfg.Journal)
+	} else if cfg.Journal != JournalDefault && production && cfg.Fallback == FallbackJSON {
+		add("Journal is ignored with FallbackJSON")
+	}
+	if cfg.Severity < SeverityNone || cfg.Severity > SeverityPrefix {
+		add("unknown Severity mode %d", cfg.Severity)
+	}
+	if cfg.Sanitize < SanitizeNone || cfg.Sanitize > SanitizeStrict {
+		add("unknown Sanitize mode %d", cfg.Sanitize)
+	}
+
+	if cfg.Layout != "" {
+		if production && cfg.Fallback == FallbackJSON {
+			add("Layout is ignored with FallbackJSON")
+		}
+		for _, p := range parseLayout(cfg.Layout, "", false).parts {
+			if p.token != tokenLiteral {
+				continue
+			}
+			for rest := p.literal; ; {
+				start := strings.IndexByte(rest, '{')
+				end := strings.IndexByte(rest[max(start, 0):], '}')
+				if start < 0 || end < 0 {
+					break
+				}
+				add("unknown Layout placeholder %s is printed literally", rest[start:start+end+1])
+				rest = rest[start+end+1:]
+			}
+		}
+	} else if cfg.TimeFormat != "" {
+		add("TimeFormat is only used with Layout")
+	}
+
+	profile, source := cfg.Colors, "Colors"
+	if profile == "" {
+		profile, source = ColorProfile(os.Getenv(ColorsEnv)), ColorsEnv
+	}
+	if _, ok := colorProfiles[profile]; profile != "" && !ok {
+		add("%s has unknown color profile %q, dark colors will be used", source, profile)
+	}
+	for level := range cfg.Palette {
+		if !validLevel(level) {
+			add("Palette has unknown level %d", level)
+		}
+	}
+	if production && (cfg.Colors != "" || cfg.Palette != nil) {
+		add("Colors and Palette are ignored in production mode")
+	}
+	if production && cfg.SourceContext {
+		add("SourceContext is ignored in production mode")
+	}
+
+	if cfg.FilePath == "" {
+		if cfg.FileAsync {
+			add("FileAsync is ignored without FilePath")
+		}
+		if cfg.FileLevels != nil {
+			add("FileLevels is ignored without FilePath")
+		}
+	} else if info, err := os.Stat(filepath.Dir(cfg.FilePath)); err != nil {
+		add("log file directory: %v", err)
+	} else if !info.IsDir() {
+		add("log file directory %s is not a directory", filepath.Dir(cfg.FilePath))
+	}
+
+	checkMapping := func(name string, m *LevelMapping) {
+		if m == nil {
+			return
+		}
+		if !validLevel(m.Min) {
+			add("%s.Min has unknown level %d", name, m.Min)
+		}
+		for from, to := range m.Remap {
+			if !validLevel(from) || !validLevel(to) {
+				add("%s.Remap has unknown level in %d -> %d", name, from, to)
+			}
+		}
+	}
+	checkMapping("ConsoleLevels", cfg.ConsoleLevels)
+	checkMapping("FileLevels", cfg.FileLevels)
+
+	for name, level := range cfg.LoggerLevels {
+		if name != "" && (strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") || strings.Contains(name, "..")) {
+			add("LoggerLevels has malformed logger name %q", name)
+		}
+		if !validLevel(level) {
+			add("LoggerLevels[%q] has unknown level %d", name, level)
+		}
+	}
+
+	if len(cfg.Fields)%2 != 0 {
+		add("Fields has an odd number of elements, the last key has no value")
+	}
+	for i := 0; i < len(cfg.Fields); i += 2 {
+		if _, ok := cfg.Fields[i].(string); !ok {
+			add("Fields key %v is not a string", cfg.Fields[i])
+		}
+	}
+
+	if cfg.MaxLineBytes > 0 && cfg.MaxLineBytes < MinLineBytes {
+		add("MaxLineBytes %d is below MinLineBytes, %d will be used", cfg.MaxLineBytes, MinLineBytes)
+	}
+
+	if (cfg.CrashLoopRestarts != 0 || cfg.CrashLoopWindow != 0) && cfg.RestartStateFile == "" {
+		add("CrashLoopRestarts and CrashLoopWindow are ignored without RestartStateFile")
+	}
+	if cfg.Version != "" && !cfg.StartupEntry {
+		add("Version is only used with StartupEntry")
+	}
+
+	if id := resolveIdentifier(cfg); strings.ContainsAny(id, " \t\n:[]") {
+		add("identifier %q contains spaces, colons, or brackets that syslog parsers split on", id)
+	}
+
+	if cfg.Locale != "" {
+		lang, _, _ := strings.Cut(cfg.Locale, "_")
+		catalogsMu.RLock()
+		_, exact := catalogs[cfg.Locale]
+		_, base := catalogs[lang]
+		catalogsMu.RUnlock()
+		if !exact && !base {
+			add("no catalog registered for Locale %q", cfg.Locale)
+		}
+	}
+
+	if levels := os.Getenv("LOGGER_LEVELS"); levels != "" {
+		_, levelErrs := parseLevelSpec(levels)
+		for _, err := range levelErrs {
+			errs = append(errs, fmt.Errorf("logger: %w", err))
+		}
+	}
+	if _, err := parseEnvFields(os.Getenv("LOGGER_FIELDS")); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// validLevel reports whether level is one of the defined levels.
+func validLevel(level Level) bool {
+	return level >= DebugLevel && level <= FatalLevel
+}
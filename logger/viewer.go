@@ -0,0 +1,384 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// viewerRefresh is the shortest interval between two redraws of the viewer.
+const viewerRefresh = 50 * time.Millisecond
+
+// StartViewer replaces the development console with an interactive viewer
+// of the entries in ring, drawn on the terminal's alternate screen. A nil
+// ring creates one of DefaultRingSize entries and registers it with
+// AddSink. The viewer follows new entries until paused, and understands:
+//
+//	space      pause or follow
+//	1-5        show DEBUG, INFO, WARN, ERROR, or FATAL and above
+//	/          filter by regular expression (Enter applies, Esc clears)
+//	e, E       jump to the previous or next ERROR or FATAL entry
+//	j, k, ↓, ↑ scroll a line; PgDn, PgUp scroll a page
+//	g, G       jump to the oldest entry or follow the newest
+//	q          quit the viewer
+//
+// DEBUG to ERROR console output is discarded while the viewer runs and
+// restored when it quits or stop is called; files and sinks are unaffected.
+// Standard input must be a terminal; the viewer is unsupported on
+// platforms without termios, such as Windows.
+//
+//	stop, err := logger.StartViewer(nil)
+//	if err != nil {
+//	    logger.Warnf("log viewer unavailable: %v", err)
+//	}
+//	defer stop()
+func StartViewer(ring *RingSink) (stop func(), err error) {
+	in, out := int(os.Stdin.Fd()), os.Stdout
+	restoreTerm, err := makeRaw(in)
+	if err != nil {
+		return nil, fmt.Errorf("logger: viewer needs a terminal: %w", err)
+	}
+	if ring == nil {
+		ring = NewRingSink(0)
+		AddSink(ring)
+	}
+	restoreConsole := silenceConsole()
+	fmt.Fprint(out, "\033[?1049h\033[?25l")
+
+	v := newViewer(ring)
+	keys := make(chan string)
+	go readKeys(bufio.NewReader(os.Stdin), keys)
+
+	done, exited := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(exited)
+		tick := time.NewTicker(viewerRefresh)
+		defer tick.Stop()
+		dirty := true
+		for {
+			select {
+			case <-done:
+				return
+			case k := <-keys:
+				if v.handleKey(k) {
+					return
+				}
+				dirty = true
+			case <-ring.notify:
+				dirty = true
+			case <-tick.C:
+				if dirty {
+					rows, cols, err := terminalSize(in)
+					if err != nil || rows <= 0 || cols <= 0 {
+						rows, cols = 24, 80
+					}
+					v.render(out, rows, cols)
+					dirty = false
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+			fmt.Fprint(out, "\033[?25h\033[?1049l")
+			restoreTerm()
+			restoreConsole()
+		})
+	}
+	go func() {
+		// quitting from the keyboard restores the terminal without stop
+		<-exited
+		stop()
+	}()
+	return stop, nil
+}
+
+// silenceConsole discards DEBUG to ERROR console output and returns a
+// function restoring it, unless Init replaced the loggers in the meantime.
+func silenceConsole() (restore func()) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	loggers := []*log.Logger{Debug, Info, Warning, Error}
+	writers := make([]io.Writer, len(loggers))
+	for i, l := range loggers {
+		writers[i] = l.Writer()
+		l.SetOutput(io.Discard)
+	}
+	return func() {
+		logMutex.Lock()
+		defer logMutex.Unlock()
+		for i, l := range loggers {
+			if l.Writer() == io.Discard {
+				l.SetOutput(writers[i])
+			}
+		}
+	}
+}
+
+// readKeys sends the keys read from r to keys until r fails. Escape
+// sequences for arrows and page keys are reported by name.
+func readKeys(r *bufio.Reader, keys chan<- string) {
+	for {
+		c, _, err := r.ReadRune()
+		if err != nil {
+			return
+		}
+		key := string(c)
+		switch c {
+		case '\r', '\n':
+			key = "enter"
+		case 0x7f, 0x08:
+			key = "backspace"
+		case 0x03:
+			key = "q"
+		case 0x1b:
+			key = "esc"
+			if r.Buffered() > 0 {
+				key = readEscape(r)
+			}
+		}
+		keys <- key
+	}
+}
+
+// readEscape reads the rest of an escape sequence from r.
+func readEscape(r *bufio.Reader) string {
+	if b, _ := r.ReadByte(); b != '[' {
+		return "esc"
+	}
+	var seq []byte
+	for r.Buffered() > 0 {
+		b, _ := r.ReadByte()
+		seq = append(seq, b)
+		if b >= 0x40 && b <= 0x7e {
+			break
+		}
+	}
+	switch string(seq) {
+	case "A":
+		return "up"
+	case "B":
+		return "down"
+	case "5~":
+		return "pgup"
+	case "6~":
+		return "pgdn"
+	case "H", "1~":
+		return "home"
+	case "F", "4~":
+		return "end"
+	}
+	return "esc"
+}
+
+// viewer is the state of the interactive log viewer, kept apart from the
+// terminal so it can be driven by keys and rendered to any writer.
+type viewer struct {
+	ring   *RingSink
+	min    Level
+	filter *regexp.Regexp
+
+	paused bool
+	bottom int // with paused, one past the last visible line
+	page   int // lines per page at the last render
+
+	prompt  bool // reading a filter expression
+	input   string
+	message string // shown once in the status bar, e.g. an invalid filter
+}
+
+func newViewer(ring *RingSink) *viewer {
+	return &viewer{ring: ring, min: DebugLevel, page: 23}
+}
+
+// lines returns the entries passing the level and regexp filters, rendered
+// without color.
+func (v *viewer) lines() ([]string, []Level) {
+	var lines []string
+	var levels []Level
+	enc := TextEncoder{TimeFormat: "15:04:05.000"}
+	for _, e := range v.ring.Entries() {
+		if e.Level < v.min {
+			continue
+		}
+		b, _ := enc.Encode(e)
+		line := strings.ReplaceAll(string(b), "\n", " ")
+		if v.filter != nil && !v.filter.MatchString(line) {
+			continue
+		}
+		lines = append(lines, line)
+		levels = append(levels, e.Level)
+	}
+	return lines, levels
+}
+
+// handleKey applies a key and reports whether the viewer should quit.
+func (v *viewer) handleKey(key string) (quit bool) {
+	v.message = ""
+	if v.prompt {
+		v.editPrompt(key)
+		return false
+	}
+	lines, levels := v.lines()
+	switch key {
+	case "q":
+		return true
+	case " ":
+		v.paused = !v.paused
+		v.bottom = len(lines)
+	case "1", "2", "3", "4", "5":
+		v.min = Level(key[0] - '1')
+		v.paused = false
+	case "/":
+		v.prompt, v.input = true, ""
+		if v.filter != nil {
+			v.input = v.filter.String()
+		}
+	case "j", "down":
+		v.scroll(1, len(lines))
+	case "k", "up":
+		v.scroll(-1, len(lines))
+	case "pgdn":
+		v.scroll(v.page, len(lines))
+	case "pgup":
+		v.scroll(-v.page, len(lines))
+	case "g", "home":
+		v.paused, v.bottom = true, min(v.page, len(lines))
+	case "G", "end":
+		v.paused = false
+	case "e":
+		v.jumpError(levels, -1)
+	case "E":
+		v.jumpError(levels, 1)
+	}
+	return false
+}
+
+// editPrompt handles a key while the filter expression is being typed.
+func (v *viewer) editPrompt(key string) {
+	switch key {
+	case "enter":
+		v.prompt = false
+		if v.input == "" {
+			v.filter = nil
+			return
+		}
+		re, err := regexp.Compile(v.input)
+		if err != nil {
+			v.message = "invalid filter: " + err.Error()
+			return
+		}
+		v.filter, v.paused = re, false
+	case "esc":
+		v.prompt, v.filter = false, nil
+	case "backspace":
+		if _, size := utf8.DecodeLastRuneInString(v.input); size > 0 {
+			v.input = v.input[:len(v.input)-size]
+		}
+	default:
+		if utf8.RuneCountInString(key) == 1 {
+			v.input += key
+		}
+	}
+}
+
+// scroll moves the view by n lines, pausing it.
+func (v *viewer) scroll(n, total int) {
+	if !v.paused {
+		v.paused, v.bottom = true, total
+	}
+	v.bottom = max(min(v.bottom+n, total), min(v.page, total))
+}
+
+// jumpError pauses the view with the previous (dir -1) or next (dir 1)
+// ERROR or FATAL line as its last line.
+func (v *viewer) jumpError(levels []Level, dir int) {
+	if !v.paused {
+		v.bottom = len(levels)
+	}
+	for i := v.bottom - 1 + dir; i >= 0 && i < len(levels); i += dir {
+		if levels[i] >= ErrorLevel {
+			v.paused, v.bottom = true, i+1
+			return
+		}
+	}
+	v.message = "no more errors"
+}
+
+// render draws the view on a rows by cols screen: the visible lines
+// followed by a status bar.
+func (v *viewer) render(w io.Writer, rows, cols int) {
+	v.page = max(rows-1, 1)
+	lines, levels := v.lines()
+	bottom := len(lines)
+	if v.paused {
+		bottom = max(min(v.bottom, len(lines)), 0)
+	}
+	top := max(bottom-v.page, 0)
+	logMutex.Lock()
+	color, palette := textFormat.color, levelColors
+	logMutex.Unlock()
+
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	for i := top; i < bottom; i++ {
+		line := truncateRunes(lines[i], cols)
+		if color {
+			tag := "[" + levelNames[levels[i]] + "]"
+			line = strings.Replace(line, tag, palette[levels[i]]+tag+colorReset, 1)
+		}
+		b.WriteString(line)
+		b.WriteString("\r\n")
+	}
+	fmt.Fprintf(&b, "\033[%d;1H\033[7m%s\033[0m", rows, padRunes(truncateRunes(v.status(len(lines)), cols), cols))
+	io.WriteString(w, b.String())
+}
+
+// status returns the text of the status bar.
+func (v *viewer) status(shown int) string {
+	if v.prompt {
+		return "filter: /" + v.input
+	}
+	mode := "FOLLOW"
+	if v.paused {
+		mode = "PAUSED"
+	}
+	s := fmt.Sprintf(" %s  level>=%s  %d shown  %d total", mode, levelNames[v.min], shown, v.ring.Total())
+	if v.filter != nil {
+		s += "  /" + v.filter.String()
+	}
+	if v.message != "" {
+		s += "  " + v.message
+	}
+	return s + "  [space] pause [1-5] level [/] filter [e/E] errors [q] quit"
+}
+
+// truncateRunes shortens s to at most n runes.
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+// padRunes pads s with spaces to n runes.
+func padRunes(s string, n int) string {
+	if pad := n - utf8.RuneCountInString(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// WriterMaxLine is the longest partial line a LineWriter buffers while
+// waiting for its newline; a longer one is logged as an entry of its own.
+const WriterMaxLine = 64 << 10
+
+// LineWriter is an io.Writer that logs each line written to it as one
+// entry, created with NewWriter.
+type LineWriter struct {
+	level  Level
+	caller string
+	fields []any
+
+	mu      sync.Mutex
+	partial []byte
+}
+
+// NewWriter returns an io.Writer for third-party code that only accepts a
+// writer, such as a subprocess's stderr or http.Server.ErrorLog. Each line
+// becomes an entry at level with keyvals as fields, attributed to where
+// NewWriter was called. Multi-line writes are split into one entry per
+// line and a trailing partial line is held until its newline arrives, so
+// writers that emit a line in pieces never produce half-entries. Blank
+// lines are skipped and "\r\n" endings are trimmed. Call Flush or Close to
+// log a final line without a newline:
+//
+//	w := logger.NewWriter(logger.WarnLevel, "source", "ffmpeg")
+//	defer w.Close()
+//	cmd.Stderr = w
+//	// [WARN] [main.transcode:31] Past duration 0.998 too large source=ffmpeg
+func NewWriter(level Level, keyvals ...any) *LineWriter {
+	return &LineWriter{level: level, caller: getCallerInfo(2), fields: keyvals}
+}
+
+// Write logs the complete lines in p and buffers the rest. It always
+// reports len(p) bytes written.
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.partial = append(w.partial, p...)
+			if len(w.partial) > WriterMaxLine {
+				w.flushLocked()
+			}
+			break
+		}
+		w.partial = append(w.partial, p[:i]...)
+		w.flushLocked()
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// Flush logs the buffered partial line, if any.
+func (w *LineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushLocked()
+}
+
+// Close flushes w. It always returns nil.
+func (w *LineWriter) Close() error {
+	w.Flush()
+	return nil
+}
+
+// flushLocked logs the buffered line. Callers must hold w.mu.
+func (w *LineWriter) flushLocked() {
+	line := string(bytes.TrimSuffix(w.partial, []byte("\r")))
+	w.partial = w.partial[:0]
+	if strings.TrimSpace(line) == "" || !isLevelEnabled(w.level) {
+		return
+	}
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	output(loggerFor(w.level), w.level, w.caller, line, w.fields)
+}
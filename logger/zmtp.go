@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ZMTP 3.0 frame flags (https://rfc.zeromq.org/spec/23/).
+const (
+	zmtpMore    = 0x01
+	zmtpLong    = 0x02
+	zmtpCommand = 0x04
+)
+
+// zmtpMaxFrame caps frames read from a peer; subscriptions and handshake
+// commands are small.
+const zmtpMaxFrame = 64 << 10
+
+// zmtpGreeting is the 64-byte ZMTP 3.0 greeting for the NULL mechanism:
+// signature, version 3.0, mechanism name, as-server flag, and filler.
+var zmtpGreeting = func() []byte {
+	g := make([]byte, 64)
+	g[0], g[9] = 0xff, 0x7f
+	g[10], g[11] = 3, 0
+	copy(g[12:32], "NULL")
+	return g
+}()
+
+// zmtpHandshake exchanges greetings and READY commands on conn, announcing
+// socketType and accepting a peer of one of the peerTypes. Reads go through
+// r so that frames buffered after READY are kept for the caller.
+func zmtpHandshake(conn io.Writer, r *bufio.Reader, socketType string, peerTypes ...string) error {
+	if _, err := conn.Write(zmtpGreeting); err != nil {
+		return err
+	}
+	var greeting [64]byte
+	if _, err := io.ReadFull(r, greeting[:]); err != nil {
+		return err
+	}
+	if greeting[0] != 0xff || greeting[9]&0x01 == 0 || greeting[10] < 3 {
+		return errors.New("logger: zmtp peer does not speak ZMTP 3")
+	}
+	if mech := string(bytes.TrimRight(greeting[12:32], "\x00")); mech != "NULL" {
+		return fmt.Errorf("logger: unsupported zmtp mechanism %q", mech)
+	}
+
+	ready := []byte{5}
+	ready = append(ready, "READY"...)
+	ready = appendZMTPProperty(ready, "Socket-Type", socketType)
+	if _, err := conn.Write(appendZMTPFrame(nil, zmtpCommand, ready)); err != nil {
+		return err
+	}
+	flags, body, err := readZMTPFrame(r)
+	if err != nil {
+		return err
+	}
+	name, props, ok := parseZMTPCommand(body)
+	if flags&zmtpCommand == 0 || !ok || name != "READY" {
+		return errors.New("logger: expected zmtp READY command")
+	}
+	peer := props["socket-type"]
+	for _, t := range peerTypes {
+		if peer == t {
+			return nil
+		}
+	}
+	reason := "invalid socket type"
+	msg := append([]byte{5}, "ERROR"...)
+	msg = append(append(msg, byte(len(reason))), reason...)
+	conn.Write(appendZMTPFrame(nil, zmtpCommand, msg))
+	return fmt.Errorf("logger: zmtp %s socket cannot connect to %s", peer, socketType)
+}
+
+// appendZMTPFrame appends body as one frame with flags, choosing the short
+// or long size encoding.
+func appendZMTPFrame(b []byte, flags byte, body []byte) []byte {
+	if len(body) > 255 {
+		b = append(b, flags|zmtpLong)
+		b = binary.BigEndian.AppendUint64(b, uint64(len(body)))
+	} else {
+		b = append(b, flags, byte(len(body)))
+	}
+	return append(b, body...)
+}
+
+// appendZMTPProperty appends a metadata property of a READY command.
+func appendZMTPProperty(b []byte, name, value string) []byte {
+	b = append(b, byte(len(name)))
+	b = append(b, name...)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(value)))
+	return append(b, value...)
+}
+
+// readZMTPFrame reads one frame, rejecting frames over zmtpMaxFrame.
+func readZMTPFrame(r *bufio.Reader) (flags byte, body []byte, err error) {
+	if flags, err = r.ReadByte(); err != nil {
+		return 0, nil, err
+	}
+	var size uint64
+	if flags&zmtpLong != 0 {
+		var n [8]byte
+		if _, err := io.ReadFull(r, n[:]); err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(n[:])
+	} else {
+		n, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		size = uint64(n)
+	}
+	if size > zmtpMaxFrame {
+		return 0, nil, fmt.Errorf("logger: zmtp frame of %d bytes exceeds limit", size)
+	}
+	body = make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return flags, body, nil
+}
+
+// parseZMTPCommand splits a command body into its name and metadata
+// properties, with property names lowercased as they are case-insensitive.
+// Commands other than READY keep their data in props[""].
+func parseZMTPCommand(body []byte) (name string, props map[string]string, ok bool) {
+	if len(body) < 1 || len(body) < 1+int(body[0]) {
+		return "", nil, false
+	}
+	name, body = string(body[1:1+body[0]]), body[1+body[0]:]
+	props = make(map[string]string)
+	if name != "READY" {
+		props[""] = string(body)
+		return name, props, true
+	}
+	for len(body) > 0 {
+		n := int(body[0])
+		if len(body) < 1+n+4 {
+			return "", nil, false
+		}
+		key := strings.ToLower(string(body[1 : 1+n]))
+		body = body[1+n:]
+		v := binary.BigEndian.Uint32(body)
+		if uint64(len(body)-4) < uint64(v) {
+			return "", nil, false
+		}
+		props[key] = string(body[4 : 4+v])
+		body = body[4+v:]
+	}
+	return name, props, true
+}
+
+// zmtpSubscription decodes a subscription sent by a SUB socket: a message
+// starting with 1 (subscribe) or 0 (cancel) in ZMTP 3.0, or a SUBSCRIBE or
+// CANCEL command from ZMTP 3.1 peers.
+func zmtpSubscription(flags byte, body []byte) (topic string, subscribe, ok bool) {
+	if flags&zmtpCommand != 0 {
+		name, props, ok := parseZMTPCommand(body)
+		if !ok || (name != "SUBSCRIBE" && name != "CANCEL") {
+			return "", false, false
+		}
+		return props[""], name == "SUBSCRIBE", true
+	}
+	if flags&zmtpMore != 0 || len(body) == 0 || body[0] > 1 {
+		return "", false, false
+	}
+	return string(body[1:]), body[0] == 1, true
+}
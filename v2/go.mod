@@ -0,0 +1,7 @@
+module github.com/mordilloSan/go_logger/v2
+
+go 1.25.4
+
+// v1.8.0 is the first go_logger release with the APIs this module uses.
+// Tag it before v2.0.0 and add go.sum with GOWORK=off go mod tidy.
+require github.com/mordilloSan/go_logger v1.8.0
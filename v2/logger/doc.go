@@ -0,0 +1,32 @@
+// Package logger is version 2 of go_logger. It adds instance loggers
+// configured with options and returned by New, and an options-based Init,
+// while keeping the package-level helpers of version 1 as a facade over the
+// same engine, so a program can move one package at a time:
+//
+//	import (
+//	    logv1 "github.com/mordilloSan/go_logger/logger"
+//	    "github.com/mordilloSan/go_logger/v2/logger"
+//	)
+//
+//	logger.Init(logger.WithMode("production"), logger.WithFile("/var/log/app.log"))
+//	logv1.Info.Println("old call sites keep working")
+//	logger.Info("new call sites", "user", id) // same console, file, and sinks
+//
+// Entry, Level, Sink, and Encoder are the version 1 types, so sinks and
+// encoders written for either version work with both.
+//
+// # Migration plan
+//
+//  1. Replace logv1.Init or InitWithConfig with Init and options. Init
+//     validates the configuration and returns the problems Validate finds.
+//  2. Replace the package-level calls, e.g. logv1.InfoKV and
+//     logv1.InfoContext, with Info and InfoContext, and logv1.Get with
+//     Default().Named. These log through the version 1 engine, so named
+//     logger levels, routes, sinks, and context fields apply unchanged.
+//  3. Where a component needs its own outputs, such as a library or a
+//     test, take a *Logger as a dependency and construct it with New.
+//     Instance loggers have their own level, encoder, writer, and sinks, and
+//     share no state with the global pipeline.
+//  4. Once no call site imports version 1, the facade is the only user of
+//     its engine, which later releases can move into this module.
+package logger
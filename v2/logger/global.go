@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+
+	v1 "github.com/mordilloSan/go_logger/logger"
+)
+
+// global is the backend of Default and its descendants: the version 1
+// engine, configured by Init.
+type global struct{}
+
+func (global) enabled(name string, level Level) bool {
+	return v1.Get(name).Enabled(level)
+}
+
+func (global) log(ctx context.Context, skip int, name string, level Level, msg string, keyvals []any) {
+	v1.Get(name).LogSkip(ctx, skip+1, level, msg, keyvals...)
+}
+
+var (
+	defaultLogger = &Logger{out: global{}}
+	// std is the logger used by the package-level functions
+	std atomic.Pointer[Logger]
+)
+
+func init() {
+	std.Store(defaultLogger)
+}
+
+// Default returns the root logger of the global pipeline. Its entries go
+// where version 1's do.
+func Default() *Logger {
+	return defaultLogger
+}
+
+// SetDefault makes the package-level functions log through l, e.g. an
+// instance logger in tests, and returns a function restoring the previous
+// logger. Version 1 call sites are not affected.
+func SetDefault(l *Logger) (restore func()) {
+	prev := std.Swap(l)
+	return func() { std.Store(prev) }
+}
+
+// --- Package-level functions (facade) ---
+
+// Debug logs a debug message with key-value pairs through the default logger.
+func Debug(msg string, keyvals ...any) {
+	std.Load().log(context.Background(), 0, DebugLevel, msg, keyvals)
+}
+
+// Info logs an informational message with key-value pairs through the
+// default logger.
+func Info(msg string, keyvals ...any) {
+	std.Load().log(context.Background(), 0, InfoLevel, msg, keyvals)
+}
+
+// Warn logs a warning message with key-value pairs through the default logger.
+func Warn(msg string, keyvals ...any) {
+	std.Load().log(context.Background(), 0, WarnLevel, msg, keyvals)
+}
+
+// Error logs an error message with key-value pairs through the default logger.
+func Error(msg string, keyvals ...any) {
+	std.Load().log(context.Background(), 0, ErrorLevel, msg, keyvals)
+}
+
+// Fatal logs a fatal message with key-value pairs through the default
+// logger, then exits with status 1.
+func Fatal(msg string, keyvals ...any) {
+	std.Load().log(context.Background(), 0, FatalLevel, msg, keyvals)
+}
+
+// DebugContext logs a debug message with key-value pairs and fields from ctx.
+func DebugContext(ctx context.Context, msg string, keyvals ...any) {
+	std.Load().log(ctx, 0, DebugLevel, msg, keyvals)
+}
+
+// InfoContext logs an informational message with key-value pairs and
+// fields from ctx.
+func InfoContext(ctx context.Context, msg string, keyvals ...any) {
+	std.Load().log(ctx, 0, InfoLevel, msg, keyvals)
+}
+
+// WarnContext logs a warning message with key-value pairs and fields from ctx.
+func WarnContext(ctx context.Context, msg string, keyvals ...any) {
+	std.Load().log(ctx, 0, WarnLevel, msg, keyvals)
+}
+
+// ErrorContext logs an error message with key-value pairs and fields from ctx.
+func ErrorContext(ctx context.Context, msg string, keyvals ...any) {
+	std.Load().log(ctx, 0, ErrorLevel, msg, keyvals)
+}
+
+// FatalContext logs a fatal message with key-value pairs and fields from
+// ctx, then exits with status 1.
+func FatalContext(ctx context.Context, msg string, keyvals ...any) {
+	std.Load().log(ctx, 0, FatalLevel, msg, keyvals)
+}
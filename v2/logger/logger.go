@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "github.com/mordilloSan/go_logger/logger"
+)
+
+// Logger writes entries either through the global pipeline configured by
+// Init (Default and its descendants) or to its own outputs (New and its
+// descendants). Named and With return descendants sharing those outputs.
+// A Logger is safe for concurrent use.
+type Logger struct {
+	out    backend
+	name   string
+	fields []any
+}
+
+// backend is where a Logger's entries go.
+type backend interface {
+	enabled(name string, level Level) bool
+	// log writes an entry attributed to the function skip frames above
+	// the caller of log, which is Logger.log.
+	log(ctx context.Context, skip int, name string, level Level, msg string, keyvals []any)
+}
+
+// exit ends the process after a FATAL entry of an instance logger.
+var exit = os.Exit
+
+// Named returns a descendant logger called name, or parent.name when l is
+// itself named. Its entries carry the name in LoggerKey; for Default
+// descendants the version 1 named logger settings, such as levels and
+// sinks set through logv1.Get(name), apply.
+func (l *Logger) Named(name string) *Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	return &Logger{out: l.out, name: name, fields: l.fields}
+}
+
+// With returns a descendant logger adding keyvals to every entry, before
+// the entry's own fields.
+func (l *Logger) With(keyvals ...any) *Logger {
+	return &Logger{out: l.out, name: l.name, fields: append(l.fields[:len(l.fields):len(l.fields)], keyvals...)}
+}
+
+// Name returns the logger's name.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// Enabled reports whether l writes entries at level.
+func (l *Logger) Enabled(level Level) bool {
+	return l.out.enabled(l.name, level)
+}
+
+// Debug logs a debug message with structured key-value pairs.
+func (l *Logger) Debug(msg string, keyvals ...any) {
+	l.log(context.Background(), 0, DebugLevel, msg, keyvals)
+}
+
+// Info logs an informational message with structured key-value pairs.
+func (l *Logger) Info(msg string, keyvals ...any) {
+	l.log(context.Background(), 0, InfoLevel, msg, keyvals)
+}
+
+// Warn logs a warning message with structured key-value pairs.
+func (l *Logger) Warn(msg string, keyvals ...any) {
+	l.log(context.Background(), 0, WarnLevel, msg, keyvals)
+}
+
+// Error logs an error message with structured key-value pairs.
+func (l *Logger) Error(msg string, keyvals ...any) {
+	l.log(context.Background(), 0, ErrorLevel, msg, keyvals)
+}
+
+// Fatal logs a fatal message with structured key-value pairs and then
+// exits with status 1.
+func (l *Logger) Fatal(msg string, keyvals ...any) {
+	l.log(context.Background(), 0, FatalLevel, msg, keyvals)
+}
+
+// Log logs a message at level with fields from ctx. Instance loggers
+// ignore ctx; Default descendants add the fields of ctx like the version 1
+// *Context functions.
+func (l *Logger) Log(ctx context.Context, level Level, msg string, keyvals ...any) {
+	l.log(ctx, 0, level, msg, keyvals)
+}
+
+// log adds l's fields and hands the entry to the backend. It must be
+// called from an exported function or method, which the entry skips to
+// name its caller; skip counts any frames in between.
+func (l *Logger) log(ctx context.Context, skip int, level Level, msg string, keyvals []any) {
+	if len(l.fields) > 0 {
+		keyvals = append(l.fields[:len(l.fields):len(l.fields)], keyvals...)
+	}
+	l.out.log(ctx, skip+2, l.name, level, msg, keyvals)
+}
+
+// --- Instance loggers ---
+
+// instance is the backend of loggers built with New.
+type instance struct {
+	level atomic.Int32
+	now   func() time.Time
+
+	mu      sync.Mutex
+	w       io.Writer
+	enc     Encoder
+	sinks   []Sink
+	failing map[Sink]bool
+}
+
+// New returns a logger with its own outputs, independent of Init and the
+// global pipeline. Without options it writes INFO and above to stderr as
+// text:
+//
+//	log := logger.New(
+//	    logger.WithLevel(logger.DebugLevel),
+//	    logger.WithOutput(os.Stdout),
+//	    logger.WithEncoder(logger.JSONEncoder{}),
+//	    logger.WithSink(auditSink),
+//	    logger.WithFields("component", "billing"),
+//	)
+//	defer log.Close()
+//	log.Info("invoice sent", "id", id)
+//
+// Entries are encoded and handed to its sinks on the logging goroutine.
+func New(opts ...Option) *Logger {
+	o := options{level: InfoLevel, w: os.Stderr, enc: TextEncoder{TimeFormat: v1.DefaultTimeFormat}, now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	in := &instance{now: o.now, w: o.w, enc: o.enc, sinks: o.sinks, failing: map[Sink]bool{}}
+	in.level.Store(int32(o.level))
+	return &Logger{out: in, name: o.name, fields: o.fields}
+}
+
+// SetLevel sets the lowest level written by an instance logger and every
+// logger sharing its outputs. It does nothing for Default descendants,
+// whose levels are set with Init and the version 1 API.
+func (l *Logger) SetLevel(level Level) {
+	if in, ok := l.out.(*instance); ok {
+		in.level.Store(int32(level))
+	}
+}
+
+// Close closes the sinks of an instance logger, returning their errors
+// joined. For Default descendants it does nothing; use the package-level
+// Close.
+func (l *Logger) Close() error {
+	in, ok := l.out.(*instance)
+	if !ok {
+		return nil
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	var errs []error
+	for _, s := range in.sinks {
+		if err := s.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("logger: closing %T: %w", s, err))
+		}
+	}
+	in.sinks = nil
+	return errors.Join(errs...)
+}
+
+func (in *instance) enabled(_ string, level Level) bool {
+	return level >= Level(in.level.Load())
+}
+
+func (in *instance) log(_ context.Context, skip int, name string, level Level, msg string, keyvals []any) {
+	if in.enabled(name, level) {
+		e := &Entry{Time: in.now(), Level: level, Caller: callerName(skip + 1), Message: msg, Fields: keyvals}
+		if name != "" {
+			e.Fields = append([]any{LoggerKey, name}, keyvals...)
+		}
+		in.write(e)
+	}
+	if level == FatalLevel {
+		exit(1)
+	}
+}
+
+// write encodes e to the writer and hands it to each sink, reporting a
+// failing sink on stderr once, and again only after it has recovered.
+func (in *instance) write(e *Entry) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if in.w != nil {
+		if data, err := in.enc.Encode(e); err == nil {
+			in.w.Write(append(data, '\n'))
+		}
+	}
+	for _, s := range in.sinks {
+		err := s.WriteEntry(e)
+		if err != nil && !in.failing[s] {
+			fmt.Fprintf(os.Stderr, "logger: sink %T failed: %v\n", s, err)
+		}
+		in.failing[s] = err != nil
+	}
+}
+
+// callerName returns "package.Function:line" for the function depth
+// frames above its caller, as version 1 names callers.
+func callerName(depth int) string {
+	var pcs [1]uintptr
+	if runtime.Callers(depth+2, pcs[:]) == 0 {
+		return "unknown"
+	}
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	if frame.Function == "" {
+		return "unknown"
+	}
+	name := frame.Function
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	return fmt.Sprintf("%s:%d", name, frame.Line)
+}
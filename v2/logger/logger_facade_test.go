@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	v1 "github.com/mordilloSan/go_logger/logger"
+)
+
+func TestFacade_SharesTheGlobalPipeline(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("LOGGER_LEVELS", "")
+	var buf bytes.Buffer
+	defer v1.SetOutputs(&buf, &buf)()
+	if err := Init(WithMode("development"), WithVerbose(true)); err != nil {
+		t.Fatal(err)
+	}
+	defer v1.Init("development", true)
+
+	Info("from v2", "k", "v")
+	v1.InfoKV("from v1")
+	Default().Named("http").With("req", 1).Warn("slow", "ms", 250)
+	InfoContext(v1.ContextWithFields(context.Background(), "trace_id", "abc"), "with context")
+	v1.Get("http").SetLevel(ErrorLevel)
+	defer v1.Get("http").ResetLevel()
+	Default().Named("http").Warn("dropped")
+
+	got := buf.String()
+	for _, want := range []string{
+		"[logger.TestFacade_SharesTheGlobalPipeline:22] from v2 k=v",
+		"from v1",
+		"[logger.TestFacade_SharesTheGlobalPipeline:24] slow logger=http req=1 ms=250",
+		"[logger.TestFacade_SharesTheGlobalPipeline:25] with context trace_id=abc",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "dropped") || Default().Named("http").Enabled(WarnLevel) {
+		t.Errorf("expected the version 1 level of http to apply, got:\n%s", got)
+	}
+}
+
+func TestSetDefault_RedirectsPackageFunctions(t *testing.T) {
+	var buf bytes.Buffer
+	restore := SetDefault(New(WithOutput(&buf), WithEncoder(TextEncoder{})))
+	Error("captured", "n", 1)
+	restore()
+
+	if got := buf.String(); got != "[ERROR] [logger.TestSetDefault_RedirectsPackageFunctions:49] captured n=1\n" {
+		t.Fatalf("unexpected output %q", got)
+	}
+	if Default() != std.Load() {
+		t.Fatal("expected restore to reinstate the default logger")
+	}
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordSink struct {
+	entries []*Entry
+	closed  bool
+}
+
+func (s *recordSink) WriteEntry(e *Entry) error {
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+func (s *recordSink) Close() error {
+	s.closed = true
+	return errors.New("already closed")
+}
+
+func TestNew_WritesToOwnOutputs(t *testing.T) {
+	var buf bytes.Buffer
+	sink := &recordSink{}
+	now := time.Date(2026, 3, 2, 10, 14, 7, 0, time.UTC)
+	log := New(
+		WithOutput(&buf),
+		WithEncoder(JSONEncoder{}),
+		WithSink(sink),
+		WithFields("component", "billing"),
+		WithClock(func() time.Time { return now }),
+	)
+
+	log.Debug("hidden")
+	log.Named("invoices").With("run", 7).Warn("invoice late", "id", 42)
+
+	want := `{"time":"2026-03-02T10:14:07Z","level":"WARN","caller":"logger.TestNew_WritesToOwnOutputs:39","msg":"invoice late",` +
+		`"logger":"invoices","component":"billing","run":7,"id":42}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+	if len(sink.entries) != 1 || sink.entries[0].Message != "invoice late" {
+		t.Fatalf("expected the sink to get the WARN entry, got %+v", sink.entries)
+	}
+
+	log.SetLevel(DebugLevel)
+	if !log.Named("x").Enabled(DebugLevel) {
+		t.Fatal("expected SetLevel to apply to descendants")
+	}
+	if err := log.Close(); err == nil || !strings.Contains(err.Error(), "*logger.recordSink") || !sink.closed {
+		t.Fatalf("expected the sink closed with its error, got %v", err)
+	}
+}
+
+func TestNew_FatalExits(t *testing.T) {
+	var code int
+	saved := exit
+	exit = func(c int) { code = c }
+	defer func() { exit = saved }()
+
+	var buf bytes.Buffer
+	New(WithOutput(&buf), WithLevel(ErrorLevel)).Fatal("giving up")
+	if code != 1 || !strings.Contains(buf.String(), "[FATAL]") {
+		t.Fatalf("expected exit status 1 after the entry, got %d and %q", code, buf.String())
+	}
+}
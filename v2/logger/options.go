@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"io"
+	"time"
+
+	v1 "github.com/mordilloSan/go_logger/logger"
+)
+
+// Option configures a logger built with New.
+type Option func(*options)
+
+type options struct {
+	level  Level
+	w      io.Writer
+	enc    Encoder
+	sinks  []Sink
+	name   string
+	fields []any
+	now    func() time.Time
+}
+
+// WithLevel sets the lowest level written; the default is InfoLevel.
+func WithLevel(level Level) Option {
+	return func(o *options) { o.level = level }
+}
+
+// WithOutput sets the writer entries are encoded to, one per line; the
+// default is os.Stderr. A nil w writes to the sinks only.
+func WithOutput(w io.Writer) Option {
+	return func(o *options) { o.w = w }
+}
+
+// WithEncoder sets the encoder used for the writer; the default is a
+// TextEncoder with timestamps.
+func WithEncoder(enc Encoder) Option {
+	return func(o *options) { o.enc = enc }
+}
+
+// WithSink adds a sink receiving every entry written. The logger's Close
+// closes it.
+func WithSink(s Sink) Option {
+	return func(o *options) { o.sinks = append(o.sinks, s) }
+}
+
+// WithName names the logger, as Named does.
+func WithName(name string) Option {
+	return func(o *options) { o.name = name }
+}
+
+// WithFields adds key-value pairs to every entry, as With does.
+func WithFields(keyvals ...any) Option {
+	return func(o *options) { o.fields = append(o.fields, keyvals...) }
+}
+
+// WithClock sets the function returning entry times, for tests.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) { o.now = now }
+}
+
+// InitOption configures the global pipeline set up by Init.
+type InitOption func(*Config)
+
+// WithConfig starts from cfg instead of the environment defaults; apply it
+// before other options.
+func WithConfig(cfg Config) InitOption {
+	return func(c *Config) { *c = cfg }
+}
+
+// WithMode sets "development" or "production" mode.
+func WithMode(mode string) InitOption {
+	return func(c *Config) { c.Mode = mode }
+}
+
+// WithVerbose enables DEBUG entries in development mode.
+func WithVerbose(verbose bool) InitOption {
+	return func(c *Config) { c.Verbose = verbose }
+}
+
+// WithFile also writes entries to the file at path.
+func WithFile(path string) InitOption {
+	return func(c *Config) { c.FilePath = path }
+}
+
+// Init sets up the global pipeline used by Default, its descendants, the
+// package-level functions, and version 1 call sites. It starts from the
+// LOGGER_MODE, LOGGER_VERBOSE, and LOGGER_FILE environment variables, like
+// version 1's DefaultConfig, applies opts in order, and returns the
+// problems the version 1 Validate finds, after initializing anyway:
+//
+//	if err := logger.Init(logger.WithMode("production"), logger.WithFile("/var/log/app.log")); err != nil {
+//	    logger.Warn("logging configuration", "error", err)
+//	}
+func Init(opts ...InitOption) error {
+	cfg := v1.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	err := v1.Validate(cfg)
+	v1.InitWithConfig(cfg)
+	return err
+}
+
+// Close flushes and closes the global pipeline's log file and sinks.
+func Close() error {
+	return v1.Close()
+}
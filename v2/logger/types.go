@@ -0,0 +1,37 @@
+package logger
+
+import (
+	v1 "github.com/mordilloSan/go_logger/logger"
+)
+
+// Version 1 types shared by both versions.
+type (
+	// Entry is one log entry as passed to sinks and encoders.
+	Entry = v1.Entry
+	// Level is the severity of an entry.
+	Level = v1.Level
+	// Sink receives entries; see the version 1 documentation for its
+	// concurrency contract.
+	Sink = v1.Sink
+	// Encoder renders an entry for a sink or an instance logger's writer.
+	Encoder = v1.Encoder
+	// Config is the configuration built by Init's options.
+	Config = v1.Config
+
+	// TextEncoder renders "[LEVEL] [caller] message key=value" lines.
+	TextEncoder = v1.TextEncoder
+	// JSONEncoder renders single-line JSON objects.
+	JSONEncoder = v1.JSONEncoder
+)
+
+// Levels, lowest first.
+const (
+	DebugLevel = v1.DebugLevel
+	InfoLevel  = v1.InfoLevel
+	WarnLevel  = v1.WarnLevel
+	ErrorLevel = v1.ErrorLevel
+	FatalLevel = v1.FatalLevel
+)
+
+// LoggerKey is the field naming the logger an entry came from.
+const LoggerKey = v1.LoggerKey